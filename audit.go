@@ -0,0 +1,204 @@
+package brigodier
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// AuditEntry is an audit record of an input executed by Dispatcher.Execute.
+type AuditEntry struct {
+	Time  time.Time
+	Input string
+	// Path is the names of the nodes the input resolved to, following redirects.
+	Path []string
+	// Principal is who executed the input as returned by Dispatcher.AuditPrincipal
+	// or the name of the Source if AuditPrincipal is nil.
+	Principal string
+	// Err is the error returned by Execute, if any.
+	Err error
+}
+
+// AuditSink receives an AuditEntry after every Execute.
+// Implementations must be safe for concurrent use and handle their own errors.
+type AuditSink interface {
+	Audit(entry *AuditEntry)
+}
+
+// AuditSinkFunc is a convenient function type implementing the AuditSink interface.
+type AuditSinkFunc func(entry *AuditEntry)
+
+// Audit implements AuditSink.
+func (f AuditSinkFunc) Audit(entry *AuditEntry) { f(entry) }
+
+// audit sends the AuditEntry of an executed input to the Dispatcher.AuditSink.
+func (d *Dispatcher) audit(parse *ParseResults, err error) {
+	entry := &AuditEntry{
+		Time:  time.Now(),
+		Input: parse.Reader.String,
+		Err:   err,
+	}
+	for c := parse.Context; c != nil; c = c.Child {
+		for _, n := range c.Nodes {
+			entry.Path = append(entry.Path, n.Node.Name())
+		}
+	}
+	if ctx := parse.Context.Context; ctx != nil {
+		if d.AuditPrincipal != nil {
+			entry.Principal = d.AuditPrincipal(ctx)
+		} else if src := SourceFrom(ctx); src != nil {
+			entry.Principal = src.Name()
+		}
+	}
+	d.AuditSink.Audit(entry)
+}
+
+// JSONAuditSink is an AuditSink writing each AuditEntry as a line of JSON.
+//
+// The records are hash chained to be tamper-evident: each record contains
+// the SHA-256 hash of the previous record and its own hash covering the
+// previous hash, so modifying or removing records invalidates all following
+// records, see VerifyAuditLog.
+type JSONAuditSink struct {
+	mu   sync.Mutex
+	w    io.Writer
+	prev string // hash of the last record
+	err  error
+}
+
+// auditRecord is the JSON representation of an AuditEntry.
+type auditRecord struct {
+	Time      time.Time `json:"time"`
+	Input     string    `json:"input"`
+	Path      []string  `json:"path"`
+	Principal string    `json:"principal,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Prev      string    `json:"prev"`
+	Hash      string    `json:"hash"`
+}
+
+var _ AuditSink = (*JSONAuditSink)(nil)
+
+// NewJSONAuditSink returns a new JSONAuditSink writing to w
+// starting a new hash chain.
+func NewJSONAuditSink(w io.Writer) *JSONAuditSink {
+	return &JSONAuditSink{w: w}
+}
+
+// OpenAuditLog opens or creates the JSON-lines audit log file at path for appending
+// and continues the hash chain of the existing records after verifying them.
+// The returned sink must be closed to close the file.
+func OpenAuditLog(path string) (*JSONAuditSink, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	prev, err := verifyAuditLog(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &JSONAuditSink{w: f, prev: prev}, nil
+}
+
+// Audit implements AuditSink.
+func (s *JSONAuditSink) Audit(entry *AuditEntry) {
+	r := auditRecord{
+		Time:      entry.Time.UTC(),
+		Input:     entry.Input,
+		Path:      entry.Path,
+		Principal: entry.Principal,
+	}
+	if entry.Err != nil {
+		r.Error = entry.Err.Error()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return
+	}
+	r.Prev = s.prev
+	r.Hash = r.hash()
+	b, err := json.Marshal(r)
+	if err == nil {
+		_, err = s.w.Write(append(b, '\n'))
+	}
+	if err != nil {
+		s.err = err
+		return
+	}
+	s.prev = r.Hash
+}
+
+// Close closes the underlying writer if it is an io.Closer.
+func (s *JSONAuditSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Err returns the first error writing a record, if any.
+// No records are written after an error.
+func (s *JSONAuditSink) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// hash returns the hash of the record excluding its Hash field.
+func (r auditRecord) hash() string {
+	r.Hash = ""
+	b, _ := json.Marshal(r)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// ErrAuditLogTampered indicates that an audit log failed verification.
+var ErrAuditLogTampered = errors.New("audit log tampered")
+
+// VerifyAuditLog verifies the hash chain of a JSON-lines audit log
+// written by JSONAuditSink and returns an error wrapping
+// ErrAuditLogTampered if it was modified.
+func VerifyAuditLog(r io.Reader) error {
+	_, err := verifyAuditLog(r)
+	return err
+}
+
+// verifyAuditLog returns the hash of the last record of a verified audit log.
+func verifyAuditLog(r io.Reader) (prev string, err error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	for line := 1; sc.Scan(); line++ {
+		var rec auditRecord
+		if err = json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			return "", fmt.Errorf("%w: line %d: %v", ErrAuditLogTampered, line, err)
+		}
+		if rec.Prev != prev || rec.Hash != rec.hash() {
+			return "", fmt.Errorf("%w: line %d: hash mismatch", ErrAuditLogTampered, line)
+		}
+		prev = rec.Hash
+	}
+	return prev, sc.Err()
+}
+
+// ContextPrincipal returns a function usable as Dispatcher.AuditPrincipal
+// formatting the context.Context value of key, e.g. a player name.
+func ContextPrincipal(key interface{}) func(ctx context.Context) string {
+	return func(ctx context.Context) string {
+		if v := ctx.Value(key); v != nil {
+			return fmt.Sprint(v)
+		}
+		return ""
+	}
+}
@@ -0,0 +1,71 @@
+package brigodier
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type auditUserKey struct{}
+
+func TestDispatcher_AuditSink(t *testing.T) {
+	var entries []*AuditEntry
+	errFail := errors.New("fail")
+	d := NewDispatcher(WithAuditSink(AuditSinkFunc(func(e *AuditEntry) {
+		entries = append(entries, e)
+	}), ContextPrincipal(auditUserKey{})))
+	tp := d.Register(Literal("tp").Then(Argument("target", StringWord).
+		Executes(CommandFunc(func(c *CommandContext) error { return errFail }))))
+	d.Register(Literal("back").Redirect(tp))
+
+	ctx := context.WithValue(context.TODO(), auditUserKey{}, "Steve")
+	require.ErrorIs(t, d.Do(ctx, "back Alex"), errFail)
+	require.ErrorIs(t, d.Do(context.TODO(), "unknown"), ErrDispatcherUnknownCommand)
+
+	require.Len(t, entries, 2)
+	require.Equal(t, "back Alex", entries[0].Input)
+	require.Equal(t, []string{"back", "target"}, entries[0].Path)
+	require.Equal(t, "Steve", entries[0].Principal)
+	require.ErrorIs(t, entries[0].Err, errFail)
+	require.False(t, entries[0].Time.IsZero())
+	require.Empty(t, entries[1].Principal)
+	require.ErrorIs(t, entries[1].Err, ErrDispatcherUnknownCommand)
+}
+
+func TestJSONAuditSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	sink, err := OpenAuditLog(path)
+	require.NoError(t, err)
+	d := NewDispatcher(WithAuditSink(sink, nil))
+	d.Register(Literal("say").Executes(CommandFunc(func(c *CommandContext) error { return nil })))
+	require.NoError(t, d.Do(context.TODO(), "say"))
+	require.NoError(t, sink.Err())
+	require.NoError(t, sink.Close())
+
+	// The chain is continued.
+	sink, err = OpenAuditLog(path)
+	require.NoError(t, err)
+	d.AuditSink = sink
+	require.Error(t, d.Do(context.TODO(), "say x"))
+	require.NoError(t, sink.Close())
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, 2, strings.Count(string(b), "\n"))
+	require.Contains(t, string(b), `"path":["say"]`)
+	require.NoError(t, VerifyAuditLog(bytes.NewReader(b)))
+
+	tampered := bytes.Replace(b, []byte(`"input":"say x"`), []byte(`"input":"say y"`), 1)
+	require.ErrorIs(t, VerifyAuditLog(bytes.NewReader(tampered)), ErrAuditLogTampered)
+	removed := b[bytes.IndexByte(b, '\n')+1:]
+	require.ErrorIs(t, VerifyAuditLog(bytes.NewReader(removed)), ErrAuditLogTampered)
+
+	require.NoError(t, os.WriteFile(path, tampered, 0o600))
+	_, err = OpenAuditLog(path)
+	require.ErrorIs(t, err, ErrAuditLogTampered)
+}
@@ -0,0 +1,56 @@
+package brigodier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrRequirementNotMet is returned for a source of Dispatcher.ExecuteAll
+// that can not use a node of the parsed command.
+var ErrRequirementNotMet = errors.New("dispatcher: requirement not met")
+
+// ExecuteResult is the result of executing a command for one source by Dispatcher.ExecuteAll.
+type ExecuteResult struct {
+	Source context.Context // The context the command was executed with.
+	Err    error           // The error returned by Execute for the source.
+}
+
+// ExecuteAll executes the pre-parsed command once for each of the sources without
+// parsing it again, e.g. for an admin tool broadcasting a command to many players.
+// It returns the results in the order of the sources.
+//
+// Each source replaces the context.Context the command was parsed with.
+// Since requirements are checked while parsing, the requirements of the
+// nodes up to the first redirect with a RedirectModifier are checked again
+// for each source and ErrRequirementNotMet is returned for sources that can
+// not use them. Nodes after such a redirect are executed with the context
+// returned by its RedirectModifier as usual.
+func (d *Dispatcher) ExecuteAll(parse *ParseResults, sources []context.Context) []ExecuteResult {
+	results := make([]ExecuteResult, len(sources))
+	for i, source := range sources {
+		results[i] = ExecuteResult{Source: source, Err: d.executeAs(parse, source)}
+	}
+	return results
+}
+
+func (d *Dispatcher) executeAs(parse *ParseResults, source context.Context) error {
+	c := parse.Context.CopyFor(source)
+	// Contexts following a redirect without RedirectModifier run for the source as well.
+	for ctx := c; ctx != nil; ctx = ctx.Child {
+		for _, node := range ctx.Nodes {
+			if !node.Node.CanUse(c) {
+				return fmt.Errorf("%w: %s", ErrRequirementNotMet, node.Node.Name())
+			}
+		}
+		if ctx.Modifier != nil {
+			break
+		}
+	}
+	return d.Execute(&ParseResults{
+		Context:    c,
+		Reader:     parse.Reader,
+		Errs:       parse.Errs,
+		dispatcher: parse.dispatcher,
+	})
+}
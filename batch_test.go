@@ -0,0 +1,68 @@
+package brigodier
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDispatcher_ExecuteAll(t *testing.T) {
+	var healed []string
+	errFull := errors.New("already full health")
+	d := NewDispatcher()
+	d.Register(Literal("heal").
+		Requires(func(ctx context.Context) bool { return SourceFrom(ctx).(*testSource).name != "Guest" }).
+		Executes(CommandFunc(func(c *CommandContext) error {
+			if c.Source().Name() == "Steve" {
+				return errFull
+			}
+			healed = append(healed, c.Source().Name())
+			return nil
+		})))
+
+	admin := WithSource(context.TODO(), &testSource{name: "Admin", op: true})
+	parse := d.Parse(admin, "heal")
+	sources := []context.Context{
+		WithSource(context.TODO(), &testSource{name: "Alex"}),
+		WithSource(context.TODO(), &testSource{name: "Steve"}),
+		WithSource(context.TODO(), &testSource{name: "Guest"}),
+		WithSource(context.TODO(), &testSource{name: "Notch"}),
+	}
+	results := d.ExecuteAll(parse, sources)
+	require.Len(t, results, 4)
+	for i, r := range results {
+		require.Equal(t, sources[i], r.Source)
+	}
+	require.NoError(t, results[0].Err)
+	require.ErrorIs(t, results[1].Err, errFull)
+	require.ErrorIs(t, results[2].Err, ErrRequirementNotMet)
+	require.NoError(t, results[3].Err)
+	require.Equal(t, []string{"Alex", "Notch"}, healed)
+
+	// the parse results can still be executed for the original source
+	require.NoError(t, d.Execute(parse))
+	require.Equal(t, []string{"Alex", "Notch", "Admin"}, healed)
+
+	results = d.ExecuteAll(d.Parse(admin, "unknown"), sources[:1])
+	require.ErrorIs(t, results[0].Err, ErrDispatcherUnknownCommand)
+}
+
+func TestDispatcher_ExecuteAll_Redirect(t *testing.T) {
+	var ran []string
+	d := NewDispatcher()
+	d.Register(Literal("op").
+		Requires(func(ctx context.Context) bool { return SourceFrom(ctx).(*testSource).op }).
+		Executes(CommandFunc(func(c *CommandContext) error {
+			ran = append(ran, c.Source().Name())
+			return nil
+		})))
+	d.Register(Literal("run").Redirect(&d.Root))
+
+	admin := WithSource(context.TODO(), &testSource{name: "Admin", op: true})
+	player := WithSource(context.TODO(), &testSource{name: "Player"})
+	results := d.ExecuteAll(d.Parse(admin, "run op"), []context.Context{player, admin})
+	require.ErrorIs(t, results[0].Err, ErrRequirementNotMet)
+	require.NoError(t, results[1].Err)
+	require.Equal(t, []string{"Admin"}, ran)
+}
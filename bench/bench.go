@@ -0,0 +1,120 @@
+// Package bench contains generators of large command trees and
+// benchmarks of the Dispatcher operations on them.
+//
+// The generated trees are meant as a performance baseline and for profiling, e.g.:
+//
+//	go test ./bench -bench . -benchmem -cpuprofile cpu.out
+package bench
+
+import (
+	"context"
+	"fmt"
+	"go.minekube.com/brigodier"
+	"strings"
+)
+
+// Tree is a generated command tree with an input executing one of its commands.
+type Tree struct {
+	Name       string
+	Dispatcher *brigodier.Dispatcher
+	// Input is an executable command.
+	Input string
+	// Partial is an incomplete Input to request suggestions for.
+	Partial string
+}
+
+// Command is the no-op command executed by all generated trees.
+var Command = brigodier.CommandFunc(func(*brigodier.CommandContext) error { return nil })
+
+// Wide returns a tree with n root literals "command0" to "command<n-1>"
+// each taking an int argument.
+func Wide(n int) *Tree {
+	d := new(brigodier.Dispatcher)
+	for i := 0; i < n; i++ {
+		d.Register(brigodier.Literal(fmt.Sprintf("command%d", i)).
+			Executes(Command).
+			Then(brigodier.Argument("value", brigodier.Int).Executes(Command)),
+		)
+	}
+	last := fmt.Sprintf("command%d", n-1)
+	return &Tree{
+		Name:       fmt.Sprintf("wide=%d", n),
+		Dispatcher: d,
+		Input:      last + " 42",
+		Partial:    last[:len(last)-1],
+	}
+}
+
+// Deep returns a tree with a single root literal and depth nested
+// nodes alternating between literals and int arguments, each having
+// an executable sibling that does not match the input.
+func Deep(depth int) *Tree {
+	var node brigodier.Builder = brigodier.Literal("end").Executes(Command)
+	input := make([]string, 0, depth+2)
+	for i := depth; i > 0; i-- {
+		if i%2 == 0 {
+			node = brigodier.Literal(fmt.Sprintf("literal%d", i)).
+				Then(node).
+				Then(brigodier.Literal(fmt.Sprintf("other%d", i)).Executes(Command))
+		} else {
+			node = brigodier.Argument(fmt.Sprintf("argument%d", i), brigodier.Int).
+				Then(node).
+				Then(brigodier.Argument(fmt.Sprintf("other%d", i), brigodier.Bool).Executes(Command))
+		}
+	}
+	input = append(input, "deep")
+	for i := 1; i <= depth; i++ {
+		if i%2 == 0 {
+			input = append(input, fmt.Sprintf("literal%d", i))
+		} else {
+			input = append(input, fmt.Sprint(i))
+		}
+	}
+	d := new(brigodier.Dispatcher)
+	d.Register(brigodier.Literal("deep").Then(node))
+	return &Tree{
+		Name:       fmt.Sprintf("deep=%d", depth),
+		Dispatcher: d,
+		Input:      strings.Join(append(input, "end"), " "),
+		Partial:    strings.Join(append(input, "e"), " "),
+	}
+}
+
+// Redirects returns a tree modeled after the "execute" command whose
+// input passes through hops redirects, each applying a RedirectModifier:
+//
+//	execute as <n> ... run leaf
+func Redirects(hops int) *Tree {
+	d := new(brigodier.Dispatcher)
+	d.Register(brigodier.Literal("leaf").Executes(Command))
+	execute := d.Register(brigodier.Literal("execute"))
+	modifier := brigodier.ModifierFunc(func(c *brigodier.CommandContext) (context.Context, error) {
+		return c, nil
+	})
+	d.Register(brigodier.Literal("execute").
+		Then(brigodier.Literal("as").
+			Then(brigodier.Argument("n", brigodier.Int).RedirectWithModifier(execute, modifier))).
+		Then(brigodier.Literal("run").Redirect(&d.Root)),
+	)
+	var b strings.Builder
+	b.WriteString("execute")
+	for i := 0; i < hops; i++ {
+		fmt.Fprintf(&b, " as %d", i)
+	}
+	b.WriteString(" run ")
+	return &Tree{
+		Name:       fmt.Sprintf("redirects=%d", hops),
+		Dispatcher: d,
+		Input:      b.String() + "leaf",
+		Partial:    b.String() + "le",
+	}
+}
+
+// Trees returns the trees used by the benchmarks of this package.
+func Trees() []*Tree {
+	return []*Tree{
+		Wide(10000),
+		Deep(64),
+		Redirects(64),
+	}
+}
@@ -0,0 +1,72 @@
+package bench
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestTrees(t *testing.T) {
+	for _, tree := range Trees() {
+		t.Run(tree.Name, func(t *testing.T) {
+			d := tree.Dispatcher
+			require.NoError(t, d.Do(context.TODO(), tree.Input))
+			suggestions, err := d.CompletionSuggestions(d.Parse(context.TODO(), tree.Partial))
+			require.NoError(t, err)
+			require.NotEmpty(t, suggestions.Suggestions)
+		})
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	for _, tree := range Trees() {
+		b.Run(tree.Name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				tree.Dispatcher.Parse(context.TODO(), tree.Input)
+			}
+		})
+	}
+}
+
+func BenchmarkExecute(b *testing.B) {
+	for _, tree := range Trees() {
+		b.Run(tree.Name, func(b *testing.B) {
+			parse := tree.Dispatcher.Parse(context.TODO(), tree.Input)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := tree.Dispatcher.Execute(parse); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkCompletionSuggestions(b *testing.B) {
+	for _, tree := range Trees() {
+		b.Run(tree.Name, func(b *testing.B) {
+			parse := tree.Dispatcher.Parse(context.TODO(), tree.Partial)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := tree.Dispatcher.CompletionSuggestions(parse); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkAllUsage(b *testing.B) {
+	for _, tree := range Trees() {
+		b.Run(tree.Name, func(b *testing.B) {
+			d := tree.Dispatcher
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				d.AllUsage(context.TODO(), &d.Root, false)
+			}
+		})
+	}
+}
@@ -0,0 +1,321 @@
+package brigodier
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// BindTag is the struct field tag key used by Bind.
+const BindTag = "brig"
+
+// ErrInvalidBindTag indicates an invalid struct field tag used with Bind.
+var ErrInvalidBindTag = errors.New("invalid brig tag")
+
+// Bind returns a builder for the literal command whose arguments are generated
+// from the exported fields of the struct type T in declaration order.
+// When the command is executed, the parsed arguments are stored into a new T
+// that is passed to handler.
+//
+// The argument of a field is configured by its struct tag:
+//
+//	type GiveArgs struct {
+//		Item  string `brig:"item,word"`
+//		Count int    `brig:"count,int,min=1,max=64,optional"`
+//	}
+//	b, err := Bind("give", func(c *CommandContext, args *GiveArgs) error { ... })
+//	d.Register(b)
+//
+// The tag consists of the argument name (defaults to the lower-cased field name),
+// the optional argument type and options. The type defaults to the field's kind
+// and is one of int, int32, int64, uint, uint32, uint64, float32, float64, bool,
+// string (quotable), word or greedy.
+// Options are min=N and max=N for numeric types and optional, which makes the
+// argument and all following arguments optional. Fields tagged "-" are skipped.
+// Integer bounds are narrowed to the range of the field, e.g. -128 to 127 for int8.
+func Bind[T any](literal string, handler func(c *CommandContext, args *T) error) (LiteralNodeBuilder, error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %s is not a struct", ErrInvalidBindTag, typ)
+	}
+	fields, err := bindFields(typ)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := CommandFunc(func(c *CommandContext) error {
+		args := new(T)
+		v := reflect.ValueOf(args).Elem()
+		for _, f := range fields {
+			result, ok := c.Get(f.name)
+			if !ok {
+				continue
+			}
+			field := v.Field(f.index)
+			field.Set(reflect.ValueOf(result).Convert(field.Type()))
+		}
+		return handler(c, args)
+	})
+
+	// Build the chain of arguments from the last to the first.
+	var next Builder
+	for i := len(fields) - 1; i >= 0; i-- {
+		arg := Argument(fields[i].name, fields[i].typ)
+		if next == nil {
+			next = arg.Executes(cmd)
+			continue
+		}
+		b := arg.Then(next)
+		if fields[i+1].optional {
+			b = b.Executes(cmd)
+		}
+		next = b
+	}
+	root := Literal(literal)
+	if next == nil {
+		return root.Executes(cmd), nil
+	}
+	b := root.Then(next)
+	if fields[0].optional {
+		b = b.Executes(cmd)
+	}
+	return b, nil
+}
+
+type bindField struct {
+	index    int
+	name     string
+	typ      ArgumentType
+	optional bool
+}
+
+func bindFields(typ reflect.Type) ([]bindField, error) {
+	var fields []bindField
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		tag, hasTag := sf.Tag.Lookup(BindTag)
+		if sf.PkgPath != "" || tag == "-" {
+			continue // unexported or skipped
+		}
+		f, err := parseBindTag(sf, tag)
+		if err != nil {
+			return nil, fmt.Errorf("%w on field %s: %v", ErrInvalidBindTag, sf.Name, err)
+		}
+		if f.typ == nil {
+			if hasTag {
+				return nil, fmt.Errorf("%w on field %s: no argument type for %s", ErrInvalidBindTag, sf.Name, sf.Type)
+			}
+			continue // untagged field of unsupported kind
+		}
+		if len(fields) != 0 && fields[len(fields)-1].optional && !f.optional {
+			return nil, fmt.Errorf("%w on field %s: required argument after optional argument", ErrInvalidBindTag, sf.Name)
+		}
+		f.index = i
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+func parseBindTag(sf reflect.StructField, tag string) (f bindField, err error) {
+	parts := strings.Split(tag, ",")
+	f.name = strings.ToLower(sf.Name)
+	if parts[0] != "" {
+		f.name = parts[0]
+	}
+	typeName := ""
+	if len(parts) > 1 && !strings.Contains(parts[1], "=") && parts[1] != "optional" {
+		typeName = parts[1]
+		parts = parts[1:]
+	}
+	if typeName == "" {
+		typeName = bindKindType(sf.Type.Kind())
+	}
+
+	var min, max *float64
+	for _, opt := range parts[1:] {
+		key, value, _ := strings.Cut(opt, "=")
+		switch key {
+		case "optional":
+			f.optional = true
+		case "min", "max":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return f, fmt.Errorf("invalid %s %q", key, value)
+			}
+			if key == "min" {
+				min = &n
+			} else {
+				max = &n
+			}
+		default:
+			return f, fmt.Errorf("unknown option %q", opt)
+		}
+	}
+
+	f.typ, err = bindArgumentType(typeName, min, max)
+	if err != nil {
+		return f, err
+	}
+	if f.typ == nil {
+		return f, nil
+	}
+	// Also reject integer to string conversions which ConvertibleTo allows.
+	zero := reflect.TypeOf(bindZero(f.typ))
+	if !zero.ConvertibleTo(sf.Type) || (zero.Kind() == reflect.String) != (sf.Type.Kind() == reflect.String) {
+		return f, fmt.Errorf("type %s can not be stored in %s", typeName, sf.Type)
+	}
+	if !clampBindBounds(f.typ, sf.Type) {
+		return f, fmt.Errorf("bounds of type %s are outside of the range of %s", typeName, sf.Type)
+	}
+	return f, nil
+}
+
+// clampBindBounds narrows the bounds of an integer type t to the range of the
+// integer field type so parsed values are not truncated when stored.
+// It returns false if no value within the bounds fits into the field.
+func clampBindBounds(t ArgumentType, field reflect.Type) bool {
+	var (
+		lo int64
+		hi uint64
+	)
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		lo, hi = -1<<(field.Bits()-1), 1<<(field.Bits()-1)-1
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		lo, hi = 0, 1<<field.Bits()-1
+	default:
+		return true
+	}
+	switch t := t.(type) {
+	case *Int32ArgumentType:
+		if int64(t.Min) < lo {
+			t.Min = int32(lo)
+		}
+		if t.Max >= 0 && uint64(t.Max) > hi {
+			t.Max = int32(hi)
+		}
+		return t.Min <= t.Max
+	case *Int64ArgumentType:
+		if t.Min < lo {
+			t.Min = lo
+		}
+		if t.Max >= 0 && uint64(t.Max) > hi {
+			t.Max = int64(hi)
+		}
+		return t.Min <= t.Max
+	case *Uint32ArgumentType:
+		if uint64(t.Max) > hi {
+			t.Max = uint32(hi)
+		}
+		return t.Min <= t.Max
+	case *Uint64ArgumentType:
+		if t.Max > hi {
+			t.Max = hi
+		}
+		return t.Min <= t.Max
+	}
+	return true
+}
+
+// bindKindType returns the default type name for a field kind.
+func bindKindType(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return "int32"
+	case reflect.Int64:
+		return "int64"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return "uint32"
+	case reflect.Uint64:
+		return "uint64"
+	case reflect.Float32:
+		return "float32"
+	case reflect.Float64:
+		return "float64"
+	case reflect.Bool:
+		return "bool"
+	case reflect.String:
+		return "string"
+	}
+	return ""
+}
+
+func bindArgumentType(name string, min, max *float64) (ArgumentType, error) {
+	bound := func(p *float64, def float64) float64 {
+		if p == nil {
+			return def
+		}
+		return *p
+	}
+	numeric := true
+	var t ArgumentType
+	switch name {
+	case "":
+		return nil, nil
+	case "int", "int32":
+		t = &Int32ArgumentType{Min: int32(bound(min, MinInt32)), Max: int32(bound(max, MaxInt32))}
+	case "int64":
+		t = &Int64ArgumentType{Min: MinInt64, Max: MaxInt64}
+		if min != nil {
+			t.(*Int64ArgumentType).Min = int64(*min)
+		}
+		if max != nil {
+			t.(*Int64ArgumentType).Max = int64(*max)
+		}
+	case "uint", "uint32":
+		t = &Uint32ArgumentType{Min: uint32(bound(min, MinUint32)), Max: uint32(bound(max, MaxUint32))}
+	case "uint64":
+		t = &Uint64ArgumentType{Min: MinUint64, Max: MaxUint64}
+		if min != nil {
+			t.(*Uint64ArgumentType).Min = uint64(*min)
+		}
+		if max != nil {
+			t.(*Uint64ArgumentType).Max = uint64(*max)
+		}
+	case "float32":
+		t = &Float32ArgumentType{Min: float32(bound(min, MinFloat32)), Max: float32(bound(max, MaxFloat32))}
+	case "float", "float64":
+		t = &Float64ArgumentType{Min: bound(min, MinFloat64), Max: bound(max, MaxFloat64)}
+	default:
+		numeric = false
+		switch name {
+		case "bool":
+			t = Bool
+		case "string":
+			t = String
+		case "word":
+			t = StringWord
+		case "greedy":
+			t = StringPhrase
+		default:
+			return nil, fmt.Errorf("unknown type %q", name)
+		}
+	}
+	if !numeric && (min != nil || max != nil) {
+		return nil, fmt.Errorf("min and max are not supported by type %s", name)
+	}
+	return t, nil
+}
+
+// bindZero returns the zero value of the result of a type returned by bindArgumentType.
+func bindZero(t ArgumentType) interface{} {
+	switch t.(type) {
+	case *Int32ArgumentType:
+		return int32(0)
+	case *Int64ArgumentType:
+		return int64(0)
+	case *Uint32ArgumentType:
+		return uint32(0)
+	case *Uint64ArgumentType:
+		return uint64(0)
+	case *Float32ArgumentType:
+		return float32(0)
+	case *Float64ArgumentType:
+		return float64(0)
+	case *BoolArgumentType:
+		return false
+	}
+	return ""
+}
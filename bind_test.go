@@ -0,0 +1,108 @@
+package brigodier
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type giveArgs struct {
+	Player string  `brig:"player,word"`
+	Item   string  `brig:",word"`
+	Count  int     `brig:"count,min=1,max=64,optional"`
+	Damage float64 `brig:",optional"`
+	note   string
+	Skip   string `brig:"-"`
+}
+
+func TestBind(t *testing.T) {
+	var got []giveArgs
+	b, err := Bind("give", func(c *CommandContext, args *giveArgs) error {
+		got = append(got, *args)
+		return nil
+	})
+	require.NoError(t, err)
+	var d Dispatcher
+	d.Register(b)
+
+	require.NoError(t, d.Do(context.TODO(), "give Steve diamond"))
+	require.NoError(t, d.Do(context.TODO(), "give Steve diamond 64"))
+	require.NoError(t, d.Do(context.TODO(), "give Steve diamond 2 0.5"))
+	require.ErrorIs(t, d.Do(context.TODO(), "give Steve diamond 65"), ErrArgumentIntegerTooHigh)
+	require.ErrorIs(t, d.Do(context.TODO(), "give Steve"), ErrDispatcherUnknownCommand)
+	require.Equal(t, []giveArgs{
+		{Player: "Steve", Item: "diamond"},
+		{Player: "Steve", Item: "diamond", Count: 64},
+		{Player: "Steve", Item: "diamond", Count: 2, Damage: 0.5},
+	}, got)
+	require.Equal(t, []string{
+		"give [player] [item]",
+		"give [player] [item] [count]",
+		"give [player] [item] [count] [damage]",
+	}, d.AllUsage(context.TODO(), &d.Root, false))
+}
+
+func TestBind_FieldRange(t *testing.T) {
+	type args struct {
+		Level int8
+		Port  uint16
+		Slot  uint8 `brig:"slot,int"`
+	}
+	var got args
+	b, err := Bind("set", func(c *CommandContext, a *args) error {
+		got = *a
+		return nil
+	})
+	require.NoError(t, err)
+	var d Dispatcher
+	d.Register(b)
+
+	require.NoError(t, d.Do(context.TODO(), "set -128 65535 255"))
+	require.Equal(t, args{Level: -128, Port: 65535, Slot: 255}, got)
+	require.ErrorIs(t, d.Do(context.TODO(), "set 300 1 1"), ErrArgumentIntegerTooHigh)
+	require.ErrorIs(t, d.Do(context.TODO(), "set -129 1 1"), ErrArgumentIntegerTooLow)
+	require.ErrorIs(t, d.Do(context.TODO(), "set 1 65536 1"), ErrArgumentIntegerTooHigh)
+	require.ErrorIs(t, d.Do(context.TODO(), "set 1 1 -1"), ErrArgumentIntegerTooLow)
+
+	_, err = Bind("cmd", func(*CommandContext, *struct {
+		A uint8 `brig:"a,int,max=-1"`
+	}) error {
+		return nil
+	})
+	require.ErrorIs(t, err, ErrInvalidBindTag)
+}
+
+func TestBind_InvalidTags(t *testing.T) {
+	handler := func(*CommandContext, *struct{ A int }) error { return nil }
+	_, err := Bind("ok", handler)
+	require.NoError(t, err)
+
+	_, err = Bind("cmd", func(*CommandContext, *struct {
+		A int `brig:"a,optional"`
+		B int
+	}) error {
+		return nil
+	})
+	require.ErrorIs(t, err, ErrInvalidBindTag)
+
+	_, err = Bind("cmd", func(*CommandContext, *struct {
+		A string `brig:"a,int"`
+	}) error {
+		return nil
+	})
+	require.ErrorIs(t, err, ErrInvalidBindTag)
+
+	_, err = Bind("cmd", func(*CommandContext, *struct {
+		A string `brig:"a,word,min=1"`
+	}) error {
+		return nil
+	})
+	require.ErrorIs(t, err, ErrInvalidBindTag)
+
+	_, err = Bind("cmd", func(*CommandContext, *struct {
+		A []int `brig:"a"`
+	}) error {
+		return nil
+	})
+	require.ErrorIs(t, err, ErrInvalidBindTag)
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
 )
 
 // Dispatcher is the command dispatcher,
@@ -13,23 +16,149 @@ type Dispatcher struct {
 	// This is often useful as a target of an
 	// ArgumentBuilder.Redirect, AllUsage or SmartUsage.
 	Root RootCommandNode
+
+	// MaxDispatchDepth limits how deep commands may re-entrantly
+	// dispatch other commands using CommandContext.Dispatcher.
+	// Zero uses DefaultMaxDispatchDepth and a negative value disables the limit.
+	MaxDispatchDepth int
+
+	// MaxRedirects limits how many redirects a single parsed input may follow.
+	// Zero uses DefaultMaxRedirects and a negative value disables the limit.
+	MaxRedirects int
+
+	// ExecuteTimeout optionally limits how long a command may run.
+	// The command's CommandContext is done when the timeout is reached
+	// and Execute returns a CommandTimeoutError if the command overran.
+	// It can be overridden per node using the Timeout builder method.
+	ExecuteTimeout time.Duration
+
+	// CaseInsensitiveLiterals makes literals match the input ignoring case.
+	// A literal matching the input exactly is preferred.
+	CaseInsensitiveLiterals bool
+
+	// NamePolicy optionally normalizes or rejects the literals
+	// of commands registered using Register and TryRegister,
+	// e.g. LowercaseNames. Nodes added using AddChild are not affected.
+	NamePolicy NamePolicy
+
+	// ConflictPolicy decides what Register does if the
+	// literal of a command is already registered.
+	// The default is ConflictMerge.
+	ConflictPolicy ConflictPolicy
+
+	// MergeStrategy decides how the properties of nodes are merged
+	// by Register if the ConflictPolicy is ConflictMerge, see MergeChild.
+	MergeStrategy MergeStrategy
+
+	// ArgumentSiblingsOnLiteral also tries the argument siblings of a literal
+	// matching the input, like Brigadier does in some ambiguous cases.
+	// By default a matching literal wins and its argument siblings are not tried,
+	// e.g. for "foo bar baz" with the children "bar <int>" and "<word> baz" of "foo",
+	// only "bar" is tried and parsing fails at "baz", whereas this mode
+	// falls back to "<word> baz". If both parse the input, the literal still wins.
+	ArgumentSiblingsOnLiteral bool
+
+	// SnapSuggestionsToToken makes CompletionSuggestionsCursor extend the ranges
+	// of suggestions ending at the cursor to the end of the token under the cursor,
+	// so that applying a suggestion replaces the whole token even if the cursor
+	// is in the middle of it.
+	SnapSuggestionsToToken bool
+
+	// Types optionally replaces DefaultTypes as the TypeRegistry
+	// used to serialize the argument types of the command tree.
+	Types *TypeRegistry
+
+	// ErrorProvider optionally replaces the syntax errors returned by
+	// Execute and Validate, e.g. to translate them for the user.
+	ErrorProvider ErrorProvider
+
+	// Instrumentation optionally observes parsing, execution
+	// and suggestions, e.g. for tracing and metrics.
+	Instrumentation Instrumentation
+
+	// Cooldowns tracks the cooldowns of nodes, see the Cooldown builder method.
+	// Commands executed through a node with a cooldown fail with
+	// ErrNoCooldowns if it is nil.
+	Cooldowns *Cooldowns
+
+	// AuditSink optionally receives an AuditEntry after every Execute.
+	AuditSink AuditSink
+	// AuditPrincipal optionally extracts who executes an input from the
+	// context.Context passed to Parse for the AuditEntry.
+	// If nil, the name of the Source is used.
+	AuditPrincipal func(ctx context.Context) string
+
+	beforeExecute   []BeforeExecuteFn
+	afterExecute    []AfterExecuteFn
+	onDeprecated    []DeprecatedFn
+	parseCache      *parseCache
+	suggestionCache *suggestionCache
+	namespaces      map[string]string // Plain literal to namespace, see RegisterNamespaced
+	paths           atomic.Value      // of *pathIndex, see Path
 }
 
+// ErrorProvider replaces a syntax error created by the Dispatcher.
+// The error is a *CommandSyntaxError, usually wrapping a *ReaderError
+// that holds the cause and the position in the input.
+type ErrorProvider func(err *CommandSyntaxError) error
+
+// DefaultMaxDispatchDepth is the default of Dispatcher.MaxDispatchDepth.
+const DefaultMaxDispatchDepth = 32
+
+// DefaultMaxRedirects is the default of Dispatcher.MaxRedirects.
+const DefaultMaxRedirects = 256
+
 // Register registers new commands.
 // This is a shortcut for calling Dispatcher.Root.AddChild after building the provided command.
 //
 // As RootCommandNode can only hold literals, this method will only allow literal arguments.
+//
+// If the literal is already registered, the Dispatcher.ConflictPolicy decides
+// whether the command is merged, rejected, replaces the registered one or is ignored.
+// In the latter case the registered command is returned.
+//
+// Register panics if the command is rejected by the Dispatcher.NamePolicy
+// or the Dispatcher.ConflictPolicy, use TryRegister to handle the error instead.
 func (d *Dispatcher) Register(command LiteralNodeBuilder) *LiteralCommandNode {
-	b := command.BuildLiteral()
-	d.Root.AddChild(b)
+	b, err := d.TryRegister(command)
+	if err != nil {
+		panic(err)
+	}
 	return b
 }
 
+// TryRegister is like Register but returns a *NameError
+// if the Dispatcher.NamePolicy rejected a literal of the command,
+// a *CommandConflictError if the Dispatcher.ConflictPolicy is ConflictError
+// or a *MergeConflictError if the Dispatcher.MergeStrategy rejected merging it.
+// Nothing is registered if an error is returned.
+func (d *Dispatcher) TryRegister(command LiteralNodeBuilder) (*LiteralCommandNode, error) {
+	b := command.BuildLiteral()
+	if d.NamePolicy != nil {
+		if err := d.applyNamePolicy(b); err != nil {
+			return nil, err
+		}
+	}
+	if err := d.checkConflict(b); err != nil {
+		return nil, err
+	}
+	b = d.addRoot(b)
+	d.ClearParseCache()
+	d.ClearSuggestionCache()
+	return b, nil
+}
+
 var (
 	// ErrDispatcherUnknownCommand indicates that an input command was not found.
 	ErrDispatcherUnknownCommand = errors.New("dispatcher: unknown command")
 	// ErrDispatcherUnknownArgument indicates that the argument of an input command was not found.
 	ErrDispatcherUnknownArgument = errors.New("dispatcher: unknown argument")
+	// ErrDispatcherMaxDepthExceeded occurs when commands re-entrantly dispatched
+	// more commands than allowed by Dispatcher.MaxDispatchDepth.
+	ErrDispatcherMaxDepthExceeded = errors.New("dispatcher: maximum dispatch depth exceeded")
+	// ErrTooManyRedirects occurs when an input follows more redirects
+	// than allowed by Dispatcher.MaxRedirects.
+	ErrTooManyRedirects = errors.New("dispatcher: too many redirects")
 )
 
 // Do parses and then executes the specified command and returns the execution error, if any.
@@ -49,33 +178,44 @@ func (d *Dispatcher) Do(ctx context.Context, command string) error {
 // If the command passes through a node that is CommandNode.IsFork then it will be 'forked'.
 // A forked command will not return a CommandSyntaxError.
 //
-// TODO After each and any command is ran, a registered callback given to #setConsumer(ResultConsumer)}
-// will be notified of the result and success of the command. You can use that method to gather more meaningful
+// After each and any command is ran, the hooks registered with AfterExecute
+// will be notified of the result and success of the command. You can use them to gather more meaningful
 // results than this method will return, especially when a command forks.
 func (d *Dispatcher) Execute(parse *ParseResults) error {
-	if parse.Reader.CanRead() {
-		if len(parse.Errs) == 1 {
-			return parse.firstErr()
-		} else if parse.Context.Range.IsEmpty() {
-			return &CommandSyntaxError{Err: &ReaderError{
-				Err:    ErrDispatcherUnknownCommand,
-				Reader: parse.Reader,
-			}}
-		} else {
-			return &CommandSyntaxError{Err: &ReaderError{
-				Err:    ErrDispatcherUnknownArgument,
-				Reader: parse.Reader,
-			}}
-		}
+	_, err := d.ExecuteResult(parse)
+	return err
+}
+
+// ExecuteResult is like Execute but also returns the sum of the results of the
+// commands that ran successfully, e.g. the number of affected entities.
+// Commands implementing ResultCommand, like ResultFunc, report their result
+// and other commands count as 1, so for a forked command without
+// ResultCommand it is the number of successful forks.
+func (d *Dispatcher) ExecuteResult(parse *ParseResults) (result int, err error) {
+	if d.Instrumentation != nil {
+		end := d.Instrumentation.StartExecute(parse)
+		defer func() { end(err) }()
+	}
+	if d.AuditSink != nil {
+		defer func() { d.audit(parse, err) }()
+	}
+	depth, err := d.check(parse)
+	if err != nil {
+		return 0, d.provideError(err)
 	}
 
 	forked := false
 	foundCommand := false
 	original := parse.Context.build(parse.Reader.String)
+	for c := original; c != nil; c = c.Child {
+		c.dispatcher = d
+		c.depth = depth
+	}
+	d.notifyDeprecated(original)
+	nodes := chainNodes(original)
 	contexts := []*CommandContext{original}
 	var next []*CommandContext
 
-	var err error
 	for contexts != nil {
 		size := len(contexts)
 		for i := 0; i < size; i++ {
@@ -89,20 +229,25 @@ func (d *Dispatcher) Execute(parse *ParseResults) error {
 					if modifier == nil {
 						next = append(next, child.CopyFor(theContext))
 					} else {
-						result, err := modifier.Apply(theContext)
+						ctx, err := modifier.Apply(theContext)
 						if err != nil {
 							if !forked {
-								return err
+								return result, err
 							}
 						} else {
-							next = append(next, child.CopyFor(result))
+							next = append(next, child.CopyFor(ctx))
 						}
 					}
 				}
 			} else if theContext.Command != nil {
 				foundCommand = true
-				if err = theContext.Command.Run(theContext); err != nil && !forked {
-					return err
+				n, err := d.run(theContext, nodes)
+				if err != nil {
+					if !forked {
+						return result, err
+					}
+				} else {
+					result += n
 				}
 			}
 		}
@@ -112,15 +257,172 @@ func (d *Dispatcher) Execute(parse *ParseResults) error {
 	}
 
 	if !foundCommand {
-		return &CommandSyntaxError{Err: &ReaderError{
+		return 0, d.provideError(&CommandSyntaxError{Err: &ReaderError{
 			Err:    ErrDispatcherUnknownCommand,
 			Reader: parse.Reader,
-		}}
+		}})
+	}
+	return result, nil
+}
+
+// Validate performs the same checks as Execute without running any Command or RedirectModifier.
+// It returns nil if the parse results would be executed by Execute, e.g. to show
+// whether the input is valid while the user is still typing.
+//
+// Since no RedirectModifier is applied, errors returned by modifiers or commands
+// are not detected.
+func (d *Dispatcher) Validate(parse *ParseResults) error {
+	if _, err := d.check(parse); err != nil {
+		return d.provideError(err)
+	}
+	for c := parse.Context; c != nil; c = c.Child {
+		if c.Child != nil {
+			if !c.Child.HasNodes() {
+				break
+			}
+			return nil
+		} else if c.Command != nil {
+			return nil
+		}
+	}
+	return d.provideError(&CommandSyntaxError{Err: &ReaderError{
+		Err:    ErrDispatcherUnknownCommand,
+		Reader: parse.Reader,
+	}})
+}
+
+// provideError replaces a syntax error using the Dispatcher.ErrorProvider.
+func (d *Dispatcher) provideError(err error) error {
+	if d.ErrorProvider == nil {
+		return err
+	}
+	if syntaxErr, ok := err.(*CommandSyntaxError); ok {
+		return d.ErrorProvider(syntaxErr)
+	}
+	return err
+}
+
+// check checks the parse results before executing them
+// and returns the dispatch depth of the parsed context.
+func (d *Dispatcher) check(parse *ParseResults) (depth int, err error) {
+	if parse.Reader.CanRead() {
+		if len(parse.Errs) == 1 {
+			return 0, parse.firstErr()
+		} else if parse.Context.Range.IsEmpty() {
+			return 0, &CommandSyntaxError{Err: &ReaderError{
+				Err:    ErrDispatcherUnknownCommand,
+				Reader: parse.Reader,
+			}}
+		} else {
+			return 0, &CommandSyntaxError{Err: &ReaderError{
+				Err:    ErrDispatcherUnknownArgument,
+				Reader: parse.Reader,
+			}}
+		}
+	}
+
+	depth = dispatchDepth(parse.Context)
+	if maxDepth := d.maxDispatchDepth(); maxDepth >= 0 && depth > maxDepth {
+		return 0, fmt.Errorf("%w (%d > %d)", ErrDispatcherMaxDepthExceeded, depth, maxDepth)
+	}
+	return depth, nil
+}
+
+// chainNodes returns the nodes of the context chain starting at c in input order,
+// including the nodes before redirects and forks, so that their rate limits,
+// cooldowns, injections and timeouts apply to the executed command.
+func chainNodes(c *CommandContext) []*ParsedCommandNode {
+	if c.Child == nil {
+		return c.Nodes
+	}
+	var nodes []*ParsedCommandNode
+	for ; c != nil; c = c.Child {
+		nodes = append(nodes, c.Nodes...)
+	}
+	return nodes
+}
+
+// run runs the command of a CommandContext
+// given the nodes of its context chain, see chainNodes.
+func (d *Dispatcher) run(c *CommandContext, nodes []*ParsedCommandNode) (result int, err error) {
+	if d.Instrumentation != nil {
+		end := d.Instrumentation.StartCommand(c)
+		defer func() { end(err) }()
+	}
+	if len(d.afterExecute) != 0 {
+		defer func() {
+			for _, fn := range d.afterExecute {
+				fn(c, err)
+			}
+		}()
+	}
+	for _, fn := range d.beforeExecute {
+		if err = fn(c); err != nil {
+			return 0, err
+		}
+	}
+	if err = checkRateLimits(c, nodes); err != nil {
+		return 0, err
+	}
+	if err = d.Cooldowns.check(c, nodes); err != nil {
+		return 0, err
+	}
+	if c, err = inject(c, nodes); err != nil {
+		return 0, err
+	}
+	timeout := d.ExecuteTimeout
+	for i := len(nodes) - 1; i >= 0; i-- { // nearest node first
+		if t := nodes[i].Node.Timeout(); t != 0 {
+			timeout = t
+			break
+		}
+	}
+	if timeout <= 0 {
+		return runCommand(c.Command, c)
 	}
-	return nil
+	ctx, cancel := context.WithTimeout(c, timeout)
+	defer cancel()
+	result, err = runCommand(c.Command, c.CopyFor(ctx))
+	if ctx.Err() == context.DeadlineExceeded && c.Err() == nil {
+		return result, &CommandTimeoutError{Timeout: timeout, Err: err}
+	}
+	return result, err
+}
+
+func (d *Dispatcher) maxDispatchDepth() int {
+	if d.MaxDispatchDepth == 0 {
+		return DefaultMaxDispatchDepth
+	}
+	return d.MaxDispatchDepth
+}
+
+func (d *Dispatcher) maxRedirects() int {
+	if d.MaxRedirects == 0 {
+		return DefaultMaxRedirects
+	}
+	return d.MaxRedirects
+}
+
+// dispatchDepth returns how many commands are currently executing
+// up the context chain of the given CommandContext.
+func dispatchDepth(c *CommandContext) int {
+	if c.Context == nil {
+		return 0
+	}
+	parent, ok := c.Context.Value(commandContextKey{}).(*CommandContext)
+	if !ok || parent.dispatcher == nil {
+		return 0
+	}
+	return parent.depth + 1
 }
 
-// RedirectModifier modifies
+// RedirectModifier modifies the context.Context commands following a redirect
+// are executed with, most commonly to swap the Source, e.g. "execute as <player> run ...",
+// see SourceModifier.
+//
+// Apply is called with the CommandContext of the redirecting node and returns
+// the context.Context of the redirect target. Forks apply the modifier once per
+// result and ignore errors.
 type RedirectModifier interface {
 	Apply(ctx *CommandContext) (context.Context, error)
 }
@@ -140,29 +442,16 @@ func (m ModifierFunc) Apply(c *CommandContext) (context.Context, error) { return
 // The only guarantee made is that for the same command tree and the same version of this library, the result of
 // this method will ALWAYS be a valid input for FindNode, which should return the same node as provided to this method.
 func (d *Dispatcher) Path(target CommandNode) []string {
-	var nodes [][]CommandNode
-	d.addPaths(&d.Root, &nodes, &[]CommandNode{})
-	for _, list := range nodes {
-		if list[len(list)-1] == target {
-			var result []string
-			for _, node := range list {
-				if node != &d.Root {
-					result = append(result, node.Name())
-				}
-			}
-			return result
-		}
+	var result []string
+	for _, node := range d.pathNodes(target) {
+		result = append(result, node.Name())
 	}
-	return nil
+	return result
 }
 
-func (d *Dispatcher) addPaths(node CommandNode, result *[][]CommandNode, parents *[]CommandNode) {
-	current := append([]CommandNode{}, *parents...) // copy
-	current = append(current, node)
-	*result = append(*result, current)
-	for _, child := range node.Children() {
-		d.addPaths(child, result, &current)
-	}
+// pathNodes returns the nodes of the path to target as found by Path.
+func (d *Dispatcher) pathNodes(target CommandNode) []CommandNode {
+	return d.pathIndex().pathNodes(&d.Root, target)
 }
 
 // FindNode finds a node by its path.
@@ -182,6 +471,35 @@ func (d *Dispatcher) FindNode(path ...string) CommandNode {
 	return node
 }
 
+// Detach removes the node at the path from the command tree and returns it with
+// its subtree and whether there was such a node, e.g. to move a command under
+// an admin namespace at runtime:
+//
+//	if cmd, ok := d.Detach("ban"); ok {
+//		d.FindNode("admin").AddChild(cmd)
+//	}
+//
+// Redirects to nodes of the subtree are not changed.
+func (d *Dispatcher) Detach(path ...string) (CommandNode, bool) {
+	if len(path) == 0 {
+		return nil, false
+	}
+	parent := d.FindNode(path[:len(path)-1]...)
+	if parent == nil {
+		return nil, false
+	}
+	node, ok := parent.DetachChild(path[len(path)-1])
+	if !ok {
+		return nil, false
+	}
+	if len(path) == 1 {
+		delete(d.namespaces, path[0])
+	}
+	d.ClearParseCache()
+	d.ClearSuggestionCache()
+	return node, true
+}
+
 // Command is the command run by Dispatcher.Execute for a matching input.
 type Command interface {
 	Run(c *CommandContext) error
@@ -193,6 +511,33 @@ type CommandFunc func(c *CommandContext) error
 // Run implements Command.
 func (cf CommandFunc) Run(c *CommandContext) error { return cf(c) }
 
+// ResultCommand is a Command reporting a result, see Dispatcher.ExecuteResult.
+type ResultCommand interface {
+	Command
+	// RunResult runs the command and returns its result.
+	RunResult(c *CommandContext) (int, error)
+}
+
+// ResultFunc is a convenient function type implementing the ResultCommand interface.
+type ResultFunc func(c *CommandContext) (int, error)
+
+// Run implements Command and discards the result.
+func (rf ResultFunc) Run(c *CommandContext) error {
+	_, err := rf(c)
+	return err
+}
+
+// RunResult implements ResultCommand.
+func (rf ResultFunc) RunResult(c *CommandContext) (int, error) { return rf(c) }
+
+// runCommand runs the command and returns its result, which is 1 if it is no ResultCommand.
+func runCommand(cmd Command, c *CommandContext) (int, error) {
+	if rc, ok := cmd.(ResultCommand); ok {
+		return rc.RunResult(c)
+	}
+	return 1, cmd.Run(c)
+}
+
 // CommandNode is a command node in a tree.
 type CommandNode interface {
 	// Arguments returns the nodes's arguments.
@@ -204,6 +549,7 @@ type CommandNode interface {
 	// for certain subjects, e.g. whether the entity seeing/executing a command is allowed.
 	CanUse(ctx context.Context) bool
 	// RelevantNodes returns the relevant nodes of a Node for an input.
+	// Argument nodes are returned in the same order as registered.
 	RelevantNodes(input *StringReader) []CommandNode
 	// Parse parses the given reader input.
 	Parse(ctx *CommandContext, rd *StringReader) error
@@ -216,11 +562,39 @@ type CommandNode interface {
 	setCommand(Command)
 	// Requirement is the optional condition used to run CanUse.
 	Requirement() RequireFn
+	// RateLimiter is the optional RateLimiter consulted before executing
+	// commands through the node.
+	// May return nil.
+	RateLimiter() RateLimiter
+	// Timeout is the optional execution timeout of commands executed through the node
+	// overriding Dispatcher.ExecuteTimeout.
+	// May return zero.
+	Timeout() time.Duration
+	// Cooldown is the optional minimum duration between executions of
+	// commands through the node by the same source, see Dispatcher.Cooldowns.
+	// May return zero.
+	Cooldown() time.Duration
+	// Injections are the implicit arguments of the node, e.g. declared using the Inject builder method.
+	Injections() []InjectedArgument
+	// Meta returns the metadata of the node, e.g. set using the Meta builder method.
+	// Integrations can use it to attach arbitrary values to nodes.
+	Meta() *Metadata
+	// Tags returns the tags of the node, e.g. set using the Tags builder method.
+	Tags() []string
+	// HasTag indicates whether the node has the tag.
+	HasTag(tag string) bool
+	// Deprecated returns the deprecation message of the node
+	// or an empty string if the node is not deprecated.
+	Deprecated() string
 	// RedirectModifier is the optional redirect modifier.
 	// May return nil.
 	RedirectModifier() RedirectModifier
 	// IsFork indicated whether the node is a fork.
 	IsFork() bool
+	// ID returns the identifier of the node that is unique within the process
+	// and stable for the lifetime of the node, see Dispatcher.NodeByID.
+	// Copies of the node, e.g. by Clone or CreateBuilder, have a new ID.
+	ID() uint64
 	// Name returns the name of the node.
 	Name() string
 	// Children returns the node's children.
@@ -228,13 +602,30 @@ type CommandNode interface {
 	Children() map[string]CommandNode
 	// ChildrenOrdered returns the node's children in the same order as registered.
 	ChildrenOrdered() StringCommandNodeMap
+	// ArgumentsOrdered returns the argument children in the same order as registered.
+	ArgumentsOrdered() []*ArgumentCommandNode
+	// LiteralsWithPrefix returns the literal children whose literal starts with
+	// prefix ignoring case in the same order as registered.
+	LiteralsWithPrefix(prefix string) []*LiteralCommandNode
+	// Count returns the number of nodes in the subtree of the node including itself.
+	Count() int
+	// Depth returns the number of nodes on the longest path below the node.
+	Depth() int
 	// AddChild adds node children to the node.
 	// Passing nil is valid and is ignored.
+	// The nodes are shared, not copied, see CopyInto.
 	AddChild(nodes ...CommandNode)
 	// RemoveChild removes child nodes from the node
 	RemoveChild(names ...string)
+	// DetachChild removes the child node and returns it with its subtree,
+	// e.g. to add it to another node, and whether there was such a child.
+	DetachChild(name string) (CommandNode, bool)
 	// UsageText returns the usage text of the node.
 	UsageText() string
+	// Clone returns a copy of the node with the same children.
+	// If deep, the children are copied recursively and redirects
+	// to nodes of the subtree point to the copied nodes.
+	Clone(deep bool) CommandNode
 	// CreateBuilder creates a new builder without children from the node.
 	//
 	// Note that a RootCommandNode returns a no-operation builder where Build() returns nil.
@@ -245,22 +636,64 @@ type CommandNode interface {
 // RequireFn is the function used for CommandNode.CanUse.
 type RequireFn func(context.Context) bool
 
+// RequiresAll returns a RequireFn that is met if all fns are met.
+// Nil fns are always met.
+func RequiresAll(fns ...RequireFn) RequireFn {
+	return func(ctx context.Context) bool {
+		for _, fn := range fns {
+			if fn != nil && !fn(ctx) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// RequiresAny returns a RequireFn that is met if any of fns is met.
+// Nil fns are always met, no fns are never met.
+func RequiresAny(fns ...RequireFn) RequireFn {
+	return func(ctx context.Context) bool {
+		for _, fn := range fns {
+			if fn == nil || fn(ctx) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
 // Node is a node with the common fields and wrapped by
 // RootCommandNode, LiteralCommandNode and ArgumentCommandNode.
 type Node struct {
+	id              uint64 // Lazily assigned, see ID. First field for 64-bit alignment of atomic operations.
 	childrenOrdered StringCommandNodeMap
 	children        map[string]CommandNode
 	literals        map[string]*LiteralCommandNode
 	arguments       map[string]*ArgumentCommandNode
+	argumentOrder   []*ArgumentCommandNode
 	requirement     RequireFn
 	redirect        CommandNode
 	command         Command
 	modifier        RedirectModifier
 	forks           bool
+	literalIndex    literalIndex
+	rateLimiter     RateLimiter
+	timeout         time.Duration
+	cooldown        time.Duration
+	injections      []InjectedArgument
+	meta            Metadata
+	tags            []string
+	deprecation     string
+	usage           string // Optional UsageText override
 }
 
 // AddChild adds a CommandNode to the Node's children.
 // Most often times one should use Dispatcher.Register instead.
+//
+// The node is shared, not copied: a node added to multiple parents is the
+// same node under each of them, so children added to it later or merged into
+// it by adding a node with the same name appear under all parents.
+// Use CopyInto to add an independent copy.
 func (n *Node) AddChild(nodes ...CommandNode) {
 	for _, node := range nodes {
 		if node == nil {
@@ -272,20 +705,18 @@ func (n *Node) AddChild(nodes ...CommandNode) {
 
 		child := n.Children()[node.Name()]
 		if child != nil {
-			// We've found something to merge onto
-			if node.Command() != nil {
-				child.setCommand(node.Command())
-			}
-			for _, grandchild := range node.Children() {
-				child.AddChild(grandchild)
-			}
+			// We've found something to merge onto,
+			// the default MergeStrategy has no conflicts
+			_ = mergeNode(child, node, MergeStrategy{}, []string{node.Name()}, true)
 		} else {
 			n.putChild(node.Name(), node)
 			switch t := node.(type) {
 			case *LiteralCommandNode:
 				n.Literals()[node.Name()] = t
+				n.literalIndex.insert(t)
 			case *ArgumentCommandNode:
 				n.Arguments()[node.Name()] = t
+				n.argumentOrder = append(n.argumentOrder, t)
 			}
 		}
 	}
@@ -296,8 +727,27 @@ func (n *Node) putChild(name string, node CommandNode) {
 	n.ChildrenOrdered().Put(name, node)
 }
 
+func (n *Node) DetachChild(name string) (CommandNode, bool) {
+	child, ok := n.children[name]
+	if ok {
+		n.RemoveChild(name)
+	}
+	return child, ok
+}
+
 func (n *Node) RemoveChild(names ...string) {
 	for _, name := range names {
+		if lit, ok := n.literals[name]; ok {
+			n.literalIndex.remove(lit)
+		}
+		if arg, ok := n.arguments[name]; ok {
+			for i, a := range n.argumentOrder {
+				if a == arg {
+					n.argumentOrder = append(n.argumentOrder[:i:i], n.argumentOrder[i+1:]...)
+					break
+				}
+			}
+		}
 		delete(n.Children(), name)
 		delete(n.Arguments(), name)
 		delete(n.Literals(), name)
@@ -307,6 +757,14 @@ func (n *Node) RemoveChild(names ...string) {
 
 func (n *Node) RedirectModifier() RedirectModifier { return n.modifier }
 func (n *Node) Requirement() RequireFn             { return n.requirement }
+func (n *Node) RateLimiter() RateLimiter           { return n.rateLimiter }
+func (n *Node) Timeout() time.Duration             { return n.timeout }
+func (n *Node) Cooldown() time.Duration            { return n.cooldown }
+func (n *Node) Injections() []InjectedArgument     { return n.injections }
+func (n *Node) Meta() *Metadata                    { return &n.meta }
+func (n *Node) Tags() []string                     { return n.tags }
+func (n *Node) HasTag(tag string) bool             { return containsString(n.tags, tag) }
+func (n *Node) Deprecated() string                 { return n.deprecation }
 
 func (n *Node) ChildrenOrdered() StringCommandNodeMap {
 	if n.childrenOrdered == nil {
@@ -328,6 +786,18 @@ func (n *Node) Literals() map[string]*LiteralCommandNode {
 	}
 	return n.literals
 }
+
+// ArgumentsOrdered returns the argument children in the same order as registered.
+// Only children added using AddChild are considered.
+func (n *Node) ArgumentsOrdered() []*ArgumentCommandNode { return n.argumentOrder }
+
+// LiteralsWithPrefix returns the literal children whose literal starts with
+// prefix ignoring case in the same order as registered.
+// Only children added using AddChild are considered.
+func (n *Node) LiteralsWithPrefix(prefix string) []*LiteralCommandNode {
+	return n.literalIndex.withPrefix(strings.ToLower(prefix))
+}
+
 func (n *Node) Arguments() map[string]*ArgumentCommandNode {
 	if n.arguments == nil {
 		n.arguments = map[string]*ArgumentCommandNode{}
@@ -363,12 +833,26 @@ type LiteralCommandNode struct {
 	Node
 	Literal string
 
+	mappedArgument string
+	mappedValue    interface{}
+
 	cachedLiteralLowerCase string
 }
 
-func (n *LiteralCommandNode) String() string    { return fmt.Sprintf("<literal %s>", n.Literal) }
-func (n *LiteralCommandNode) Name() string      { return n.Literal }
-func (n *LiteralCommandNode) UsageText() string { return n.Literal }
+func (n *LiteralCommandNode) String() string { return fmt.Sprintf("<literal %s>", n.Literal) }
+func (n *LiteralCommandNode) Name() string   { return n.Literal }
+func (n *LiteralCommandNode) UsageText() string {
+	if n.usage != "" {
+		return n.usage
+	}
+	return n.Literal
+}
+
+// Mapped returns the argument name and value stored when the
+// literal is matched if the literal was built using MappedLiteral.
+func (n *LiteralCommandNode) Mapped() (argumentName string, value interface{}) {
+	return n.mappedArgument, n.mappedValue
+}
 
 // ArgumentCommandNode is an argument command node storing
 // the argument type, name and optional custom suggestions.
@@ -379,10 +863,23 @@ type ArgumentCommandNode struct {
 	name              string
 	argType           ArgumentType
 	customSuggestions SuggestionProvider // Optional
+	canonicalize      CanonicalizeFn     // Optional
+	transforms        []TransformFn      // Optional
+	clientParserID    string             // Optional
+	clientParserProps TypeProperties     // Optional
 
 	cachedUsageText string
 }
 
+// TransformFn validates and transforms a parsed argument value.
+// See RequiredArgumentBuilder.Map.
+type TransformFn func(v interface{}) (interface{}, error)
+
+// CanonicalizeFn normalizes a parsed argument value before it is stored in
+// the CommandContext, e.g. lower-casing a key or resolving an alias to an ID,
+// so that commands and audit logs only ever see the canonical form.
+type CanonicalizeFn func(v interface{}) interface{}
+
 func (a *ArgumentCommandNode) String() string {
 	return fmt.Sprintf("<argument %s:%s>", a.name, a.argType)
 }
@@ -390,6 +887,13 @@ func (a *ArgumentCommandNode) Name() string                          { return a.
 func (a *ArgumentCommandNode) Type() ArgumentType                    { return a.argType }
 func (a *ArgumentCommandNode) CustomSuggestions() SuggestionProvider { return a.customSuggestions }
 
+// ClientParser returns the parser identifier and properties
+// sent to clients if set using RequiredArgumentBuilder.ClientParser.
+func (a *ArgumentCommandNode) ClientParser() (id string, props TypeProperties) {
+	return a.clientParserID, a.clientParserProps
+}
+func (a *ArgumentCommandNode) Canonicalizer() CanonicalizeFn { return a.canonicalize }
+
 const (
 	// UsageArgumentOpen is the open rune for ArgumentCommandNode.UsageText.
 	UsageArgumentOpen rune = '['
@@ -397,9 +901,40 @@ const (
 	UsageArgumentClose rune = ']'
 )
 
+// UsageHintProvider is an optional interface implemented by an ArgumentType
+// to provide the usage text of arguments of the type, e.g. using TypedUsageHint.
+type UsageHintProvider interface {
+	// UsageHint returns the usage text of an argument with the given name.
+	UsageHint(name string) string
+}
+
+// UsageText returns the usage text of the argument.
+// It is the text set using the Usage builder method, the UsageHintProvider.UsageHint
+// of the argument type or the argument name enclosed in UsageArgumentOpen and UsageArgumentClose.
 func (a *ArgumentCommandNode) UsageText() string {
+	if a.usage != "" {
+		return a.usage
+	}
 	if a.cachedUsageText == "" {
-		a.cachedUsageText = fmt.Sprintf("%c%s%c", UsageArgumentOpen, a.name, UsageArgumentClose)
+		if h, ok := a.argType.(UsageHintProvider); ok {
+			a.cachedUsageText = h.UsageHint(a.name)
+		}
+		if a.cachedUsageText == "" {
+			a.cachedUsageText = fmt.Sprintf("%c%s%c", UsageArgumentOpen, a.name, UsageArgumentClose)
+		}
 	}
 	return a.cachedUsageText
 }
+
+// TypedUsageHint returns a usage hint of an argument including its type
+// and the bounds of numeric builtin types, e.g. "<count:int32[1..64]>".
+// It is useful to implement UsageHintProvider.
+//
+// The hint contains no ArgumentSeparator, so usages can still be
+// split into arguments at spaces, e.g. by WrapUsage.
+func TypedUsageHint(name string, t ArgumentType) string {
+	if min, max, ok := typeRange(t); ok {
+		return fmt.Sprintf("<%s:%s%c%v..%v%c>", name, t, UsageOptionalOpen, min, max, UsageOptionalClose)
+	}
+	return fmt.Sprintf("<%s:%s>", name, t)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/stretchr/testify/require"
+	"strings"
 	"testing"
 )
 
@@ -324,3 +325,273 @@ func TestDispatcher_FindNode_DoesntExist(t *testing.T) {
 	var d Dispatcher
 	require.Nil(t, d.FindNode("foo", "bar"))
 }
+
+func TestCommandContext_Dispatcher(t *testing.T) {
+	var d Dispatcher
+	var called bool
+	d.Register(Literal("target").Executes(CommandFunc(func(c *CommandContext) error {
+		called = true
+		return nil
+	})))
+	d.Register(Literal("alias").Executes(CommandFunc(func(c *CommandContext) error {
+		require.Equal(t, &d, c.Dispatcher())
+		return c.Dispatcher().Do(c, "target")
+	})))
+
+	require.NoError(t, d.Do(context.TODO(), "alias"))
+	require.True(t, called)
+}
+
+func TestDispatcher_Execute_MaxDispatchDepth(t *testing.T) {
+	d := &Dispatcher{MaxDispatchDepth: 3}
+	var depth int
+	d.Register(Literal("loop").Executes(CommandFunc(func(c *CommandContext) error {
+		depth++
+		return c.Dispatcher().Do(c, "loop")
+	})))
+
+	err := d.Do(context.TODO(), "loop")
+	require.ErrorIs(t, err, ErrDispatcherMaxDepthExceeded)
+	require.Equal(t, 4, depth)
+}
+
+func TestDispatcher_Execute_Canonicalize(t *testing.T) {
+	var d Dispatcher
+	var key string
+	d.Register(Literal("get").Then(
+		Argument("key", StringWord).
+			Canonicalize(func(v interface{}) interface{} { return strings.ToLower(v.(string)) }).
+			Executes(CommandFunc(func(c *CommandContext) error {
+				key = c.String("key")
+				return nil
+			})),
+	))
+
+	require.NoError(t, d.Do(context.TODO(), "get FooBar"))
+	require.Equal(t, "foobar", key)
+}
+
+func TestParsedArgument_RawAs(t *testing.T) {
+	var d Dispatcher
+	d.Register(Literal("foo").Then(Argument("bar", Int)))
+
+	const input = "foo 0x10"
+	arg := d.Parse(context.TODO(), input).Context.Arguments["bar"]
+	require.Equal(t, "0x10", arg.Raw(input))
+
+	i, ok := As[int32](arg)
+	require.True(t, ok)
+	require.Equal(t, int32(16), i)
+
+	_, ok = As[string](arg)
+	require.False(t, ok)
+	_, ok = As[int32](nil)
+	require.False(t, ok)
+}
+
+func TestDispatcher_Execute_ValidateMap(t *testing.T) {
+	var d Dispatcher
+	errOdd := errors.New("odd number")
+	var half int32
+	d.Register(Literal("half").Then(
+		Argument("n", Int).
+			Validate(func(v interface{}) error {
+				if v.(int32)%2 != 0 {
+					return errOdd
+				}
+				return nil
+			}).
+			Map(func(v interface{}) (interface{}, error) { return v.(int32) / 2, nil }).
+			Executes(CommandFunc(func(c *CommandContext) error {
+				half = c.Int32("n")
+				return nil
+			})),
+	))
+
+	require.NoError(t, d.Do(context.TODO(), "half 10"))
+	require.Equal(t, int32(5), half)
+
+	err := d.Do(context.TODO(), "half 7")
+	require.ErrorIs(t, err, errOdd)
+	var rErr *ReaderError
+	require.True(t, errors.As(err, &rErr))
+	require.Equal(t, 5, rErr.Reader.Cursor)
+}
+
+func TestDispatcher_Validate(t *testing.T) {
+	var d Dispatcher
+	var ran bool
+	cmd := CommandFunc(func(c *CommandContext) error { ran = true; return nil })
+	d.Register(Literal("foo").Then(Argument("bar", Int)).Executes(cmd))
+	d.Register(Literal("redirect").Redirect(&d.Root))
+
+	require.NoError(t, d.Validate(d.Parse(context.TODO(), "foo")))
+	require.NoError(t, d.Validate(d.Parse(context.TODO(), "redirect foo")))
+
+	var err *ReaderError
+	require.True(t, errors.As(d.Validate(d.Parse(context.TODO(), "foo 5")), &err))
+	require.ErrorIs(t, err, ErrDispatcherUnknownCommand)
+	require.ErrorIs(t, d.Validate(d.Parse(context.TODO(), "bar")), ErrDispatcherUnknownCommand)
+	require.ErrorIs(t, d.Validate(d.Parse(context.TODO(), "foo x")), ErrReaderExpectedInt)
+	require.False(t, ran)
+}
+
+func benchmarkDispatcher(depth int) (*Dispatcher, string) {
+	var d Dispatcher
+	cmd := CommandFunc(func(c *CommandContext) error { return nil })
+	var input []string
+	var node Builder = Literal("cmd").Executes(cmd)
+	for i := depth; i > 0; i-- {
+		if i%2 == 0 {
+			node = Literal(fmt.Sprintf("l%d", i)).Then(node).
+				Then(Literal(fmt.Sprintf("other%d", i)).Executes(cmd))
+		} else {
+			node = Argument(fmt.Sprintf("a%d", i), Int).Then(node).
+				Then(Argument(fmt.Sprintf("b%d", i), Bool).Executes(cmd))
+		}
+	}
+	for i := 1; i <= depth; i++ {
+		if i%2 == 0 {
+			input = append(input, fmt.Sprintf("l%d", i))
+		} else {
+			input = append(input, fmt.Sprint(i))
+		}
+	}
+	d.Register(Literal("root").Then(node))
+	return &d, "root " + strings.Join(append(input, "cmd"), " ")
+}
+
+func BenchmarkDispatcher_Parse(b *testing.B) {
+	for _, depth := range []int{2, 16} {
+		b.Run(fmt.Sprintf("depth=%d", depth), func(b *testing.B) {
+			d, input := benchmarkDispatcher(depth)
+			require.NoError(b, d.Validate(d.Parse(context.TODO(), input)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				d.Parse(context.TODO(), input)
+			}
+		})
+	}
+}
+
+func BenchmarkDispatcher_Do(b *testing.B) {
+	d, input := benchmarkDispatcher(16)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = d.Do(context.TODO(), input)
+	}
+}
+
+func TestDispatcher_Execute_AmbiguousArgumentsRegistrationOrder(t *testing.T) {
+	var d Dispatcher
+	var got []string
+	d.Register(Literal("foo").
+		Then(Argument("number", Int).Executes(CommandFunc(func(c *CommandContext) error {
+			got = append(got, "number")
+			return nil
+		}))).
+		Then(Argument("word", String).Executes(CommandFunc(func(c *CommandContext) error {
+			got = append(got, "word")
+			return nil
+		}))),
+	)
+	for i := 0; i < 50; i++ {
+		require.NoError(t, d.Do(context.TODO(), "foo 5"))
+	}
+	for _, name := range got {
+		require.Equal(t, "number", name)
+	}
+
+	foo := d.Root.Children()["foo"]
+	require.Equal(t, "word", foo.ArgumentsOrdered()[1].Name())
+	foo.RemoveChild("number")
+	require.Len(t, foo.ArgumentsOrdered(), 1)
+}
+
+func TestDispatcher_Parse_SharedContextBranches(t *testing.T) {
+	var d Dispatcher
+	cmd := CommandFunc(func(c *CommandContext) error { return nil })
+	d.Register(Literal("foo").Then(
+		Argument("a", Int).
+			Then(Argument("b", Bool).Executes(cmd)).
+			Then(Argument("c", Int).Then(Literal("bar").Executes(cmd))).
+			Then(Argument("d", String).Executes(cmd)),
+	))
+
+	for i := 0; i < 10; i++ {
+		parse := d.Parse(context.TODO(), "foo 1 2 bar")
+		require.NoError(t, d.Validate(parse))
+		require.Len(t, parse.Context.Arguments, 2)
+		require.Equal(t, 1, parse.Context.Int("a"))
+		require.Equal(t, 2, parse.Context.Int("c"))
+		require.Len(t, parse.Context.Nodes, 4)
+
+		parse = d.Parse(context.TODO(), "foo 1 true")
+		require.NoError(t, d.Validate(parse))
+		require.Len(t, parse.Context.Arguments, 2)
+		require.True(t, parse.Context.Bool("b"))
+		require.Len(t, parse.Context.Nodes, 3)
+	}
+}
+
+func TestDispatcher_Parse_MaxRedirects(t *testing.T) {
+	d := NewDispatcher(WithMaxRedirects(3))
+	var ran int
+	cmd := CommandFunc(func(c *CommandContext) error {
+		ran++
+		return nil
+	})
+	loop := d.Register(Literal("loop").Executes(cmd))
+	loop.AddChild(Literal("again").Executes(cmd).Redirect(loop).Build())
+
+	require.NoError(t, d.Do(context.TODO(), "loop again again again again"))
+	require.Equal(t, 1, ran)
+	require.ErrorIs(t, d.Do(context.TODO(), "loop again again again again again"), ErrTooManyRedirects)
+
+	d.MaxRedirects = -1
+	input := "loop" + strings.Repeat(" again", 10000)
+	parse := d.Parse(context.TODO(), input)
+	require.False(t, parse.Reader.CanRead())
+	require.NoError(t, d.Execute(parse))
+	require.Equal(t, 2, ran)
+}
+
+func TestDispatcher_ExecuteResult(t *testing.T) {
+	var d Dispatcher
+	d.Register(Literal("count").Then(Argument("n", Int).ExecutesResult(func(c *CommandContext) (int, error) {
+		return c.Int("n"), nil
+	})))
+	d.Register(Literal("ok").ExecutesFunc(func(c *CommandContext) error { return nil }))
+	d.Register(Literal("all").Fork(&d.Root, ModifierFunc(func(c *CommandContext) (context.Context, error) {
+		return c, nil
+	})))
+
+	result, err := d.ExecuteResult(d.Parse(context.TODO(), "count 5"))
+	require.NoError(t, err)
+	require.Equal(t, 5, result)
+	result, err = d.ExecuteResult(d.Parse(context.TODO(), "ok"))
+	require.NoError(t, err)
+	require.Equal(t, 1, result)
+	result, err = d.ExecuteResult(d.Parse(context.TODO(), "all count 3"))
+	require.NoError(t, err)
+	require.Equal(t, 3, result)
+	require.NoError(t, d.Do(context.TODO(), "count 2"))
+}
+
+func TestRequiresAllAny(t *testing.T) {
+	yes := func(context.Context) bool { return true }
+	no := func(context.Context) bool { return false }
+	ctx := context.TODO()
+	require.True(t, RequiresAll()(ctx))
+	require.True(t, RequiresAll(yes, nil, yes)(ctx))
+	require.False(t, RequiresAll(yes, no)(ctx))
+	require.False(t, RequiresAny()(ctx))
+	require.True(t, RequiresAny(no, yes)(ctx))
+	require.False(t, RequiresAny(no, no)(ctx))
+
+	var d Dispatcher
+	d.Register(Literal("a").Requires(RequiresAny(no, RequiresAll(yes, yes))).ExecutesFunc(func(c *CommandContext) error { return nil }))
+	require.NoError(t, d.Do(ctx, "a"))
+}
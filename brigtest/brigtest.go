@@ -0,0 +1,168 @@
+// Package brigtest provides assertions for testing commands
+// registered to a brigodier Dispatcher:
+//
+//	func TestTeleport(t *testing.T) {
+//		d := brigodier.NewDispatcher()
+//		registerTeleport(d)
+//		brigtest.AssertExecutes(t, d, "tp alice 10", map[string]interface{}{"target": "alice", "y": int32(10)})
+//		brigtest.AssertSyntaxError(t, d, "tp alice x", brigodier.ErrReaderExpectedInt, 9)
+//		brigtest.AssertSuggestions(t, d, "tp ", -1, "alice", "bob")
+//		brigtest.AssertSnapshot(t, d, "testdata/commands.golden")
+//	}
+//
+// The package level functions use context.Background as command source,
+// use a Source to test commands with requirements or sources.
+package brigtest
+
+import (
+	"context"
+	"errors"
+	"go.minekube.com/brigodier"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// Source runs the assertions with Context as the context.Context
+// the inputs are parsed and executed with.
+type Source struct {
+	Context context.Context
+}
+
+// background is the Source of the package level functions.
+var background = Source{Context: context.Background()}
+
+// AssertExecutes asserts that the input executes successfully,
+// see Source.AssertExecutes.
+func AssertExecutes(t testing.TB, d *brigodier.Dispatcher, input string, wantArgs map[string]interface{}) {
+	t.Helper()
+	background.AssertExecutes(t, d, input, wantArgs)
+}
+
+// AssertSyntaxError asserts that the input fails with a syntax error,
+// see Source.AssertSyntaxError.
+func AssertSyntaxError(t testing.TB, d *brigodier.Dispatcher, input string, wantErr error, wantCursor int) {
+	t.Helper()
+	background.AssertSyntaxError(t, d, input, wantErr, wantCursor)
+}
+
+// AssertSuggestions asserts the suggestions for the input,
+// see Source.AssertSuggestions.
+func AssertSuggestions(t testing.TB, d *brigodier.Dispatcher, input string, cursor int, want ...string) {
+	t.Helper()
+	background.AssertSuggestions(t, d, input, cursor, want...)
+}
+
+// AssertExecutes asserts that the input executes without error.
+//
+// If wantArgs is not nil, it must equal the results of the arguments parsed
+// for the executed command by name, e.g. {"target": "alice", "y": int32(10)},
+// including the types returned by the ArgumentTypes.
+// For inputs passing through redirects, those are the arguments
+// parsed after the last redirect.
+func (s Source) AssertExecutes(t testing.TB, d *brigodier.Dispatcher, input string, wantArgs map[string]interface{}) {
+	t.Helper()
+	parse := d.Parse(s.Context, input)
+	if err := d.Execute(parse); err != nil {
+		t.Fatalf("execute %q: unexpected error: %v", input, err)
+	}
+	if wantArgs == nil {
+		return
+	}
+	c := parse.Context
+	for c.Child != nil {
+		c = c.Child
+	}
+	for name, want := range wantArgs {
+		arg, ok := c.Arguments[name]
+		if !ok {
+			t.Errorf("execute %q: missing argument %q, want %T(%v)", input, name, want, want)
+			continue
+		}
+		if !reflect.DeepEqual(arg.Result, want) {
+			t.Errorf("execute %q: argument %q is %T(%v), want %T(%v)", input, name, arg.Result, arg.Result, want, want)
+		}
+	}
+	for name, arg := range c.Arguments {
+		if _, ok := wantArgs[name]; !ok {
+			t.Errorf("execute %q: unexpected argument %q: %T(%v)", input, name, arg.Result, arg.Result)
+		}
+	}
+}
+
+// AssertSyntaxError asserts that executing the input fails with a
+// brigodier.CommandSyntaxError wrapping wantErr at the input cursor wantCursor.
+// A nil wantErr matches any syntax error and a negative wantCursor any cursor.
+func (s Source) AssertSyntaxError(t testing.TB, d *brigodier.Dispatcher, input string, wantErr error, wantCursor int) {
+	t.Helper()
+	err := d.Do(s.Context, input)
+	if err == nil {
+		t.Fatalf("execute %q: expected syntax error, got none", input)
+	}
+	var syntaxErr *brigodier.CommandSyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("execute %q: expected syntax error, got %T: %v", input, err, err)
+	}
+	if wantErr != nil && !errors.Is(err, wantErr) {
+		t.Errorf("execute %q: error\n got: %v\nwant: %v", input, err, wantErr)
+	}
+	if wantCursor < 0 {
+		return
+	}
+	var readerErr *brigodier.ReaderError
+	if !errors.As(err, &readerErr) || readerErr.Reader == nil {
+		t.Errorf("execute %q: error %v has no cursor, want %d", input, err, wantCursor)
+		return
+	}
+	if readerErr.Reader.Cursor != wantCursor {
+		t.Errorf("execute %q: error %v at cursor %d, want %d", input, err, readerErr.Reader.Cursor, wantCursor)
+	}
+}
+
+// AssertSuggestions asserts that the texts of the suggestions for
+// the input at the cursor equal want in order.
+// A negative cursor suggests at the end of the input.
+func (s Source) AssertSuggestions(t testing.TB, d *brigodier.Dispatcher, input string, cursor int, want ...string) {
+	t.Helper()
+	if cursor < 0 {
+		cursor = len(input)
+	}
+	suggestions, err := d.CompletionSuggestionsCursor(d.Parse(s.Context, input), cursor)
+	if err != nil {
+		t.Fatalf("suggest %q at %d: unexpected error: %v", input, cursor, err)
+	}
+	got := make([]string, 0, len(suggestions.Suggestions))
+	for _, suggestion := range suggestions.Suggestions {
+		got = append(got, suggestion.Text)
+	}
+	if len(want) == 0 {
+		want = []string{}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("suggest %q at %d:\n got: %q\nwant: %q", input, cursor, got, want)
+	}
+}
+
+// UpdateEnv is the environment variable that makes AssertSnapshot update
+// the golden files instead of comparing them if set to a non-empty value:
+//
+//	BRIGTEST_UPDATE=1 go test ./...
+const UpdateEnv = "BRIGTEST_UPDATE"
+
+// AssertSnapshot asserts that the brigodier.Dispatcher.Snapshot of the
+// whole command tree equals the golden file at path, e.g. "testdata/commands.golden".
+// The golden file is written if it does not exist or UpdateEnv is set.
+func AssertSnapshot(t testing.TB, d *brigodier.Dispatcher, path string) {
+	t.Helper()
+	_, err := os.Stat(path)
+	if os.Getenv(UpdateEnv) != "" || errors.Is(err, os.ErrNotExist) {
+		if err := d.WriteSnapshot(path, &d.Root); err != nil {
+			t.Fatalf("write snapshot: %v", err)
+		}
+		t.Logf("wrote snapshot %s", path)
+		return
+	}
+	if err := d.CompareSnapshot(path, &d.Root); err != nil {
+		t.Errorf("%v\nrun with %s=1 to update the golden file", err, UpdateEnv)
+	}
+}
@@ -0,0 +1,110 @@
+package brigtest
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"go.minekube.com/brigodier"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// recorder records the failures of assertions.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+func (r *recorder) Logf(string, ...interface{}) {}
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+	runtime.Goexit()
+}
+
+// run returns the failures of the assertion.
+func run(assert func(t testing.TB)) []string {
+	r := &recorder{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert(r)
+	}()
+	<-done
+	return r.failures
+}
+
+type suggestionProviderFunc func(*brigodier.CommandContext, *brigodier.SuggestionsBuilder) *brigodier.Suggestions
+
+func (f suggestionProviderFunc) Suggestions(c *brigodier.CommandContext, b *brigodier.SuggestionsBuilder) *brigodier.Suggestions {
+	return f(c, b)
+}
+
+func newDispatcher() *brigodier.Dispatcher {
+	nop := brigodier.CommandFunc(func(*brigodier.CommandContext) error { return nil })
+	d := brigodier.NewDispatcher()
+	tp := d.Register(brigodier.Literal("tp").
+		Then(brigodier.Argument("target", brigodier.StringWord).
+			Suggests(suggestionProviderFunc(func(_ *brigodier.CommandContext, b *brigodier.SuggestionsBuilder) *brigodier.Suggestions {
+				return b.Suggest("alice").Suggest("bob").Build()
+			})).
+			Then(brigodier.Argument("y", brigodier.Int).Executes(nop))))
+	d.Register(brigodier.Literal("teleport").Redirect(tp))
+	d.Register(brigodier.Literal("admin").
+		Requires(func(ctx context.Context) bool { return ctx.Value("op") != nil }).
+		Executes(nop))
+	return d
+}
+
+func TestAssertExecutes(t *testing.T) {
+	d := newDispatcher()
+	AssertExecutes(t, d, "tp alice 10", map[string]interface{}{"target": "alice", "y": int32(10)})
+	AssertExecutes(t, d, "teleport bob 1", map[string]interface{}{"target": "bob", "y": int32(1)})
+	AssertExecutes(t, d, "tp alice 10", nil)
+	Source{Context: context.WithValue(context.Background(), "op", true)}.AssertExecutes(t, d, "admin", nil)
+
+	require.Len(t, run(func(t testing.TB) { AssertExecutes(t, d, "tp alice 10", map[string]interface{}{"y": int32(10)}) }), 1)
+	require.Len(t, run(func(t testing.TB) { AssertExecutes(t, d, "admin", nil) }), 1)
+}
+
+func TestAssertSyntaxError(t *testing.T) {
+	d := newDispatcher()
+	AssertSyntaxError(t, d, "tp alice x", brigodier.ErrReaderExpectedInt, 9)
+	AssertSyntaxError(t, d, "unknown", brigodier.ErrDispatcherUnknownCommand, 0)
+	AssertSyntaxError(t, d, "tp alice x", nil, -1)
+
+	require.Len(t, run(func(t testing.TB) { AssertSyntaxError(t, d, "tp alice 1", nil, -1) }), 1)
+	require.Len(t, run(func(t testing.TB) { AssertSyntaxError(t, d, "tp alice x", brigodier.ErrReaderExpectedBool, 9) }), 1)
+	require.Len(t, run(func(t testing.TB) { AssertSyntaxError(t, d, "tp alice x", nil, 3) }), 1)
+}
+
+func TestAssertSuggestions(t *testing.T) {
+	d := newDispatcher()
+	AssertSuggestions(t, d, "tp ", -1, "alice", "bob")
+	AssertSuggestions(t, d, "t", -1, "tp", "teleport")
+	AssertSuggestions(t, d, "tp alice", 3, "alice", "bob")
+	AssertSuggestions(t, d, "tp alice 1 ", -1)
+
+	require.Len(t, run(func(t testing.TB) { AssertSuggestions(t, d, "tp ", -1, "bob") }), 1)
+}
+
+func TestAssertSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "commands.golden")
+	d := newDispatcher()
+	require.Empty(t, run(func(t testing.TB) { AssertSnapshot(t, d, path) })) // written
+	AssertSnapshot(t, d, path)
+
+	d.Register(brigodier.Literal("new"))
+	failures := run(func(t testing.TB) { AssertSnapshot(t, d, path) })
+	require.Len(t, failures, 1)
+	require.Contains(t, failures[0], "+new")
+
+	t.Setenv(UpdateEnv, "1")
+	AssertSnapshot(t, d, path)
+	t.Setenv(UpdateEnv, "")
+	AssertSnapshot(t, d, path)
+}
@@ -0,0 +1,227 @@
+package brigtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.minekube.com/brigodier"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// ArgumentCase is an input of TestArgumentType.
+type ArgumentCase struct {
+	Input string // The input starting at the argument.
+	// Want is the expected result of parsing Input.
+	// If nil, the result is not checked.
+	Want interface{}
+	// Rest is the input expected to remain after parsing,
+	// e.g. " 10" for the Input "alice 10" of a single word argument.
+	Rest string
+	// Fail is whether parsing Input is expected to fail.
+	Fail bool
+	// Err is the error the parse error is expected to wrap. It implies Fail.
+	Err error
+}
+
+// randomInputs is the number of random inputs parsed by TestArgumentType.
+const randomInputs = 500
+
+// argumentPrefix precedes the inputs parsed by TestArgumentType
+// to verify that argument types start reading at the cursor.
+const argumentPrefix = "cmd "
+
+// TestArgumentType verifies that the ArgumentType behaves like the
+// builtin types, so third-party types work with the Dispatcher:
+//
+//	func TestDurationArgument(t *testing.T) {
+//		brigtest.TestArgumentType(t, DurationArgument{},
+//			brigtest.ArgumentCase{Input: "5s", Want: 5 * time.Second},
+//			brigtest.ArgumentCase{Input: "5s next", Want: 5 * time.Second, Rest: " next"},
+//			brigtest.ArgumentCase{Input: "5x", Err: ErrInvalidDuration},
+//		)
+//	}
+//
+// Each case is parsed starting at a non-zero cursor and must return the
+// expected result and leave the expected rest of the input unread, or fail.
+//
+// Parsing the cases, their prefixes and random inputs derived from them
+// must further hold these properties:
+//   - Parse does not panic and returns the same outcome when repeated.
+//   - Parse moves the cursor within the input and not before its start.
+//   - Errors wrap a *brigodier.CommandSyntaxError, like the errors of the builtin types.
+//
+// If the type implements brigodier.SuggestionProvider, the suggestions for
+// all of these inputs must be within the input after the argument start and
+// within the range of the returned brigodier.Suggestions.
+func TestArgumentType(t testing.TB, at brigodier.ArgumentType, cases ...ArgumentCase) {
+	t.Helper()
+	for _, c := range cases {
+		testArgumentCase(t, at, c)
+	}
+	for _, input := range conformanceInputs(cases) {
+		testArgumentInput(t, at, input)
+		testArgumentSuggestions(t, at, input)
+	}
+}
+
+func testArgumentCase(t testing.TB, at brigodier.ArgumentType, c ArgumentCase) {
+	t.Helper()
+	p := parseArgument(at, c.Input)
+	if p.panicked != nil {
+		t.Errorf("%s: parse %q panicked: %v", at, c.Input, p.panicked)
+		return
+	}
+	if c.Fail || c.Err != nil {
+		if p.err == nil {
+			t.Errorf("%s: parse %q: expected error, got %T(%v)", at, c.Input, p.result, p.result)
+		} else if c.Err != nil && !errors.Is(p.err, c.Err) {
+			t.Errorf("%s: parse %q: error\n got: %v\nwant: %v", at, c.Input, p.err, c.Err)
+		}
+		return
+	}
+	if p.err != nil {
+		t.Errorf("%s: parse %q: unexpected error: %v", at, c.Input, p.err)
+		return
+	}
+	if c.Want != nil && !reflect.DeepEqual(p.result, c.Want) {
+		t.Errorf("%s: parse %q: result is %T(%v), want %T(%v)", at, c.Input, p.result, p.result, c.Want, c.Want)
+	}
+	if rest := p.rest(); rest != c.Rest {
+		t.Errorf("%s: parse %q: rest is %q, want %q", at, c.Input, rest, c.Rest)
+	}
+}
+
+func testArgumentInput(t testing.TB, at brigodier.ArgumentType, input string) {
+	t.Helper()
+	p := parseArgument(at, input)
+	if p.panicked != nil {
+		t.Errorf("%s: parse %q panicked: %v", at, input, p.panicked)
+		return
+	}
+	if p.cursor < len(argumentPrefix) || p.cursor > len(argumentPrefix)+len(input) {
+		t.Errorf("%s: parse %q: cursor %d outside of the argument input [%d, %d]",
+			at, input, p.cursor, len(argumentPrefix), len(argumentPrefix)+len(input))
+	}
+	var syntaxErr *brigodier.CommandSyntaxError
+	if p.err != nil && !errors.As(p.err, &syntaxErr) {
+		t.Errorf("%s: parse %q: error %T(%v) does not wrap *brigodier.CommandSyntaxError", at, input, p.err, p.err)
+	}
+	again := parseArgument(at, input)
+	if fmt.Sprint(again.result, again.err, again.cursor) != fmt.Sprint(p.result, p.err, p.cursor) {
+		t.Errorf("%s: parse %q is not deterministic: (%v, %v, %d) then (%v, %v, %d)",
+			at, input, p.result, p.err, p.cursor, again.result, again.err, again.cursor)
+	}
+}
+
+func testArgumentSuggestions(t testing.TB, at brigodier.ArgumentType, input string) {
+	t.Helper()
+	provider, ok := at.(brigodier.SuggestionProvider)
+	if !ok {
+		return
+	}
+	full := argumentPrefix + input
+	lower := strings.ToLower(full)
+	builder := &brigodier.SuggestionsBuilder{
+		Input:              full,
+		InputLowerCase:     lower,
+		Start:              len(argumentPrefix),
+		Remaining:          input,
+		RemainingLowerCase: lower[len(argumentPrefix):],
+	}
+	if q, ok := at.(brigodier.SuggestionQuoter); ok {
+		builder.Quote = q.QuoteSuggestion
+	}
+	var suggestions *brigodier.Suggestions
+	if r := catch(func() {
+		suggestions = provider.Suggestions(&brigodier.CommandContext{Context: context.Background(), Input: full}, builder)
+	}); r != nil {
+		t.Errorf("%s: suggest %q panicked: %v", at, input, r)
+		return
+	}
+	if suggestions == nil {
+		t.Errorf("%s: suggest %q: nil suggestions", at, input)
+		return
+	}
+	for _, s := range suggestions.Suggestions {
+		r := s.Range
+		if r.Start < builder.Start || r.End > len(full) || r.Start > r.End {
+			t.Errorf("%s: suggest %q: range [%d, %d] of %q outside of the argument input [%d, %d]",
+				at, input, r.Start, r.End, s.Text, builder.Start, len(full))
+		}
+		if r.Start < suggestions.Range.Start || r.End > suggestions.Range.End {
+			t.Errorf("%s: suggest %q: range [%d, %d] of %q outside of the suggestions range [%d, %d]",
+				at, input, r.Start, r.End, s.Text, suggestions.Range.Start, suggestions.Range.End)
+		}
+	}
+}
+
+// parsed is the outcome of parsing an argument.
+type parsed struct {
+	input    string
+	result   interface{}
+	err      error
+	cursor   int
+	panicked interface{}
+}
+
+// rest returns the unread input.
+func (p *parsed) rest() string {
+	full := argumentPrefix + p.input
+	if p.cursor < 0 || p.cursor > len(full) {
+		return ""
+	}
+	return full[p.cursor:]
+}
+
+// parseArgument parses the input preceded by argumentPrefix.
+func parseArgument(at brigodier.ArgumentType, input string) *parsed {
+	p := &parsed{input: input}
+	rd := &brigodier.StringReader{String: argumentPrefix + input, Cursor: len(argumentPrefix)}
+	p.panicked = catch(func() { p.result, p.err = at.Parse(rd) })
+	p.cursor = rd.Cursor
+	return p
+}
+
+// catch returns the value fn panicked with, if any.
+func catch(fn func()) (r interface{}) {
+	defer func() { r = recover() }()
+	fn()
+	return nil
+}
+
+// conformanceInputs returns the inputs and prefixes of the cases and random
+// inputs made of their runes and characters with special meaning in commands.
+func conformanceInputs(cases []ArgumentCase) []string {
+	seen := map[string]bool{}
+	var inputs []string
+	add := func(input string) {
+		if !seen[input] {
+			seen[input] = true
+			inputs = append(inputs, input)
+		}
+	}
+	alphabet := []rune(" \"'\\-+.0129aexZé")
+	for _, c := range cases {
+		runes := []rune(c.Input)
+		for i := 0; i <= len(runes); i++ {
+			add(string(runes[:i]))
+		}
+		alphabet = append(alphabet, runes...)
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < randomInputs; i++ {
+		var b strings.Builder
+		if len(cases) != 0 && rnd.Intn(2) == 0 {
+			// mutate a case input
+			b.WriteString(cases[rnd.Intn(len(cases))].Input)
+		}
+		for n := rnd.Intn(8); n > 0; n-- {
+			b.WriteRune(alphabet[rnd.Intn(len(alphabet))])
+		}
+		add(b.String())
+	}
+	return inputs
+}
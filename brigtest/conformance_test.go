@@ -0,0 +1,96 @@
+package brigtest
+
+import (
+	"go.minekube.com/brigodier"
+	"strings"
+	"testing"
+)
+
+func TestTestArgumentType_Builtin(t *testing.T) {
+	TestArgumentType(t, brigodier.Bool,
+		ArgumentCase{Input: "true", Want: true},
+		ArgumentCase{Input: "false rest", Want: false, Rest: " rest"},
+		ArgumentCase{Input: "yes", Fail: true},
+		ArgumentCase{Input: "", Err: brigodier.ErrReaderExpectedBool},
+	)
+	TestArgumentType(t, &brigodier.Int32ArgumentType{Min: 0, Max: 10},
+		ArgumentCase{Input: "5", Want: int32(5)},
+		ArgumentCase{Input: "0x0a 1", Want: int32(10), Rest: " 1"},
+		ArgumentCase{Input: "11", Err: brigodier.ErrArgumentIntegerTooHigh},
+		ArgumentCase{Input: "x", Err: brigodier.ErrReaderExpectedInt},
+	)
+	TestArgumentType(t, brigodier.String,
+		ArgumentCase{Input: `"a b" c`, Want: "a b", Rest: " c"},
+		ArgumentCase{Input: `"a`, Fail: true},
+	)
+	TestArgumentType(t, brigodier.StringPhrase,
+		ArgumentCase{Input: "a b c", Want: "a b c"},
+	)
+}
+
+func TestTestArgumentType_Violations(t *testing.T) {
+	wrongCursor := &brigodier.ArgumentTypeFuncs{
+		Name: "wrongCursor",
+		ParseFn: func(rd *brigodier.StringReader) (interface{}, error) {
+			rd.Cursor = 0 // ignores the start
+			return rd.String, nil
+		},
+	}
+	plainErr := &brigodier.ArgumentTypeFuncs{
+		Name: "plainErr",
+		ParseFn: func(rd *brigodier.StringReader) (interface{}, error) {
+			if !rd.CanRead() {
+				return nil, brigodier.ErrReaderExpectedInt // not wrapped
+			}
+			rd.Cursor = len(rd.String)
+			return nil, nil
+		},
+	}
+	panics := &brigodier.ArgumentTypeFuncs{
+		Name: "panics",
+		ParseFn: func(rd *brigodier.StringReader) (interface{}, error) {
+			rd.Cursor += 2
+			return rd.String[rd.Cursor-1], nil
+		},
+	}
+	badSuggestions := &brigodier.ArgumentTypeFuncs{
+		Name:    "badSuggestions",
+		ParseFn: brigodier.StringWord.Parse,
+		SuggestionsFn: func(_ *brigodier.CommandContext, b *brigodier.SuggestionsBuilder) *brigodier.Suggestions {
+			return &brigodier.Suggestions{Suggestions: []*brigodier.Suggestion{{Range: brigodier.StringRange{End: 1}, Text: "x"}}}
+		},
+	}
+	for _, test := range []struct {
+		at   brigodier.ArgumentType
+		want string
+	}{
+		{wrongCursor, "outside of the argument input"},
+		{plainErr, "does not wrap *brigodier.CommandSyntaxError"},
+		{panics, "panicked"},
+		{badSuggestions, "suggest"},
+	} {
+		failures := run(func(t testing.TB) { TestArgumentType(t, test.at, ArgumentCase{Input: "ab"}) })
+		if len(failures) == 0 {
+			t.Errorf("%s: expected failures", test.at)
+			continue
+		}
+		found := false
+		for _, f := range failures {
+			found = found || strings.Contains(f, test.want)
+		}
+		if !found {
+			t.Errorf("%s: no failure contains %q: %q", test.at, test.want, failures[0])
+		}
+	}
+
+	failures := run(func(t testing.TB) {
+		TestArgumentType(t, brigodier.StringWord,
+			ArgumentCase{Input: "a b", Want: "a", Rest: " c"},
+			ArgumentCase{Input: "a", Fail: true},
+			ArgumentCase{Input: "a", Want: "b"},
+		)
+	})
+	if len(failures) != 3 {
+		t.Errorf("expected 3 failures, got %q", failures)
+	}
+}
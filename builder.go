@@ -1,10 +1,84 @@
 package brigodier
 
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
 // Literal returns a new literal node builder.
 func Literal(literal string) *LiteralArgumentBuilder {
 	return &LiteralArgumentBuilder{Literal: literal}
 }
 
+// MappedLiteral returns a new literal node builder that, when matched,
+// stores value as parsed argument with the given argument name.
+//
+// Use it for keyword-like arguments so that commands can read the
+// argument instead of inspecting which literal branch was matched.
+func MappedLiteral(literal, argumentName string, value interface{}) *LiteralArgumentBuilder {
+	return &LiteralArgumentBuilder{Literal: literal, MappedArgument: argumentName, MappedValue: value}
+}
+
+// Choices returns a MappedLiteral builder for each choice, sorted by literal,
+// that all store their choice value as argument with the given name, e.g.
+//
+//	Literal("gamemode").Then(Choices("mode", map[string]GameMode{
+//		"survival": Survival,
+//		"creative": Creative,
+//	}).Executes(cmd).Builders()...)
+func Choices[T any](argumentName string, choices map[string]T) LiteralBuilders {
+	literals := make([]string, 0, len(choices))
+	for literal := range choices {
+		literals = append(literals, literal)
+	}
+	sort.Strings(literals)
+	builders := make(LiteralBuilders, len(literals))
+	for i, literal := range literals {
+		builders[i] = MappedLiteral(literal, argumentName, choices[literal])
+	}
+	return builders
+}
+
+// LiteralBuilders are sibling LiteralNodeBuilder that are built the same way.
+type LiteralBuilders []LiteralNodeBuilder
+
+// Builders returns the builders to be passed to a Then method.
+func (bs LiteralBuilders) Builders() []Builder {
+	builders := make([]Builder, len(bs))
+	for i, b := range bs {
+		builders[i] = b
+	}
+	return builders
+}
+
+// Then adds arguments to each resulting LiteralCommandNode.
+func (bs LiteralBuilders) Then(arguments ...Builder) LiteralBuilders {
+	for _, b := range bs {
+		b.Then(arguments...)
+	}
+	return bs
+}
+
+// Executes defines the Command of each resulting LiteralCommandNode.
+func (bs LiteralBuilders) Executes(command Command) LiteralBuilders {
+	for _, b := range bs {
+		b.Executes(command)
+	}
+	return bs
+}
+
+// Requires defines the RequireFn of each resulting LiteralCommandNode.
+func (bs LiteralBuilders) Requires(fn RequireFn) LiteralBuilders {
+	for _, b := range bs {
+		b.Requires(fn)
+	}
+	return bs
+}
+
 // Argument returns a new argument node builder.
 func Argument(name string, argType ArgumentType) *RequiredArgumentBuilder {
 	return &RequiredArgumentBuilder{Name: name, Type: argType}
@@ -16,10 +90,23 @@ type (
 	// NodeBuilder is a Builder with build-methods.
 	NodeBuilder interface {
 		Builder
+		Check() error
 		Then(arguments ...Builder) NodeBuilder
+		ThenNode(nodes ...CommandNode) NodeBuilder
+		ThenFunc(fn func(parent NodeBuilder)) NodeBuilder
 
 		Executes(command Command) NodeBuilder
+		ExecutesFunc(fn func(c *CommandContext) error) NodeBuilder
+		ExecutesResult(fn func(c *CommandContext) (int, error)) NodeBuilder
 		Requires(fn RequireFn) NodeBuilder
+		RateLimit(limiter RateLimiter) NodeBuilder
+		Timeout(timeout time.Duration) NodeBuilder
+		Cooldown(d time.Duration) NodeBuilder
+		Inject(argumentName string, fn InjectFn) NodeBuilder
+		Meta(key, value interface{}) NodeBuilder
+		Tags(tags ...string) NodeBuilder
+		Deprecated(message string) NodeBuilder
+		Usage(text string) NodeBuilder
 		Redirect(target CommandNode) NodeBuilder
 		RedirectWithModifier(target CommandNode, modifier RedirectModifier) NodeBuilder
 		Fork(target CommandNode, modifier RedirectModifier) NodeBuilder
@@ -30,10 +117,23 @@ type (
 		Builder
 		BuildLiteral() *LiteralCommandNode
 		NodeBuilder() NodeBuilder // Convert to NodeBuilder
+		Check() error
 		Then(arguments ...Builder) LiteralNodeBuilder
+		ThenNode(nodes ...CommandNode) LiteralNodeBuilder
+		ThenFunc(fn func(parent NodeBuilder)) LiteralNodeBuilder
 
 		Executes(command Command) LiteralNodeBuilder
+		ExecutesFunc(fn func(c *CommandContext) error) LiteralNodeBuilder
+		ExecutesResult(fn func(c *CommandContext) (int, error)) LiteralNodeBuilder
 		Requires(fn RequireFn) LiteralNodeBuilder
+		RateLimit(limiter RateLimiter) LiteralNodeBuilder
+		Timeout(timeout time.Duration) LiteralNodeBuilder
+		Cooldown(d time.Duration) LiteralNodeBuilder
+		Inject(argumentName string, fn InjectFn) LiteralNodeBuilder
+		Meta(key, value interface{}) LiteralNodeBuilder
+		Tags(tags ...string) LiteralNodeBuilder
+		Deprecated(message string) LiteralNodeBuilder
+		Usage(text string) LiteralNodeBuilder
 		Redirect(target CommandNode) LiteralNodeBuilder
 		RedirectWithModifier(target CommandNode, modifier RedirectModifier) LiteralNodeBuilder
 		Fork(target CommandNode, modifier RedirectModifier) LiteralNodeBuilder
@@ -44,11 +144,28 @@ type (
 		Builder
 		BuildArgument() *ArgumentCommandNode
 		NodeBuilder() NodeBuilder // Convert to NodeBuilder
+		Check() error
 		Then(arguments ...Builder) ArgumentNodeBuilder
+		ThenNode(nodes ...CommandNode) ArgumentNodeBuilder
+		ThenFunc(fn func(parent NodeBuilder)) ArgumentNodeBuilder
 
 		Suggests(provider SuggestionProvider) ArgumentNodeBuilder
+		Canonicalize(fn CanonicalizeFn) ArgumentNodeBuilder
+		ClientParser(id string, props TypeProperties) ArgumentNodeBuilder
+		Validate(fn func(v interface{}) error) ArgumentNodeBuilder
+		Map(fn TransformFn) ArgumentNodeBuilder
 		Executes(command Command) ArgumentNodeBuilder
+		ExecutesFunc(fn func(c *CommandContext) error) ArgumentNodeBuilder
+		ExecutesResult(fn func(c *CommandContext) (int, error)) ArgumentNodeBuilder
 		Requires(fn RequireFn) ArgumentNodeBuilder
+		RateLimit(limiter RateLimiter) ArgumentNodeBuilder
+		Timeout(timeout time.Duration) ArgumentNodeBuilder
+		Cooldown(d time.Duration) ArgumentNodeBuilder
+		Inject(argumentName string, fn InjectFn) ArgumentNodeBuilder
+		Meta(key, value interface{}) ArgumentNodeBuilder
+		Tags(tags ...string) ArgumentNodeBuilder
+		Deprecated(message string) ArgumentNodeBuilder
+		Usage(text string) ArgumentNodeBuilder
 		Redirect(target CommandNode) ArgumentNodeBuilder
 		RedirectWithModifier(target CommandNode, modifier RedirectModifier) ArgumentNodeBuilder
 		Fork(target CommandNode, modifier RedirectModifier) ArgumentNodeBuilder
@@ -57,7 +174,9 @@ type (
 
 	// LiteralArgumentBuilder builds a LiteralCommandNode.
 	LiteralArgumentBuilder struct {
-		Literal string
+		Literal        string
+		MappedArgument string      // Optional argument name to store MappedValue as.
+		MappedValue    interface{} // Optional argument value stored when the literal is matched.
 		ArgumentBuilder
 	}
 	// RequiredArgumentBuilder builds an ArgumentCommandNode.
@@ -65,6 +184,11 @@ type (
 		Name                string
 		Type                ArgumentType
 		SuggestionsProvider SuggestionProvider // Optional
+		Canonicalizer       CanonicalizeFn     // Optional
+		Transforms          []TransformFn      // Optional
+		// Optional parser identifier and properties sent to clients.
+		ClientParserID         string
+		ClientParserProperties TypeProperties
 		ArgumentBuilder
 	}
 )
@@ -75,12 +199,22 @@ var _ ArgumentNodeBuilder = (*RequiredArgumentBuilder)(nil)
 // ArgumentBuilder has the common builder fields and is wrapped by
 // LiteralArgumentBuilder and RequiredArgumentBuilder
 type ArgumentBuilder struct {
-	Arguments   RootCommandNode
-	Command     Command
-	Requirement RequireFn
-	Target      CommandNode
-	Modifier    RedirectModifier
-	Forks       bool
+	Arguments      RootCommandNode
+	Command        Command
+	Requirement    RequireFn
+	Target         CommandNode
+	Modifier       RedirectModifier
+	Forks          bool
+	RateLimiter    RateLimiter
+	ExecuteTimeout time.Duration
+	CooldownTime   time.Duration
+	Injections     []InjectedArgument
+	Metadata       Metadata
+	TagNames       []string
+	Deprecation    string
+	UsageText      string
+
+	err error // The first error found while building, see Check.
 }
 
 func (b *ArgumentBuilder) build() *Node {
@@ -90,6 +224,14 @@ func (b *ArgumentBuilder) build() *Node {
 		command:     b.Command,
 		modifier:    b.Modifier,
 		forks:       b.Forks,
+		rateLimiter: b.RateLimiter,
+		timeout:     b.ExecuteTimeout,
+		cooldown:    b.CooldownTime,
+		injections:  append([]InjectedArgument(nil), b.Injections...),
+		meta:        b.Metadata.Copy(),
+		tags:        append([]string(nil), b.TagNames...),
+		deprecation: b.Deprecation,
+		usage:       b.UsageText,
 	}
 	b.Arguments.ChildrenOrdered().Range(func(_ string, arg CommandNode) bool {
 		n.AddChild(arg)
@@ -100,6 +242,9 @@ func (b *ArgumentBuilder) build() *Node {
 
 func (b *ArgumentBuilder) then(arguments ...Builder) {
 	for _, a := range arguments {
+		if c, ok := a.(interface{ Check() error }); ok && b.err == nil {
+			b.err = c.Check()
+		}
 		b.Arguments.AddChild(a.Build())
 	}
 }
@@ -116,6 +261,47 @@ func (b *RequiredArgumentBuilder) Then(arguments ...Builder) ArgumentNodeBuilder
 	return b
 }
 
+// ThenNode adds already built nodes to the resulting LiteralCommandNode, see ArgumentBuilder.ThenNode.
+func (b *LiteralArgumentBuilder) ThenNode(nodes ...CommandNode) LiteralNodeBuilder {
+	b.ArgumentBuilder.ThenNode(nodes...)
+	return b
+}
+
+// ThenNode adds already built nodes to the resulting ArgumentCommandNode, see ArgumentBuilder.ThenNode.
+func (b *RequiredArgumentBuilder) ThenNode(nodes ...CommandNode) ArgumentNodeBuilder {
+	b.ArgumentBuilder.ThenNode(nodes...)
+	return b
+}
+
+// ThenNode adds already built nodes to the resulting CommandNode.
+// The nodes are shared, not copied, so the resulting node and any other parent
+// of the nodes see the same subtree. Add copies using CommandNode.Clone instead
+// to modify them independently. Nil nodes are ignored.
+func (b *ArgumentBuilder) ThenNode(nodes ...CommandNode) *ArgumentBuilder {
+	b.Arguments.AddChild(nodes...)
+	return b
+}
+
+// ThenFunc calls fn with the builder of the resulting LiteralCommandNode,
+// e.g. to add arguments in a loop without breaking the chain of builder calls:
+//
+//	Literal("gamemode").ThenFunc(func(parent NodeBuilder) {
+//		for _, mode := range modes {
+//			parent.Then(Literal(mode).Executes(setMode(mode)))
+//		}
+//	})
+func (b *LiteralArgumentBuilder) ThenFunc(fn func(parent NodeBuilder)) LiteralNodeBuilder {
+	fn(b.NodeBuilder())
+	return b
+}
+
+// ThenFunc calls fn with the builder of the resulting ArgumentCommandNode,
+// see LiteralArgumentBuilder.ThenFunc.
+func (b *RequiredArgumentBuilder) ThenFunc(fn func(parent NodeBuilder)) ArgumentNodeBuilder {
+	fn(b.NodeBuilder())
+	return b
+}
+
 func (b *RequiredArgumentBuilder) NodeBuilder() NodeBuilder { return &nodeBuilder{a: b} }
 func (b *LiteralArgumentBuilder) NodeBuilder() NodeBuilder  { return &nodeBuilder{l: b} }
 
@@ -124,8 +310,17 @@ func (n *LiteralCommandNode) CreateBuilder() NodeBuilder {
 	return &nodeBuilder{l: n.CreateLiteralBuilder()}
 }
 func (n *LiteralCommandNode) CreateLiteralBuilder() LiteralNodeBuilder {
-	return Literal(n.Literal).
+	b := MappedLiteral(n.Literal, n.mappedArgument, n.mappedValue)
+	b.Metadata = n.meta.Copy()
+	b.TagNames = append([]string(nil), n.tags...)
+	b.Injections = append([]InjectedArgument(nil), n.injections...)
+	return b.
 		Requires(n.Requirement()).
+		RateLimit(n.RateLimiter()).
+		Timeout(n.Timeout()).
+		Cooldown(n.Cooldown()).
+		Deprecated(n.Deprecated()).
+		Usage(n.usage).
 		Forward(n.Redirect(), n.RedirectModifier(), n.IsFork()).
 		Executes(n.Command())
 }
@@ -133,8 +328,10 @@ func (n *LiteralCommandNode) CreateLiteralBuilder() LiteralNodeBuilder {
 func (b *LiteralArgumentBuilder) Build() CommandNode { return b.BuildLiteral() }
 func (b *LiteralArgumentBuilder) BuildLiteral() *LiteralCommandNode {
 	return &LiteralCommandNode{
-		Node:    *b.ArgumentBuilder.build(),
-		Literal: b.Literal,
+		Node:           *b.ArgumentBuilder.build(),
+		Literal:        b.Literal,
+		mappedArgument: b.MappedArgument,
+		mappedValue:    b.MappedValue,
 	}
 }
 
@@ -143,10 +340,22 @@ func (a *ArgumentCommandNode) CreateBuilder() NodeBuilder {
 	return &nodeBuilder{a: a.CreateArgumentBuilder()}
 }
 func (a *ArgumentCommandNode) CreateArgumentBuilder() ArgumentNodeBuilder {
-	return Argument(a.Name(), a.Type()).
+	b := Argument(a.Name(), a.Type())
+	b.Transforms = append([]TransformFn(nil), a.transforms...)
+	b.Metadata = a.meta.Copy()
+	b.TagNames = append([]string(nil), a.tags...)
+	b.Injections = append([]InjectedArgument(nil), a.injections...)
+	return b.
 		Requires(a.Requirement()).
+		RateLimit(a.RateLimiter()).
+		Timeout(a.Timeout()).
+		Cooldown(a.Cooldown()).
+		Deprecated(a.Deprecated()).
+		Usage(a.usage).
 		Forward(a.Redirect(), a.RedirectModifier(), a.IsFork()).
 		Suggests(a.CustomSuggestions()).
+		Canonicalize(a.Canonicalizer()).
+		ClientParser(a.ClientParser()).
 		Executes(a.Command())
 }
 
@@ -157,6 +366,10 @@ func (b *RequiredArgumentBuilder) BuildArgument() *ArgumentCommandNode {
 		name:              b.Name,
 		argType:           b.Type,
 		customSuggestions: b.SuggestionsProvider,
+		canonicalize:      b.Canonicalizer,
+		transforms:        append([]TransformFn(nil), b.Transforms...),
+		clientParserID:    b.ClientParserID,
+		clientParserProps: b.ClientParserProperties,
 	}
 }
 
@@ -166,6 +379,38 @@ func (b *RequiredArgumentBuilder) Suggests(provider SuggestionProvider) Argument
 	return b
 }
 
+// Canonicalize defines the CanonicalizeFn of the resulting ArgumentCommandNode.
+func (b *RequiredArgumentBuilder) Canonicalize(fn CanonicalizeFn) ArgumentNodeBuilder {
+	b.Canonicalizer = fn
+	return b
+}
+
+// ClientParser defines the parser identifier and properties of the resulting ArgumentCommandNode
+// sent to clients instead of the ones derived from its ArgumentType, e.g. "minecraft:entity"
+// for an argument type parsing entity selectors. See TypeRegistry.ClientParser.
+func (b *RequiredArgumentBuilder) ClientParser(id string, props TypeProperties) ArgumentNodeBuilder {
+	b.ClientParserID = id
+	b.ClientParserProperties = props
+	return b
+}
+
+// Validate adds a validation to the resulting ArgumentCommandNode that is run right after
+// its ArgumentType parsed a value. A returned error fails parsing at the argument's position,
+// e.g. for semantic checks like whether a player is online.
+func (b *RequiredArgumentBuilder) Validate(fn func(v interface{}) error) ArgumentNodeBuilder {
+	return b.Map(func(v interface{}) (interface{}, error) {
+		return v, fn(v)
+	})
+}
+
+// Map adds a transformation to the resulting ArgumentCommandNode that is run right after
+// its ArgumentType parsed a value and replaces the value. A returned error fails parsing
+// at the argument's position. Validations and transformations run in the order they were added.
+func (b *RequiredArgumentBuilder) Map(fn TransformFn) ArgumentNodeBuilder {
+	b.Transforms = append(b.Transforms, fn)
+	return b
+}
+
 // Executes defines the Command of the resulting LiteralCommandNode.
 func (b *LiteralArgumentBuilder) Executes(command Command) LiteralNodeBuilder {
 	b.ArgumentBuilder.Executes(command)
@@ -184,6 +429,30 @@ func (b *ArgumentBuilder) Executes(command Command) *ArgumentBuilder {
 	return b
 }
 
+// ExecutesFunc defines the Command of the resulting LiteralCommandNode as a CommandFunc.
+func (b *LiteralArgumentBuilder) ExecutesFunc(fn func(c *CommandContext) error) LiteralNodeBuilder {
+	b.ArgumentBuilder.Executes(CommandFunc(fn))
+	return b
+}
+
+// ExecutesFunc defines the Command of the resulting ArgumentCommandNode as a CommandFunc.
+func (b *RequiredArgumentBuilder) ExecutesFunc(fn func(c *CommandContext) error) ArgumentNodeBuilder {
+	b.ArgumentBuilder.Executes(CommandFunc(fn))
+	return b
+}
+
+// ExecutesResult defines the Command of the resulting LiteralCommandNode as a ResultFunc.
+func (b *LiteralArgumentBuilder) ExecutesResult(fn func(c *CommandContext) (int, error)) LiteralNodeBuilder {
+	b.ArgumentBuilder.Executes(ResultFunc(fn))
+	return b
+}
+
+// ExecutesResult defines the Command of the resulting ArgumentCommandNode as a ResultFunc.
+func (b *RequiredArgumentBuilder) ExecutesResult(fn func(c *CommandContext) (int, error)) ArgumentNodeBuilder {
+	b.ArgumentBuilder.Executes(ResultFunc(fn))
+	return b
+}
+
 // Requires defines the RequireFn of the resulting LiteralCommandNode.
 func (b *LiteralArgumentBuilder) Requires(fn RequireFn) LiteralNodeBuilder {
 	b.ArgumentBuilder.Requires(fn)
@@ -202,6 +471,167 @@ func (b *ArgumentBuilder) Requires(fn RequireFn) *ArgumentBuilder {
 	return b
 }
 
+// RateLimit defines the RateLimiter of the resulting LiteralCommandNode.
+func (b *LiteralArgumentBuilder) RateLimit(limiter RateLimiter) LiteralNodeBuilder {
+	b.ArgumentBuilder.RateLimit(limiter)
+	return b
+}
+
+// RateLimit defines the RateLimiter of the resulting ArgumentCommandNode.
+func (b *RequiredArgumentBuilder) RateLimit(limiter RateLimiter) ArgumentNodeBuilder {
+	b.ArgumentBuilder.RateLimit(limiter)
+	return b
+}
+
+// RateLimit defines the RateLimiter of the resulting CommandNode.
+// The RateLimiter applies to all commands executed through the node.
+func (b *ArgumentBuilder) RateLimit(limiter RateLimiter) *ArgumentBuilder {
+	b.RateLimiter = limiter
+	return b
+}
+
+// Timeout defines the execution timeout of the resulting LiteralCommandNode.
+func (b *LiteralArgumentBuilder) Timeout(timeout time.Duration) LiteralNodeBuilder {
+	b.ArgumentBuilder.Timeout(timeout)
+	return b
+}
+
+// Timeout defines the execution timeout of the resulting ArgumentCommandNode.
+func (b *RequiredArgumentBuilder) Timeout(timeout time.Duration) ArgumentNodeBuilder {
+	b.ArgumentBuilder.Timeout(timeout)
+	return b
+}
+
+// Timeout defines the execution timeout of the resulting CommandNode.
+// It overrides Dispatcher.ExecuteTimeout for all commands executed through the node.
+func (b *ArgumentBuilder) Timeout(timeout time.Duration) *ArgumentBuilder {
+	b.ExecuteTimeout = timeout
+	return b
+}
+
+// Cooldown defines the cooldown of the resulting LiteralCommandNode.
+func (b *LiteralArgumentBuilder) Cooldown(d time.Duration) LiteralNodeBuilder {
+	b.ArgumentBuilder.Cooldown(d)
+	return b
+}
+
+// Cooldown defines the cooldown of the resulting ArgumentCommandNode.
+func (b *RequiredArgumentBuilder) Cooldown(d time.Duration) ArgumentNodeBuilder {
+	b.ArgumentBuilder.Cooldown(d)
+	return b
+}
+
+// Cooldown defines the cooldown of the resulting CommandNode.
+// Commands executed through the node are rejected with a *CooldownError
+// until d has passed since the last execution through the node by the
+// same source, as tracked by Dispatcher.Cooldowns.
+func (b *ArgumentBuilder) Cooldown(d time.Duration) *ArgumentBuilder {
+	b.CooldownTime = d
+	return b
+}
+
+// Inject declares an implicit argument of the resulting LiteralCommandNode.
+func (b *LiteralArgumentBuilder) Inject(argumentName string, fn InjectFn) LiteralNodeBuilder {
+	b.ArgumentBuilder.Inject(argumentName, fn)
+	return b
+}
+
+// Inject declares an implicit argument of the resulting ArgumentCommandNode.
+func (b *RequiredArgumentBuilder) Inject(argumentName string, fn InjectFn) ArgumentNodeBuilder {
+	b.ArgumentBuilder.Inject(argumentName, fn)
+	return b
+}
+
+// Inject declares an implicit argument of the resulting CommandNode resolved by fn
+// from the execution environment rather than the input, see InjectFn.
+func (b *ArgumentBuilder) Inject(argumentName string, fn InjectFn) *ArgumentBuilder {
+	b.Injections = append(b.Injections, InjectedArgument{Name: argumentName, Resolve: fn})
+	return b
+}
+
+// Meta sets a metadata value of the resulting LiteralCommandNode.
+func (b *LiteralArgumentBuilder) Meta(key, value interface{}) LiteralNodeBuilder {
+	b.ArgumentBuilder.Meta(key, value)
+	return b
+}
+
+// Meta sets a metadata value of the resulting ArgumentCommandNode.
+func (b *RequiredArgumentBuilder) Meta(key, value interface{}) ArgumentNodeBuilder {
+	b.ArgumentBuilder.Meta(key, value)
+	return b
+}
+
+// Meta sets a metadata value of the resulting CommandNode, see CommandNode.Meta.
+func (b *ArgumentBuilder) Meta(key, value interface{}) *ArgumentBuilder {
+	b.Metadata.Set(key, value)
+	return b
+}
+
+// Tags adds tags to the resulting LiteralCommandNode.
+func (b *LiteralArgumentBuilder) Tags(tags ...string) LiteralNodeBuilder {
+	b.ArgumentBuilder.Tags(tags...)
+	return b
+}
+
+// Tags adds tags to the resulting ArgumentCommandNode.
+func (b *RequiredArgumentBuilder) Tags(tags ...string) ArgumentNodeBuilder {
+	b.ArgumentBuilder.Tags(tags...)
+	return b
+}
+
+// Tags adds tags to the resulting CommandNode, see Dispatcher.NodesByTag.
+// Duplicate tags are ignored.
+func (b *ArgumentBuilder) Tags(tags ...string) *ArgumentBuilder {
+	for _, tag := range tags {
+		if !containsString(b.TagNames, tag) {
+			b.TagNames = append(b.TagNames, tag)
+		}
+	}
+	return b
+}
+
+// Deprecated marks the resulting LiteralCommandNode as deprecated.
+func (b *LiteralArgumentBuilder) Deprecated(message string) LiteralNodeBuilder {
+	b.ArgumentBuilder.Deprecated(message)
+	return b
+}
+
+// Deprecated marks the resulting ArgumentCommandNode as deprecated.
+func (b *RequiredArgumentBuilder) Deprecated(message string) ArgumentNodeBuilder {
+	b.ArgumentBuilder.Deprecated(message)
+	return b
+}
+
+// Deprecated marks the resulting CommandNode as deprecated with a message
+// telling users what to use instead, e.g. "use /newcmd instead".
+// An empty message removes the mark.
+//
+// Executing a deprecated command notifies the hooks registered using Dispatcher.OnDeprecated.
+func (b *ArgumentBuilder) Deprecated(message string) *ArgumentBuilder {
+	b.Deprecation = message
+	return b
+}
+
+// Usage overrides the usage text of the resulting LiteralCommandNode.
+func (b *LiteralArgumentBuilder) Usage(text string) LiteralNodeBuilder {
+	b.ArgumentBuilder.Usage(text)
+	return b
+}
+
+// Usage overrides the usage text of the resulting ArgumentCommandNode.
+func (b *RequiredArgumentBuilder) Usage(text string) ArgumentNodeBuilder {
+	b.ArgumentBuilder.Usage(text)
+	return b
+}
+
+// Usage overrides the CommandNode.UsageText of the resulting CommandNode
+// used by SmartUsage and AllUsage, e.g. "<x:int[1..64]>".
+// An empty text restores the default usage text.
+func (b *ArgumentBuilder) Usage(text string) *ArgumentBuilder {
+	b.UsageText = text
+	return b
+}
+
 // Redirect defines the redirect node of the resulting LiteralCommandNode.
 func (b *LiteralArgumentBuilder) Redirect(target CommandNode) LiteralNodeBuilder {
 	b.ArgumentBuilder.Redirect(target)
@@ -267,6 +697,9 @@ func (b *RequiredArgumentBuilder) Forward(target CommandNode, modifier RedirectM
 
 func (b *ArgumentBuilder) Forward(target CommandNode, modifier RedirectModifier, fork bool) *ArgumentBuilder {
 	if len(b.Arguments.children) != 0 {
+		if b.err == nil && target != nil {
+			b.err = &BuilderError{Err: ErrBuilderRedirectWithChildren}
+		}
 		return b // cannot forward a node with children
 	}
 	b.Target = target
@@ -275,14 +708,98 @@ func (b *ArgumentBuilder) Forward(target CommandNode, modifier RedirectModifier,
 	return b
 }
 
+// Builder validation errors, see LiteralArgumentBuilder.Check and RequiredArgumentBuilder.Check.
+var (
+	// ErrBuilderEmptyLiteral indicates a literal without name that can never be parsed.
+	ErrBuilderEmptyLiteral = errors.New("empty literal")
+	// ErrBuilderLiteralWhitespace indicates a literal containing whitespace
+	// that can never be parsed since arguments are separated by ArgumentSeparator.
+	ErrBuilderLiteralWhitespace = errors.New("literal contains whitespace")
+	// ErrBuilderNilArgumentType indicates an argument without ArgumentType.
+	ErrBuilderNilArgumentType = errors.New("nil argument type")
+	// ErrBuilderRedirectWithChildren indicates a node that has both
+	// children and a redirect target, in which case the children are never parsed.
+	ErrBuilderRedirectWithChildren = errors.New("redirect on node with children")
+)
+
+// BuilderError is returned by Check for an invalid builder.
+type BuilderError struct {
+	Path []string // The path of the invalid node starting at the checked builder.
+	Err  error
+}
+
+// Unwrap implements errors.Unwrap.
+func (e *BuilderError) Unwrap() error { return e.Err }
+func (e *BuilderError) Error() string {
+	return fmt.Sprintf("invalid builder %q: %v", strings.Join(e.Path, " "), e.Err)
+}
+
+// check returns the first error of the builder named name
+// or its children prepending name to the BuilderError.Path.
+func (b *ArgumentBuilder) check(name string, err error) error {
+	if err == nil && b.err == nil && b.Target != nil && len(b.Arguments.children) != 0 {
+		err = ErrBuilderRedirectWithChildren
+	}
+	if err == nil {
+		err = b.err
+	}
+	if err == nil {
+		return nil
+	}
+	var builderErr *BuilderError
+	if !errors.As(err, &builderErr) {
+		return &BuilderError{Path: []string{name}, Err: err}
+	}
+	return &BuilderError{Path: append([]string{name}, builderErr.Path...), Err: builderErr.Err}
+}
+
+// Check returns a *BuilderError if the builder or one of its children added
+// with Then is invalid, e.g. if the literal is empty or contains whitespace
+// or if the node has both children and a redirect.
+// Build does not fail for invalid builders, use Check or MustBuild to catch them early.
+func (b *LiteralArgumentBuilder) Check() error {
+	var err error
+	if b.Literal == "" {
+		err = ErrBuilderEmptyLiteral
+	} else if strings.IndexFunc(b.Literal, unicode.IsSpace) != -1 {
+		err = ErrBuilderLiteralWhitespace
+	}
+	return b.ArgumentBuilder.check(b.Literal, err)
+}
+
+// Check returns a *BuilderError if the builder or one of its children added
+// with Then is invalid, e.g. if the ArgumentType is nil
+// or if the node has both children and a redirect.
+// Build does not fail for invalid builders, use Check or MustBuild to catch them early.
+func (b *RequiredArgumentBuilder) Check() error {
+	var err error
+	if b.Type == nil {
+		err = ErrBuilderNilArgumentType
+	}
+	return b.ArgumentBuilder.check(b.Name, err)
+}
+
+// MustBuild builds the builder but panics if its Check method returns an error, e.g.
+//
+//	d.Root.AddChild(MustBuild(Literal("foo").Then(Argument("bar", Int))))
+func MustBuild(b Builder) CommandNode {
+	if c, ok := b.(interface{ Check() error }); ok {
+		if err := c.Check(); err != nil {
+			panic(err)
+		}
+	}
+	return b.Build()
+}
+
 // CreateBuilder cannot create a builder from root node and returns a nop-builder!
 func (r *RootCommandNode) CreateBuilder() NodeBuilder {
 	return nil
 }
 
 // nodeBuilder implements NodeBuilder and wraps either one of:
-//  LiteralNodeBuilder
-//  ArgumentNodeBuilder
+//
+//	LiteralNodeBuilder
+//	ArgumentNodeBuilder
 type nodeBuilder struct {
 	l LiteralNodeBuilder
 	a ArgumentNodeBuilder
@@ -297,6 +814,13 @@ func (b *nodeBuilder) Build() CommandNode {
 	return b.l.Build()
 }
 
+func (b *nodeBuilder) Check() error {
+	if b.l == nil {
+		return b.a.Check()
+	}
+	return b.l.Check()
+}
+
 func (b *nodeBuilder) Then(arguments ...Builder) NodeBuilder {
 	if b.l == nil {
 		b.a.Then(arguments...)
@@ -306,6 +830,20 @@ func (b *nodeBuilder) Then(arguments ...Builder) NodeBuilder {
 	return b
 }
 
+func (b *nodeBuilder) ThenNode(nodes ...CommandNode) NodeBuilder {
+	if b.l == nil {
+		b.a.ThenNode(nodes...)
+	} else {
+		b.l.ThenNode(nodes...)
+	}
+	return b
+}
+
+func (b *nodeBuilder) ThenFunc(fn func(parent NodeBuilder)) NodeBuilder {
+	fn(b)
+	return b
+}
+
 func (b *nodeBuilder) Executes(command Command) NodeBuilder {
 	if b.l == nil {
 		b.a.Executes(command)
@@ -315,6 +853,14 @@ func (b *nodeBuilder) Executes(command Command) NodeBuilder {
 	return b
 }
 
+func (b *nodeBuilder) ExecutesFunc(fn func(c *CommandContext) error) NodeBuilder {
+	return b.Executes(CommandFunc(fn))
+}
+
+func (b *nodeBuilder) ExecutesResult(fn func(c *CommandContext) (int, error)) NodeBuilder {
+	return b.Executes(ResultFunc(fn))
+}
+
 func (b *nodeBuilder) Requires(fn RequireFn) NodeBuilder {
 	if b.l == nil {
 		b.a.Requires(fn)
@@ -324,6 +870,78 @@ func (b *nodeBuilder) Requires(fn RequireFn) NodeBuilder {
 	return b
 }
 
+func (b *nodeBuilder) RateLimit(limiter RateLimiter) NodeBuilder {
+	if b.l == nil {
+		b.a.RateLimit(limiter)
+	} else {
+		b.l.RateLimit(limiter)
+	}
+	return b
+}
+
+func (b *nodeBuilder) Timeout(timeout time.Duration) NodeBuilder {
+	if b.l == nil {
+		b.a.Timeout(timeout)
+	} else {
+		b.l.Timeout(timeout)
+	}
+	return b
+}
+
+func (b *nodeBuilder) Cooldown(d time.Duration) NodeBuilder {
+	if b.l == nil {
+		b.a.Cooldown(d)
+	} else {
+		b.l.Cooldown(d)
+	}
+	return b
+}
+
+func (b *nodeBuilder) Inject(argumentName string, fn InjectFn) NodeBuilder {
+	if b.l == nil {
+		b.a.Inject(argumentName, fn)
+	} else {
+		b.l.Inject(argumentName, fn)
+	}
+	return b
+}
+
+func (b *nodeBuilder) Meta(key, value interface{}) NodeBuilder {
+	if b.l == nil {
+		b.a.Meta(key, value)
+	} else {
+		b.l.Meta(key, value)
+	}
+	return b
+}
+
+func (b *nodeBuilder) Tags(tags ...string) NodeBuilder {
+	if b.l == nil {
+		b.a.Tags(tags...)
+	} else {
+		b.l.Tags(tags...)
+	}
+	return b
+}
+
+func (b *nodeBuilder) Deprecated(message string) NodeBuilder {
+	if b.l == nil {
+		b.a.Deprecated(message)
+	} else {
+		b.l.Deprecated(message)
+	}
+	return b
+}
+
+func (b *nodeBuilder) Usage(text string) NodeBuilder {
+	if b.l == nil {
+		b.a.Usage(text)
+	} else {
+		b.l.Usage(text)
+	}
+	return b
+}
+
 func (b *nodeBuilder) Redirect(target CommandNode) NodeBuilder {
 	if b.l == nil {
 		b.a.Redirect(target)
@@ -365,9 +983,22 @@ type nopNodeBuilder struct{}
 var _ NodeBuilder = (*nopNodeBuilder)(nil)
 
 func (b *nopNodeBuilder) Build() CommandNode                                             { return nil }
+func (b *nopNodeBuilder) Check() error                                                   { return nil }
 func (b *nopNodeBuilder) Then(...Builder) NodeBuilder                                    { return b }
+func (b *nopNodeBuilder) ThenNode(...CommandNode) NodeBuilder                            { return b }
+func (b *nopNodeBuilder) ThenFunc(func(NodeBuilder)) NodeBuilder                         { return b }
 func (b *nopNodeBuilder) Executes(Command) NodeBuilder                                   { return b }
+func (b *nopNodeBuilder) ExecutesFunc(func(*CommandContext) error) NodeBuilder           { return b }
+func (b *nopNodeBuilder) ExecutesResult(func(*CommandContext) (int, error)) NodeBuilder  { return b }
 func (b *nopNodeBuilder) Requires(RequireFn) NodeBuilder                                 { return b }
+func (b *nopNodeBuilder) RateLimit(RateLimiter) NodeBuilder                              { return b }
+func (b *nopNodeBuilder) Timeout(time.Duration) NodeBuilder                              { return b }
+func (b *nopNodeBuilder) Cooldown(time.Duration) NodeBuilder                             { return b }
+func (b *nopNodeBuilder) Inject(string, InjectFn) NodeBuilder                            { return b }
+func (b *nopNodeBuilder) Meta(interface{}, interface{}) NodeBuilder                      { return b }
+func (b *nopNodeBuilder) Tags(...string) NodeBuilder                                     { return b }
+func (b *nopNodeBuilder) Deprecated(string) NodeBuilder                                  { return b }
+func (b *nopNodeBuilder) Usage(string) NodeBuilder                                       { return b }
 func (b *nopNodeBuilder) Redirect(CommandNode) NodeBuilder                               { return b }
 func (b *nopNodeBuilder) RedirectWithModifier(CommandNode, RedirectModifier) NodeBuilder { return b }
 func (b *nopNodeBuilder) Fork(CommandNode, RedirectModifier) NodeBuilder                 { return b }
@@ -1,6 +1,7 @@
 package brigodier
 
 import (
+	"context"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
@@ -11,3 +12,95 @@ func Test_CreateBuilder_Executes(t *testing.T) {
 	build := node.CreateBuilder().Build()
 	require.NotNil(t, build.Command())
 }
+
+func Test_CreateBuilder_Canonicalize(t *testing.T) {
+	fn := func(v interface{}) interface{} { return v }
+	node := Argument("test", Int).Canonicalize(fn).BuildArgument()
+	build := node.CreateArgumentBuilder().BuildArgument()
+	require.NotNil(t, build.Canonicalizer())
+}
+
+func TestChoices(t *testing.T) {
+	type mode int
+	var (
+		d   Dispatcher
+		got interface{}
+	)
+	cmd := CommandFunc(func(c *CommandContext) error {
+		got = c.Arguments["mode"].Result
+		return nil
+	})
+	d.Register(Literal("gamemode").Then(
+		Choices("mode", map[string]mode{"survival": 0, "creative": 1}).Executes(cmd).Builders()...,
+	))
+
+	require.NoError(t, d.Do(context.TODO(), "gamemode creative"))
+	require.Equal(t, mode(1), got)
+	require.Equal(t, []string{"gamemode creative", "gamemode survival"}, d.AllUsage(context.TODO(), &d.Root, false))
+}
+
+func Test_CreateBuilder_MappedLiteral(t *testing.T) {
+	node := MappedLiteral("on", "state", true).BuildLiteral()
+	name, value := node.CreateLiteralBuilder().BuildLiteral().Mapped()
+	require.Equal(t, "state", name)
+	require.Equal(t, true, value)
+}
+
+func Test_CreateBuilder_RateLimit(t *testing.T) {
+	limiter := NewTokenBucket(0, 1, nil)
+	node := Literal("test").RateLimit(limiter).Build()
+	require.Equal(t, limiter, node.CreateBuilder().Build().RateLimiter())
+}
+
+func TestBuilder_Check(t *testing.T) {
+	require.NoError(t, Literal("foo").Then(Argument("bar", Int)).Check())
+
+	var builderErr *BuilderError
+	err := Literal("").Check()
+	require.ErrorIs(t, err, ErrBuilderEmptyLiteral)
+	require.ErrorAs(t, err, &builderErr)
+	require.Equal(t, []string{""}, builderErr.Path)
+
+	require.ErrorIs(t, Literal("foo bar").Check(), ErrBuilderLiteralWhitespace)
+	require.ErrorIs(t, Argument("bar", nil).Check(), ErrBuilderNilArgumentType)
+
+	err = Literal("foo").Then(Literal("bar").Then(Argument("baz", nil))).Check()
+	require.ErrorIs(t, err, ErrBuilderNilArgumentType)
+	require.ErrorAs(t, err, &builderErr)
+	require.Equal(t, []string{"foo", "bar", "baz"}, builderErr.Path)
+	require.EqualError(t, err, `invalid builder "foo bar baz": nil argument type`)
+
+	var d Dispatcher
+	require.ErrorIs(t, Literal("foo").Then(Literal("bar")).Redirect(&d.Root).Check(), ErrBuilderRedirectWithChildren)
+	require.ErrorIs(t, Literal("foo").Redirect(&d.Root).Then(Literal("bar")).Check(), ErrBuilderRedirectWithChildren)
+	require.ErrorIs(t, Literal("foo").Then(Literal("bar").NodeBuilder().Then(Literal(""))).Check(), ErrBuilderEmptyLiteral)
+
+	require.Panics(t, func() { MustBuild(Literal("foo").Then(Literal(""))) })
+	require.NotPanics(t, func() { MustBuild(Argument("bar", Int)) })
+}
+
+func TestBuilder_ThenNode(t *testing.T) {
+	shared := Argument("player", StringWord).Build()
+	kick := Literal("kick").ThenNode(shared, nil).Build()
+	ban := Literal("ban").NodeBuilder().ThenNode(shared).Build()
+	require.Same(t, shared, kick.Children()["player"])
+	require.Same(t, shared, ban.Children()["player"])
+
+	arg := Argument("x", Int).ThenNode(Literal("y").Build()).Build()
+	require.NotNil(t, arg.Children()["y"])
+}
+
+func TestBuilder_ThenFunc(t *testing.T) {
+	modes := []string{"survival", "creative"}
+	node := Literal("gamemode").ThenFunc(func(parent NodeBuilder) {
+		for _, mode := range modes {
+			parent.Then(Literal(mode))
+		}
+	}).Build()
+	require.Equal(t, []string{"survival", "creative"}, node.ChildrenOrdered().Keys())
+
+	node = Argument("x", Int).NodeBuilder().ThenFunc(func(parent NodeBuilder) {
+		parent.Then(Literal("y"))
+	}).Build()
+	require.NotNil(t, node.Children()["y"])
+}
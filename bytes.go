@@ -0,0 +1,136 @@
+package brigodier
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Builtin BytesArgumentType values.
+var (
+	// Base64Argument parses base64 encoded bytes of up to DefaultMaxBytes.
+	Base64Argument ArgumentType = &BytesArgumentType{Encoding: BytesBase64}
+	// HexArgument parses hex encoded bytes of up to DefaultMaxBytes.
+	HexArgument ArgumentType = &BytesArgumentType{Encoding: BytesHex}
+)
+
+// DefaultMaxBytes is the maximum number of decoded bytes
+// of a BytesArgumentType without MaxSize.
+const DefaultMaxBytes = 16 << 10
+
+var (
+	// ErrArgumentInvalidBytes occurs when the read value is not validly encoded.
+	ErrArgumentInvalidBytes = errors.New("invalid bytes")
+	// ErrArgumentBytesTooLarge occurs when the decoded bytes exceed the specified maximum size.
+	ErrArgumentBytesTooLarge = errors.New("bytes too large")
+)
+
+// BytesEncoding is the text encoding of a BytesArgumentType.
+type BytesEncoding uint8
+
+// Supported BytesEncoding values.
+const (
+	// BytesBase64 is the standard or URL-safe base64 encoding with optional padding.
+	BytesBase64 BytesEncoding = iota
+	// BytesHex is the hexadecimal encoding.
+	BytesHex
+)
+
+func (e BytesEncoding) String() string {
+	switch e {
+	case BytesBase64:
+		return "base64"
+	case BytesHex:
+		return "hex"
+	}
+	return fmt.Sprintf("BytesEncoding(%d)", uint8(e))
+}
+
+// BytesArgumentType parses an encoded blob up to the next space into a []byte,
+// e.g. for commands transferring small payloads like skins or signatures.
+type BytesArgumentType struct {
+	Encoding BytesEncoding
+	// MaxSize is the maximum number of decoded bytes, DefaultMaxBytes if zero.
+	// The size is checked before the blob is decoded.
+	MaxSize int
+}
+
+func (t *BytesArgumentType) String() string { return t.Encoding.String() }
+func (t *BytesArgumentType) Parse(rd *StringReader) (interface{}, error) {
+	start := rd.Cursor
+	value := rd.ReadWhile(func(c rune) bool { return c != ' ' })
+	if value == "" {
+		return nil, &CommandSyntaxError{Err: &ReaderError{
+			Err:    fmt.Errorf("%w: expected %s", ErrArgumentInvalidBytes, t.Encoding),
+			Reader: rd,
+		}}
+	}
+	max := t.MaxSize
+	if max <= 0 {
+		max = DefaultMaxBytes
+	}
+	var (
+		size int
+		enc  *base64.Encoding
+		b    []byte
+		err  error
+	)
+	switch t.Encoding {
+	case BytesHex:
+		size = len(value) / 2
+	default:
+		enc = base64.RawStdEncoding
+		if strings.ContainsAny(value, "-_") {
+			enc = base64.RawURLEncoding
+		}
+		padded := len(value)
+		value = strings.TrimRight(value, "=")
+		if padding := padded - len(value); padding != 0 && (padding > 2 || padded%4 != 0) {
+			err = errors.New("invalid padding")
+		}
+		size = enc.DecodedLen(len(value))
+	}
+	if err == nil && size > max {
+		rd.Cursor = start
+		return nil, &CommandSyntaxError{Err: &ReaderError{
+			Err:    fmt.Errorf("%w (%d > %d)", ErrArgumentBytesTooLarge, size, max),
+			Reader: rd,
+		}}
+	}
+	if err == nil {
+		if enc != nil {
+			b, err = enc.DecodeString(value)
+		} else {
+			b, err = hex.DecodeString(value)
+		}
+	}
+	if err != nil {
+		invalid := rd.String[start:rd.Cursor]
+		rd.Cursor = start
+		return nil, &CommandSyntaxError{Err: &ReaderError{
+			Err: &ReaderInvalidValueError{
+				Type:  t,
+				Value: invalid,
+				Err:   fmt.Errorf("%w %s: %v", ErrArgumentInvalidBytes, t.Encoding, err),
+			},
+			Reader: rd,
+		}}
+	}
+	return b, nil
+}
+
+// Bytes returns the parsed []byte argument from the command context.
+// It returns nil if not found.
+func (c *CommandContext) Bytes(argumentName string) []byte {
+	if c.Arguments == nil {
+		return nil
+	}
+	r, ok := c.Arguments[argumentName]
+	if !ok {
+		return nil
+	}
+	v, _ := r.Result.([]byte)
+	return v
+}
@@ -0,0 +1,65 @@
+package brigodier
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestBytesArgumentType(t *testing.T) {
+	for input, want := range map[string]string{
+		"aGk/":  "hi?",
+		"aGk_":  "hi?", // URL-safe
+		"aGk=":  "hi",
+		"aGk":   "hi",
+		"/w== ": "\xff",
+	} {
+		b, err := Base64Argument.Parse(&StringReader{String: input})
+		require.NoError(t, err, input)
+		require.Equal(t, []byte(want), b, input)
+	}
+
+	r := &StringReader{String: "cafe 1"}
+	b, err := HexArgument.Parse(r)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0xca, 0xfe}, b)
+	require.Equal(t, " 1", r.Remaining())
+
+	r = &StringReader{String: "set caf"}
+	r.Cursor = 4
+	_, err = HexArgument.Parse(r)
+	require.ErrorIs(t, err, ErrArgumentInvalidBytes)
+	require.Equal(t, 4, r.Cursor)
+
+	_, err = Base64Argument.Parse(&StringReader{String: "a*b="})
+	require.ErrorIs(t, err, ErrArgumentInvalidBytes)
+
+	_, err = HexArgument.Parse(&StringReader{String: " "})
+	require.ErrorIs(t, err, ErrArgumentInvalidBytes)
+
+	for _, input := range []string{"AA=====", "AA=", "aGk==", "AAAA====", "="} {
+		r = &StringReader{String: input}
+		_, err = Base64Argument.Parse(r)
+		require.ErrorIs(t, err, ErrArgumentInvalidBytes, input)
+		require.Equal(t, 0, r.Cursor, input)
+	}
+
+	small := &BytesArgumentType{Encoding: BytesHex, MaxSize: 2}
+	r = &StringReader{String: "set 010203"}
+	r.Cursor = 4
+	_, err = small.Parse(r)
+	require.ErrorIs(t, err, ErrArgumentBytesTooLarge)
+	require.Equal(t, "bytes too large (3 > 2)", err.Error())
+	var readerErr *ReaderError
+	require.ErrorAs(t, err, &readerErr)
+	require.Equal(t, 4, r.Cursor)
+
+	d := NewDispatcher()
+	var got []byte
+	d.Register(Literal("skin").Then(Argument("data", Base64Argument).Executes(CommandFunc(func(c *CommandContext) error {
+		got = c.Bytes("data")
+		return nil
+	}))))
+	require.NoError(t, d.Do(context.TODO(), "skin AQID"))
+	require.Equal(t, []byte{1, 2, 3}, got)
+}
@@ -0,0 +1,198 @@
+// Package cli runs a brigodier Dispatcher as command line interface,
+// so a single command tree can be used both in game and from a terminal.
+//
+// The package is independent of a specific CLI framework. NewCommand converts
+// a subtree into a Command hierarchy whose fields map 1:1 to a cobra.Command:
+//
+//	func toCobra(c *cli.Command) *cobra.Command {
+//		cmd := &cobra.Command{
+//			Use:                c.Use,
+//			DisableFlagParsing: true, // brigodier parses all arguments
+//		}
+//		if c.Run != nil {
+//			cmd.RunE = func(cmd *cobra.Command, args []string) error {
+//				return c.Run(cmd.Context(), args)
+//			}
+//		}
+//		cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+//			return c.Complete(cmd.Context(), args, toComplete), cobra.ShellCompDirectiveNoFileComp
+//		}
+//		for _, sub := range c.Commands {
+//			cmd.AddCommand(toCobra(sub))
+//		}
+//		return cmd
+//	}
+//
+// Alternatively, a single command can delegate all arguments to Run and Complete:
+//
+//	root := &cobra.Command{
+//		Use:                "mycli",
+//		DisableFlagParsing: true, // brigodier parses all arguments
+//		RunE: func(cmd *cobra.Command, args []string) error {
+//			return cli.Run(cmd.Context(), d, args)
+//		},
+//		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+//			return cli.Complete(cmd.Context(), d, args, toComplete), cobra.ShellCompDirectiveNoFileComp
+//		},
+//	}
+package cli
+
+import (
+	"context"
+	"go.minekube.com/brigodier"
+	"strings"
+)
+
+// Command is a command of a command line hierarchy converted from
+// a brigodier subtree by NewCommand.
+type Command struct {
+	// Use is the one-line usage of the command, the literal
+	// followed by the usage of its arguments, e.g. "tp <target>".
+	Use string
+	// Run executes the command with its positional arguments.
+	// It is nil if neither the literal nor any of its
+	// arguments are executable without a subcommand.
+	Run func(ctx context.Context, args []string) error
+	// Complete returns the completions for the positional argument
+	// toComplete following the arguments args, see the Complete function.
+	Complete func(ctx context.Context, args []string, toComplete string) []string
+	// Commands are the subcommands converted from the literal children.
+	Commands []*Command
+}
+
+// NewCommand converts the node at path and its literal descendants into
+// a Command hierarchy, or returns nil if there is no such node.
+// An empty path converts the root node, whose Use is empty.
+//
+// Literal children become subcommands and argument children become the
+// positional arguments of a command. Only nodes usable by ctx are converted.
+func NewCommand(ctx context.Context, d *brigodier.Dispatcher, path ...string) *Command {
+	node := brigodier.CommandNode(&d.Root)
+	if len(path) != 0 {
+		if node = d.FindNode(path...); node == nil {
+			return nil
+		}
+	}
+	return newCommand(ctx, d, node, path)
+}
+
+func newCommand(ctx context.Context, d *brigodier.Dispatcher, node brigodier.CommandNode, path []string) *Command {
+	path = path[:len(path):len(path)] // append to copies
+	cmd := &Command{
+		Complete: func(ctx context.Context, args []string, toComplete string) []string {
+			return Complete(ctx, d, append(path, args...), toComplete)
+		},
+	}
+	runnable := node.Command() != nil
+	var args []string
+	usage := d.SmartUsage(ctx, node)
+	node.ChildrenOrdered().Range(func(name string, child brigodier.CommandNode) bool {
+		if !child.CanUse(ctx) {
+			return true
+		}
+		if _, ok := child.(*brigodier.LiteralCommandNode); ok {
+			cmd.Commands = append(cmd.Commands, newCommand(ctx, d, child, append(path, name)))
+			return true
+		}
+		u, ok := usage.Get(child)
+		if !ok {
+			u = child.UsageText()
+		}
+		args = append(args, u)
+		runnable = true
+		return true
+	})
+	if len(path) != 0 {
+		cmd.Use = path[len(path)-1]
+		if len(args) != 0 {
+			cmd.Use += " " + strings.Join(args, " | ")
+		}
+	}
+	if runnable {
+		cmd.Run = func(ctx context.Context, args []string) error {
+			return Run(ctx, d, append(path, args...))
+		}
+	}
+	return cmd
+}
+
+// Join joins command line arguments to a brigodier command input.
+//
+// Arguments that are empty or contain whitespace or quotes are quoted,
+// so they are read as a single string argument, e.g. ["say", "hello world"]
+// becomes `say "hello world"`.
+func Join(args []string) string {
+	var b strings.Builder
+	for i, arg := range args {
+		if i != 0 {
+			b.WriteRune(brigodier.ArgumentSeparator)
+		}
+		b.WriteString(quote(arg))
+	}
+	return b.String()
+}
+
+func quote(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\n\"'") {
+		return arg
+	}
+	var b strings.Builder
+	b.WriteRune(brigodier.SyntaxDoubleQuote)
+	for _, c := range arg {
+		if c == brigodier.SyntaxDoubleQuote || c == brigodier.SyntaxEscape {
+			b.WriteRune(brigodier.SyntaxEscape)
+		}
+		b.WriteRune(c)
+	}
+	b.WriteRune(brigodier.SyntaxDoubleQuote)
+	return b.String()
+}
+
+// Run parses and executes the command line arguments, e.g. os.Args[1:].
+func Run(ctx context.Context, d *brigodier.Dispatcher, args []string) error {
+	return d.Do(ctx, Join(args))
+}
+
+// Complete returns the completions for the argument toComplete
+// following the already typed arguments args.
+//
+// Like args, toComplete is the unquoted argument as passed by the shell
+// and may contain whitespace, e.g. "John D" completes to "John Doe".
+func Complete(ctx context.Context, d *brigodier.Dispatcher, args []string, toComplete string) []string {
+	input := Join(args)
+	if len(args) != 0 {
+		input += string(brigodier.ArgumentSeparator)
+	}
+	start := len(input)
+	if toComplete != "" {
+		// Leave the quotes open to complete the rest of the argument.
+		input += strings.TrimSuffix(quote(toComplete), `"`)
+	}
+	suggestions, err := d.CompletionSuggestions(d.Parse(ctx, input))
+	if err != nil {
+		return nil
+	}
+	completions := make([]string, 0, len(suggestions.Suggestions))
+	for _, s := range suggestions.Suggestions {
+		if s.Range.Start < start {
+			// The suggestion replaces earlier input and can not be
+			// expressed as completion of the current argument.
+			continue
+		}
+		completions = append(completions, unquote(input[start:s.Range.Start]+s.Text))
+	}
+	return completions
+}
+
+// unquote returns the argument completed by text as passed by the shell,
+// or text itself if it is not a single brigodier string.
+func unquote(text string) string {
+	if text == "" || !brigodier.IsQuotedStringStart(rune(text[0])) {
+		return text
+	}
+	rd := &brigodier.StringReader{String: text}
+	if arg, err := rd.ReadString(); err == nil && !rd.CanRead() {
+		return arg
+	}
+	return text
+}
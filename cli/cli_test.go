@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"go.minekube.com/brigodier"
+	"strings"
+	"testing"
+)
+
+func TestJoin(t *testing.T) {
+	require.Equal(t, `say "hello world" "'x'" "" "a\"b" "c\\d e"`,
+		Join([]string{"say", "hello world", "'x'", "", `a"b`, `c\d e`}))
+}
+
+func TestRunComplete(t *testing.T) {
+	d := new(brigodier.Dispatcher)
+	var got string
+	d.Register(brigodier.Literal("greet").Then(
+		brigodier.Argument("name", brigodier.String).Then(
+			brigodier.Argument("loud", brigodier.Bool).Executes(brigodier.CommandFunc(func(c *brigodier.CommandContext) error {
+				got = c.String("name")
+				return nil
+			})),
+		),
+	))
+	d.Register(brigodier.Literal("gamemode"))
+
+	require.NoError(t, Run(context.TODO(), d, []string{"greet", "John Doe", "true"}))
+	require.Equal(t, "John Doe", got)
+
+	require.Equal(t, []string{"greet", "gamemode"}, Complete(context.TODO(), d, nil, "g"))
+	require.Equal(t, []string{"true"}, Complete(context.TODO(), d, []string{"greet", "Jane Doe"}, "t"))
+}
+
+type names []string
+
+func (n names) Suggestions(_ *brigodier.CommandContext, b *brigodier.SuggestionsBuilder) *brigodier.Suggestions {
+	typed := strings.TrimPrefix(b.Remaining, `"`)
+	for _, name := range n {
+		if strings.HasPrefix(name, typed) {
+			b.Suggest(name)
+		}
+	}
+	return b.Build()
+}
+
+func TestComplete_Quoted(t *testing.T) {
+	d := new(brigodier.Dispatcher)
+	d.Register(brigodier.Literal("greet").Then(
+		brigodier.Argument("name", brigodier.String).
+			Suggests(names{"John Doe", "Jane Doe", "Jim"}),
+	))
+
+	require.Equal(t, []string{"John Doe"}, Complete(context.TODO(), d, []string{"greet"}, "John D"))
+	require.Equal(t, []string{"Jim"}, Complete(context.TODO(), d, []string{"greet"}, "Ji"))
+}
+
+func TestNewCommand(t *testing.T) {
+	d := new(brigodier.Dispatcher)
+	var got []string
+	run := brigodier.CommandFunc(func(c *brigodier.CommandContext) error {
+		got = append(got, c.Input)
+		return nil
+	})
+	d.Register(brigodier.Literal("world").
+		Then(brigodier.Literal("list").Executes(run)).
+		Then(brigodier.Literal("load").Then(
+			brigodier.Argument("name", brigodier.String).Executes(run),
+		)),
+	)
+	d.Register(brigodier.Literal("admin").Requires(func(context.Context) bool { return false }).Executes(run))
+
+	root := NewCommand(context.TODO(), d)
+	require.NotNil(t, root)
+	require.Equal(t, "", root.Use)
+	require.Nil(t, root.Run)
+	require.Len(t, root.Commands, 1)
+
+	world := root.Commands[0]
+	require.Equal(t, "world", world.Use)
+	require.Nil(t, world.Run)
+	require.Len(t, world.Commands, 2)
+	require.Equal(t, "list", world.Commands[0].Use)
+	load := world.Commands[1]
+	require.Equal(t, "load [name]", load.Use)
+	require.Empty(t, load.Commands)
+
+	require.NoError(t, world.Commands[0].Run(context.TODO(), nil))
+	require.NoError(t, load.Run(context.TODO(), []string{"my world"}))
+	require.Equal(t, []string{"world list", `world load "my world"`}, got)
+	require.Equal(t, []string{"list", "load"}, world.Complete(context.TODO(), nil, "l"))
+
+	require.Equal(t, "load [name]", NewCommand(context.TODO(), d, "world", "load").Use)
+	require.Nil(t, NewCommand(context.TODO(), d, "missing"))
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"context"
+	"go.minekube.com/brigodier"
+	"strings"
+	"unicode/utf8"
+)
+
+// Completer completes lines of a terminal application using the
+// Dispatcher.CompletionSuggestionsCursor. It implements the AutoCompleter
+// interface of github.com/chzyer/readline, so it is used with one line of glue:
+//
+//	rl, err := readline.NewEx(&readline.Config{Prompt: "> ", AutoComplete: cli.NewCompleter(ctx, d)})
+type Completer struct {
+	Dispatcher *brigodier.Dispatcher
+	// Context is the context.Context lines are parsed with.
+	// If nil, context.Background is used.
+	Context context.Context
+}
+
+// NewCompleter returns a new Completer.
+func NewCompleter(ctx context.Context, d *brigodier.Dispatcher) *Completer {
+	return &Completer{Dispatcher: d, Context: ctx}
+}
+
+// Do returns the completions of the line at the rune offset pos as the text to
+// insert at pos for each suggestion and the number of runes before pos that
+// the suggestions replace, as expected by readline.
+//
+// Suggestions that do not start with the text they replace, e.g. since they
+// matched ignoring case, can not be inserted and are omitted.
+func (c *Completer) Do(line []rune, pos int) (newLine [][]rune, length int) {
+	if pos < 0 || pos > len(line) {
+		pos = len(line)
+	}
+	ctx := c.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	input := string(line)
+	cursor := len(string(line[:pos]))
+	suggestions, err := c.Dispatcher.CompletionSuggestionsCursor(c.Dispatcher.Parse(ctx, input), cursor)
+	if err != nil || len(suggestions.Suggestions) == 0 {
+		return nil, 0
+	}
+	start := suggestions.Range.Start
+	if start > cursor {
+		start = cursor
+	}
+	typed := input[start:cursor]
+	for _, s := range suggestions.Suggestions {
+		if s.Range.Start != start || !strings.HasPrefix(s.Text, typed) {
+			continue
+		}
+		newLine = append(newLine, []rune(s.Text[len(typed):]))
+	}
+	return newLine, utf8.RuneCountInString(typed)
+}
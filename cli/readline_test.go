@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"go.minekube.com/brigodier"
+	"testing"
+)
+
+func TestCompleter(t *testing.T) {
+	d := new(brigodier.Dispatcher)
+	d.Register(brigodier.Literal("teleport").Then(brigodier.Argument("target", brigodier.StringWord)))
+	d.Register(brigodier.Literal("tell"))
+	d.Register(brigodier.Literal("größe"))
+	c := NewCompleter(context.TODO(), d)
+
+	candidates := func(line string, pos int) ([]string, int) {
+		newLine, length := c.Do([]rune(line), pos)
+		var out []string
+		for _, l := range newLine {
+			out = append(out, string(l))
+		}
+		return out, length
+	}
+
+	got, length := candidates("te", 2)
+	require.Equal(t, []string{"leport", "ll"}, got)
+	require.Equal(t, 2, length)
+
+	got, length = candidates("gr", -1)
+	require.Equal(t, []string{"öße"}, got)
+	require.Equal(t, 2, length)
+
+	got, length = candidates("grö", 3)
+	require.Equal(t, []string{"ße"}, got)
+	require.Equal(t, 3, length)
+
+	// case-insensitive matches can not be inserted
+	got, _ = candidates("TE", 2)
+	require.Empty(t, got)
+
+	// completing in the middle of the line
+	got, length = candidates("tel alice", 2)
+	require.Equal(t, []string{"leport", "ll"}, got)
+	require.Equal(t, 2, length)
+
+	got, length = candidates("", 0)
+	require.Equal(t, []string{"teleport", "tell", "größe"}, got)
+	require.Zero(t, length)
+}
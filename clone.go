@@ -0,0 +1,155 @@
+package brigodier
+
+import "sync/atomic"
+
+// Clone returns a copy of the Dispatcher with a deep copy of its command tree,
+// see CommandNode.Clone. Redirects to nodes of the tree, including the
+// Dispatcher.Root, point to the copied nodes.
+//
+// The copy has the same options and hooks and can be modified
+// independently, e.g. to prepare a modified tree before swapping it in.
+func (d *Dispatcher) Clone() *Dispatcher {
+	clone := *d
+	clone.paths = atomic.Value{}
+	clone.beforeExecute = append([]BeforeExecuteFn(nil), d.beforeExecute...)
+	clone.afterExecute = append([]AfterExecuteFn(nil), d.afterExecute...)
+	clone.onDeprecated = append([]DeprecatedFn(nil), d.onDeprecated...)
+	if d.parseCache != nil {
+		clone.parseCache = newParseCache(d.parseCache.size)
+	}
+	if d.suggestionCache != nil {
+		clone.suggestionCache = newSuggestionCache(d.suggestionCache.size)
+	}
+	if d.namespaces != nil {
+		clone.namespaces = make(map[string]string, len(d.namespaces))
+		for k, v := range d.namespaces {
+			clone.namespaces[k] = v
+		}
+	}
+	clone.Root = RootCommandNode{Node: d.Root.Node.copy()}
+	c := cloner{&d.Root: &clone.Root}
+	c.cloneChildren(&d.Root, &clone.Root)
+	c.redirect()
+	return &clone
+}
+
+// Clone returns a copy of the root node.
+// See CommandNode.Clone.
+func (r *RootCommandNode) Clone(deep bool) CommandNode {
+	return cloneNode(r, deep)
+}
+
+// Clone returns a copy of the literal node.
+// See CommandNode.Clone.
+func (n *LiteralCommandNode) Clone(deep bool) CommandNode {
+	return cloneNode(n, deep)
+}
+
+// Clone returns a copy of the argument node.
+// See CommandNode.Clone.
+func (a *ArgumentCommandNode) Clone(deep bool) CommandNode {
+	return cloneNode(a, deep)
+}
+
+func cloneNode(node CommandNode, deep bool) CommandNode {
+	if !deep {
+		clone := copyNode(node)
+		node.ChildrenOrdered().Range(func(_ string, child CommandNode) bool {
+			clone.AddChild(child)
+			return true
+		})
+		return clone
+	}
+	c := cloner{}
+	clone := c.clone(node)
+	c.redirect()
+	return clone
+}
+
+// cloner deep copies nodes and maps the original nodes to their copies.
+type cloner map[CommandNode]CommandNode
+
+func (c cloner) clone(node CommandNode) CommandNode {
+	if clone, ok := c[node]; ok {
+		return clone
+	}
+	clone := copyNode(node)
+	c[node] = clone
+	c.cloneChildren(node, clone)
+	return clone
+}
+
+func (c cloner) cloneChildren(node, clone CommandNode) {
+	node.ChildrenOrdered().Range(func(_ string, child CommandNode) bool {
+		clone.AddChild(c.clone(child))
+		return true
+	})
+}
+
+// redirect re-points the redirects of the copies to copied nodes.
+func (c cloner) redirect() {
+	for _, clone := range c {
+		n := baseNode(clone)
+		if target, ok := c[n.redirect]; ok {
+			n.redirect = target
+		}
+	}
+}
+
+// copyNode returns a copy of the node without children.
+func copyNode(node CommandNode) CommandNode {
+	switch n := node.(type) {
+	case *RootCommandNode:
+		return &RootCommandNode{Node: n.Node.copy()}
+	case *LiteralCommandNode:
+		return &LiteralCommandNode{
+			Node:           n.Node.copy(),
+			Literal:        n.Literal,
+			mappedArgument: n.mappedArgument,
+			mappedValue:    n.mappedValue,
+		}
+	case *ArgumentCommandNode:
+		return &ArgumentCommandNode{
+			Node:              n.Node.copy(),
+			name:              n.name,
+			argType:           n.argType,
+			customSuggestions: n.customSuggestions,
+			canonicalize:      n.canonicalize,
+			transforms:        append([]TransformFn(nil), n.transforms...),
+			clientParserID:    n.clientParserID,
+			clientParserProps: n.clientParserProps,
+		}
+	}
+	return nil
+}
+
+func baseNode(node CommandNode) *Node {
+	switch n := node.(type) {
+	case *RootCommandNode:
+		return &n.Node
+	case *LiteralCommandNode:
+		return &n.Node
+	case *ArgumentCommandNode:
+		return &n.Node
+	}
+	return nil
+}
+
+// copy returns a copy of the Node without children.
+func (n *Node) copy() Node {
+	return Node{
+		requirement: n.requirement,
+		redirect:    n.redirect,
+		command:     n.command,
+		modifier:    n.modifier,
+		forks:       n.forks,
+		rateLimiter: n.rateLimiter,
+		timeout:     n.timeout,
+		cooldown:    n.cooldown,
+		injections:  append([]InjectedArgument(nil), n.injections...),
+		meta:        n.meta.Copy(),
+		tags:        append([]string(nil), n.tags...),
+		deprecation: n.deprecation,
+		usage:       n.usage,
+	}
+}
@@ -0,0 +1,55 @@
+package brigodier
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCommandNode_Clone(t *testing.T) {
+	var d Dispatcher
+	cmd := CommandFunc(func(c *CommandContext) error { return nil })
+	outside := d.Register(Literal("outside"))
+	foo := d.Register(Literal("foo").Tags("t").Then(
+		Literal("bar").Then(Argument("n", Int).Executes(cmd)),
+		Literal("out").Redirect(outside),
+	))
+	foo.AddChild(Literal("self").Redirect(foo).Build())
+
+	shallow := foo.Clone(false).(*LiteralCommandNode)
+	require.NotSame(t, foo, shallow)
+	require.Equal(t, "foo", shallow.Literal)
+	require.Equal(t, []string{"t"}, shallow.Tags())
+	require.Same(t, foo.Children()["bar"], shallow.Children()["bar"])
+	shallow.RemoveChild("bar")
+	require.NotNil(t, foo.Children()["bar"])
+
+	deep := foo.Clone(true).(*LiteralCommandNode)
+	require.NotSame(t, foo.Children()["bar"], deep.Children()["bar"])
+	require.NotNil(t, deep.Children()["bar"].Children()["n"].Command())
+	require.Same(t, deep, deep.Children()["self"].Redirect())
+	require.Same(t, outside, deep.Children()["out"].Redirect())
+	deep.Children()["bar"].RemoveChild("n")
+	require.NotNil(t, foo.Children()["bar"].Children()["n"])
+}
+
+func TestDispatcher_Clone(t *testing.T) {
+	d := NewDispatcher(WithMaxRedirects(3))
+	var ran int
+	d.Register(Literal("foo").Executes(CommandFunc(func(c *CommandContext) error {
+		ran++
+		return nil
+	})))
+	d.Register(Literal("run").Redirect(&d.Root))
+
+	clone := d.Clone()
+	require.Equal(t, 3, clone.MaxRedirects)
+	require.Same(t, &clone.Root, clone.FindNode("run").Redirect())
+	require.NoError(t, clone.Do(context.TODO(), "run run foo"))
+	require.Equal(t, 1, ran)
+
+	clone.Root.RemoveChild("foo")
+	require.Error(t, clone.Do(context.TODO(), "foo"))
+	require.NoError(t, d.Do(context.TODO(), "run foo"))
+	require.Equal(t, 2, ran)
+}
@@ -0,0 +1,127 @@
+package brigodier
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ColorArgument is the builtin ColorArgumentType.
+var ColorArgument ArgumentType = &ColorArgumentType{}
+
+// Color is an RGB color parsed by ColorArgumentType.
+type Color struct {
+	Name    string // The name of the color if it is one of NamedColors.
+	R, G, B uint8
+}
+
+// RGB returns the color as 0xRRGGBB integer.
+func (c Color) RGB() uint32 { return uint32(c.R)<<16 | uint32(c.G)<<8 | uint32(c.B) }
+
+// Hex returns the color in #RRGGBB notation.
+func (c Color) Hex() string { return fmt.Sprintf("#%06X", c.RGB()) }
+
+func (c Color) String() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Hex()
+}
+
+// ColorFromRGB returns the Color of a 0xRRGGBB integer.
+func ColorFromRGB(rgb uint32) Color {
+	return Color{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb)}
+}
+
+// NamedColors is the palette of named colors accepted by ColorArgumentType.
+var NamedColors = []Color{
+	{"black", 0x00, 0x00, 0x00},
+	{"dark_blue", 0x00, 0x00, 0xAA},
+	{"dark_green", 0x00, 0xAA, 0x00},
+	{"dark_aqua", 0x00, 0xAA, 0xAA},
+	{"dark_red", 0xAA, 0x00, 0x00},
+	{"dark_purple", 0xAA, 0x00, 0xAA},
+	{"gold", 0xFF, 0xAA, 0x00},
+	{"gray", 0xAA, 0xAA, 0xAA},
+	{"dark_gray", 0x55, 0x55, 0x55},
+	{"blue", 0x55, 0x55, 0xFF},
+	{"green", 0x55, 0xFF, 0x55},
+	{"aqua", 0x55, 0xFF, 0xFF},
+	{"red", 0xFF, 0x55, 0x55},
+	{"light_purple", 0xFF, 0x55, 0xFF},
+	{"yellow", 0xFF, 0xFF, 0x55},
+	{"white", 0xFF, 0xFF, 0xFF},
+}
+
+// ErrArgumentInvalidColor occurs when the read value is no valid color.
+var ErrArgumentInvalidColor = errors.New("invalid color")
+
+// ColorArgumentType parses a Color from one of the NamedColors (e.g. "red", "gold"),
+// a hexadecimal "#RRGGBB" or a decimal or 0x prefixed integer value.
+type ColorArgumentType struct{}
+
+func (t *ColorArgumentType) String() string { return "color" }
+func (t *ColorArgumentType) Parse(rd *StringReader) (interface{}, error) {
+	start := rd.Cursor
+	switch {
+	case rd.CanRead() && rd.Peek() == '#':
+		rd.Skip()
+		if hex := rd.ReadWhile(isHexDigit); len(hex) == 6 {
+			rgb, _ := strconv.ParseUint(hex, 16, 32)
+			return ColorFromRGB(uint32(rgb)), nil
+		}
+	case rd.CanRead() && isDigit(rd.Peek()):
+		rgb, err := rd.readUint(32)
+		if err != nil {
+			return nil, err
+		}
+		if rgb > 0xFFFFFF {
+			rd.Cursor = start
+			return nil, &CommandSyntaxError{Err: fmt.Errorf("%w (%d > %d)",
+				ErrArgumentIntegerTooHigh, rgb, 0xFFFFFF)}
+		}
+		return ColorFromRGB(uint32(rgb)), nil
+	default:
+		name := rd.ReadUnquotedString()
+		for _, c := range NamedColors {
+			if strings.EqualFold(c.Name, name) {
+				return c, nil
+			}
+		}
+	}
+	value := rd.String[start:rd.Cursor]
+	rd.Cursor = start
+	return nil, &CommandSyntaxError{Err: &ReaderError{
+		Err: &ReaderInvalidValueError{
+			Type:  t,
+			Value: value,
+			Err:   fmt.Errorf("%w %q", ErrArgumentInvalidColor, value),
+		},
+		Reader: rd,
+	}}
+}
+
+// Suggestions implements SuggestionProvider and suggests the NamedColors.
+func (t *ColorArgumentType) Suggestions(_ *CommandContext, builder *SuggestionsBuilder) *Suggestions {
+	for _, c := range NamedColors {
+		if strings.HasPrefix(c.Name, builder.RemainingLowerCase) {
+			builder.Suggest(c.Name)
+		}
+	}
+	return builder.Build()
+}
+
+// Color returns the parsed Color argument from the command context.
+// It returns the zero-value if not found.
+func (c *CommandContext) Color(argumentName string) Color {
+	if c.Arguments == nil {
+		return Color{}
+	}
+	r, ok := c.Arguments[argumentName]
+	if !ok {
+		return Color{}
+	}
+	v, _ := r.Result.(Color)
+	return v
+}
@@ -0,0 +1,57 @@
+package brigodier
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestColorType_Parse(t *testing.T) {
+	for input, expected := range map[string]Color{
+		"Gold":     {Name: "gold", R: 0xFF, G: 0xAA},
+		"#12ab34":  {R: 0x12, G: 0xAB, B: 0x34},
+		"16777215": {R: 0xFF, G: 0xFF, B: 0xFF},
+		"0x00FF00": {G: 0xFF},
+	} {
+		r := &StringReader{String: input}
+		c, err := ColorArgument.Parse(r)
+		require.NoError(t, err, input)
+		require.Equal(t, expected, c, input)
+		require.Empty(t, r.Remaining())
+	}
+}
+
+func TestColorType_Parse_Invalid(t *testing.T) {
+	for _, input := range []string{"pink", "#12ab3", "#"} {
+		_, err := ColorArgument.Parse(&StringReader{String: input})
+		require.ErrorIs(t, err, ErrArgumentInvalidColor, input)
+		var rErr *ReaderError
+		require.True(t, errors.As(err, &rErr))
+		require.Equal(t, 0, rErr.Reader.Cursor)
+	}
+
+	_, err := ColorArgument.Parse(&StringReader{String: "16777216"})
+	require.ErrorIs(t, err, ErrArgumentIntegerTooHigh)
+}
+
+func TestColor_Hex(t *testing.T) {
+	require.Equal(t, "#FFAA00", Color{R: 0xFF, G: 0xAA}.Hex())
+	require.Equal(t, "#00000A", ColorFromRGB(10).String())
+}
+
+func TestColorType_Suggestions(t *testing.T) {
+	var d Dispatcher
+	d.Register(Literal("color").Then(Argument("c", ColorArgument)))
+	testSuggestions(t, &d, "color dark_", 11, StringRange{Start: 6, End: 11},
+		"dark_blue", "dark_green", "dark_aqua", "dark_red", "dark_purple", "dark_gray")
+
+	var color Color
+	d.Register(Literal("paint").Then(Argument("c", ColorArgument).
+		Executes(CommandFunc(func(c *CommandContext) error {
+			color = c.Color("c")
+			return nil
+		}))))
+	require.NoError(t, d.Do(context.TODO(), "paint red"))
+	require.Equal(t, "red", color.Name)
+}
@@ -0,0 +1,256 @@
+package brigodier
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ExampleProvider is an optional interface implemented by an ArgumentType
+// to provide example values, e.g. used to generate shell completion scripts.
+type ExampleProvider interface {
+	// Examples returns example values of the argument type.
+	Examples() []string
+}
+
+// Examples implements ExampleProvider.
+func (t *BoolArgumentType) Examples() []string { return []string{"true", "false"} }
+
+// Examples implements ExampleProvider and returns the names of the NamedColors.
+func (t *ColorArgumentType) Examples() []string {
+	names := make([]string, len(NamedColors))
+	for i, c := range NamedColors {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// Shell is a shell supported by Dispatcher.WriteCompletion.
+type Shell string
+
+// Supported Shell values.
+const (
+	Bash Shell = "bash"
+	Zsh  Shell = "zsh"
+	Fish Shell = "fish"
+)
+
+// WriteCompletion writes a completion script for the given shell to w
+// completing the command tree below node for the program prog.
+//
+// The script is static: literals complete to their names and arguments complete
+// to the examples of their ArgumentType if it implements ExampleProvider.
+// Redirects are followed, but only the first registered argument of a node is
+// considered and requirements are not checked. Arguments of a MultiTokenArgumentType
+// consume their Tokens as words, or a single word if the number of tokens varies.
+//
+// To install, e.g. for bash, source the output:
+//
+//	source <(mycli completion bash)
+func (d *Dispatcher) WriteCompletion(w io.Writer, shell Shell, node CommandNode, prog string) error {
+	m := &completionMachine{ids: map[CommandNode]int{}}
+	m.state(node)
+	fn := "_" + completionIdent(prog) + "_complete"
+	var b strings.Builder
+	switch shell {
+	case Bash:
+		m.writeBash(&b, fn, prog)
+	case Zsh:
+		m.writeZsh(&b, fn, prog)
+	case Fish:
+		m.writeFish(&b, fn, prog)
+	default:
+		return fmt.Errorf("unsupported shell %q", shell)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// completionMachine is the state machine of a command tree where each
+// state is a node whose children are the candidates of the next word.
+type completionMachine struct {
+	ids    map[CommandNode]int
+	states []completionState
+}
+
+type completionState struct {
+	words    []string       // candidates
+	literals map[string]int // literal -> next state
+	argument int            // next state for any other word, -1 if none
+}
+
+// state returns the state of the node, adding it if necessary.
+func (m *completionMachine) state(n CommandNode) int {
+	// Nodes redirecting without children of their own continue at the target.
+	for seen := map[CommandNode]bool{}; n.Redirect() != nil && len(n.Children()) == 0 && !seen[n]; {
+		seen[n] = true
+		n = n.Redirect()
+	}
+	if id, ok := m.ids[n]; ok {
+		return id
+	}
+	id := len(m.states)
+	m.ids[n] = id
+	m.states = append(m.states, completionState{literals: map[string]int{}, argument: -1})
+
+	var (
+		words    []string
+		literals = map[string]int{}
+		argument = -1
+	)
+	n.ChildrenOrdered().Range(func(_ string, child CommandNode) bool {
+		switch t := child.(type) {
+		case *LiteralCommandNode:
+			words = append(words, t.Literal)
+			literals[t.Literal] = m.state(t)
+		case *ArgumentCommandNode:
+			if argument != -1 {
+				return true
+			}
+			if e, ok := t.Type().(ExampleProvider); ok {
+				words = append(words, e.Examples()...)
+			}
+			argument = m.state(t)
+			// Skip the remaining tokens of a multi-token argument.
+			for n := argumentTokens(t.Type()); n > 1; n-- {
+				argument = m.token(argument)
+			}
+		}
+		return true
+	})
+	m.states[id] = completionState{words: words, literals: literals, argument: argument}
+	return id
+}
+
+// token adds a state without candidates that continues at next for any word.
+func (m *completionMachine) token(next int) int {
+	m.states = append(m.states, completionState{literals: map[string]int{}, argument: next})
+	return len(m.states) - 1
+}
+
+// transitions returns the "<state> <word>" transitions of a state
+// in a stable order. A word of "*" matches any word.
+func (s *completionState) transitions(id int) (keys []string, next []int) {
+	literals := make([]string, 0, len(s.literals))
+	for l := range s.literals {
+		literals = append(literals, l)
+	}
+	sort.Strings(literals)
+	for _, l := range literals {
+		keys = append(keys, fmt.Sprintf("%d %s", id, l))
+		next = append(next, s.literals[l])
+	}
+	if s.argument != -1 {
+		keys = append(keys, fmt.Sprintf("%d *", id))
+		next = append(next, s.argument)
+	}
+	return keys, next
+}
+
+func (m *completionMachine) writeBash(b *strings.Builder, fn, prog string) {
+	fmt.Fprintf(b, "# bash completion for %s\n", prog)
+	fmt.Fprintf(b, "%s() {\n", fn)
+	m.writeTables(b)
+	b.WriteString(`	local state=0 next i
+	for ((i = 1; i < COMP_CWORD; i++)); do
+		next=${trans["$state ${COMP_WORDS[i]}"]}
+		[[ -z $next ]] && next=${trans["$state *"]}
+		[[ -z $next ]] && return
+		state=$next
+	done
+	local cur=${COMP_WORDS[COMP_CWORD]} cand
+	COMPREPLY=()
+	while IFS= read -r cand; do
+		[[ -n $cand && $cand == "$cur"* ]] && COMPREPLY+=("$cand")
+	done <<<"${cands[$state]}"
+}
+`)
+	fmt.Fprintf(b, "complete -F %s %s\n", fn, shellQuote(prog))
+}
+
+func (m *completionMachine) writeZsh(b *strings.Builder, fn, prog string) {
+	fmt.Fprintf(b, "#compdef %s\n", prog)
+	fmt.Fprintf(b, "%s() {\n", fn)
+	m.writeTables(b)
+	b.WriteString(`	local state=0 next i
+	for ((i = 2; i < CURRENT; i++)); do
+		next=${trans[$state ${words[i]}]}
+		[[ -z $next ]] && next=${trans[$state *]}
+		[[ -z $next ]] && return 1
+		state=$next
+	done
+	compadd -- ${(f)cands[$state]}
+}
+`)
+	fmt.Fprintf(b, "compdef %s %s\n", fn, shellQuote(prog))
+}
+
+// writeTables writes the bash/zsh associative arrays trans and cands.
+func (m *completionMachine) writeTables(b *strings.Builder) {
+	b.WriteString("\tlocal -A trans=(\n")
+	for id := range m.states {
+		keys, next := m.states[id].transitions(id)
+		for i, key := range keys {
+			fmt.Fprintf(b, "\t\t[%s]=%d\n", shellQuote(key), next[i])
+		}
+	}
+	b.WriteString("\t)\n\tlocal -A cands=(\n")
+	for id, s := range m.states {
+		if len(s.words) != 0 {
+			fmt.Fprintf(b, "\t\t[%d]=%s\n", id, shellQuote(strings.Join(s.words, "\n")))
+		}
+	}
+	b.WriteString("\t)\n")
+}
+
+func (m *completionMachine) writeFish(b *strings.Builder, fn, prog string) {
+	fmt.Fprintf(b, "# fish completion for %s\n", prog)
+	fmt.Fprintf(b, "function %s_state\n\tset -l state 0\n", fn)
+	b.WriteString("\tfor word in (commandline -opc)[2..-1]\n\t\tswitch \"$state $word\"\n")
+	for id := range m.states {
+		keys, next := m.states[id].transitions(id)
+		for i, key := range keys {
+			pattern := fishQuote(key)
+			if strings.HasSuffix(key, " *") {
+				pattern = fishQuote(strings.TrimSuffix(key, "*")) + "'*'"
+			}
+			fmt.Fprintf(b, "\t\t\tcase %s\n\t\t\t\tset state %d\n", pattern, next[i])
+		}
+	}
+	b.WriteString("\t\t\tcase '*'\n\t\t\t\treturn 1\n\t\tend\n\tend\n\techo $state\nend\n")
+
+	fmt.Fprintf(b, "function %s\n\tswitch (%s_state)\n", fn, fn)
+	for id, s := range m.states {
+		if len(s.words) == 0 {
+			continue
+		}
+		quoted := make([]string, len(s.words))
+		for i, w := range s.words {
+			quoted[i] = fishQuote(w)
+		}
+		fmt.Fprintf(b, "\t\tcase %d\n\t\t\tprintf '%%s\\n' %s\n", id, strings.Join(quoted, " "))
+	}
+	b.WriteString("\tend\nend\n")
+	fmt.Fprintf(b, "complete -c %s -f -a '(%s)'\n", fishQuote(prog), fn)
+}
+
+// completionIdent returns s as shell function identifier.
+func completionIdent(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// shellQuote single-quotes s for bash and zsh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishQuote single-quotes s for fish.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,71 @@
+package brigodier
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func completionDispatcher() *Dispatcher {
+	var d Dispatcher
+	cmd := CommandFunc(func(c *CommandContext) error { return nil })
+	d.Register(Literal("gamemode").
+		Then(Literal("survival").Executes(cmd)).
+		Then(Literal("creative").Executes(cmd)))
+	d.Register(Literal("fly").Then(Argument("enabled", Bool).Executes(cmd)))
+	d.Register(Literal("it's").Executes(cmd))
+	d.Register(Literal("tp").Then(Argument("pos", vec3ArgumentType{}).Then(Argument("ground", Bool).Executes(cmd))))
+	execute := d.Register(Literal("execute"))
+	d.Register(Literal("execute").
+		Then(Literal("as").Then(Argument("player", StringWord).Redirect(execute))).
+		Then(Literal("run").Redirect(&d.Root)))
+	return &d
+}
+
+func TestDispatcher_WriteCompletion(t *testing.T) {
+	d := completionDispatcher()
+	for _, shell := range []Shell{Bash, Zsh, Fish} {
+		var b strings.Builder
+		require.NoError(t, d.WriteCompletion(&b, shell, &d.Root, "my-cli"))
+		require.Contains(t, b.String(), "_my_cli_complete")
+		require.Contains(t, b.String(), "gamemode")
+	}
+	require.Error(t, d.WriteCompletion(&strings.Builder{}, "powershell", &d.Root, "my-cli"))
+}
+
+func TestDispatcher_WriteCompletion_Bash(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not installed")
+	}
+	d := completionDispatcher()
+	var script strings.Builder
+	require.NoError(t, d.WriteCompletion(&script, Bash, &d.Root, "my-cli"))
+
+	complete := func(words ...string) []string {
+		t.Helper()
+		var b strings.Builder
+		b.WriteString(script.String())
+		b.WriteString("COMP_WORDS=(my-cli")
+		for _, w := range words {
+			b.WriteString(" " + shellQuote(w))
+		}
+		b.WriteString(")\n")
+		fmt.Fprintf(&b, "COMP_CWORD=%d\n", len(words))
+		b.WriteString("_my_cli_complete\nprintf '%s\\n' \"${COMPREPLY[@]}\"\n")
+		out, err := exec.Command(bash, "-c", b.String()).CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.Fields(string(out))
+	}
+
+	require.Equal(t, []string{"gamemode", "fly", "it's", "tp", "execute"}, complete(""))
+	require.Equal(t, []string{"survival"}, complete("gamemode", "s"))
+	require.Equal(t, []string{"true", "false"}, complete("fly", ""))
+	require.Equal(t, []string{"as", "run"}, complete("execute", "as", "Steve", ""))
+	require.Equal(t, []string{"fly"}, complete("execute", "as", "Steve", "run", "fl"))
+	require.Empty(t, complete("tp", "1", "2", ""))
+	require.Equal(t, []string{"true", "false"}, complete("tp", "1", "2", "3", ""))
+	require.Empty(t, complete("unknown", ""))
+}
@@ -0,0 +1,138 @@
+package brigodier
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Either returns an ArgumentType that parses a or, if that fails, b.
+// If both fail the error of b is returned.
+// Suggestions of both types are merged.
+func Either(a, b ArgumentType) ArgumentType { return &EitherArgumentType{A: a, B: b} }
+
+// ListOf returns an ArgumentType that parses one or more elem values
+// separated by sep, e.g. "1,2,3" for ListOf(Int, ','), into a []interface{}.
+// If sep is the ArgumentSeparator, the list consumes the following tokens
+// as long as they are elements, e.g. "1 2 3" for ListOf(Int, ' ').
+// Suggestions are provided by elem for the last element of the list.
+func ListOf(elem ArgumentType, sep rune) ArgumentType {
+	return &ListArgumentType{Elem: elem, Separator: sep}
+}
+
+// Optional returns an ArgumentType that parses t or returns def
+// without consuming input if there is no more input to parse for it.
+func Optional(t ArgumentType, def interface{}) ArgumentType {
+	return &OptionalArgumentType{Type: t, Default: def}
+}
+
+// EitherArgumentType is an ArgumentType created by Either.
+type EitherArgumentType struct{ A, B ArgumentType }
+
+func (t *EitherArgumentType) String() string { return fmt.Sprintf("%s|%s", t.A, t.B) }
+func (t *EitherArgumentType) Parse(rd *StringReader) (interface{}, error) {
+	fork := rd.Fork()
+	if v, err := t.A.Parse(fork); err == nil {
+		fork.Commit()
+		return v, nil
+	}
+	return t.B.Parse(rd)
+}
+
+// Tokens implements MultiTokenArgumentType and returns
+// the tokens of A and B if equal, otherwise 0.
+func (t *EitherArgumentType) Tokens() int {
+	if n := argumentTokens(t.A); n == argumentTokens(t.B) {
+		return n
+	}
+	return 0
+}
+
+// Suggestions implements SuggestionProvider.
+func (t *EitherArgumentType) Suggestions(ctx *CommandContext, builder *SuggestionsBuilder) *Suggestions {
+	return MergeSuggestions(builder.Input, []*Suggestions{
+		ProvideSuggestions(t.A, ctx, builder.CreateOffset(builder.Start)),
+		ProvideSuggestions(t.B, ctx, builder.CreateOffset(builder.Start)),
+	})
+}
+
+// ListArgumentType is an ArgumentType created by ListOf.
+type ListArgumentType struct {
+	Elem      ArgumentType
+	Separator rune
+}
+
+func (t *ListArgumentType) String() string { return fmt.Sprintf("list<%s>", t.Elem) }
+func (t *ListArgumentType) Parse(rd *StringReader) (interface{}, error) {
+	start := rd.Cursor
+	var values []interface{}
+	for {
+		v, err := t.Elem.Parse(rd)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+		if t.Separator == ArgumentSeparator {
+			return t.parseTokens(rd, values), nil
+		}
+		if !rd.CanRead() || rd.Peek() == ArgumentSeparator {
+			return values, nil
+		}
+		if err = rd.Expect(t.Separator); err != nil {
+			rd.Cursor = start
+			return nil, err
+		}
+	}
+}
+
+// parseTokens parses the elements following the first one as long as
+// the next token is an element, e.g. "1 2 3" of "1 2 3 name" for ListOf(Int, ' ').
+func (t *ListArgumentType) parseTokens(rd *StringReader, values []interface{}) []interface{} {
+	for rd.CanRead() && rd.Peek() == ArgumentSeparator {
+		fork := rd.Fork()
+		fork.Skip()
+		v, err := t.Elem.Parse(fork)
+		if err != nil || fork.CanRead() && fork.Peek() != ArgumentSeparator {
+			break
+		}
+		fork.Commit()
+		values = append(values, v)
+	}
+	return values
+}
+
+// Tokens implements MultiTokenArgumentType. Lists separated
+// by ArgumentSeparator consume a varying number of tokens.
+func (t *ListArgumentType) Tokens() int {
+	if t.Separator == ArgumentSeparator || argumentTokens(t.Elem) != 1 {
+		return 0
+	}
+	return 1
+}
+
+// Suggestions implements SuggestionProvider.
+func (t *ListArgumentType) Suggestions(ctx *CommandContext, builder *SuggestionsBuilder) *Suggestions {
+	offset := strings.LastIndex(builder.Remaining, string(t.Separator)) + 1
+	return ProvideSuggestions(t.Elem, ctx, builder.CreateOffset(builder.Start+offset))
+}
+
+// OptionalArgumentType is an ArgumentType created by Optional.
+type OptionalArgumentType struct {
+	Type    ArgumentType
+	Default interface{}
+}
+
+func (t *OptionalArgumentType) String() string { return fmt.Sprintf("%s?", t.Type) }
+func (t *OptionalArgumentType) Parse(rd *StringReader) (interface{}, error) {
+	if !rd.CanRead() || rd.Peek() == ArgumentSeparator {
+		return t.Default, nil
+	}
+	return t.Type.Parse(rd)
+}
+
+// Tokens implements MultiTokenArgumentType and returns the tokens of Type.
+func (t *OptionalArgumentType) Tokens() int { return argumentTokens(t.Type) }
+
+// Suggestions implements SuggestionProvider.
+func (t *OptionalArgumentType) Suggestions(ctx *CommandContext, builder *SuggestionsBuilder) *Suggestions {
+	return ProvideSuggestions(t.Type, ctx, builder)
+}
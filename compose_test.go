@@ -0,0 +1,105 @@
+package brigodier
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestEither_Parse(t *testing.T) {
+	typ := Either(Int, Bool)
+	v, err := typ.Parse(&StringReader{String: "12"})
+	require.NoError(t, err)
+	require.Equal(t, int32(12), v)
+
+	v, err = typ.Parse(&StringReader{String: "true"})
+	require.NoError(t, err)
+	require.Equal(t, true, v)
+
+	r := &StringReader{String: "foo"}
+	_, err = typ.Parse(r)
+	require.Error(t, err)
+	require.Equal(t, 0, r.Cursor)
+}
+
+func TestListOf_Parse(t *testing.T) {
+	r := &StringReader{String: "1,2,3 rest"}
+	v, err := ListOf(Int, ',').Parse(r)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{int32(1), int32(2), int32(3)}, v)
+	require.Equal(t, " rest", r.Remaining())
+}
+
+func TestListOf_Parse_ArgumentSeparator(t *testing.T) {
+	list := ListOf(Int, ' ')
+	r := &StringReader{String: "1 2 3 rest"}
+	v, err := list.Parse(r)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{int32(1), int32(2), int32(3)}, v)
+	require.Equal(t, " rest", r.Remaining())
+	require.Equal(t, 0, list.(MultiTokenArgumentType).Tokens())
+
+	r = &StringReader{String: "1 2x"}
+	v, err = list.Parse(r)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{int32(1)}, v)
+	require.Equal(t, " 2x", r.Remaining())
+
+	var d Dispatcher
+	var sum int32
+	d.Register(Literal("sum").Then(Argument("n", list).Executes(CommandFunc(func(c *CommandContext) error {
+		for _, n := range c.Arguments["n"].Result.([]interface{}) {
+			sum += n.(int32)
+		}
+		return nil
+	}))))
+	require.NoError(t, d.Do(context.TODO(), "sum 1 2 3"))
+	require.Equal(t, int32(6), sum)
+}
+
+func TestListOf_Parse_Invalid(t *testing.T) {
+	_, err := ListOf(Int, ',').Parse(&StringReader{String: "1,x"})
+	require.ErrorIs(t, err, ErrReaderExpectedInt)
+	var rErr *ReaderError
+	require.True(t, errors.As(err, &rErr))
+	require.Equal(t, 2, rErr.Reader.Cursor)
+
+	_, err = ListOf(Int, ',').Parse(&StringReader{String: "1;2"})
+	var eErr *ReaderExpectedRuneError
+	require.True(t, errors.As(err, &eErr))
+	require.Equal(t, ',', eErr.Rune)
+}
+
+func TestOptional_Parse(t *testing.T) {
+	r := &StringReader{String: " rest"}
+	v, err := Optional(Int, int32(5)).Parse(r)
+	require.NoError(t, err)
+	require.Equal(t, int32(5), v)
+	require.Equal(t, 0, r.Cursor)
+
+	v, err = Optional(Int, int32(5)).Parse(&StringReader{String: "7"})
+	require.NoError(t, err)
+	require.Equal(t, int32(7), v)
+}
+
+func TestCompose_Suggestions(t *testing.T) {
+	var d Dispatcher
+	d.Register(Literal("either").Then(Argument("v", Either(Bool, ColorArgument))))
+	d.Register(Literal("list").Then(Argument("v", ListOf(ColorArgument, ','))))
+
+	testSuggestions(t, &d, "either t", 8, StringRange{Start: 7, End: 8}, "true")
+	testSuggestions(t, &d, "either ", 7, StringRange{Start: 7, End: 7}, append([]string{"true"}, namedColors()...)...)
+	testSuggestions(t, &d, "list red,gr", 11, StringRange{Start: 9, End: 11}, "gray", "green")
+
+	parse := d.Parse(context.TODO(), "list red,gold")
+	require.False(t, parse.Reader.CanRead())
+}
+
+func namedColors() []string {
+	names := make([]string, len(NamedColors))
+	for i, c := range NamedColors {
+		names[i] = c.Name
+	}
+	return names
+}
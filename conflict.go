@@ -0,0 +1,107 @@
+package brigodier
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ConflictPolicy decides what Dispatcher.Register does if
+// a command with the same literal is already registered.
+type ConflictPolicy uint8
+
+// ConflictPolicy values.
+const (
+	// ConflictMerge merges the command into the registered command,
+	// replacing the Command of nodes that both define one. This is the default.
+	ConflictMerge ConflictPolicy = iota
+	// ConflictError rejects the command with a *CommandConflictError.
+	ConflictError
+	// ConflictReplace replaces the registered command.
+	ConflictReplace
+	// ConflictFirstWins keeps the registered command and ignores the new one.
+	ConflictFirstWins
+)
+
+func (p ConflictPolicy) String() string {
+	switch p {
+	case ConflictMerge:
+		return "merge"
+	case ConflictError:
+		return "error"
+	case ConflictReplace:
+		return "replace"
+	case ConflictFirstWins:
+		return "first-wins"
+	}
+	return fmt.Sprintf("ConflictPolicy(%d)", uint8(p))
+}
+
+// ErrCommandConflict is wrapped by CommandConflictError.
+var ErrCommandConflict = errors.New("command conflict")
+
+// CommandConflictError is returned by Dispatcher.TryRegister if
+// the Dispatcher.ConflictPolicy is ConflictError and the literal is already registered.
+type CommandConflictError struct {
+	Literal string // The conflicting literal.
+	// Paths are the paths, starting with Literal, that exist both
+	// in the registered and the new command, in registration order.
+	Paths [][]string
+}
+
+// Unwrap implements errors.Unwrap.
+func (e *CommandConflictError) Unwrap() error { return ErrCommandConflict }
+func (e *CommandConflictError) Error() string {
+	paths := make([]string, len(e.Paths))
+	for i, p := range e.Paths {
+		paths[i] = strings.Join(p, " ")
+	}
+	return fmt.Sprintf("%v %q: colliding paths %q", ErrCommandConflict, e.Literal, paths)
+}
+
+// checkConflict returns a *CommandConflictError if the literal is
+// already registered and the Dispatcher.ConflictPolicy is ConflictError
+// or a *MergeConflictError if the Dispatcher.MergeStrategy rejects merging it.
+func (d *Dispatcher) checkConflict(literal *LiteralCommandNode) error {
+	existing := d.Root.Children()[literal.Literal]
+	if existing == nil {
+		return nil
+	}
+	if d.ConflictPolicy == ConflictMerge {
+		return mergeNode(existing, literal, d.MergeStrategy, []string{literal.Literal}, false)
+	}
+	if d.ConflictPolicy != ConflictError {
+		return nil
+	}
+	err := &CommandConflictError{Literal: literal.Literal}
+	var collide func(a, b CommandNode, path []string)
+	collide = func(a, b CommandNode, path []string) {
+		err.Paths = append(err.Paths, path)
+		b.ChildrenOrdered().Range(func(name string, child CommandNode) bool {
+			if other := a.Children()[name]; other != nil {
+				collide(other, child, append(append(make([]string, 0, len(path)+1), path...), name))
+			}
+			return true
+		})
+	}
+	collide(existing, literal, []string{literal.Literal})
+	return err
+}
+
+// addRoot adds the literal to the root respecting the Dispatcher.ConflictPolicy
+// and returns the registered node. checkConflict must be called before.
+func (d *Dispatcher) addRoot(literal *LiteralCommandNode) *LiteralCommandNode {
+	if existing, ok := d.Root.Literals()[literal.Literal]; ok {
+		switch d.ConflictPolicy {
+		case ConflictFirstWins:
+			return existing
+		case ConflictReplace:
+			d.Root.RemoveChild(literal.Literal)
+		case ConflictMerge:
+			_ = mergeNode(existing, literal, d.MergeStrategy, []string{literal.Literal}, true)
+			return literal
+		}
+	}
+	d.Root.AddChild(literal)
+	return literal
+}
@@ -0,0 +1,63 @@
+package brigodier
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDispatcher_ConflictPolicy(t *testing.T) {
+	var ran string
+	cmd := func(name string) Command {
+		return CommandFunc(func(c *CommandContext) error {
+			ran = name
+			return nil
+		})
+	}
+	register := func(d *Dispatcher) (*LiteralCommandNode, *LiteralCommandNode, error) {
+		first := d.Register(Literal("foo").Executes(cmd("first")).
+			Then(Literal("bar").Then(Argument("n", Int).Executes(cmd("first n")))))
+		second, err := d.TryRegister(Literal("foo").Executes(cmd("second")).
+			Then(Literal("bar").Then(Argument("n", Int).Executes(cmd("second n")))).
+			Then(Literal("baz").Executes(cmd("second baz"))))
+		return first, second, err
+	}
+
+	d := NewDispatcher()
+	_, _, err := register(d)
+	require.NoError(t, err)
+	require.NoError(t, d.Do(context.TODO(), "foo"))
+	require.Equal(t, "second", ran)
+	require.NoError(t, d.Do(context.TODO(), "foo baz"))
+	require.Equal(t, "second baz", ran)
+
+	d = NewDispatcher(WithConflictPolicy(ConflictError))
+	first, second, err := register(d)
+	require.Nil(t, second)
+	require.ErrorIs(t, err, ErrCommandConflict)
+	var conflictErr *CommandConflictError
+	require.ErrorAs(t, err, &conflictErr)
+	require.Equal(t, "foo", conflictErr.Literal)
+	require.Equal(t, [][]string{{"foo"}, {"foo", "bar"}, {"foo", "bar", "n"}}, conflictErr.Paths)
+	require.EqualError(t, err, `command conflict "foo": colliding paths ["foo" "foo bar" "foo bar n"]`)
+	require.Equal(t, first, d.FindNode("foo"))
+	require.Nil(t, d.FindNode("foo", "baz"))
+	require.Panics(t, func() { d.Register(Literal("foo")) })
+
+	d = NewDispatcher(WithConflictPolicy(ConflictReplace))
+	_, second, err = register(d)
+	require.NoError(t, err)
+	require.Equal(t, second, d.FindNode("foo"))
+	require.NoError(t, d.Do(context.TODO(), "foo bar 1"))
+	require.Equal(t, "second n", ran)
+
+	d = NewDispatcher(WithConflictPolicy(ConflictFirstWins))
+	first, second, err = register(d)
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+	require.Nil(t, d.FindNode("foo", "baz"))
+	require.NoError(t, d.Do(context.TODO(), "foo"))
+	require.Equal(t, "first", ran)
+
+	require.Equal(t, "first-wins", ConflictFirstWins.String())
+}
@@ -0,0 +1,80 @@
+package brigodier
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// contextJSON is the JSON representation of a CommandContext.
+type contextJSON struct {
+	Input     string                     `json:"input"`
+	Source    string                     `json:"source,omitempty"`
+	Range     rangeJSON                  `json:"range"`
+	Path      []string                   `json:"path"`
+	Nodes     []*ParsedCommandNode       `json:"nodes"`
+	Arguments map[string]*ParsedArgument `json:"arguments,omitempty"`
+	Forks     bool                       `json:"forks,omitempty"`
+	Child     *CommandContext            `json:"child,omitempty"`
+}
+
+type rangeJSON struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// MarshalJSON implements json.Marshaler, e.g. to log executed commands structurally:
+//
+//	{"input":"tp alice","source":"console","range":{"start":0,"end":8},"path":["tp","target"],
+//	 "nodes":[{"name":"tp","range":{"start":0,"end":2}},{"name":"target","range":{"start":3,"end":8}}],
+//	 "arguments":{"target":{"range":{"start":3,"end":8},"value":"alice"}}}
+//
+// The name of the Source is included if there is one. Argument values are
+// stringified using fmt.Sprint, the input can be parsed again to replay the command.
+func (c *CommandContext) MarshalJSON() ([]byte, error) {
+	j := contextJSON{
+		Input:     c.Input,
+		Range:     rangeJSON{Start: c.Range.Start, End: c.Range.End},
+		Path:      make([]string, 0, len(c.Nodes)),
+		Nodes:     c.Nodes,
+		Arguments: c.Arguments,
+		Forks:     c.Forks,
+		Child:     c.Child,
+	}
+	if j.Nodes == nil {
+		j.Nodes = []*ParsedCommandNode{}
+	}
+	if c.Context != nil {
+		if src := SourceFrom(c.Context); src != nil {
+			j.Source = src.Name()
+		}
+	}
+	for _, n := range c.Nodes {
+		j.Path = append(j.Path, n.Node.Name())
+	}
+	return json.Marshal(j)
+}
+
+// MarshalJSON implements json.Marshaler and encodes the name of the node and its range.
+func (n *ParsedCommandNode) MarshalJSON() ([]byte, error) {
+	j := struct {
+		Name  string     `json:"name"`
+		Range *rangeJSON `json:"range,omitempty"`
+	}{Name: n.Node.Name()}
+	if n.Range != nil {
+		j.Range = &rangeJSON{Start: n.Range.Start, End: n.Range.End}
+	}
+	return json.Marshal(j)
+}
+
+// MarshalJSON implements json.Marshaler and encodes the range of the argument
+// and its value stringified using fmt.Sprint. Injected arguments have no range.
+func (a *ParsedArgument) MarshalJSON() ([]byte, error) {
+	j := struct {
+		Range *rangeJSON `json:"range,omitempty"`
+		Value string     `json:"value"`
+	}{Value: fmt.Sprint(a.Result)}
+	if a.Range != nil {
+		j.Range = &rangeJSON{Start: a.Range.Start, End: a.Range.End}
+	}
+	return json.Marshal(j)
+}
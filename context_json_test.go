@@ -0,0 +1,56 @@
+package brigodier
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCommandContext_MarshalJSON(t *testing.T) {
+	var d Dispatcher
+	var executed []byte
+	tp := d.Register(Literal("tp").Then(Argument("target", StringWord).Then(
+		Argument("y", Int).Executes(CommandFunc(func(c *CommandContext) error {
+			var err error
+			executed, err = json.Marshal(c)
+			return err
+		})))))
+	d.Register(Literal("teleport").Redirect(tp))
+
+	ctx := WithSource(context.TODO(), &testSource{name: "console"})
+	require.NoError(t, d.Do(ctx, "tp alice 64"))
+	require.JSONEq(t, `{
+		"input": "tp alice 64",
+		"source": "console",
+		"range": {"start": 0, "end": 11},
+		"path": ["tp", "target", "y"],
+		"nodes": [
+			{"name": "tp", "range": {"start": 0, "end": 2}},
+			{"name": "target", "range": {"start": 3, "end": 8}},
+			{"name": "y", "range": {"start": 9, "end": 11}}
+		],
+		"arguments": {
+			"target": {"range": {"start": 3, "end": 8}, "value": "alice"},
+			"y": {"range": {"start": 9, "end": 11}, "value": "64"}
+		}
+	}`, string(executed))
+
+	parse := d.Parse(context.TODO(), "teleport bob 1")
+	b, err := json.Marshal(parse.Context)
+	require.NoError(t, err)
+	var decoded struct {
+		Path  []string `json:"path"`
+		Child *struct {
+			Path []string `json:"path"`
+		} `json:"child"`
+	}
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	require.Equal(t, []string{"teleport"}, decoded.Path)
+	require.Equal(t, []string{"target", "y"}, decoded.Child.Path)
+
+	// replay
+	var replay struct{ Input string }
+	require.NoError(t, json.Unmarshal(executed, &replay))
+	require.NoError(t, d.Do(ctx, replay.Input))
+}
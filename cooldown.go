@@ -0,0 +1,149 @@
+package brigodier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCooldown indicates that a command was executed again before the cooldown of a node expired.
+// The returned error is a *CooldownError wrapping ErrCooldown.
+var ErrCooldown = errors.New("command on cooldown")
+
+// ErrNoCooldowns is returned by Dispatcher.Execute for commands executed
+// through a node with a cooldown if Dispatcher.Cooldowns is nil.
+var ErrNoCooldowns = errors.New("dispatcher: no Cooldowns to track node cooldown")
+
+// CooldownError is returned by Dispatcher.Execute if the cooldown of a node has not expired.
+type CooldownError struct {
+	Node      CommandNode   // The node whose cooldown has not expired.
+	Remaining time.Duration // The duration until the cooldown expires.
+}
+
+// Unwrap implements errors.Unwrap and returns ErrCooldown.
+func (e *CooldownError) Unwrap() error { return ErrCooldown }
+func (e *CooldownError) Error() string {
+	return fmt.Sprintf("%s: %s remaining", ErrCooldown, e.Remaining)
+}
+
+// Cooldowns tracks when the cooldowns of nodes set using the Cooldown
+// builder method expire per source key extracted from the context.
+// It is set as Dispatcher.Cooldowns and consulted by Dispatcher.Execute
+// before running a command.
+type Cooldowns struct {
+	// Key optionally extracts the source key from the context, e.g. the
+	// ID of the executing player. If nil, the Source is the key.
+	// Keys must be comparable.
+	Key func(ctx context.Context) interface{}
+
+	now     func() time.Time
+	mu      sync.Mutex
+	expires map[cooldownKey]time.Time
+}
+
+type cooldownKey struct {
+	node   CommandNode
+	source interface{}
+}
+
+// NewCooldowns returns a new Cooldowns.
+func NewCooldowns(key func(ctx context.Context) interface{}) *Cooldowns {
+	return &Cooldowns{Key: key}
+}
+
+func (c *Cooldowns) key(node CommandNode, ctx context.Context) cooldownKey {
+	k := cooldownKey{node: node}
+	if c.Key != nil {
+		k.source = c.Key(ctx)
+	} else {
+		k.source = SourceFrom(ctx)
+	}
+	return k
+}
+
+func (c *Cooldowns) time() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+// check rejects the command if the cooldown of a node executed with the context
+// has not expired and otherwise starts the cooldowns of all these nodes.
+func (c *Cooldowns) check(ctx *CommandContext, parsed []*ParsedCommandNode) error {
+	var nodes []CommandNode
+	for _, p := range parsed {
+		if p.Node.Cooldown() > 0 {
+			nodes = append(nodes, p.Node)
+		}
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+	if c == nil {
+		return ErrNoCooldowns
+	}
+	now := c.time()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, node := range nodes {
+		if expires, ok := c.expires[c.key(node, ctx)]; ok && now.Before(expires) {
+			return &CooldownError{Node: node, Remaining: expires.Sub(now)}
+		}
+	}
+	if c.expires == nil {
+		c.expires = map[cooldownKey]time.Time{}
+	}
+	for _, node := range nodes {
+		c.expires[c.key(node, ctx)] = now.Add(node.Cooldown())
+	}
+	return nil
+}
+
+// Remaining returns the duration until the cooldown of the node
+// expires for the source of ctx or zero if it has expired.
+func (c *Cooldowns) Remaining(ctx context.Context, node CommandNode) time.Duration {
+	key := c.key(node, ctx)
+	now := c.time()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expires, ok := c.expires[key]
+	if !ok {
+		return 0
+	}
+	if !now.Before(expires) {
+		delete(c.expires, key)
+		return 0
+	}
+	return expires.Sub(now)
+}
+
+// Reset expires the cooldown of the node for the source of ctx.
+func (c *Cooldowns) Reset(ctx context.Context, node CommandNode) {
+	key := c.key(node, ctx)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.expires, key)
+}
+
+// ResetAll expires all cooldowns.
+func (c *Cooldowns) ResetAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.expires = nil
+}
+
+// Prune forgets expired cooldowns, e.g. to be called periodically
+// to free the memory of sources that left.
+func (c *Cooldowns) Prune() {
+	now := c.time()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, expires := range c.expires {
+		if !now.Before(expires) {
+			delete(c.expires, key)
+		}
+	}
+}
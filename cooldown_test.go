@@ -0,0 +1,77 @@
+package brigodier
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestDispatcher_Execute_Cooldown(t *testing.T) {
+	now := time.Unix(0, 0)
+	cooldowns := NewCooldowns(nil)
+	cooldowns.now = func() time.Time { return now }
+	var (
+		d     = &Dispatcher{Cooldowns: cooldowns}
+		times int
+	)
+	cmd := CommandFunc(func(c *CommandContext) error { times++; return nil })
+	d.Register(Literal("heal").Cooldown(time.Minute).Executes(cmd))
+	heal := d.FindNode("heal")
+
+	alice := WithSource(context.TODO(), &testSource{name: "alice"})
+	bob := WithSource(context.TODO(), &testSource{name: "bob"})
+	require.NoError(t, d.Do(alice, "heal"))
+	require.NoError(t, d.Do(bob, "heal"))
+
+	now = now.Add(20 * time.Second)
+	err := d.Do(alice, "heal")
+	require.ErrorIs(t, err, ErrCooldown)
+	var cdErr *CooldownError
+	require.True(t, errors.As(err, &cdErr))
+	require.Equal(t, heal, cdErr.Node)
+	require.Equal(t, 40*time.Second, cdErr.Remaining)
+	require.Equal(t, 40*time.Second, cooldowns.Remaining(alice, heal))
+
+	cooldowns.Reset(alice, heal)
+	require.Zero(t, cooldowns.Remaining(alice, heal))
+	require.NoError(t, d.Do(alice, "heal"))
+
+	now = now.Add(40 * time.Second)
+	require.NoError(t, d.Do(bob, "heal"))
+	cooldowns.ResetAll()
+	require.NoError(t, d.Do(bob, "heal"))
+	require.Equal(t, 5, times)
+
+	now = now.Add(time.Hour)
+	cooldowns.Prune()
+	require.Empty(t, cooldowns.expires)
+}
+
+func TestDispatcher_Execute_CooldownRedirect(t *testing.T) {
+	var (
+		d     = &Dispatcher{Cooldowns: NewCooldowns(nil)}
+		times int
+	)
+	d.Register(Literal("say").Executes(CommandFunc(func(c *CommandContext) error { times++; return nil })))
+	execute := d.Register(Literal("execute").Cooldown(time.Hour))
+	execute.AddChild(Literal("run").Redirect(&d.Root).Build())
+
+	alice := WithSource(context.TODO(), &testSource{name: "alice"})
+	require.NoError(t, d.Do(alice, "execute run say"))
+	err := d.Do(alice, "execute run say")
+	require.ErrorIs(t, err, ErrCooldown)
+	var cdErr *CooldownError
+	require.True(t, errors.As(err, &cdErr))
+	require.Equal(t, execute, cdErr.Node)
+	require.NoError(t, d.Do(alice, "say"))
+	require.Equal(t, 2, times)
+}
+
+func TestDispatcher_Execute_CooldownWithoutCooldowns(t *testing.T) {
+	var d Dispatcher
+	d.Register(Literal("heal").Cooldown(time.Minute).Executes(CommandFunc(func(c *CommandContext) error { return nil })))
+	require.ErrorIs(t, d.Do(context.TODO(), "heal"), ErrNoCooldowns)
+	require.Equal(t, time.Minute, d.FindNode("heal").CreateBuilder().Build().Cooldown())
+}
@@ -0,0 +1,46 @@
+package brigodier
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDispatcher_Detach(t *testing.T) {
+	d := NewDispatcher(WithParseCache(8))
+	var banned string
+	d.Register(Literal("ban").Then(Argument("player", StringWord).Executes(CommandFunc(func(c *CommandContext) error {
+		banned = c.String("player")
+		return nil
+	}))))
+	d.Register(Literal("admin"))
+	require.NoError(t, d.Do(context.TODO(), "ban alice"))
+
+	ban, ok := d.Detach("ban")
+	require.True(t, ok)
+	require.Equal(t, "ban", ban.Name())
+	require.Nil(t, d.FindNode("ban"))
+	require.Error(t, d.Do(context.TODO(), "ban bob"))
+
+	d.FindNode("admin").AddChild(ban)
+	require.NoError(t, d.Do(context.TODO(), "admin ban bob"))
+	require.Equal(t, "bob", banned)
+
+	player, ok := d.Detach("admin", "ban", "player")
+	require.True(t, ok)
+	require.Equal(t, "player", player.Name())
+	require.Empty(t, ban.Children())
+
+	_, ok = d.Detach("admin", "missing")
+	require.False(t, ok)
+	_, ok = d.Detach("missing", "ban")
+	require.False(t, ok)
+	_, ok = d.Detach()
+	require.False(t, ok)
+
+	d.RegisterNamespaced("plugin", Literal("cmd"))
+	_, ok = d.Detach("cmd")
+	require.True(t, ok)
+	_, ok = d.Namespace("cmd")
+	require.False(t, ok)
+}
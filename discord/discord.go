@@ -0,0 +1,388 @@
+// Package discord converts a brigodier command tree to Discord application (slash) commands.
+//
+// Root literals become chat input commands and nested literals become subcommand
+// groups and subcommands. The arguments below a subcommand become its options,
+// where an argument is optional if the node before it is executable.
+// Sibling literals built using brigodier.Choices become a string option with choices.
+//
+// Discord only supports a subset of what a command tree can express. Structures
+// that can not be mapped, e.g. redirects, arguments branching into different
+// arguments or literals nested too deep, result in an *Error.
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"go.minekube.com/brigodier"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// OptionType is the type of an application command option.
+type OptionType int
+
+// Supported OptionType values.
+const (
+	OptionSubCommand      OptionType = 1
+	OptionSubCommandGroup OptionType = 2
+	OptionString          OptionType = 3
+	OptionInteger         OptionType = 4
+	OptionBoolean         OptionType = 5
+	OptionUser            OptionType = 6
+	OptionChannel         OptionType = 7
+	OptionRole            OptionType = 8
+	OptionMentionable     OptionType = 9
+	OptionNumber          OptionType = 10
+	OptionAttachment      OptionType = 11
+)
+
+// Limits of application commands enforced by Discord.
+const (
+	MaxOptions           = 25
+	MaxChoices           = 25
+	MaxNameLength        = 32
+	MaxDescriptionLength = 100
+)
+
+// ChatInputCommand is the type of slash commands.
+const ChatInputCommand = 1
+
+type (
+	// Command is an application command in the JSON format of the Discord API.
+	Command struct {
+		Type        int       `json:"type"`
+		Name        string    `json:"name"`
+		Description string    `json:"description"`
+		Options     []*Option `json:"options,omitempty"`
+	}
+	// Option is an application command option in the JSON format of the Discord API.
+	Option struct {
+		Type        OptionType `json:"type"`
+		Name        string     `json:"name"`
+		Description string     `json:"description"`
+		Required    bool       `json:"required,omitempty"`
+		Choices     []*Choice  `json:"choices,omitempty"`
+		Options     []*Option  `json:"options,omitempty"`
+		MinValue    *float64   `json:"min_value,omitempty"`
+		MaxValue    *float64   `json:"max_value,omitempty"`
+	}
+	// Choice is a choice of a string option.
+	Choice struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+)
+
+// Options are the options used by Commands.
+type Options struct {
+	// Description optionally returns the description of a command, subcommand or option.
+	// Discord requires a description, so if nil or empty the node name is used.
+	Description func(node brigodier.CommandNode) string
+	// OptionType optionally maps custom argument types to an option type.
+	// If it returns false, the builtin mapping is used.
+	OptionType func(t brigodier.ArgumentType) (OptionType, bool)
+}
+
+var (
+	// ErrRedirect indicates a redirecting node.
+	ErrRedirect = errors.New("redirects are not supported")
+	// ErrTooDeep indicates literals nested deeper than command, group and subcommand.
+	ErrTooDeep = errors.New("literals nested too deep")
+	// ErrBranching indicates a node with children that are not a single argument,
+	// choices or subcommands.
+	ErrBranching = errors.New("children can not be mapped to a single option")
+	// ErrMixedChildren indicates a node with both subcommands and arguments.
+	ErrMixedChildren = errors.New("subcommands can not be mixed with arguments")
+	// ErrNotExecutable indicates a leaf node without command.
+	ErrNotExecutable = errors.New("leaf node is not executable")
+	// ErrUnsupportedType indicates an argument type without option type.
+	ErrUnsupportedType = errors.New("unsupported argument type")
+	// ErrInvalidName indicates a name not allowed by Discord.
+	ErrInvalidName = errors.New("invalid name")
+	// ErrTooMany indicates more options, choices or subcommands than allowed by Discord.
+	ErrTooMany = errors.New("too many options")
+)
+
+// Error is returned by Commands if a node can not be mapped.
+type Error struct {
+	Path []string // The path to the node.
+	Err  error
+}
+
+// Unwrap implements errors.Unwrap.
+func (e *Error) Unwrap() error { return e.Err }
+func (e *Error) Error() string {
+	return fmt.Sprintf("discord: %q: %v", strings.Join(e.Path, " "), e.Err)
+}
+
+// Commands converts the root literals of the Dispatcher to application commands.
+func Commands(d *brigodier.Dispatcher, opts Options) ([]*Command, error) {
+	c := &converter{opts: opts}
+	var (
+		commands []*Command
+		err      error
+	)
+	d.Root.ChildrenOrdered().Range(func(_ string, node brigodier.CommandNode) bool {
+		var cmd *Command
+		if cmd, err = c.command(node); err == nil {
+			commands = append(commands, cmd)
+		}
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return commands, nil
+}
+
+type converter struct {
+	opts Options
+}
+
+func (c *converter) command(node brigodier.CommandNode) (*Command, error) {
+	path := []string{node.Name()}
+	if err := c.check(path, node); err != nil {
+		return nil, err
+	}
+	options, err := c.children(path, node, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &Command{
+		Type:        ChatInputCommand,
+		Name:        node.Name(),
+		Description: c.description(node),
+		Options:     options,
+	}, nil
+}
+
+// children converts the children of a command (depth 0), group (1) or subcommand (2).
+func (c *converter) children(path []string, node brigodier.CommandNode, depth int) ([]*Option, error) {
+	var subcommands, others []brigodier.CommandNode
+	node.ChildrenOrdered().Range(func(_ string, child brigodier.CommandNode) bool {
+		if isSubcommand(child) {
+			subcommands = append(subcommands, child)
+		} else {
+			others = append(others, child)
+		}
+		return true
+	})
+	if len(subcommands) == 0 || depth == 2 {
+		return c.options(path, node)
+	}
+	if len(others) != 0 {
+		return nil, &Error{Path: path, Err: ErrMixedChildren}
+	}
+	if node.Command() != nil {
+		return nil, &Error{Path: path, Err: fmt.Errorf("%w: executable node with subcommands", ErrMixedChildren)}
+	}
+	if len(subcommands) > MaxOptions {
+		return nil, &Error{Path: path, Err: ErrTooMany}
+	}
+	options := make([]*Option, 0, len(subcommands))
+	for _, sub := range subcommands {
+		subPath := append(path[:len(path):len(path)], sub.Name())
+		if err := c.check(subPath, sub); err != nil {
+			return nil, err
+		}
+		opt := &Option{Type: OptionSubCommand, Name: sub.Name(), Description: c.description(sub)}
+		if depth == 0 && hasSubcommands(sub) {
+			opt.Type = OptionSubCommandGroup
+		} else if depth == 1 && hasSubcommands(sub) {
+			return nil, &Error{Path: subPath, Err: ErrTooDeep}
+		}
+		var err error
+		if opt.Options, err = c.children(subPath, sub, depth+1); err != nil {
+			return nil, err
+		}
+		options = append(options, opt)
+	}
+	return options, nil
+}
+
+// options converts the chain of arguments below node to options.
+func (c *converter) options(path []string, node brigodier.CommandNode) ([]*Option, error) {
+	var options []*Option
+	for {
+		required := node.Command() == nil
+		var (
+			arguments []*brigodier.ArgumentCommandNode
+			choices   []*brigodier.LiteralCommandNode
+			next      brigodier.CommandNode
+		)
+		node.ChildrenOrdered().Range(func(_ string, child brigodier.CommandNode) bool {
+			switch t := child.(type) {
+			case *brigodier.ArgumentCommandNode:
+				arguments = append(arguments, t)
+			case *brigodier.LiteralCommandNode:
+				choices = append(choices, t)
+			}
+			return true
+		})
+		var opt *Option
+		switch {
+		case len(arguments) == 0 && len(choices) == 0:
+			if !required {
+				return options, nil
+			}
+			return nil, &Error{Path: path, Err: ErrNotExecutable}
+		case len(arguments) == 1 && len(choices) == 0:
+			next = arguments[0]
+			t, err := c.optionType(arguments[0].Type())
+			if err != nil {
+				return nil, &Error{Path: append(path, next.Name()), Err: err}
+			}
+			opt = &Option{Type: t, Name: next.Name(), Description: c.description(next)}
+			opt.MinValue, opt.MaxValue = bounds(arguments[0].Type())
+		case len(arguments) == 0:
+			var err error
+			if opt, err = c.choices(path, choices); err != nil {
+				return nil, err
+			}
+			next = choices[0]
+		default:
+			return nil, &Error{Path: path, Err: ErrBranching}
+		}
+		path = append(path[:len(path):len(path)], next.Name())
+		if err := c.check(path, next); err != nil {
+			return nil, err
+		}
+		opt.Required = required
+		options = append(options, opt)
+		if len(options) > MaxOptions {
+			return nil, &Error{Path: path, Err: ErrTooMany}
+		}
+		node = next
+	}
+}
+
+// choices converts sibling literals built by brigodier.Choices to a string option.
+func (c *converter) choices(path []string, literals []*brigodier.LiteralCommandNode) (*Option, error) {
+	name, _ := literals[0].Mapped()
+	if name == "" {
+		return nil, &Error{Path: path, Err: fmt.Errorf("%w: literal %q is not a mapped choice", ErrBranching, literals[0].Literal)}
+	}
+	if len(literals) > MaxChoices {
+		return nil, &Error{Path: path, Err: ErrTooMany}
+	}
+	first := childNames(literals[0])
+	opt := &Option{Type: OptionString, Name: name, Description: name}
+	if c.opts.Description != nil && c.opts.Description(literals[0]) != "" {
+		opt.Description = c.description(literals[0])
+	}
+	for _, l := range literals {
+		if n, _ := l.Mapped(); n != name {
+			return nil, &Error{Path: path, Err: fmt.Errorf("%w: choices of %q and %q", ErrBranching, name, n)}
+		}
+		if (l.Command() != nil) != (literals[0].Command() != nil) || childNames(l) != first {
+			return nil, &Error{Path: append(path, l.Literal), Err: fmt.Errorf("%w: choices continue differently", ErrBranching)}
+		}
+		opt.Choices = append(opt.Choices, &Choice{Name: l.Literal, Value: l.Literal})
+	}
+	return opt, nil
+}
+
+var validName = regexp.MustCompile(`^[-_\p{Ll}\p{Lo}\p{N}]{1,32}$`)
+
+// check checks a node before converting it.
+func (c *converter) check(path []string, node brigodier.CommandNode) error {
+	if node.Redirect() != nil {
+		return &Error{Path: path, Err: ErrRedirect}
+	}
+	if !validName.MatchString(node.Name()) {
+		return &Error{Path: path, Err: fmt.Errorf("%w %q", ErrInvalidName, node.Name())}
+	}
+	return nil
+}
+
+func (c *converter) description(node brigodier.CommandNode) string {
+	var desc string
+	if c.opts.Description != nil {
+		desc = c.opts.Description(node)
+	}
+	if desc == "" {
+		desc = node.Name()
+	}
+	if r := []rune(desc); len(r) > MaxDescriptionLength {
+		desc = string(r[:MaxDescriptionLength-1]) + "…"
+	}
+	return desc
+}
+
+func (c *converter) optionType(t brigodier.ArgumentType) (OptionType, error) {
+	if c.opts.OptionType != nil {
+		if o, ok := c.opts.OptionType(t); ok {
+			return o, nil
+		}
+	}
+	switch t.(type) {
+	case brigodier.StringType, *brigodier.WordArgumentType, *brigodier.StringLenArgumentType,
+		*brigodier.IdentifierArgumentType, *brigodier.BytesArgumentType,
+		*brigodier.PlayerArgumentType, *brigodier.ColorArgumentType, *brigodier.PathArgumentType:
+		return OptionString, nil
+	case *brigodier.BoolArgumentType:
+		return OptionBoolean, nil
+	case *brigodier.Int32ArgumentType, *brigodier.Int64ArgumentType,
+		*brigodier.Uint32ArgumentType, *brigodier.Uint64ArgumentType:
+		return OptionInteger, nil
+	case *brigodier.Float32ArgumentType, *brigodier.Float64ArgumentType:
+		return OptionNumber, nil
+	}
+	return 0, fmt.Errorf("%w %s", ErrUnsupportedType, t)
+}
+
+// maxSafeInteger is the bound of integers representable by Discord (a JavaScript number).
+const maxSafeInteger = 1<<53 - 1
+
+// bounds returns the min and max values of numeric argument types
+// or nil if they are not narrower than what Discord supports.
+func bounds(t brigodier.ArgumentType) (min, max *float64) {
+	var lo, hi float64
+	switch t := t.(type) {
+	case *brigodier.Int32ArgumentType:
+		lo, hi = float64(t.Min), float64(t.Max)
+	case *brigodier.Int64ArgumentType:
+		lo, hi = float64(t.Min), float64(t.Max)
+	case *brigodier.Uint32ArgumentType:
+		lo, hi = float64(t.Min), float64(t.Max)
+	case *brigodier.Uint64ArgumentType:
+		lo, hi = float64(t.Min), float64(t.Max)
+	case *brigodier.Float32ArgumentType:
+		lo, hi = float64(t.Min), float64(t.Max)
+	case *brigodier.Float64ArgumentType:
+		lo, hi = t.Min, t.Max
+	default:
+		return nil, nil
+	}
+	if lo > -maxSafeInteger && !math.IsInf(lo, 0) {
+		min = &lo
+	}
+	if hi < maxSafeInteger && !math.IsInf(hi, 0) {
+		max = &hi
+	}
+	return min, max
+}
+
+// isSubcommand reports whether node is a literal that is not a mapped choice.
+func isSubcommand(node brigodier.CommandNode) bool {
+	l, ok := node.(*brigodier.LiteralCommandNode)
+	if !ok {
+		return false
+	}
+	name, _ := l.Mapped()
+	return name == ""
+}
+
+// hasSubcommands reports whether any child of node is a subcommand.
+func hasSubcommands(node brigodier.CommandNode) (ok bool) {
+	node.ChildrenOrdered().Range(func(_ string, child brigodier.CommandNode) bool {
+		ok = isSubcommand(child)
+		return !ok
+	})
+	return ok
+}
+
+func childNames(node brigodier.CommandNode) string {
+	return strings.Join(node.ChildrenOrdered().Keys(), " ")
+}
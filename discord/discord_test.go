@@ -0,0 +1,112 @@
+package discord
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"go.minekube.com/brigodier"
+	"strings"
+	"testing"
+)
+
+var cmd = brigodier.CommandFunc(func(*brigodier.CommandContext) error { return nil })
+
+func TestCommands(t *testing.T) {
+	d := new(brigodier.Dispatcher)
+	d.Register(brigodier.Literal("ban").Then(
+		brigodier.Argument("player", &brigodier.PlayerArgumentType{}).Executes(cmd).Then(
+			brigodier.Argument("days", &brigodier.Int32ArgumentType{Min: 1, Max: 365}).Executes(cmd),
+		),
+	))
+	d.Register(brigodier.Literal("config").
+		Then(brigodier.Literal("pvp").Then(brigodier.Argument("enabled", brigodier.Bool).Executes(cmd))).
+		Then(brigodier.Literal("mode").Then(brigodier.Choices("mode", map[string]int{
+			"easy": 0,
+			"hard": 1,
+		}).Executes(cmd).Builders()...)),
+	)
+	d.Register(brigodier.Literal("team").
+		Then(brigodier.Literal("member").
+			Then(brigodier.Literal("add").Then(brigodier.Argument("name", brigodier.StringWord).Executes(cmd))).
+			Then(brigodier.Literal("list").Executes(cmd))))
+
+	descriptions := map[string]string{"ban": "Bans a player."}
+	commands, err := Commands(d, Options{Description: func(n brigodier.CommandNode) string {
+		return descriptions[n.Name()]
+	}})
+	require.NoError(t, err)
+
+	b, err := json.Marshal(commands)
+	require.NoError(t, err)
+	require.JSONEq(t, `[
+		{"type":1,"name":"ban","description":"Bans a player.","options":[
+			{"type":3,"name":"player","description":"player","required":true},
+			{"type":4,"name":"days","description":"days","min_value":1,"max_value":365}
+		]},
+		{"type":1,"name":"config","description":"config","options":[
+			{"type":1,"name":"pvp","description":"pvp","options":[
+				{"type":5,"name":"enabled","description":"enabled","required":true}
+			]},
+			{"type":1,"name":"mode","description":"mode","options":[
+				{"type":3,"name":"mode","description":"mode","required":true,"choices":[
+					{"name":"easy","value":"easy"},{"name":"hard","value":"hard"}
+				]}
+			]}
+		]},
+		{"type":1,"name":"team","description":"team","options":[
+			{"type":2,"name":"member","description":"member","options":[
+				{"type":1,"name":"add","description":"add","options":[
+					{"type":3,"name":"name","description":"name","required":true}
+				]},
+				{"type":1,"name":"list","description":"list"}
+			]}
+		]}
+	]`, string(b))
+}
+
+func TestCommands_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(d *brigodier.Dispatcher)
+		err      error
+		path     string
+	}{
+		{"redirect", func(d *brigodier.Dispatcher) {
+			d.Register(brigodier.Literal("alias").Redirect(&d.Root))
+		}, ErrRedirect, "alias"},
+		{"too deep", func(d *brigodier.Dispatcher) {
+			d.Register(brigodier.Literal("a").Then(brigodier.Literal("b").Then(
+				brigodier.Literal("c").Then(brigodier.Literal("d").Executes(cmd)))))
+		}, ErrTooDeep, "a b c"},
+		{"mixed", func(d *brigodier.Dispatcher) {
+			d.Register(brigodier.Literal("a").
+				Then(brigodier.Literal("b").Executes(cmd)).
+				Then(brigodier.Argument("c", brigodier.Bool).Executes(cmd)))
+		}, ErrMixedChildren, "a"},
+		{"branching", func(d *brigodier.Dispatcher) {
+			d.Register(brigodier.Literal("a").
+				Then(brigodier.Argument("b", brigodier.Bool).Executes(cmd)).
+				Then(brigodier.Argument("c", brigodier.Int).Executes(cmd)))
+		}, ErrBranching, "a"},
+		{"not executable", func(d *brigodier.Dispatcher) {
+			d.Register(brigodier.Literal("a").Then(brigodier.Argument("b", brigodier.Bool)))
+		}, ErrNotExecutable, "a b"},
+		{"invalid name", func(d *brigodier.Dispatcher) {
+			d.Register(brigodier.Literal("Upper").Executes(cmd))
+		}, ErrInvalidName, "Upper"},
+		{"unsupported type", func(d *brigodier.Dispatcher) {
+			d.Register(brigodier.Literal("a").Then(brigodier.Argument("b", brigodier.ListOf(brigodier.Int, ',')).Executes(cmd)))
+		}, ErrUnsupportedType, "a b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := new(brigodier.Dispatcher)
+			tt.register(d)
+			_, err := Commands(d, Options{})
+			require.ErrorIs(t, err, tt.err)
+			var e *Error
+			require.True(t, errors.As(err, &e))
+			require.Equal(t, tt.path, strings.Join(e.Path, " "))
+		})
+	}
+}
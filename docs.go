@@ -0,0 +1,212 @@
+package brigodier
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// DocsFormat is the output format used by Dispatcher.ExportDocs.
+type DocsFormat uint8
+
+// Supported DocsFormat values.
+const (
+	DocsMarkdown DocsFormat = iota
+	DocsHTML
+)
+
+// DocsOptions are the options used by Dispatcher.ExportDocs.
+type DocsOptions struct {
+	Format DocsFormat
+	// Title is the optional title of the document.
+	Title string
+	// Context optionally restricts the documented commands to the nodes it can use.
+	// If nil, all commands are documented.
+	Context context.Context
+	// Description optionally returns the description of a command or argument node.
+	Description func(node CommandNode) string
+	// Permission optionally returns the permission string required to use a node.
+	Permission func(node CommandNode) string
+}
+
+// ExportDocs writes reference documentation of the commands registered on the Dispatcher to w.
+//
+// The documentation contains one section per root command with its SmartUsage,
+// its description, permission and deprecation and a table of all its arguments
+// with their name, type and bounds.
+func (d *Dispatcher) ExportDocs(w io.Writer, opts DocsOptions) error {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	canUse := func(n CommandNode) bool { return opts.Context == nil || n.CanUse(opts.Context) }
+	text := func(fn func(CommandNode) string, n CommandNode) string {
+		if fn == nil {
+			return ""
+		}
+		return fn(n)
+	}
+
+	var sections []docsSection
+	d.Root.ChildrenOrdered().Range(func(_ string, node CommandNode) bool {
+		if !canUse(node) {
+			return true
+		}
+		s := docsSection{
+			Name:        node.Name(),
+			Description: text(opts.Description, node),
+			Permission:  text(opts.Permission, node),
+			Deprecated:  node.Deprecated(),
+		}
+		if s.Usage = d.smartUsage(ctx, node, false, false); s.Usage == "" {
+			s.Usage = node.UsageText()
+		}
+		seen := map[CommandNode]bool{}
+		var walk func(n CommandNode)
+		walk = func(n CommandNode) {
+			n.ChildrenOrdered().Range(func(_ string, child CommandNode) bool {
+				if seen[child] || !canUse(child) {
+					return true
+				}
+				seen[child] = true
+				if arg, ok := child.(*ArgumentCommandNode); ok {
+					s.Arguments = append(s.Arguments, docsArgument{
+						Name:        arg.Name(),
+						Type:        arg.Type().String(),
+						Bounds:      typeBounds(arg.Type()),
+						Description: text(opts.Description, arg),
+						Permission:  text(opts.Permission, arg),
+						Deprecated:  arg.Deprecated(),
+					})
+				}
+				walk(child)
+				return true
+			})
+		}
+		walk(node)
+		sections = append(sections, s)
+		return true
+	})
+
+	var b strings.Builder
+	switch opts.Format {
+	case DocsMarkdown:
+		writeMarkdownDocs(&b, opts.Title, sections)
+	case DocsHTML:
+		writeHTMLDocs(&b, opts.Title, sections)
+	default:
+		return fmt.Errorf("unknown docs format %d", opts.Format)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type docsSection struct {
+	Name, Usage, Description, Permission, Deprecated string
+	Arguments                                        []docsArgument
+}
+
+type docsArgument struct {
+	Name, Type, Bounds, Description, Permission, Deprecated string
+}
+
+// description returns the description of the argument including its deprecation.
+func (a *docsArgument) description() string {
+	if a.Deprecated == "" {
+		return a.Description
+	}
+	return strings.TrimSpace("Deprecated: " + a.Deprecated + ". " + a.Description)
+}
+
+// typeBounds returns the bounds of a numeric builtin argument type
+// in interval notation or an empty string if it is not bounded.
+func typeBounds(t ArgumentType) string {
+	if min, max, ok := typeRange(t); ok {
+		return fmt.Sprintf("[%v, %v]", min, max)
+	}
+	return ""
+}
+
+// typeRange returns the bounds of a numeric builtin argument type
+// and whether they differ from the bounds of the underlying Go type.
+func typeRange(t ArgumentType) (min, max interface{}, ok bool) {
+	format := func(min, max, typeMin, typeMax interface{}) (interface{}, interface{}, bool) {
+		return min, max, min != typeMin || max != typeMax
+	}
+	switch t := t.(type) {
+	case *Int32ArgumentType:
+		return format(t.Min, t.Max, int32(MinInt32), int32(MaxInt32))
+	case *Int64ArgumentType:
+		return format(t.Min, t.Max, int64(MinInt64), int64(MaxInt64))
+	case *Uint32ArgumentType:
+		return format(t.Min, t.Max, uint32(MinUint32), uint32(MaxUint32))
+	case *Uint64ArgumentType:
+		return format(t.Min, t.Max, uint64(MinUint64), uint64(MaxUint64))
+	case *Float32ArgumentType:
+		return format(t.Min, t.Max, float32(MinFloat32), float32(MaxFloat32))
+	case *Float64ArgumentType:
+		return format(t.Min, t.Max, float64(MinFloat64), float64(MaxFloat64))
+	}
+	return nil, nil, false
+}
+
+func writeMarkdownDocs(b *strings.Builder, title string, sections []docsSection) {
+	cell := strings.NewReplacer("|", `\|`, "\n", " ").Replace
+	if title != "" {
+		fmt.Fprintf(b, "# %s\n\n", title)
+	}
+	for _, s := range sections {
+		fmt.Fprintf(b, "## %s\n\n", s.Name)
+		if s.Deprecated != "" {
+			fmt.Fprintf(b, "**Deprecated:** %s\n\n", s.Deprecated)
+		}
+		if s.Description != "" {
+			fmt.Fprintf(b, "%s\n\n", s.Description)
+		}
+		if s.Permission != "" {
+			fmt.Fprintf(b, "Permission: `%s`\n\n", s.Permission)
+		}
+		fmt.Fprintf(b, "```\n%s\n```\n\n", s.Usage)
+		if len(s.Arguments) == 0 {
+			continue
+		}
+		b.WriteString("| Argument | Type | Bounds | Description | Permission |\n")
+		b.WriteString("|---|---|---|---|---|\n")
+		for _, a := range s.Arguments {
+			fmt.Fprintf(b, "| `%s` | `%s` | %s | %s | %s |\n", cell(a.Name), cell(a.Type),
+				cell(a.Bounds), cell(a.description()), cell(a.Permission))
+		}
+		b.WriteString("\n")
+	}
+}
+
+func writeHTMLDocs(b *strings.Builder, title string, sections []docsSection) {
+	esc := html.EscapeString
+	if title != "" {
+		fmt.Fprintf(b, "<h1>%s</h1>\n", esc(title))
+	}
+	for _, s := range sections {
+		fmt.Fprintf(b, "<section id=\"%s\">\n<h2>%s</h2>\n", esc(s.Name), esc(s.Name))
+		if s.Deprecated != "" {
+			fmt.Fprintf(b, "<p><strong>Deprecated:</strong> %s</p>\n", esc(s.Deprecated))
+		}
+		if s.Description != "" {
+			fmt.Fprintf(b, "<p>%s</p>\n", esc(s.Description))
+		}
+		if s.Permission != "" {
+			fmt.Fprintf(b, "<p>Permission: <code>%s</code></p>\n", esc(s.Permission))
+		}
+		fmt.Fprintf(b, "<pre>%s</pre>\n", esc(s.Usage))
+		if len(s.Arguments) != 0 {
+			b.WriteString("<table>\n<tr><th>Argument</th><th>Type</th><th>Bounds</th><th>Description</th><th>Permission</th></tr>\n")
+			for _, a := range s.Arguments {
+				fmt.Fprintf(b, "<tr><td><code>%s</code></td><td><code>%s</code></td><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+					esc(a.Name), esc(a.Type), esc(a.Bounds), esc(a.description()), esc(a.Permission))
+			}
+			b.WriteString("</table>\n")
+		}
+		b.WriteString("</section>\n")
+	}
+}
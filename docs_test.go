@@ -0,0 +1,49 @@
+package brigodier
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestDispatcher_ExportDocs(t *testing.T) {
+	var d Dispatcher
+	cmd := CommandFunc(func(c *CommandContext) error { return nil })
+	d.Register(Literal("give").Then(
+		Argument("item", StringWord).Then(
+			Argument("count", &Int32ArgumentType{Min: 1, Max: 64}).Executes(cmd),
+		).Executes(cmd),
+	))
+	d.Register(Literal("help").Executes(cmd).Deprecated("use /info instead"))
+
+	descriptions := map[string]string{"give": "Gives an item.", "count": "The | stack size."}
+	opts := DocsOptions{
+		Title:       "Commands",
+		Description: func(n CommandNode) string { return descriptions[n.Name()] },
+		Permission: func(n CommandNode) string {
+			if n.Name() == "give" {
+				return "server.give"
+			}
+			return ""
+		},
+	}
+
+	var b strings.Builder
+	require.NoError(t, d.ExportDocs(&b, opts))
+	require.Equal(t, "# Commands\n\n"+
+		"## give\n\nGives an item.\n\nPermission: `server.give`\n\n"+
+		"```\ngive [item] [[count]]\n```\n\n"+
+		"| Argument | Type | Bounds | Description | Permission |\n"+
+		"|---|---|---|---|---|\n"+
+		"| `item` | `string` |  |  |  |\n"+
+		"| `count` | `int32` | [1, 64] | The \\| stack size. |  |\n\n"+
+		"## help\n\n**Deprecated:** use /info instead\n\n```\nhelp\n```\n\n", b.String())
+
+	b.Reset()
+	opts.Format = DocsHTML
+	require.NoError(t, d.ExportDocs(&b, opts))
+	require.Contains(t, b.String(), "<h1>Commands</h1>")
+	require.Contains(t, b.String(), "<pre>give [item] [[count]]</pre>")
+	require.Contains(t, b.String(), "<td>[1, 64]</td>")
+	require.Contains(t, b.String(), "<p><strong>Deprecated:</strong> use /info instead</p>")
+}
@@ -0,0 +1,121 @@
+package brigodier
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteDOT writes the command tree below node to w in the Graphviz DOT language.
+//
+// Literals are drawn as boxes, arguments as ellipses and executable nodes with a double border.
+// Redirects are drawn as dashed edges to their target and forks as dotted edges.
+//
+// If parse is not nil, the nodes matched by parse are highlighted and every redirect hop
+// and fork expansion taken for the parsed input is drawn as a bold edge labeled with
+// the hop number and the input range of the child context it continued with.
+// This makes chains of redirects like "execute as ... run ..." debuggable visually.
+func (d *Dispatcher) WriteDOT(w io.Writer, node CommandNode, parse *ParseResults) error {
+	g := &dotGraph{ids: map[CommandNode]int{}}
+	g.WriteString("digraph commands {\n")
+	g.WriteString("\tnode [fontname=monospace];\n")
+	g.walk(node)
+	if parse != nil {
+		g.annotate(parse)
+	}
+	g.WriteString("}\n")
+	_, err := io.WriteString(w, g.String())
+	return err
+}
+
+type dotGraph struct {
+	strings.Builder
+	ids map[CommandNode]int
+}
+
+// id returns the DOT node id of n and whether it was already declared.
+func (g *dotGraph) id(n CommandNode) (string, bool) {
+	id, ok := g.ids[n]
+	if !ok {
+		id = len(g.ids)
+		g.ids[n] = id
+	}
+	return fmt.Sprintf("n%d", id), ok
+}
+
+func (g *dotGraph) walk(n CommandNode) string {
+	id, declared := g.id(n)
+	if declared {
+		return id
+	}
+	attrs := []string{"label=" + dotQuote(dotLabel(n))}
+	switch t := n.(type) {
+	case *RootCommandNode:
+		attrs = append(attrs, "shape=point")
+	case *LiteralCommandNode:
+		attrs = append(attrs, "shape=box")
+	case *ArgumentCommandNode:
+		attrs = append(attrs, "shape=ellipse", "tooltip="+dotQuote(t.Type().String()))
+	}
+	if n.Command() != nil {
+		attrs = append(attrs, "peripheries=2")
+	}
+	fmt.Fprintf(g, "\t%s [%s];\n", id, strings.Join(attrs, " "))
+
+	n.ChildrenOrdered().Range(func(_ string, child CommandNode) bool {
+		fmt.Fprintf(g, "\t%s -> %s;\n", id, g.walk(child))
+		return true
+	})
+	if n.Redirect() != nil {
+		style, label := "dashed", "redirect"
+		if n.IsFork() {
+			style, label = "dotted", "fork"
+		}
+		if n.RedirectModifier() != nil {
+			label += "*"
+		}
+		fmt.Fprintf(g, "\t%s -> %s [style=%s label=%s];\n", id, g.walk(n.Redirect()), style, dotQuote(label))
+	}
+	return id
+}
+
+// annotate highlights the nodes and redirect hops taken by the parsed input.
+func (g *dotGraph) annotate(parse *ParseResults) {
+	input := parse.Reader.String
+	hop := 0
+	for c := parse.Context; c != nil; c = c.Child {
+		prev := c.RootNode
+		for _, parsed := range c.Nodes {
+			id := g.walk(parsed.Node)
+			fmt.Fprintf(g, "\t%s [style=filled fillcolor=lightblue xlabel=%s];\n",
+				id, dotQuote(parsed.Range.Get(input)))
+			if prev != nil {
+				fmt.Fprintf(g, "\t%s -> %s [color=blue penwidth=2];\n", g.walk(prev), id)
+			}
+			prev = parsed.Node
+		}
+		if c.Child == nil || len(c.Nodes) == 0 {
+			continue
+		}
+		hop++
+		kind := "redirect"
+		if c.Forks {
+			kind = "fork"
+		}
+		last := c.Nodes[len(c.Nodes)-1].Node
+		fmt.Fprintf(g, "\t%s -> %s [color=red penwidth=2 style=bold label=%s];\n",
+			g.walk(last), g.walk(c.Child.RootNode),
+			dotQuote(fmt.Sprintf("#%d %s %d:%d", hop, kind, c.Child.Range.Start, c.Child.Range.End)))
+	}
+}
+
+func dotLabel(n CommandNode) string {
+	if _, ok := n.(*RootCommandNode); ok {
+		return "<root>"
+	}
+	return n.UsageText()
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
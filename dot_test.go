@@ -0,0 +1,46 @@
+package brigodier
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDispatcher_WriteDOT(t *testing.T) {
+	var d Dispatcher
+	cmd := CommandFunc(func(c *CommandContext) error { return nil })
+	d.Register(Literal("foo").Then(Argument("bar", Int).Executes(cmd)))
+	d.Register(Literal("redirect").Redirect(&d.Root))
+
+	b := new(bytes.Buffer)
+	require.NoError(t, d.WriteDOT(b, &d.Root, nil))
+	require.Equal(t, `digraph commands {
+	node [fontname=monospace];
+	n0 [label="<root>" shape=point];
+	n1 [label="foo" shape=box];
+	n2 [label="[bar]" shape=ellipse tooltip="int32" peripheries=2];
+	n1 -> n2;
+	n0 -> n1;
+	n3 [label="redirect" shape=box];
+	n3 -> n0 [style=dashed label="redirect"];
+	n0 -> n3;
+}
+`, b.String())
+}
+
+func TestDispatcher_WriteDOT_Hops(t *testing.T) {
+	var d Dispatcher
+	cmd := CommandFunc(func(c *CommandContext) error { return nil })
+	mod := ModifierFunc(func(c *CommandContext) (context.Context, error) { return c, nil })
+	d.Register(Literal("actual").Executes(cmd))
+	d.Register(Literal("redirected").Redirect(&d.Root))
+	d.Register(Literal("forked").Fork(&d.Root, mod))
+
+	b := new(bytes.Buffer)
+	parse := d.Parse(context.TODO(), "redirected forked actual")
+	require.NoError(t, d.WriteDOT(b, &d.Root, parse))
+	require.Contains(t, b.String(), `n2 -> n0 [color=red penwidth=2 style=bold label="#1 redirect 11:17"];`)
+	require.Contains(t, b.String(), `n3 -> n0 [color=red penwidth=2 style=bold label="#2 fork 18:24"];`)
+	require.Contains(t, b.String(), `n1 [style=filled fillcolor=lightblue xlabel="actual"];`)
+}
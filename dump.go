@@ -0,0 +1,134 @@
+package brigodier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/emirpasic/gods/maps/linkedhashmap"
+	"io"
+)
+
+// DumpFormat is the output format used by Dispatcher.Dump.
+type DumpFormat uint8
+
+// Supported DumpFormat values.
+const (
+	DumpSmartUsage DumpFormat = iota // One Dispatcher.SmartUsage line per child node.
+	DumpAllUsage                     // One Dispatcher.AllUsage line per executable command.
+	DumpJSON                         // The Brigadier-style JSON tree.
+)
+
+func (f DumpFormat) String() string {
+	switch f {
+	case DumpSmartUsage:
+		return "usage"
+	case DumpAllUsage:
+		return "all"
+	case DumpJSON:
+		return "json"
+	}
+	return fmt.Sprintf("DumpFormat(%d)", uint8(f))
+}
+
+// Dump writes the command tree below node to w in the given format.
+//
+// The output is always restricted to the nodes the given context.Context can use,
+// so it is safe to show it to the subject that requested the dump.
+func (d *Dispatcher) Dump(ctx context.Context, w io.Writer, node CommandNode, format DumpFormat) error {
+	switch format {
+	case DumpSmartUsage:
+		var err error
+		d.SmartUsage(ctx, node).Range(func(_ CommandNode, usage string) bool {
+			_, err = fmt.Fprintln(w, usage)
+			return err == nil
+		})
+		return err
+	case DumpAllUsage:
+		for _, usage := range d.AllUsage(ctx, node, true) {
+			if _, err := fmt.Fprintln(w, usage); err != nil {
+				return err
+			}
+		}
+		return nil
+	case DumpJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(d.dumpJSON(ctx, node))
+	}
+	return fmt.Errorf("unknown dump format %s", format)
+}
+
+// DumpSink returns the io.Writer the output of a DumpCommand is written to.
+// It allows writing the dump back to the subject that executed the command.
+type DumpSink func(c *CommandContext) io.Writer
+
+// DumpCommand returns a builder for a command that dumps the live command tree to sink
+// so that the tree can be inspected without access to the code that registered it.
+//
+// The resulting command accepts an optional format literal ("usage", "all" or "json")
+// and defaults to DumpSmartUsage. The dump is restricted to the nodes the executing
+// CommandContext can use. Use Requires on the returned builder to restrict
+// who may execute the command at all.
+//
+//	d.Register(Literal("commands").Then(d.DumpCommand("dump", sink)))
+func (d *Dispatcher) DumpCommand(literal string, sink DumpSink) LiteralNodeBuilder {
+	dump := func(format DumpFormat) Command {
+		return CommandFunc(func(c *CommandContext) error {
+			return d.Dump(c, sink(c), &d.Root, format)
+		})
+	}
+	return Literal(literal).Executes(dump(DumpSmartUsage)).Then(
+		Literal(DumpSmartUsage.String()).Executes(dump(DumpSmartUsage)),
+		Literal(DumpAllUsage.String()).Executes(dump(DumpAllUsage)),
+		Literal(DumpJSON.String()).Executes(dump(DumpJSON)),
+	)
+}
+
+// Node types used in the JSON dump.
+const (
+	nodeTypeRoot     = "root"
+	nodeTypeLiteral  = "literal"
+	nodeTypeArgument = "argument"
+)
+
+// nodeJSON is the JSON representation of a CommandNode.
+type nodeJSON struct {
+	Type       string         `json:"type"`
+	Children   *orderedNodes  `json:"children,omitempty"`
+	Executable bool           `json:"executable,omitempty"`
+	Redirect   []string       `json:"redirect,omitempty"`
+	Parser     string         `json:"parser,omitempty"`
+	Properties TypeProperties `json:"properties,omitempty"`
+}
+
+// orderedNodes keeps the children of a nodeJSON in registration order.
+type orderedNodes struct{ *linkedhashmap.Map }
+
+func (m *orderedNodes) MarshalJSON() ([]byte, error) { return m.Map.ToJSON() }
+
+func (d *Dispatcher) dumpJSON(ctx context.Context, node CommandNode) *nodeJSON {
+	result := &nodeJSON{Executable: node.Command() != nil}
+	switch t := node.(type) {
+	case *RootCommandNode:
+		result.Type = nodeTypeRoot
+	case *LiteralCommandNode:
+		result.Type = nodeTypeLiteral
+	case *ArgumentCommandNode:
+		result.Type = nodeTypeArgument
+		result.Parser, result.Properties = d.types().parserOf(t)
+	}
+	node.ChildrenOrdered().Range(func(name string, child CommandNode) bool {
+		if !child.CanUse(ctx) {
+			return true
+		}
+		if result.Children == nil {
+			result.Children = &orderedNodes{linkedhashmap.New()}
+		}
+		result.Children.Put(name, d.dumpJSON(ctx, child))
+		return true
+	})
+	if node.Redirect() != nil {
+		result.Redirect = d.Path(node.Redirect())
+	}
+	return result
+}
@@ -0,0 +1,65 @@
+package brigodier
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/require"
+	"io"
+	"testing"
+)
+
+func TestDispatcher_Dump_SmartUsage(t *testing.T) {
+	d := new(Dispatcher)
+	setupUsage(d)
+
+	b := new(bytes.Buffer)
+	require.NoError(t, d.Dump(context.TODO(), b, &d.Root, DumpSmartUsage))
+	require.Equal(t, "a (1|2)\nb 1\nc\ne [1]\nf (1|2)\ng [1]\nh [1|2|3]\ni [1|2]\nj ...\nk -> h\n", b.String())
+}
+
+func TestDispatcher_Dump_JSON(t *testing.T) {
+	var d Dispatcher
+	cmd := CommandFunc(func(c *CommandContext) error { return nil })
+	foo := d.Register(Literal("foo").Then(
+		Argument("bar", Int).Executes(cmd),
+		Literal("baz").Then(Argument("msg", StringPhrase).Executes(cmd)),
+		Literal("qux").Then(Argument("n", &Int32ArgumentType{Min: 0, Max: MaxInt32}).Executes(cmd)),
+	))
+	d.Register(Literal("hidden").Requires(func(context.Context) bool { return false }).Executes(cmd))
+	d.Register(Literal("redirect").Redirect(foo))
+
+	b := new(bytes.Buffer)
+	require.NoError(t, d.Dump(context.TODO(), b, &d.Root, DumpJSON))
+	require.JSONEq(t, `{
+  "type": "root",
+  "children": {
+    "foo": {
+      "type": "literal",
+      "children": {
+        "bar": {"type": "argument", "executable": true, "parser": "brigadier:integer"},
+        "baz": {"type": "literal", "children": {
+          "msg": {"type": "argument", "executable": true, "parser": "brigadier:string", "properties": {"type": "greedy"}}
+        }},
+        "qux": {"type": "literal", "children": {
+          "n": {"type": "argument", "executable": true, "parser": "brigadier:integer", "properties": {"min": 0}}
+        }}
+      }
+    },
+    "redirect": {"type": "literal", "redirect": ["foo"]}
+  }
+}`, b.String())
+}
+
+func TestDispatcher_DumpCommand(t *testing.T) {
+	var d Dispatcher
+	b := new(bytes.Buffer)
+	sink := func(*CommandContext) io.Writer { return b }
+	d.Register(Literal("commands").Then(d.DumpCommand("dump", sink)))
+
+	require.NoError(t, d.Do(context.TODO(), "commands dump"))
+	require.Equal(t, "commands dump [usage|all|json]\n", b.String())
+
+	b.Reset()
+	require.NoError(t, d.Do(context.TODO(), "commands dump all"))
+	require.Equal(t, "commands dump\ncommands dump usage\ncommands dump all\ncommands dump json\n", b.String())
+}
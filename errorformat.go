@@ -0,0 +1,80 @@
+package brigodier
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultErrorContext is the default of FormatErrorOptions.Context.
+const DefaultErrorContext = 10
+
+// FormatErrorOptions configures FormatError.
+type FormatErrorOptions struct {
+	// Context is the number of runes of the input shown before and after
+	// the cursor. Zero uses DefaultErrorContext and a negative value shows the whole input.
+	Context int
+	// Ellipsis marks truncated input, "..." if empty.
+	Ellipsis string
+	// Caret marks the cursor below the input, "^" if empty.
+	Caret string
+}
+
+// FormatError renders an error for a console or chat. If err wraps a *ReaderError,
+// e.g. a *CommandSyntaxError returned by Dispatcher.Execute, the error message is
+// followed by the input around the cursor of the error and a caret below the cursor:
+//
+//	expected integer
+//	...tp alice x
+//	            ^
+//
+// Other errors are rendered as their message and nil as an empty string.
+func FormatError(err error, opts FormatErrorOptions) string {
+	if err == nil {
+		return ""
+	}
+	var readerErr *ReaderError
+	if !errors.As(err, &readerErr) || readerErr.Reader == nil {
+		return err.Error()
+	}
+	if opts.Context == 0 {
+		opts.Context = DefaultErrorContext
+	}
+	if opts.Ellipsis == "" {
+		opts.Ellipsis = "..."
+	}
+	if opts.Caret == "" {
+		opts.Caret = "^"
+	}
+
+	input := readerErr.Reader.String
+	cursor := readerErr.Reader.Cursor
+	if cursor < 0 {
+		cursor = 0
+	} else if cursor > len(input) {
+		cursor = len(input)
+	}
+	before, after := []rune(input[:cursor]), []rune(input[cursor:])
+
+	b := new(strings.Builder)
+	b.WriteString(err.Error())
+	b.WriteByte('\n')
+	column := 0
+	if opts.Context > 0 && len(before) > opts.Context {
+		before = before[len(before)-opts.Context:]
+		b.WriteString(opts.Ellipsis)
+		column += utf8.RuneCountInString(opts.Ellipsis)
+	}
+	column += len(before)
+	b.WriteString(string(before))
+	if opts.Context > 0 && len(after) > opts.Context {
+		b.WriteString(string(after[:opts.Context]))
+		b.WriteString(opts.Ellipsis)
+	} else {
+		b.WriteString(string(after))
+	}
+	b.WriteByte('\n')
+	b.WriteString(strings.Repeat(" ", column))
+	b.WriteString(opts.Caret)
+	return b.String()
+}
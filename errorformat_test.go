@@ -0,0 +1,34 @@
+package brigodier
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestFormatError(t *testing.T) {
+	var d Dispatcher
+	d.Register(Literal("teleport").Then(Argument("target", StringWord).Then(Argument("y", Int))))
+
+	err := d.Do(context.TODO(), "teleport alice x")
+	require.Error(t, err)
+	require.Equal(t, err.Error()+"\n"+
+		"...ort alice x\n"+
+		"             ^", FormatError(err, FormatErrorOptions{}))
+	require.Equal(t, err.Error()+"\n"+
+		"teleport alice x\n"+
+		"               ^", FormatError(err, FormatErrorOptions{Context: -1}))
+	require.Equal(t, err.Error()+"\n"+
+		"…ice x\n"+
+		"     ^^^", FormatError(err, FormatErrorOptions{Context: 4, Ellipsis: "…", Caret: "^^^"}))
+
+	err = &CommandSyntaxError{Err: &ReaderError{
+		Err:    errors.New("bad"),
+		Reader: &StringReader{String: "säy hello world", Cursor: 3},
+	}}
+	require.Equal(t, "bad\nsäy ...\n  ^", FormatError(err, FormatErrorOptions{Context: 2}))
+
+	require.Equal(t, "plain", FormatError(errors.New("plain"), FormatErrorOptions{}))
+	require.Empty(t, FormatError(nil, FormatErrorOptions{}))
+}
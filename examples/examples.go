@@ -0,0 +1,292 @@
+// Package examples contains a small but realistic command set built with brigodier.
+//
+// It registers the commands of an in-memory game Server and doubles as an
+// end-to-end test of the library and as a template to copy from:
+//
+//	teleport <x> <y> <z>
+//	teleport <player> <x> <y> <z>
+//	teleport <player> <destination>
+//	gamemode (survival|creative|adventure|spectator) [<player>]
+//	msg [--urgent] <player> <message>
+//	execute (as <player>|at <player>) ... run <command>
+//	commands dump [usage|all|json]
+package examples
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.minekube.com/brigodier"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Player is a player on the Server.
+type Player struct {
+	Name     string
+	X, Y, Z  float64
+	GameMode string
+	Op       bool     // Whether the player may use operator commands.
+	Messages []string // Messages the player received.
+}
+
+// Send sends a message to the player.
+func (p *Player) Send(format string, a ...interface{}) {
+	p.Messages = append(p.Messages, fmt.Sprintf(format, a...))
+}
+
+// Write implements io.Writer and sends each written line to the player.
+func (p *Player) Write(b []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(b), "\n"), "\n") {
+		p.Send("%s", line)
+	}
+	return len(b), nil
+}
+
+// GameModes are the game modes a Player can be in.
+var GameModes = []string{"survival", "creative", "adventure", "spectator"}
+
+// Server is a minimal in-memory game server.
+type Server struct {
+	Players map[string]*Player // Online players by lower-case name.
+}
+
+// NewServer returns a new Server with the given online players.
+func NewServer(players ...*Player) *Server {
+	s := &Server{Players: map[string]*Player{}}
+	for _, p := range players {
+		s.Players[strings.ToLower(p.Name)] = p
+	}
+	return s
+}
+
+// ErrPlayerNotFound occurs when a command refers to a player that is not online.
+var ErrPlayerNotFound = errors.New("player not found")
+
+// Player returns the online player by case-insensitive name.
+func (s *Server) Player(name string) (*Player, error) {
+	p, ok := s.Players[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrPlayerNotFound, name)
+	}
+	return p, nil
+}
+
+type (
+	sourceKey   struct{}
+	positionKey struct{}
+	urgentKey   struct{}
+)
+
+// WithSource returns a copy of ctx with the Player executing a command.
+func WithSource(ctx context.Context, p *Player) context.Context {
+	return context.WithValue(ctx, sourceKey{}, p)
+}
+
+// Source returns the Player executing a command or nil.
+func Source(ctx context.Context) *Player {
+	p, _ := ctx.Value(sourceKey{}).(*Player)
+	return p
+}
+
+// position returns the position a command is executed at,
+// which is the position of the source unless changed by "execute at".
+func position(ctx context.Context) *Player {
+	if p, ok := ctx.Value(positionKey{}).(*Player); ok {
+		return p
+	}
+	return Source(ctx)
+}
+
+func isOp(ctx context.Context) bool {
+	p := Source(ctx)
+	return p != nil && p.Op
+}
+
+// Register registers all example commands to the dispatcher.
+func (s *Server) Register(d *brigodier.Dispatcher) {
+	s.registerTeleport(d)
+	s.registerGameMode(d)
+	s.registerMsg(d)
+	s.registerExecute(d)
+	d.Register(brigodier.Literal("commands").Requires(isOp).Then(
+		d.DumpCommand("dump", func(c *brigodier.CommandContext) io.Writer { return Source(c) }),
+	))
+}
+
+// ErrInvalidPlayerName occurs when a player name does not start with a letter.
+var ErrInvalidPlayerName = errors.New("invalid player name")
+
+// playerName is the argument type of player names.
+// Names must start with a letter so that they are never confused with coordinates.
+var playerName = &brigodier.ArgumentTypeFuncs{
+	Name: "player",
+	ParseFn: func(rd *brigodier.StringReader) (interface{}, error) {
+		start := rd.Cursor
+		name := rd.ReadUnquotedString()
+		if name == "" || !unicode.IsLetter(rune(name[0])) {
+			rd.Cursor = start
+			return nil, &brigodier.CommandSyntaxError{Err: &brigodier.ReaderError{
+				Err:    fmt.Errorf("%w %q", ErrInvalidPlayerName, name),
+				Reader: rd,
+			}}
+		}
+		return name, nil
+	},
+}
+
+// playerArgument returns an argument builder for the name of an online player.
+func (s *Server) playerArgument(name string) brigodier.ArgumentNodeBuilder {
+	return brigodier.Argument(name, playerName).
+		Canonicalize(func(v interface{}) interface{} { return strings.ToLower(v.(string)) }).
+		Suggests(s)
+}
+
+// Suggestions implements brigodier.SuggestionProvider and suggests online player names.
+func (s *Server) Suggestions(_ *brigodier.CommandContext, b *brigodier.SuggestionsBuilder) *brigodier.Suggestions {
+	names := make([]string, 0, len(s.Players))
+	for _, p := range s.Players {
+		names = append(names, p.Name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if strings.HasPrefix(strings.ToLower(name), b.RemainingLowerCase) {
+			b.Suggest(name)
+		}
+	}
+	return b.Build()
+}
+
+func (s *Server) registerTeleport(d *brigodier.Dispatcher) {
+	coordinates := func(target func(c *brigodier.CommandContext) (*Player, error)) brigodier.Builder {
+		return brigodier.Argument("x", brigodier.Float64).Then(
+			brigodier.Argument("y", brigodier.Float64).Then(
+				brigodier.Argument("z", brigodier.Float64).
+					Executes(brigodier.CommandFunc(func(c *brigodier.CommandContext) error {
+						p, err := target(c)
+						if err != nil {
+							return err
+						}
+						p.X, p.Y, p.Z = c.Float64("x"), c.Float64("y"), c.Float64("z")
+						p.Send("Teleported to %g %g %g", p.X, p.Y, p.Z)
+						return nil
+					})),
+			),
+		)
+	}
+	self := func(c *brigodier.CommandContext) (*Player, error) { return Source(c), nil }
+	named := func(c *brigodier.CommandContext) (*Player, error) { return s.Player(c.String("player")) }
+
+	d.Register(brigodier.Literal("teleport").Requires(isOp).
+		Then(coordinates(self)).
+		Then(s.playerArgument("player").
+			Then(coordinates(named)).
+			Then(s.playerArgument("destination").
+				Executes(brigodier.CommandFunc(func(c *brigodier.CommandContext) error {
+					p, err := s.Player(c.String("player"))
+					if err != nil {
+						return err
+					}
+					dest, err := s.Player(c.String("destination"))
+					if err != nil {
+						return err
+					}
+					p.X, p.Y, p.Z = dest.X, dest.Y, dest.Z
+					p.Send("Teleported to %s", dest.Name)
+					return nil
+				})),
+			),
+		),
+	)
+}
+
+func (s *Server) registerGameMode(d *brigodier.Dispatcher) {
+	gamemode := brigodier.Literal("gamemode").Requires(isOp)
+	for _, mode := range GameModes {
+		mode := mode
+		set := func(p *Player) {
+			p.GameMode = mode
+			p.Send("Set own game mode to %s", mode)
+		}
+		gamemode.Then(brigodier.Literal(mode).
+			Executes(brigodier.CommandFunc(func(c *brigodier.CommandContext) error {
+				set(Source(c))
+				return nil
+			})).
+			Then(s.playerArgument("player").
+				Executes(brigodier.CommandFunc(func(c *brigodier.CommandContext) error {
+					p, err := s.Player(c.String("player"))
+					if err != nil {
+						return err
+					}
+					set(p)
+					return nil
+				})),
+			),
+		)
+	}
+	d.Register(gamemode)
+}
+
+func (s *Server) registerMsg(d *brigodier.Dispatcher) {
+	msg := d.Register(brigodier.Literal("msg").Then(
+		s.playerArgument("player").Then(
+			brigodier.Argument("message", brigodier.StringPhrase).
+				Executes(brigodier.CommandFunc(func(c *brigodier.CommandContext) error {
+					p, err := s.Player(c.String("player"))
+					if err != nil {
+						return err
+					}
+					message := c.String("message")
+					if urgent, _ := c.Value(urgentKey{}).(bool); urgent {
+						message = strings.ToUpper(message) + "!"
+					}
+					p.Send("%s whispers: %s", Source(c).Name, message)
+					return nil
+				})),
+		),
+	))
+	// Flags are literals that redirect back to the command
+	// and remember the flag in the context using a modifier.
+	d.Register(brigodier.Literal("msg").Then(
+		brigodier.Literal("--urgent").RedirectWithModifier(msg,
+			brigodier.ModifierFunc(func(c *brigodier.CommandContext) (context.Context, error) {
+				return context.WithValue(c, urgentKey{}, true), nil
+			})),
+	))
+}
+
+func (s *Server) registerExecute(d *brigodier.Dispatcher) {
+	execute := d.Register(brigodier.Literal("execute").Requires(isOp))
+	d.Register(brigodier.Literal("execute").
+		Then(brigodier.Literal("run").Redirect(&d.Root)).
+		Then(brigodier.Literal("as").Then(
+			s.playerArgument("player").Fork(execute,
+				brigodier.ModifierFunc(func(c *brigodier.CommandContext) (context.Context, error) {
+					p, err := s.Player(c.String("player"))
+					if err != nil {
+						return nil, err
+					}
+					return WithSource(c, p), nil
+				})),
+		)).
+		Then(brigodier.Literal("at").Then(
+			s.playerArgument("player").Fork(execute,
+				brigodier.ModifierFunc(func(c *brigodier.CommandContext) (context.Context, error) {
+					p, err := s.Player(c.String("player"))
+					if err != nil {
+						return nil, err
+					}
+					return context.WithValue(c, positionKey{}, p), nil
+				})),
+		)),
+	)
+	d.Register(brigodier.Literal("where").
+		Executes(brigodier.CommandFunc(func(c *brigodier.CommandContext) error {
+			p := position(c)
+			Source(c).Send("Position %g %g %g", p.X, p.Y, p.Z)
+			return nil
+		})))
+}
@@ -0,0 +1,101 @@
+package examples
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"go.minekube.com/brigodier"
+	"testing"
+)
+
+func setup() (*brigodier.Dispatcher, *Player, *Player) {
+	alice := &Player{Name: "Alice", Op: true, GameMode: "survival"}
+	bob := &Player{Name: "Bob", X: 10, Y: 64, Z: -5, GameMode: "survival"}
+	d := new(brigodier.Dispatcher)
+	NewServer(alice, bob).Register(d)
+	return d, alice, bob
+}
+
+func Example() {
+	d, alice, bob := setup()
+	ctx := WithSource(context.Background(), alice)
+
+	_ = d.Do(ctx, "msg --urgent bob look behind you")
+	_ = d.Do(ctx, "execute as bob at alice run where")
+
+	fmt.Println(bob.Messages)
+	// Output: [Alice whispers: LOOK BEHIND YOU! Position 0 0 0]
+}
+
+func TestTeleport(t *testing.T) {
+	d, alice, bob := setup()
+	ctx := WithSource(context.TODO(), alice)
+
+	require.NoError(t, d.Do(ctx, "teleport 1 2.5 -3"))
+	require.Equal(t, [3]float64{1, 2.5, -3}, [3]float64{alice.X, alice.Y, alice.Z})
+
+	require.NoError(t, d.Do(ctx, "teleport BOB 0 100 0"))
+	require.Equal(t, [3]float64{0, 100, 0}, [3]float64{bob.X, bob.Y, bob.Z})
+
+	require.NoError(t, d.Do(ctx, "teleport alice bob"))
+	require.Equal(t, [3]float64{0, 100, 0}, [3]float64{alice.X, alice.Y, alice.Z})
+
+	require.ErrorIs(t, d.Do(ctx, "teleport carol 0 0 0"), ErrPlayerNotFound)
+}
+
+func TestTeleport_NotOp(t *testing.T) {
+	d, _, bob := setup()
+	require.ErrorIs(t, d.Do(WithSource(context.TODO(), bob), "teleport 0 0 0"), brigodier.ErrDispatcherUnknownCommand)
+}
+
+func TestGameMode(t *testing.T) {
+	d, alice, bob := setup()
+	ctx := WithSource(context.TODO(), alice)
+
+	require.NoError(t, d.Do(ctx, "gamemode creative"))
+	require.Equal(t, "creative", alice.GameMode)
+	require.NoError(t, d.Do(ctx, "gamemode spectator bob"))
+	require.Equal(t, "spectator", bob.GameMode)
+	require.Error(t, d.Do(ctx, "gamemode hardcore"))
+}
+
+func TestMsg(t *testing.T) {
+	d, alice, bob := setup()
+
+	require.NoError(t, d.Do(WithSource(context.TODO(), bob), "msg alice hello there"))
+	require.Equal(t, []string{"Bob whispers: hello there"}, alice.Messages)
+
+	require.NoError(t, d.Do(WithSource(context.TODO(), alice), "msg --urgent bob run"))
+	require.Equal(t, []string{"Alice whispers: RUN!"}, bob.Messages)
+}
+
+func TestMsg_Suggestions(t *testing.T) {
+	d, alice, _ := setup()
+	parse := d.Parse(WithSource(context.TODO(), alice), "msg ")
+	suggestions, err := d.CompletionSuggestions(parse)
+	require.NoError(t, err)
+
+	var texts []string
+	for _, s := range suggestions.Suggestions {
+		texts = append(texts, s.Text)
+	}
+	require.ElementsMatch(t, []string{"--urgent", "Alice", "Bob"}, texts)
+}
+
+func TestExecute(t *testing.T) {
+	d, alice, bob := setup()
+	ctx := WithSource(context.TODO(), alice)
+
+	require.NoError(t, d.Do(ctx, "execute as bob run msg alice hi"))
+	require.Equal(t, []string{"Bob whispers: hi"}, alice.Messages)
+
+	alice.X = 42
+	require.NoError(t, d.Do(ctx, "execute at alice as bob run where"))
+	require.Equal(t, []string{"Position 42 0 0"}, bob.Messages)
+}
+
+func TestCommandsDump(t *testing.T) {
+	d, alice, _ := setup()
+	require.NoError(t, d.Do(WithSource(context.TODO(), alice), "commands dump"))
+	require.Contains(t, alice.Messages, "msg ([player]|--urgent)")
+}
@@ -0,0 +1,35 @@
+package brigodier
+
+// Executor runs submitted functions, e.g. on the main tick goroutine of a game server.
+type Executor interface {
+	// Submit runs fn eventually. It must not block until fn has run,
+	// unless it runs fn on the calling goroutine.
+	Submit(fn func())
+}
+
+// ExecutorFunc is a convenient function type implementing the Executor interface.
+type ExecutorFunc func(fn func())
+
+// Submit implements Executor.
+func (f ExecutorFunc) Submit(fn func()) { f(fn) }
+
+// SyncExecutor runs submitted functions immediately on the calling goroutine.
+var SyncExecutor Executor = ExecutorFunc(func(fn func()) { fn() })
+
+// ExecuteOn executes the pre-parsed command on the executor and returns a channel
+// receiving the error returned by Execute, so commands can run on a game server's
+// main goroutine while parsing and suggestions run elsewhere:
+//
+//	parse := d.Parse(ctx, input) // off the main goroutine
+//	err := <-d.ExecuteOn(server.MainThread, parse)
+//
+// The channel is buffered, so it need not be received from.
+// A nil executor is the SyncExecutor.
+func (d *Dispatcher) ExecuteOn(executor Executor, parse *ParseResults) <-chan error {
+	if executor == nil {
+		executor = SyncExecutor
+	}
+	result := make(chan error, 1)
+	executor.Submit(func() { result <- d.Execute(parse) })
+	return result
+}
@@ -0,0 +1,34 @@
+package brigodier
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDispatcher_ExecuteOn(t *testing.T) {
+	tasks := make(chan func(), 1)
+	mainLoop := ExecutorFunc(func(fn func()) { tasks <- fn })
+
+	var ranOnMain bool
+	onMain := false
+	d := &Dispatcher{}
+	d.Register(Literal("save").Executes(CommandFunc(func(c *CommandContext) error {
+		ranOnMain = onMain
+		return nil
+	})))
+
+	result := d.ExecuteOn(mainLoop, d.Parse(context.TODO(), "save"))
+	select {
+	case <-result:
+		t.Fatal("executed before the executor ran the task")
+	default:
+	}
+	onMain = true
+	(<-tasks)()
+	require.NoError(t, <-result)
+	require.True(t, ranOnMain)
+
+	require.ErrorIs(t, <-d.ExecuteOn(nil, d.Parse(context.TODO(), "load")), ErrDispatcherUnknownCommand)
+	require.NoError(t, <-d.ExecuteOn(SyncExecutor, d.Parse(context.TODO(), "save")))
+}
@@ -0,0 +1,90 @@
+package brigodier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ExplainOutcome is the outcome of a node visited while parsing.
+type ExplainOutcome uint8
+
+// ExplainOutcome values.
+const (
+	// ExplainMatched indicates that the node matched the input.
+	ExplainMatched ExplainOutcome = iota
+	// ExplainFailed indicates that the node failed to parse the input.
+	ExplainFailed
+	// ExplainSkipped indicates that the node was skipped because
+	// the context.Context can not use it.
+	ExplainSkipped
+)
+
+func (o ExplainOutcome) String() string {
+	switch o {
+	case ExplainMatched:
+		return "matched"
+	case ExplainFailed:
+		return "failed"
+	case ExplainSkipped:
+		return "skipped"
+	}
+	return fmt.Sprintf("ExplainOutcome(%d)", uint8(o))
+}
+
+// ExplainStep is a node visited while parsing.
+type ExplainStep struct {
+	Node    CommandNode
+	Depth   int // The number of nodes matched before this node.
+	Cursor  int // The input cursor the node was parsed at.
+	Outcome ExplainOutcome
+	Range   StringRange // The matched input range if ExplainMatched.
+	Err     error       // The parse error if ExplainFailed.
+}
+
+// Explanation is the trace of parsing an input returned by Dispatcher.Explain.
+type Explanation struct {
+	Input string
+	// Steps are the visited nodes in the order they were visited.
+	// Nodes are visited depth-first, so the steps form a tree by their Depth.
+	Steps []ExplainStep
+	// Parse are the results of parsing the input.
+	Parse *ParseResults
+	// Err is the error returned by Dispatcher.Validate for Parse, if any.
+	Err error
+}
+
+// Explain parses the input like Parse and returns a trace of every node
+// visited during parse with its outcome, e.g. to debug why an input
+// results in an unknown command or argument. No command is executed.
+//
+// The parse cache is bypassed.
+func (d *Dispatcher) Explain(ctx context.Context, input string) *Explanation {
+	e := &Explanation{Input: input}
+	e.Parse = d.parse(ctx, &StringReader{String: input}, &parseState{explain: e})
+	e.Err = d.Validate(e.Parse)
+	return e
+}
+
+// String returns the trace as human-readable indented tree.
+func (e *Explanation) String() string {
+	b := new(strings.Builder)
+	fmt.Fprintf(b, "%q\n", e.Input)
+	for _, s := range e.Steps {
+		b.WriteString(strings.Repeat("  ", s.Depth+1))
+		fmt.Fprintf(b, "%s %s", s.Node.UsageText(), s.Outcome)
+		switch s.Outcome {
+		case ExplainMatched:
+			fmt.Fprintf(b, " %q", s.Range.Get(e.Input))
+		case ExplainFailed:
+			fmt.Fprintf(b, " at %d: %v", s.Cursor, s.Err)
+		case ExplainSkipped:
+			b.WriteString(" by requirement")
+		}
+		b.WriteByte('\n')
+	}
+	if e.Err != nil {
+		fmt.Fprintf(b, "error: %v\n", e.Err)
+	}
+	return b.String()
+}
@@ -0,0 +1,33 @@
+package brigodier
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDispatcher_Explain(t *testing.T) {
+	var d Dispatcher
+	cmd := CommandFunc(func(c *CommandContext) error { return nil })
+	d.Register(Literal("give").Then(
+		Argument("count", Int).Executes(cmd),
+		Argument("item", StringWord).Executes(cmd),
+	))
+	d.Register(Literal("admin").Requires(func(context.Context) bool { return false }).Executes(cmd))
+
+	e := d.Explain(context.TODO(), "give x")
+	require.NoError(t, e.Err)
+	require.Len(t, e.Steps, 3)
+	require.Equal(t, ExplainMatched, e.Steps[0].Outcome)
+	require.Equal(t, StringRange{Start: 0, End: 4}, e.Steps[0].Range)
+	require.Equal(t, ExplainFailed, e.Steps[1].Outcome)
+	require.ErrorIs(t, e.Steps[1].Err, ErrReaderExpectedInt)
+	require.Equal(t, 1, e.Steps[1].Depth)
+	require.Equal(t, ExplainMatched, e.Steps[2].Outcome)
+	require.Equal(t, "item", e.Steps[2].Node.Name())
+
+	e = d.Explain(context.TODO(), "admin")
+	require.ErrorIs(t, e.Err, ErrDispatcherUnknownCommand)
+	require.Equal(t, []ExplainStep{{Node: d.Root.Children()["admin"], Outcome: ExplainSkipped}}, e.Steps)
+	require.Equal(t, "\"admin\"\n  admin skipped by requirement\nerror: dispatcher: unknown command\n", e.String())
+}
@@ -0,0 +1,73 @@
+package brigodier
+
+import "context"
+
+// Extend parses the input of the results with additional input appended,
+// e.g. when a user typed more characters of a command to be completed.
+//
+// Instead of parsing the whole input again, the parsed nodes of the prefix
+// are reused as long as they are followed by an ArgumentSeparator, no other
+// sibling could have matched them, they can still be used by ctx and are no
+// arguments of a MultiTokenArgumentType consuming a varying number of tokens.
+// The results are the same as parsing the whole input using Dispatcher.Parse.
+//
+// The results must have been returned by Dispatcher.Parse or Dispatcher.ParseReader.
+func (r *ParseResults) Extend(ctx context.Context, additional string) (parse *ParseResults) {
+	d := r.dispatcher
+	input := r.Reader.String + additional
+	c := r.Context
+	if c.Child != nil || len(c.Nodes) == 0 {
+		return d.ParseReader(ctx, &StringReader{String: input, Cursor: c.cursor})
+	}
+
+	var (
+		parent CommandNode = c.RootNode
+		keep   int
+	)
+	for i, n := range c.Nodes {
+		end := n.Range.End
+		if end >= len(r.Reader.String) || rune(r.Reader.String[end]) != ArgumentSeparator ||
+			n.Node.Redirect() != nil || !n.Node.CanUse(ctx) {
+			break
+		}
+		if a, ok := n.Node.(*ArgumentCommandNode); ok && argumentTokens(a.argType) == 0 {
+			break // The argument may consume the additional tokens.
+		}
+		nodes := d.relevantNodes(parent, &StringReader{String: input, Cursor: n.Range.Start})
+		if len(nodes) != 1 || nodes[0] != n.Node {
+			break // The parsed node may have been chosen over another one.
+		}
+		parent, keep = n.Node, i+1
+	}
+	if keep == 0 {
+		return d.ParseReader(ctx, &StringReader{String: input, Cursor: c.cursor})
+	}
+
+	if d.Instrumentation != nil {
+		end := d.Instrumentation.StartParse(ctx, input)
+		defer func() { end(parse) }()
+	}
+	last := c.Nodes[keep-1]
+	ctxSoFar := &CommandContext{
+		Context:  ctx,
+		RootNode: c.RootNode,
+		Command:  last.Node.Command(),
+		Nodes:    append(make([]*ParsedCommandNode, 0, keep+1), c.Nodes[:keep]...),
+		Range:    StringRange{Start: c.Range.Start, End: last.Range.End},
+		Modifier: last.Node.RedirectModifier(),
+		Forks:    last.Node.IsFork(),
+		cursor:   c.cursor,
+	}
+	for name, arg := range c.Arguments {
+		if arg.Range.End <= last.Range.End {
+			if ctxSoFar.Arguments == nil {
+				ctxSoFar.Arguments = make(map[string]*ParsedArgument, len(c.Arguments))
+			}
+			ctxSoFar.Arguments[name] = arg
+		}
+	}
+	rd := &StringReader{String: input, Cursor: last.Range.End + 1}
+	parse = d.parseNodes(rd, parent, ctxSoFar, &parseState{})
+	parse.dispatcher = d
+	return parse
+}
@@ -0,0 +1,50 @@
+package brigodier
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseResults_Extend(t *testing.T) {
+	var d Dispatcher
+	cmd := CommandFunc(func(c *CommandContext) error { return nil })
+	tp := d.Register(Literal("tp").Then(
+		Argument("x", Int).Then(Argument("y", Int).Then(Argument("z", Int).Executes(cmd))),
+		Literal("spawn").Executes(cmd),
+	))
+	d.Register(Literal("amb").Then(
+		Argument("n", Int).Then(Literal("a").Executes(cmd)),
+		Argument("w", StringWord).Then(Literal("b").Executes(cmd)),
+	))
+	d.Register(Literal("say").Then(Argument("msg", StringPhrase).Executes(cmd)))
+	d.Register(Literal("go").Redirect(tp))
+	d.Register(Literal("sum").Then(Argument("n", ListOf(Int, ' ')).Executes(cmd).Then(Literal("end").Executes(cmd))))
+
+	for _, input := range []string{
+		"tp 1 2 3",
+		"tp spawn",
+		"tp 12 x 3",
+		"amb 1 a",
+		"amb 1 b",
+		"say hello world",
+		"go 1 2 3",
+		"sum 1 2 3 end",
+	} {
+		parse := d.Parse(context.TODO(), "")
+		for i := range input {
+			parse = parse.Extend(context.TODO(), input[i:i+1])
+			expected := d.Parse(context.TODO(), input[:i+1])
+			require.Equal(t, expected.Reader, parse.Reader, input[:i+1])
+			require.Equal(t, expected.Context.Nodes, parse.Context.Nodes, input[:i+1])
+			require.Equal(t, len(expected.Context.Arguments), len(parse.Context.Arguments), input[:i+1])
+			for name, arg := range expected.Context.Arguments {
+				require.Equal(t, arg, parse.Context.Arguments[name], input[:i+1])
+			}
+			require.Equal(t, expected.Context.Range, parse.Context.Range, input[:i+1])
+			require.Equal(t, expected.Context.Command != nil, parse.Context.Command != nil, input[:i+1])
+			require.Equal(t, len(expected.Errs), len(parse.Errs), input[:i+1])
+			require.Equal(t, d.Validate(expected), d.Validate(parse), input[:i+1])
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package brigodier
+
+import "context"
+
+// FilteredTree returns a deep copy of the command tree without the nodes
+// the context.Context can't use, e.g. to send a player-specific command tree
+// to a client.
+//
+// Redirects to nodes of the copied tree, including the Dispatcher.Root,
+// point to the copied nodes and redirects to removed nodes are dropped.
+func (d *Dispatcher) FilteredTree(ctx context.Context) *RootCommandNode {
+	root := &RootCommandNode{Node: d.Root.Node.copy()}
+	c := cloner{&d.Root: root}
+	c.cloneUsableChildren(ctx, &d.Root, root)
+	for _, clone := range c {
+		n := baseNode(clone)
+		if n.redirect != nil {
+			n.redirect = c[n.redirect]
+		}
+	}
+	return root
+}
+
+func (c cloner) cloneUsableChildren(ctx context.Context, node, clone CommandNode) {
+	node.ChildrenOrdered().Range(func(_ string, child CommandNode) bool {
+		if !child.CanUse(ctx) {
+			return true
+		}
+		childClone, ok := c[child]
+		if !ok {
+			childClone = copyNode(child)
+			c[child] = childClone
+			c.cloneUsableChildren(ctx, child, childClone)
+		}
+		clone.AddChild(childClone)
+		return true
+	})
+}
@@ -0,0 +1,34 @@
+package brigodier
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDispatcher_FilteredTree(t *testing.T) {
+	type adminKey struct{}
+	admin := func(ctx context.Context) bool { return ctx.Value(adminKey{}) != nil }
+	var d Dispatcher
+	cmd := CommandFunc(func(c *CommandContext) error { return nil })
+	ban := d.Register(Literal("ban").Requires(admin).Then(Argument("player", String).Executes(cmd)))
+	d.Register(Literal("msg").Then(
+		Argument("player", String).Executes(cmd),
+		Literal("admin").Requires(admin).Executes(cmd),
+	))
+	d.Register(Literal("b").Redirect(ban))
+	d.Register(Literal("run").Redirect(&d.Root))
+
+	tree := d.FilteredTree(context.TODO())
+	require.Equal(t, []string{"msg", "b", "run"}, tree.ChildrenOrdered().Keys())
+	require.Equal(t, []string{"player"}, tree.Children()["msg"].ChildrenOrdered().Keys())
+	require.Nil(t, tree.Children()["b"].Redirect())
+	require.Same(t, tree, tree.Children()["run"].Redirect())
+	require.NotSame(t, d.FindNode("msg"), tree.Children()["msg"])
+
+	tree = d.FilteredTree(context.WithValue(context.TODO(), adminKey{}, true))
+	require.Equal(t, []string{"ban", "msg", "b", "run"}, tree.ChildrenOrdered().Keys())
+	require.Same(t, tree.Children()["ban"], tree.Children()["b"].Redirect())
+	require.Equal(t, []string{"player", "admin"}, tree.Children()["msg"].ChildrenOrdered().Keys())
+	require.Len(t, d.Root.Children(), 4)
+}
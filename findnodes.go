@@ -0,0 +1,70 @@
+package brigodier
+
+import "strings"
+
+// FindNodes finds all nodes matching a path pattern in registration order,
+// e.g. to locate nodes in unfamiliar command trees. Each segment of the
+// pattern matches the children of the nodes matched by the previous segment:
+//
+//	name           a child named name, like FindNode
+//	*              any child
+//	[name]         an argument named name, like in its UsageText
+//	[*]            any argument
+//	[name:type]    an argument named name whose type is type, where type is
+//	               the ArgumentType.String or the identifier in Dispatcher.Types,
+//	               e.g. [*:int32] or [*:brigadier:integer]
+//
+// For example, FindNodes("tp", "*", "[*:player]") finds the player arguments
+// following any child of "tp". Redirects are not followed and a node
+// reachable through multiple matching paths is returned once.
+func (d *Dispatcher) FindNodes(pattern ...string) []CommandNode {
+	nodes := []CommandNode{&d.Root}
+	for _, segment := range pattern {
+		match := d.segmentMatcher(segment)
+		var next []CommandNode
+		seen := map[CommandNode]bool{}
+		for _, node := range nodes {
+			node.ChildrenOrdered().Range(func(_ string, child CommandNode) bool {
+				if !seen[child] && match(child) {
+					seen[child] = true
+					next = append(next, child)
+				}
+				return true
+			})
+		}
+		if len(next) == 0 {
+			return nil
+		}
+		nodes = next
+	}
+	if len(pattern) == 0 {
+		return nil
+	}
+	return nodes
+}
+
+// segmentMatcher returns the func matching a segment of a FindNodes pattern.
+func (d *Dispatcher) segmentMatcher(segment string) func(CommandNode) bool {
+	if segment == "*" {
+		return func(CommandNode) bool { return true }
+	}
+	if !strings.HasPrefix(segment, string(UsageArgumentOpen)) || !strings.HasSuffix(segment, string(UsageArgumentClose)) {
+		return func(node CommandNode) bool { return node.Name() == segment }
+	}
+	name := segment[len(string(UsageArgumentOpen)) : len(segment)-len(string(UsageArgumentClose))]
+	var typ string
+	if i := strings.IndexByte(name, ':'); i != -1 {
+		name, typ = name[:i], name[i+1:]
+	}
+	return func(node CommandNode) bool {
+		a, ok := node.(*ArgumentCommandNode)
+		if !ok || (name != "*" && a.Name() != name) {
+			return false
+		}
+		if typ == "" || a.Type().String() == typ {
+			return true
+		}
+		id, _, ok := d.types().Identify(a.Type())
+		return ok && id == typ
+	}
+}
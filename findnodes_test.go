@@ -0,0 +1,37 @@
+package brigodier
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDispatcher_FindNodes(t *testing.T) {
+	var d Dispatcher
+	d.Register(Literal("tp").
+		Then(Argument("target", StringWord).Then(Argument("x", Int))).
+		Then(Literal("here").Then(Argument("y", Float64), Argument("x", Int64))))
+	d.Register(Literal("give").Then(Argument("item", StringWord)))
+
+	names := func(nodes []CommandNode) (names []string) {
+		for _, n := range nodes {
+			names = append(names, n.Name())
+		}
+		return names
+	}
+	require.Equal(t, []string{"tp"}, names(d.FindNodes("tp")))
+	require.Equal(t, []string{"tp", "give"}, names(d.FindNodes("*")))
+	require.Equal(t, []string{"x", "y", "x"}, names(d.FindNodes("tp", "*", "*")))
+	require.Equal(t, []string{"target", "item"}, names(d.FindNodes("*", "[*]")))
+	require.Equal(t, []string{"target"}, names(d.FindNodes("*", "[target]")))
+	require.Equal(t, []string{"x"}, names(d.FindNodes("tp", "*", "[*:int32]")))
+	require.Equal(t, []string{"x"}, names(d.FindNodes("tp", "*", "[x:brigadier:long]")))
+	require.Equal(t, d.FindNode("tp", "here", "y"), d.FindNodes("tp", "here", "[*:float64]")[0])
+	require.Nil(t, d.FindNodes("tp", "[here]"))
+	require.Nil(t, d.FindNodes("missing", "*"))
+	require.Nil(t, d.FindNodes())
+
+	// shared nodes are found once
+	d.FindNode("give").AddChild(d.FindNode("tp", "here"))
+	d.FindNode("give", "item").AddChild(d.FindNode("tp", "here"))
+	require.Len(t, d.FindNodes("*", "*", "[y]"), 1)
+}
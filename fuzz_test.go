@@ -0,0 +1,104 @@
+package brigodier
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+)
+
+var (
+	fuzzLiterals  = []string{"a", "b", "ab", "foo", "bar", "Foo"}
+	fuzzArguments = []ArgumentType{Int, Bool, String, StringWord, StringPhrase, Float64, Uint, ListOf(Int, ',')}
+)
+
+// randomDispatcher builds a random command tree from seed whose commands call run.
+func randomDispatcher(seed int64, run Command) *Dispatcher {
+	r := rand.New(rand.NewSource(seed))
+	d := &Dispatcher{}
+	var build func(depth int) Builder
+	build = func(depth int) Builder {
+		var b NodeBuilder
+		if r.Intn(2) == 0 {
+			b = Literal(fuzzLiterals[r.Intn(len(fuzzLiterals))]).NodeBuilder()
+		} else {
+			b = Argument(fuzzLiterals[r.Intn(len(fuzzLiterals))],
+				fuzzArguments[r.Intn(len(fuzzArguments))]).NodeBuilder()
+		}
+		if r.Intn(2) == 0 {
+			b = b.Executes(run)
+		}
+		if depth > 0 && r.Intn(6) == 0 {
+			return b.Redirect(&d.Root)
+		}
+		if depth < 4 {
+			for i := r.Intn(3); i > 0; i-- {
+				b = b.Then(build(depth + 1))
+			}
+		}
+		return b
+	}
+	for i := r.Intn(5) + 1; i > 0; i-- {
+		d.Root.AddChild(build(0).Build())
+	}
+	return d
+}
+
+func FuzzParse(f *testing.F) {
+	for _, input := range []string{"", "a", "foo 1", "ab 1.5 true", `bar "q\"uo" x`, "a 1,2,3", "b  ", "Foo a b"} {
+		f.Add(input, int64(len(input)))
+	}
+	f.Fuzz(func(t *testing.T, input string, seed int64) {
+		var ran bool
+		d := randomDispatcher(seed, CommandFunc(func(*CommandContext) error { ran = true; return nil }))
+
+		parse := d.Parse(context.TODO(), input)
+		if cursor := parse.Reader.Cursor; cursor < 0 || cursor > len(input) {
+			t.Fatalf("cursor %d out of bounds of %q", cursor, input)
+		}
+		for c := parse.Context; c != nil; c = c.Child {
+			for _, n := range c.Nodes {
+				if n.Range.Start < 0 || n.Range.End > len(input) || n.Range.Start > n.Range.End {
+					t.Fatalf("node range %v out of bounds of %q", *n.Range, input)
+				}
+			}
+		}
+		canRead := parse.Reader.CanRead()
+		_ = d.Execute(parse)
+		if canRead && ran {
+			t.Fatalf("executed command with unparsed input %q", parse.Reader.Remaining())
+		}
+		_, _ = d.CompletionSuggestions(parse)
+	})
+}
+
+func FuzzReadQuotedString(f *testing.F) {
+	for _, input := range []string{`"hello"`, `'it''s'`, `"esc\"aped"`, `"unterminated`, `"bad\escape"`, `''`, `x`} {
+		f.Add(input)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		rd := &StringReader{String: input}
+		_, err := rd.ReadQuotedString()
+		if rd.Cursor < 0 || rd.Cursor > len(input) {
+			t.Fatalf("cursor %d out of bounds of %q", rd.Cursor, input)
+		}
+		if err == nil && input != "" && rd.Cursor == 0 {
+			t.Fatalf("read quoted string %q without consuming input", input)
+		}
+	})
+}
+
+func FuzzSmartUsage(f *testing.F) {
+	for i := int64(0); i < 8; i++ {
+		f.Add(i)
+	}
+	f.Fuzz(func(t *testing.T, seed int64) {
+		d := randomDispatcher(seed, CommandFunc(func(*CommandContext) error { return nil }))
+		d.SmartUsage(context.TODO(), &d.Root).Range(func(node CommandNode, usage string) bool {
+			if usage == "" {
+				t.Fatalf("empty usage for %s", node)
+			}
+			return true
+		})
+		d.AllUsage(context.TODO(), &d.Root, false)
+	})
+}
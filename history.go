@@ -0,0 +1,154 @@
+package brigodier
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// History records executed inputs and suggests them as full commands,
+// most frequently executed first and then most recently executed first.
+//
+// Register History.Record as hook to record executed inputs:
+//
+//	history := NewHistory(50, func(ctx context.Context) interface{} { return SourceFrom(ctx) })
+//	d := NewDispatcher(WithAfterExecute(history.Record))
+//
+// and merge the suggestions for the root with the ones of the Dispatcher:
+//
+//	s, err := d.CompletionSuggestions(parse)
+//	s = MergeSuggestions(input, []*Suggestions{history.CompletionSuggestions(parse), s})
+type History struct {
+	// Size limits the number of inputs remembered per key.
+	// The least recently executed inputs are forgotten first.
+	// A Size <= 0 remembers all inputs.
+	Size int
+	// Limit optionally limits the number of suggested inputs.
+	Limit int
+	// Key optionally extracts the history key from the context, e.g.
+	// the executing Source. If nil, all executions share one history.
+	// Keys must be comparable.
+	Key func(ctx context.Context) interface{}
+
+	mu      sync.Mutex
+	seq     uint64
+	entries map[interface{}]map[string]*historyEntry
+}
+
+type historyEntry struct {
+	input string
+	count int
+	last  uint64 // sequence number of the last execution
+}
+
+var _ DynamicSuggestionProvider = (*History)(nil)
+
+// NewHistory returns a new History.
+func NewHistory(size int, key func(ctx context.Context) interface{}) *History {
+	return &History{Size: size, Key: key}
+}
+
+// Record is an AfterExecuteFn recording the input of successfully executed commands.
+func (h *History) Record(c *CommandContext, err error) {
+	if err == nil {
+		h.Add(c, c.Input)
+	}
+}
+
+// Add records an executed input for the key of ctx.
+func (h *History) Add(ctx context.Context, input string) {
+	if input = strings.TrimSpace(input); input == "" {
+		return
+	}
+	key := h.key(ctx)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.entries == nil {
+		h.entries = map[interface{}]map[string]*historyEntry{}
+	}
+	entries := h.entries[key]
+	if entries == nil {
+		entries = map[string]*historyEntry{}
+		h.entries[key] = entries
+	}
+	h.seq++
+	e, ok := entries[input]
+	if !ok {
+		e = &historyEntry{input: input}
+		entries[input] = e
+	}
+	e.count++
+	e.last = h.seq
+	if h.Size > 0 && len(entries) > h.Size {
+		var oldest *historyEntry
+		for _, e := range entries {
+			if oldest == nil || e.last < oldest.last {
+				oldest = e
+			}
+		}
+		delete(entries, oldest.input)
+	}
+}
+
+// Inputs returns the recorded inputs for the key of ctx starting with prefix ignoring case,
+// most frequently executed first and then most recently executed first.
+func (h *History) Inputs(ctx context.Context, prefix string) []string {
+	key := h.key(ctx)
+	prefix = strings.ToLower(prefix)
+	h.mu.Lock()
+	matches := make([]historyEntry, 0, len(h.entries[key]))
+	for _, e := range h.entries[key] {
+		if strings.HasPrefix(strings.ToLower(e.input), prefix) {
+			matches = append(matches, *e)
+		}
+	}
+	h.mu.Unlock()
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].count != matches[j].count {
+			return matches[i].count > matches[j].count
+		}
+		return matches[i].last > matches[j].last
+	})
+	if h.Limit > 0 && len(matches) > h.Limit {
+		matches = matches[:h.Limit]
+	}
+	inputs := make([]string, len(matches))
+	for i, e := range matches {
+		inputs[i] = e.input
+	}
+	return inputs
+}
+
+// Suggestions implements SuggestionProvider and suggests the recorded
+// inputs starting with the remaining input of the builder.
+func (h *History) Suggestions(c *CommandContext, b *SuggestionsBuilder) *Suggestions {
+	for _, input := range h.Inputs(c, b.Remaining) {
+		b.Suggest(input)
+	}
+	return b.Build()
+}
+
+// Dynamic implements DynamicSuggestionProvider since the
+// suggestions depend on the source and change over time.
+func (h *History) Dynamic() bool { return true }
+
+// CompletionSuggestions returns the recorded inputs starting with the
+// input of the parse results as suggestions for the whole input.
+func (h *History) CompletionSuggestions(parse *ParseResults) *Suggestions {
+	input := parse.Reader.String
+	lower := strings.ToLower(input)
+	return h.Suggestions(parse.Context, &SuggestionsBuilder{
+		Input:              input,
+		InputLowerCase:     lower,
+		Remaining:          input,
+		RemainingLowerCase: lower,
+	})
+}
+
+func (h *History) key(ctx context.Context) interface{} {
+	if h.Key == nil {
+		return nil
+	}
+	return h.Key(ctx)
+}
@@ -0,0 +1,82 @@
+package brigodier
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestHistory(t *testing.T) {
+	history := NewHistory(3, func(ctx context.Context) interface{} { return SourceFrom(ctx) })
+	d := NewDispatcher(WithAfterExecute(history.Record))
+	errFail := errors.New("fail")
+	d.Register(Literal("tp").Then(Argument("player", StringWord).Executes(CommandFunc(func(c *CommandContext) error {
+		return nil
+	}))))
+	d.Register(Literal("fail").Executes(CommandFunc(func(c *CommandContext) error { return errFail })))
+
+	alex := WithSource(context.TODO(), &testSource{name: "Alex"})
+	steve := WithSource(context.TODO(), &testSource{name: "Steve"})
+	for _, input := range []string{"tp bob", "tp alice", "tp bob", "tp carol"} {
+		require.NoError(t, d.Do(alex, input))
+	}
+	require.ErrorIs(t, d.Do(alex, "fail"), errFail)
+	require.NoError(t, d.Do(steve, "tp dave"))
+
+	// frequency first, then recency
+	require.Equal(t, []string{"tp bob", "tp carol", "tp alice"}, history.Inputs(alex, ""))
+	require.Equal(t, []string{"tp bob"}, history.Inputs(alex, "TP B"))
+	require.Equal(t, []string{"tp dave"}, history.Inputs(steve, "tp"))
+	require.Empty(t, history.Inputs(context.TODO(), ""))
+
+	// least recently executed input is evicted
+	history.Add(alex, "tp eve")
+	require.Equal(t, []string{"tp bob", "tp eve", "tp carol"}, history.Inputs(alex, ""))
+
+	history.Limit = 1
+	require.Equal(t, []string{"tp bob"}, history.Inputs(alex, ""))
+	history.Limit = 0
+
+	s := history.CompletionSuggestions(d.Parse(alex, "tp c"))
+	require.Len(t, s.Suggestions, 1)
+	require.Equal(t, "tp carol", s.Suggestions[0].Text)
+	require.Equal(t, StringRange{Start: 0, End: 4}, s.Suggestions[0].Range)
+
+	// merged with the suggestions of the dispatcher
+	parse := d.Parse(alex, "t")
+	ds, err := d.CompletionSuggestions(parse)
+	require.NoError(t, err)
+	merged := MergeSuggestions(parse.Reader.String, []*Suggestions{history.CompletionSuggestions(parse), ds})
+	var texts []string
+	for _, suggestion := range merged.Suggestions {
+		texts = append(texts, suggestion.Text)
+	}
+	require.Contains(t, texts, "tp")
+	require.Contains(t, texts, "tp bob")
+}
+
+func TestHistory_SuggestionCache(t *testing.T) {
+	history := NewHistory(0, func(ctx context.Context) interface{} { return SourceFrom(ctx) })
+	d := NewDispatcher(WithSuggestionCache(8), WithAfterExecute(history.Record))
+	d.Register(Literal("say").Then(Argument("msg", StringPhrase).Executes(CommandFunc(func(c *CommandContext) error {
+		return nil
+	}))))
+	d.Register(Literal("again").Then(Argument("input", StringPhrase).Suggests(history)))
+
+	alice := WithSource(context.TODO(), &testSource{name: "Alice"})
+	bob := WithSource(context.TODO(), &testSource{name: "Bob"})
+	suggest := func(ctx context.Context) []string {
+		s, err := d.CompletionSuggestions(d.Parse(ctx, "again s"))
+		require.NoError(t, err)
+		var texts []string
+		for _, suggestion := range s.Suggestions {
+			texts = append(texts, suggestion.Text)
+		}
+		return texts
+	}
+	require.Empty(t, suggest(alice))
+	require.NoError(t, d.Do(alice, "say secret password"))
+	require.Equal(t, []string{"say secret password"}, suggest(alice))
+	require.Empty(t, suggest(bob))
+}
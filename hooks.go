@@ -0,0 +1,57 @@
+package brigodier
+
+// BeforeExecuteFn is a hook run before a command is executed.
+// A returned error cancels the execution of the command.
+type BeforeExecuteFn func(c *CommandContext) error
+
+// AfterExecuteFn is a hook run after a command was executed
+// with the error returned by the command, if any.
+type AfterExecuteFn func(c *CommandContext, err error)
+
+// DeprecatedFn is a hook notified about a deprecated node of an executed input.
+// The CommandContext is the context the node was parsed into.
+type DeprecatedFn func(c *CommandContext, node CommandNode)
+
+// BeforeExecute registers hooks run before each command is executed by Execute,
+// e.g. for permission checks or plugin-style command events.
+// If a hook returns an error, the command is not executed and the
+// error is returned by Execute as if the command returned it.
+//
+// Hooks should be registered before the Dispatcher is used.
+func (d *Dispatcher) BeforeExecute(fns ...BeforeExecuteFn) {
+	d.beforeExecute = append(d.beforeExecute, fns...)
+}
+
+// AfterExecute registers hooks run after each command is executed by Execute,
+// e.g. for audit logging. The hooks are also notified about commands that
+// were not executed because of an error returned by a BeforeExecuteFn.
+//
+// Hooks should be registered before the Dispatcher is used.
+func (d *Dispatcher) AfterExecute(fns ...AfterExecuteFn) {
+	d.afterExecute = append(d.afterExecute, fns...)
+}
+
+// OnDeprecated registers hooks notified by Execute about each node of the input
+// marked using the Deprecated builder method, e.g. to warn the user about
+// the CommandNode.Deprecated message. The hooks are run before any command is executed.
+//
+// Hooks should be registered before the Dispatcher is used.
+func (d *Dispatcher) OnDeprecated(fns ...DeprecatedFn) {
+	d.onDeprecated = append(d.onDeprecated, fns...)
+}
+
+func (d *Dispatcher) notifyDeprecated(c *CommandContext) {
+	if len(d.onDeprecated) == 0 {
+		return
+	}
+	for ; c != nil; c = c.Child {
+		for _, n := range c.Nodes {
+			if n.Node.Deprecated() == "" {
+				continue
+			}
+			for _, fn := range d.onDeprecated {
+				fn(c, n.Node)
+			}
+		}
+	}
+}
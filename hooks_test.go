@@ -0,0 +1,55 @@
+package brigodier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDispatcher_ExecuteHooks(t *testing.T) {
+	errVeto := errors.New("veto")
+	var (
+		ran    []string
+		events []string
+	)
+	d := NewDispatcher(
+		WithBeforeExecute(func(c *CommandContext) error {
+			if c.Input == "deny" {
+				return errVeto
+			}
+			events = append(events, "before "+c.Input)
+			return nil
+		}),
+		WithAfterExecute(func(c *CommandContext, err error) {
+			events = append(events, "after "+c.Input+" "+fmt.Sprint(err))
+		}),
+	)
+	cmd := CommandFunc(func(c *CommandContext) error { ran = append(ran, c.Input); return nil })
+	d.Register(Literal("allow").Executes(cmd))
+	d.Register(Literal("deny").Executes(cmd))
+
+	require.NoError(t, d.Do(context.TODO(), "allow"))
+	require.ErrorIs(t, d.Do(context.TODO(), "deny"), errVeto)
+	require.Equal(t, []string{"allow"}, ran)
+	require.Equal(t, []string{"before allow", "after allow <nil>", "after deny veto"}, events)
+}
+
+func TestDispatcher_OnDeprecated(t *testing.T) {
+	var events []string
+	d := NewDispatcher(WithOnDeprecated(func(c *CommandContext, node CommandNode) {
+		events = append(events, node.Name()+": "+node.Deprecated())
+	}))
+	tp := d.Register(Literal("tp").Then(Argument("target", StringWord).
+		Executes(CommandFunc(func(c *CommandContext) error {
+			events = append(events, "run")
+			return nil
+		}))))
+	d.Register(Literal("back").Redirect(tp).Deprecated("use /tp instead"))
+
+	require.NoError(t, d.Do(context.TODO(), "tp Steve"))
+	require.NoError(t, d.Do(context.TODO(), "back Steve"))
+	require.Equal(t, []string{"run", "back: use /tp instead", "run"}, events)
+	require.Equal(t, "use /tp instead", d.Root.Children()["back"].CreateBuilder().Build().Deprecated())
+}
@@ -0,0 +1,98 @@
+package brigodier
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Identifier is the builtin IdentifierArgumentType
+// accepting letters, digits and underscores.
+var Identifier ArgumentType = &IdentifierArgumentType{}
+
+var (
+	// ErrExpectedIdentifier occurs when an identifier was expected but none was found.
+	ErrExpectedIdentifier = errors.New("expected identifier")
+	// ErrInvalidIdentifier occurs when an identifier contains a rune that is not allowed.
+	ErrInvalidIdentifier = errors.New("invalid identifier")
+)
+
+// IdentifierArgumentType parses a single-word identifier restricted to
+// ASCII letters, digits and underscores, e.g. to name warps, homes and teams.
+//
+// Unlike WordArgumentType, the whole word up to the next space is
+// validated, so invalid runes are reported by an *InvalidRuneError
+// instead of silently ending the word.
+type IdentifierArgumentType struct {
+	// Extra are the runes allowed in addition to IsIdentifierRune, e.g. '-'.
+	Extra []rune
+	// First optionally restricts the first rune, e.g. IsASCIILetter
+	// to not allow identifiers starting with a digit or underscore.
+	First func(c rune) bool
+}
+
+// InvalidRuneError is returned by IdentifierArgumentType
+// for the first rune of an identifier that is not allowed.
+type InvalidRuneError struct {
+	Err        error  // ErrInvalidIdentifier
+	Identifier string // The invalid identifier.
+	Rune       rune   // The first invalid rune.
+	Position   int    // The 1-based position of Rune in Identifier.
+}
+
+// Unwrap implements errors.Unwrap.
+func (e *InvalidRuneError) Unwrap() error { return e.Err }
+func (e *InvalidRuneError) Error() string {
+	return fmt.Sprintf("%v %q: %q at position %d is not allowed", e.Err, e.Identifier, e.Rune, e.Position)
+}
+
+// IsIdentifierRune indicates whether c is an ASCII letter, digit or underscore.
+func IsIdentifierRune(c rune) bool { return IsASCIILetter(c) || isDigit(c) || c == '_' }
+
+// IsASCIILetter indicates whether c is an ASCII letter.
+func IsASCIILetter(c rune) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+
+func (t *IdentifierArgumentType) String() string { return "identifier" }
+func (t *IdentifierArgumentType) Parse(rd *StringReader) (interface{}, error) {
+	start := rd.Cursor
+	end := start
+	for end < len(rd.String) && rd.String[end] != ' ' {
+		end++
+	}
+	id := rd.String[start:end]
+	if id == "" {
+		return nil, &CommandSyntaxError{Err: &ReaderError{
+			Err:    ErrExpectedIdentifier,
+			Reader: rd,
+		}}
+	}
+	position := 0
+	for i, c := range id {
+		position++
+		if (position != 1 || t.First == nil || t.First(c)) && t.IsAllowed(c) {
+			continue
+		}
+		rd.Cursor = start + i
+		return nil, &CommandSyntaxError{Err: &ReaderError{
+			Err: &InvalidRuneError{
+				Err:        ErrInvalidIdentifier,
+				Identifier: id,
+				Rune:       c,
+				Position:   position,
+			},
+			Reader: rd,
+		}}
+	}
+	rd.Cursor = end
+	return id, nil
+}
+
+// IsAllowed indicates whether c is an allowed rune of the identifier.
+// The First rule is not checked.
+func (t *IdentifierArgumentType) IsAllowed(c rune) bool {
+	for _, e := range t.Extra {
+		if c == e {
+			return true
+		}
+	}
+	return IsIdentifierRune(c)
+}
@@ -0,0 +1,50 @@
+package brigodier
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestIdentifierArgumentType(t *testing.T) {
+	r := &StringReader{String: "Home_2 x"}
+	id, err := Identifier.Parse(r)
+	require.NoError(t, err)
+	require.Equal(t, "Home_2", id)
+	require.Equal(t, " x", r.Remaining())
+
+	r = &StringReader{String: "set my-home"}
+	r.Cursor = 4
+	_, err = Identifier.Parse(r)
+	require.ErrorIs(t, err, ErrInvalidIdentifier)
+	require.Equal(t, `invalid identifier "my-home": '-' at position 3 is not allowed`, err.Error())
+	var runeErr *InvalidRuneError
+	require.ErrorAs(t, err, &runeErr)
+	require.Equal(t, '-', runeErr.Rune)
+	require.Equal(t, 3, runeErr.Position)
+	require.Equal(t, 6, r.Cursor) // at the invalid rune
+
+	_, err = Identifier.Parse(&StringReader{String: "wärp"})
+	require.ErrorAs(t, err, &runeErr)
+	require.Equal(t, 'ä', runeErr.Rune)
+	require.Equal(t, 2, runeErr.Position)
+
+	_, err = Identifier.Parse(&StringReader{String: " "})
+	require.ErrorIs(t, err, ErrExpectedIdentifier)
+
+	letterFirst := &IdentifierArgumentType{Extra: []rune{'-'}, First: IsASCIILetter}
+	id, err = letterFirst.Parse(&StringReader{String: "my-home"})
+	require.NoError(t, err)
+	require.Equal(t, "my-home", id)
+	_, err = letterFirst.Parse(&StringReader{String: "2nd"})
+	require.ErrorAs(t, err, &runeErr)
+	require.Equal(t, '2', runeErr.Rune)
+	require.Equal(t, 1, runeErr.Position)
+
+	d := NewDispatcher()
+	d.Register(Literal("warp").Then(Argument("name", Identifier).Executes(CommandFunc(func(*CommandContext) error { return nil }))))
+	err = d.Do(context.TODO(), "warp sp@wn")
+	require.ErrorIs(t, err, ErrInvalidIdentifier)
+	require.ErrorAs(t, err, &runeErr)
+	require.Equal(t, '@', runeErr.Rune)
+}
@@ -0,0 +1,163 @@
+package brigodier
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/emirpasic/gods/maps/linkedhashmap"
+	"io"
+)
+
+// ImportOptions configures Dispatcher.ImportJSON.
+type ImportOptions struct {
+	// Types optionally replaces the TypeRegistry of the Dispatcher used
+	// to look up the argument parsers. Unknown parsers are imported
+	// as *PlaceholderType, see TypeRegistry.NewOrPlaceholder.
+	Types *TypeRegistry
+	// Command optionally returns the Command of an executable node by its path.
+	// Executable nodes without Command return ErrImportedCommand when executed.
+	Command func(path []string) Command
+	// RedirectDeadEnds redirects non-executable nodes without children and redirect
+	// to the root. Brigadier omits redirects to the root in its JSON format,
+	// e.g. for "execute run", so they are restored by this option.
+	RedirectDeadEnds bool
+}
+
+// ErrImportedCommand is returned by executable nodes imported by Dispatcher.ImportJSON
+// without a Command from ImportOptions.Command.
+var ErrImportedCommand = errors.New("imported command is not implemented")
+
+// ImportJSON adds the command tree read from r in Brigadier's JSON format to the Root,
+// e.g. the commands.json report of the vanilla Minecraft server or a DumpJSON.
+//
+// Children of the Root with the same name are merged like by AddChild and
+// redirects are resolved by path after the whole tree is added.
+func (d *Dispatcher) ImportJSON(r io.Reader, opts ImportOptions) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var root nodeJSON
+	if err := dec.Decode(&root); err != nil {
+		return fmt.Errorf("error decoding command tree: %w", err)
+	}
+	if root.Type != nodeTypeRoot {
+		return fmt.Errorf("error decoding command tree: expected %q node but got %q", nodeTypeRoot, root.Type)
+	}
+	if opts.Types == nil {
+		opts.Types = d.types()
+	}
+	im := &importer{opts: opts}
+	var children []CommandNode
+	var err error
+	root.children(func(name string, child *nodeJSON) bool {
+		var node CommandNode
+		if node, err = im.node(name, child, nil); err == nil {
+			children = append(children, node)
+		}
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		d.Root.AddChild(child)
+	}
+	for _, r := range im.redirects {
+		target := d.FindNode(r.target...)
+		if target == nil {
+			return fmt.Errorf("error importing %q: %w: %q", r.node.Name(), ErrRedirectNotFound, r.target)
+		}
+		baseNode(r.node).redirect = target
+	}
+	d.ClearParseCache()
+	d.ClearSuggestionCache()
+	return nil
+}
+
+type importer struct {
+	opts      ImportOptions
+	redirects []importedRedirect
+}
+
+type importedRedirect struct {
+	node   CommandNode
+	target []string
+}
+
+func (im *importer) node(name string, j *nodeJSON, parent []string) (CommandNode, error) {
+	path := append(append(make([]string, 0, len(parent)+1), parent...), name)
+	var node CommandNode
+	switch j.Type {
+	case nodeTypeLiteral:
+		node = &LiteralCommandNode{Literal: name}
+	case nodeTypeArgument:
+		t, err := im.opts.Types.NewOrPlaceholder(j.Parser, j.Properties)
+		if err != nil {
+			return nil, fmt.Errorf("error importing %q: %w", path, err)
+		}
+		node = &ArgumentCommandNode{name: name, argType: t}
+	default:
+		return nil, fmt.Errorf("error importing %q: unexpected node type %q", path, j.Type)
+	}
+	n := baseNode(node)
+	if j.Executable {
+		if im.opts.Command != nil {
+			n.command = im.opts.Command(path)
+		}
+		if n.command == nil {
+			n.command = CommandFunc(func(*CommandContext) error { return ErrImportedCommand })
+		}
+	}
+	switch {
+	case j.Redirect != nil:
+		im.redirects = append(im.redirects, importedRedirect{node: node, target: j.Redirect})
+	case im.opts.RedirectDeadEnds && !j.Executable && (j.Children == nil || j.Children.Empty()):
+		im.redirects = append(im.redirects, importedRedirect{node: node, target: []string{}})
+	}
+	var err error
+	j.children(func(name string, child *nodeJSON) bool {
+		var c CommandNode
+		if c, err = im.node(name, child, path); err == nil {
+			node.AddChild(c)
+		}
+		return err == nil
+	})
+	return node, err
+}
+
+// children calls fn for each child in order until fn returns false.
+func (j *nodeJSON) children(fn func(name string, child *nodeJSON) bool) {
+	if j.Children == nil {
+		return
+	}
+	it := j.Children.Iterator()
+	for it.Next() {
+		if !fn(it.Key().(string), it.Value().(*nodeJSON)) {
+			return
+		}
+	}
+}
+
+// UnmarshalJSON decodes the children keeping their order.
+func (m *orderedNodes) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if t, err := dec.Token(); err != nil {
+		return err
+	} else if t != json.Delim('{') {
+		return fmt.Errorf("expected children object but got %v", t)
+	}
+	m.Map = linkedhashmap.New()
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		child := new(nodeJSON)
+		if err = dec.Decode(child); err != nil {
+			return err
+		}
+		m.Put(key, child)
+	}
+	return nil
+}
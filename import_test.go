@@ -0,0 +1,99 @@
+package brigodier
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const vanillaCommandsJSON = `{
+  "type": "root",
+  "children": {
+    "msg": {
+      "type": "literal",
+      "children": {
+        "targets": {
+          "type": "argument",
+          "parser": "minecraft:entity",
+          "properties": {"amount": "multiple", "type": "players"},
+          "children": {
+            "message": {"type": "argument", "parser": "minecraft:message", "executable": true}
+          }
+        }
+      }
+    },
+    "tell": {"type": "literal", "redirect": ["msg"]},
+    "execute": {
+      "type": "literal",
+      "children": {
+        "run": {"type": "literal"},
+        "if": {
+          "type": "literal",
+          "children": {
+            "score": {
+              "type": "argument",
+              "parser": "brigadier:integer",
+              "properties": {"min": 0, "max": 100},
+              "executable": true
+            }
+          }
+        }
+      }
+    },
+    "seed": {"type": "literal", "executable": true}
+  }
+}`
+
+func TestDispatcher_ImportJSON(t *testing.T) {
+	var seeds int
+	d := &Dispatcher{}
+	require.NoError(t, d.ImportJSON(strings.NewReader(vanillaCommandsJSON), ImportOptions{
+		RedirectDeadEnds: true,
+		Command: func(path []string) Command {
+			if strings.Join(path, " ") != "seed" {
+				return nil
+			}
+			return CommandFunc(func(*CommandContext) error { seeds++; return nil })
+		},
+	}))
+
+	var names []string
+	d.Root.ChildrenOrdered().Range(func(name string, _ CommandNode) bool {
+		names = append(names, name)
+		return true
+	})
+	require.Equal(t, []string{"msg", "tell", "execute", "seed"}, names)
+
+	targets := d.FindNode("msg", "targets").(*ArgumentCommandNode)
+	require.Equal(t, &PlaceholderType{ID: "minecraft:entity", Properties: TypeProperties{"amount": "multiple", "type": "players"}}, targets.Type())
+	require.Equal(t, &Int32ArgumentType{Min: 0, Max: 100}, d.FindNode("execute", "if", "score").(*ArgumentCommandNode).Type())
+	require.Equal(t, d.FindNode("msg"), d.FindNode("tell").Redirect())
+	require.Equal(t, &d.Root, d.FindNode("execute", "run").Redirect())
+
+	require.NoError(t, d.Do(context.TODO(), "execute run seed"))
+	require.Equal(t, 1, seeds)
+	require.ErrorIs(t, d.Do(context.TODO(), "tell @a hi"), ErrImportedCommand)
+
+	// round trip
+	var dump bytes.Buffer
+	require.NoError(t, d.Dump(context.TODO(), &dump, &d.Root, DumpJSON))
+	imported := &Dispatcher{}
+	require.NoError(t, imported.ImportJSON(&dump, ImportOptions{}))
+	var again bytes.Buffer
+	require.NoError(t, imported.Dump(context.TODO(), &again, &imported.Root, DumpJSON))
+	dump.Reset()
+	require.NoError(t, d.Dump(context.TODO(), &dump, &d.Root, DumpJSON))
+	require.JSONEq(t, dump.String(), again.String())
+}
+
+func TestDispatcher_ImportJSON_Errors(t *testing.T) {
+	d := &Dispatcher{}
+	require.Error(t, d.ImportJSON(strings.NewReader(`{"type": "literal"}`), ImportOptions{}))
+	require.ErrorIs(t, d.ImportJSON(strings.NewReader(
+		`{"type": "root", "children": {"a": {"type": "literal", "redirect": ["b"]}}}`), ImportOptions{}), ErrRedirectNotFound)
+	require.ErrorIs(t, d.ImportJSON(strings.NewReader(
+		`{"type": "root", "children": {"a": {"type": "argument", "parser": "brigadier:integer", "properties": {"min": "x"}}}}`),
+		ImportOptions{}), ErrInvalidTypeProperty)
+}
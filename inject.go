@@ -0,0 +1,46 @@
+package brigodier
+
+import "fmt"
+
+// InjectFn resolves the value of an implicit argument from the execution environment,
+// e.g. the executing player from the context.Context, so that commands can read it
+// using CommandContext.Get and friends like an argument parsed from the input:
+//
+//	d.Register(Literal("heal").
+//		Inject("target", func(c *CommandContext) (interface{}, error) { return playerFrom(c) }).
+//		Executes(heal).
+//		Then(Argument("target", StringWord).Executes(heal)))
+//
+// Implicit arguments of the executed nodes are resolved by Dispatcher.Execute right
+// before running the command and only if the argument was not parsed from the input,
+// so the input can override them. Injected arguments have no range in the input.
+type InjectFn func(c *CommandContext) (interface{}, error)
+
+// InjectedArgument is an implicit argument of a node, see CommandNode.Injections.
+type InjectedArgument struct {
+	Name    string   // The argument name.
+	Resolve InjectFn // Resolves the argument value.
+}
+
+// inject returns a copy of the context with the implicit arguments of the
+// nodes executed with it, see chainNodes, or c itself if there are none,
+// since the context may be shared, e.g. by the parse cache.
+func inject(c *CommandContext, nodes []*ParsedCommandNode) (*CommandContext, error) {
+	injected := c
+	for _, parsed := range nodes {
+		for _, arg := range parsed.Node.Injections() {
+			if injected.Has(arg.Name) {
+				continue
+			}
+			v, err := arg.Resolve(injected)
+			if err != nil {
+				return c, fmt.Errorf("error injecting argument %q: %w", arg.Name, err)
+			}
+			if injected == c {
+				injected = c.Copy()
+			}
+			injected.Arguments[arg.Name] = &ParsedArgument{Result: v}
+		}
+	}
+	return injected, nil
+}
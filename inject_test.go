@@ -0,0 +1,59 @@
+package brigodier
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDispatcher_Execute_Inject(t *testing.T) {
+	d := NewDispatcher(WithParseCache(8))
+	var healed []string
+	heal := CommandFunc(func(c *CommandContext) error {
+		healed = append(healed, c.String("target"))
+		return nil
+	})
+	errNoPlayer := errors.New("not a player")
+	sender := func(c *CommandContext) (interface{}, error) {
+		if src := c.Source(); src != nil {
+			return src.Name(), nil
+		}
+		return nil, errNoPlayer
+	}
+	d.Register(Literal("heal").Inject("target", sender).Executes(heal).
+		Then(Argument("target", StringWord).Executes(heal)))
+
+	alice := WithSource(context.TODO(), &testSource{name: "alice"})
+	bob := WithSource(context.TODO(), &testSource{name: "bob"})
+	require.NoError(t, d.Do(alice, "heal"))
+	require.NoError(t, d.Do(bob, "heal"))
+	require.NoError(t, d.Do(alice, "heal carol"))
+	require.Equal(t, []string{"alice", "bob", "carol"}, healed)
+
+	err := d.Do(context.TODO(), "heal")
+	require.ErrorIs(t, err, errNoPlayer)
+
+	// the cached parse results are not modified
+	parse := d.Parse(alice, "heal")
+	require.NoError(t, d.Execute(parse))
+	require.False(t, parse.Context.Has("target"))
+
+	require.Len(t, d.FindNode("heal").CreateBuilder().Build().Injections(), 1)
+}
+
+func TestDispatcher_Execute_InjectRedirect(t *testing.T) {
+	var d Dispatcher
+	var got string
+	d.Register(Literal("whoami").Executes(CommandFunc(func(c *CommandContext) error {
+		got = c.String("caller")
+		return nil
+	})))
+	execute := d.Register(Literal("execute").Inject("caller", func(c *CommandContext) (interface{}, error) {
+		return c.Source().Name(), nil
+	}))
+	execute.AddChild(Literal("run").Redirect(&d.Root).Build())
+
+	require.NoError(t, d.Do(WithSource(context.TODO(), &testSource{name: "alice"}), "execute run whoami"))
+	require.Equal(t, "alice", got)
+}
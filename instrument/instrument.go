@@ -0,0 +1,201 @@
+// Package instrument adapts tracers and meters to brigodier.Instrumentation
+// to monitor the health of a command subsystem.
+//
+// Tracer and Meter are small interfaces to keep this package free of
+// dependencies. They are implemented on top of OpenTelemetry like:
+//
+//	type otelTracer struct{ trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string, attrs ...instrument.Attribute) (context.Context, instrument.Span) {
+//		ctx, span := t.Tracer.Start(ctx, name, trace.WithAttributes(toOtel(attrs)...))
+//		return ctx, otelSpan{span}
+//	}
+//
+//	type otelSpan struct{ trace.Span }
+//
+//	func (s otelSpan) End(err error) {
+//		if err != nil {
+//			s.RecordError(err)
+//			s.SetStatus(codes.Error, err.Error())
+//		}
+//		s.Span.End()
+//	}
+//
+// and passed to the Dispatcher:
+//
+//	d := brigodier.NewDispatcher(brigodier.WithInstrumentation(
+//		instrument.New(otelTracer{otel.Tracer("brigodier")}, otelMeter{...})))
+package instrument
+
+import (
+	"context"
+	"go.minekube.com/brigodier"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Span and metric names used by New.
+const (
+	SpanParse   = "brigodier.parse"
+	SpanExecute = "brigodier.execute"
+	SpanCommand = "brigodier.command"
+	SpanSuggest = "brigodier.suggest"
+
+	// MetricCommands counts the commands run by attribute AttrCommand and AttrError.
+	MetricCommands = "brigodier.commands"
+	// MetricDuration records the latency of each phase by attribute AttrPhase.
+	MetricDuration = "brigodier.duration"
+)
+
+// Attribute keys used by New.
+const (
+	AttrInput   = "brigodier.input"
+	AttrCommand = "brigodier.command" // The path of the command, e.g. "tp [target]".
+	AttrPhase   = "brigodier.phase"   // One of "parse", "execute", "command" and "suggest".
+	AttrError   = "brigodier.error"   // Whether the phase failed.
+)
+
+// Attribute is a key-value pair describing a span or measurement.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Tracer starts spans.
+type Tracer interface {
+	// Start starts a span as child of the span in ctx, if any.
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span is a started span.
+type Span interface {
+	// End ends the span with the error of the traced operation, if any.
+	End(err error)
+}
+
+// Meter records measurements.
+type Meter interface {
+	// Count increments the counter name by one.
+	Count(ctx context.Context, name string, attrs ...Attribute)
+	// Duration records a latency of the histogram name.
+	Duration(ctx context.Context, name string, d time.Duration, attrs ...Attribute)
+}
+
+// New returns a brigodier.Instrumentation recording spans using tracer and
+// measurements using meter. Either of them may be nil.
+//
+// Spans of commands are children of the context.Context the command
+// was executed with, since the span contexts can not be passed to commands.
+func New(tracer Tracer, meter Meter) brigodier.Instrumentation {
+	return &instrumentation{tracer: tracer, meter: meter}
+}
+
+type instrumentation struct {
+	tracer Tracer
+	meter  Meter
+}
+
+var _ brigodier.Instrumentation = (*instrumentation)(nil)
+
+func (i *instrumentation) StartParse(ctx context.Context, input string) func(*brigodier.ParseResults) {
+	end := i.start(ctx, SpanParse, "parse", Attribute{Key: AttrInput, Value: input})
+	return func(parse *brigodier.ParseResults) { end(parseError(parse)) }
+}
+
+func (i *instrumentation) StartExecute(parse *brigodier.ParseResults) func(error) {
+	return i.start(callerContext(parse), SpanExecute, "execute", Attribute{Key: AttrInput, Value: parse.Reader.String})
+}
+
+func (i *instrumentation) StartCommand(c *brigodier.CommandContext) func(error) {
+	path := commandPath(c)
+	end := i.start(c, SpanCommand, "command", Attribute{Key: AttrCommand, Value: path})
+	return func(err error) {
+		end(err)
+		if i.meter != nil {
+			i.meter.Count(c, MetricCommands,
+				Attribute{Key: AttrCommand, Value: path},
+				Attribute{Key: AttrError, Value: err != nil})
+		}
+	}
+}
+
+func (i *instrumentation) StartSuggest(parse *brigodier.ParseResults, cursor int) func(*brigodier.Suggestions, error) {
+	end := i.start(callerContext(parse), SpanSuggest, "suggest", Attribute{Key: AttrInput, Value: parse.Reader.String[:cursor]})
+	return func(_ *brigodier.Suggestions, err error) { end(err) }
+}
+
+// start starts a span and returns a function ending it and recording its duration.
+func (i *instrumentation) start(ctx context.Context, span, phase string, attrs ...Attribute) func(error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var s Span
+	if i.tracer != nil {
+		_, s = i.tracer.Start(ctx, span, attrs...)
+	}
+	start := time.Now()
+	return func(err error) {
+		if i.meter != nil {
+			i.meter.Duration(ctx, MetricDuration, time.Since(start),
+				Attribute{Key: AttrPhase, Value: phase},
+				Attribute{Key: AttrError, Value: err != nil})
+		}
+		if s != nil {
+			s.End(err)
+		}
+	}
+}
+
+// callerContext returns the context.Context the input was parsed with,
+// rather than the CommandContext wrapping it.
+func callerContext(parse *brigodier.ParseResults) context.Context {
+	if parse.Context == nil {
+		return nil
+	}
+	return parse.Context.Context
+}
+
+// parseError returns the error of the first node in registration order
+// that failed to parse the remaining input, if any, so that spans of
+// the same input always report the same error.
+func parseError(parse *brigodier.ParseResults) error {
+	if !parse.Reader.CanRead() || len(parse.Errs) == 0 {
+		return nil
+	}
+	if parse.Context != nil {
+		c := parse.Context
+		for c.Child != nil {
+			c = c.Child
+		}
+		parent := c.RootNode
+		if len(c.Nodes) != 0 {
+			parent = c.Nodes[len(c.Nodes)-1].Node
+		}
+		var err error
+		if parent != nil {
+			parent.ChildrenOrdered().Range(func(_ string, child brigodier.CommandNode) bool {
+				err = parse.Errs[child]
+				return err == nil
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	nodes := make([]brigodier.CommandNode, 0, len(parse.Errs))
+	for n := range parse.Errs {
+		nodes = append(nodes, n)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].UsageText() < nodes[j].UsageText() })
+	return parse.Errs[nodes[0]]
+}
+
+// commandPath returns the usage of the nodes of the command, e.g. "tp [target]".
+func commandPath(c *brigodier.CommandContext) string {
+	parts := make([]string, len(c.Nodes))
+	for i, n := range c.Nodes {
+		parts[i] = n.Node.UsageText()
+	}
+	return strings.Join(parts, " ")
+}
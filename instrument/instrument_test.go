@@ -0,0 +1,123 @@
+package instrument
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"go.minekube.com/brigodier"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) record(format string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	r.record("start %s %v", name, attrs[0].Value)
+	return ctx, span{r, name}
+}
+
+type span struct {
+	r    *recorder
+	name string
+}
+
+func (s span) End(err error) { s.r.record("end %s %v", s.name, err) }
+
+func (r *recorder) Count(_ context.Context, name string, attrs ...Attribute) {
+	r.record("count %s %v %v", name, attrs[0].Value, attrs[1].Value)
+}
+
+func (r *recorder) Duration(_ context.Context, name string, _ time.Duration, attrs ...Attribute) {
+	r.record("duration %s %v", name, attrs[0].Value)
+}
+
+func TestNew(t *testing.T) {
+	r := &recorder{}
+	errFail := errors.New("fail")
+	d := brigodier.NewDispatcher(brigodier.WithInstrumentation(New(r, r)))
+	d.Register(brigodier.Literal("tp").Then(brigodier.Argument("target", brigodier.StringWord).
+		Executes(brigodier.CommandFunc(func(c *brigodier.CommandContext) error { return errFail }))))
+
+	require.ErrorIs(t, d.Do(context.TODO(), "tp Steve"), errFail)
+	require.Equal(t, []string{
+		"start brigodier.parse tp Steve",
+		"duration brigodier.duration parse",
+		"end brigodier.parse <nil>",
+		"start brigodier.execute tp Steve",
+		"start brigodier.command tp [target]",
+		"duration brigodier.duration command",
+		"end brigodier.command fail",
+		"count brigodier.commands tp [target] true",
+		"duration brigodier.duration execute",
+		"end brigodier.execute fail",
+	}, r.events)
+
+	r.events = nil
+	_, err := d.CompletionSuggestions(d.Parse(context.TODO(), "t"))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"start brigodier.parse t",
+		"duration brigodier.duration parse",
+		"end brigodier.parse <nil>",
+		"start brigodier.suggest t",
+		"duration brigodier.duration suggest",
+		"end brigodier.suggest <nil>",
+	}, r.events)
+
+	// Tracer and Meter are optional.
+	d.Instrumentation = New(nil, nil)
+	require.ErrorIs(t, d.Do(context.TODO(), "tp Steve"), errFail)
+}
+
+type ctxKey struct{}
+
+type contextTracer struct{ parents map[string]context.Context }
+
+func (t *contextTracer) Start(ctx context.Context, name string, _ ...Attribute) (context.Context, Span) {
+	t.parents[name] = ctx
+	return ctx, span{&recorder{}, name}
+}
+
+func TestNew_CallerContext(t *testing.T) {
+	tracer := &contextTracer{parents: map[string]context.Context{}}
+	d := brigodier.NewDispatcher(brigodier.WithInstrumentation(New(tracer, nil)))
+	d.Register(brigodier.Literal("ping").Executes(brigodier.CommandFunc(func(*brigodier.CommandContext) error { return nil })))
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+	require.NoError(t, d.Do(ctx, "ping"))
+	_, err := d.CompletionSuggestions(d.Parse(ctx, "p"))
+	require.NoError(t, err)
+
+	for _, name := range []string{SpanParse, SpanExecute, SpanSuggest} {
+		require.Equal(t, ctx, tracer.parents[name], name)
+	}
+	require.IsType(t, &brigodier.CommandContext{}, tracer.parents[SpanCommand])
+}
+
+func TestNew_ParseError(t *testing.T) {
+	r := &recorder{}
+	d := brigodier.NewDispatcher(brigodier.WithInstrumentation(New(r, nil)))
+	d.Register(brigodier.Literal("set").
+		Then(brigodier.Argument("count", brigodier.Int)).
+		Then(brigodier.Argument("enabled", brigodier.Bool)).
+		Then(brigodier.Argument("ratio", brigodier.Float64)))
+
+	_, want := brigodier.Int.Parse(&brigodier.StringReader{String: "x"})
+	for i := 0; i < 20; i++ {
+		r.events = nil
+		d.Parse(context.TODO(), "set x")
+		require.True(t, strings.HasSuffix(r.events[1], want.Error()), r.events[1])
+	}
+}
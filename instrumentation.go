@@ -0,0 +1,42 @@
+package brigodier
+
+import "context"
+
+// Instrumentation observes the phases of a Dispatcher, e.g. to trace and
+// measure the health of the command subsystem, see package instrument.
+//
+// Each method is called when a phase starts and returns a function
+// that is called with the outcome when the phase ends.
+// Implementations must be safe for concurrent use.
+type Instrumentation interface {
+	// StartParse is called by Parse and ParseReader.
+	StartParse(ctx context.Context, input string) (end func(parse *ParseResults))
+	// StartExecute is called by Execute and Do.
+	StartExecute(parse *ParseResults) (end func(err error))
+	// StartCommand is called for each command run by Execute,
+	// including hooks registered using BeforeExecute and AfterExecute.
+	StartCommand(c *CommandContext) (end func(err error))
+	// StartSuggest is called by CompletionSuggestions and CompletionSuggestionsCursor.
+	StartSuggest(parse *ParseResults, cursor int) (end func(suggestions *Suggestions, err error))
+}
+
+// Names of the caches passed to CacheInstrumentation.CacheLookup.
+const (
+	CacheParse      = "parse"      // The cache enabled by WithParseCache.
+	CacheSuggestion = "suggestion" // The cache enabled by WithSuggestionCache.
+)
+
+// CacheInstrumentation is optionally implemented by an Instrumentation
+// to observe the lookups of the caches of a Dispatcher, e.g. to monitor hit rates.
+type CacheInstrumentation interface {
+	// CacheLookup is called for each lookup of the named cache,
+	// one of CacheParse and CacheSuggestion.
+	CacheLookup(cache string, hit bool)
+}
+
+// observeCache notifies the Instrumentation of a cache lookup if it implements CacheInstrumentation.
+func (d *Dispatcher) observeCache(cache string, hit bool) {
+	if i, ok := d.Instrumentation.(CacheInstrumentation); ok {
+		i.CacheLookup(cache, hit)
+	}
+}
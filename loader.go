@@ -0,0 +1,268 @@
+package brigodier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CommandSpec declares a command node loaded by a Loader, e.g. from a config file:
+//
+//	[{"name": "spawn", "executes": "teleport-spawn", "permission": "server.spawn"},
+//	 {"name": "s", "redirect": ["spawn"]},
+//	 {"name": "heal", "permission": "server.heal", "children": [
+//	   {"name": "amount", "type": "brigadier:integer", "properties": {"min": 1}, "executes": "heal"}
+//	 ]}]
+//
+// The struct tags support decoding JSON as well as YAML, e.g. using gopkg.in/yaml.v3:
+//
+//	var specs []*brigodier.CommandSpec
+//	if err := yaml.Unmarshal(data, &specs); err != nil { ... }
+//	err := loader.Load(d, specs)
+type CommandSpec struct {
+	// Name is the literal or the argument name.
+	Name string `json:"name" yaml:"name"`
+	// Type is the identifier of the ArgumentType of an argument in the
+	// Loader.Types, e.g. "brigadier:string". Nodes without Type are literals.
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+	// Properties are the TypeProperties of the argument type.
+	Properties TypeProperties `json:"properties,omitempty" yaml:"properties,omitempty"`
+	// Permission is optionally required to use the node, see Loader.Permission.
+	Permission string `json:"permission,omitempty" yaml:"permission,omitempty"`
+	// Executes is the name of the handler in Loader.Handlers executed by the node.
+	Executes string `json:"executes,omitempty" yaml:"executes,omitempty"`
+	// Redirect is the path of the node to redirect to, e.g. to declare an alias.
+	// A non-nil empty path redirects to the root. Nodes with a redirect must not have children
+	// and execute the Command of the target unless they declare Executes.
+	Redirect []string `json:"redirect,omitempty" yaml:"redirect,omitempty"`
+	// Usage optionally overrides the usage text of the node.
+	Usage string `json:"usage,omitempty" yaml:"usage,omitempty"`
+	// Tags are the tags of the node.
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// Children are the child nodes.
+	Children []*CommandSpec `json:"children,omitempty" yaml:"children,omitempty"`
+}
+
+// Loader registers commands declared by CommandSpecs to a Dispatcher,
+// so simple commands and aliases can be declared without recompiling.
+type Loader struct {
+	// Handlers are the Commands referenced by CommandSpec.Executes.
+	Handlers map[string]Command
+	// Permission reports whether ctx has a CommandSpec.Permission.
+	// It is required if any loaded node declares a permission.
+	Permission func(ctx context.Context, permission string) bool
+	// Types optionally replaces the TypeRegistry of the Dispatcher
+	// used to look up CommandSpec.Type.
+	Types *TypeRegistry
+}
+
+// Loader errors wrapped by LoadError.
+var (
+	// ErrUnknownHandler occurs when CommandSpec.Executes is not in the Loader.Handlers.
+	ErrUnknownHandler = errors.New("unknown handler")
+	// ErrNoPermissionCheck occurs when a CommandSpec declares a permission but Loader.Permission is nil.
+	ErrNoPermissionCheck = errors.New("permission declared without permission check")
+	// ErrRedirectNotFound occurs when the path of CommandSpec.Redirect does not exist.
+	ErrRedirectNotFound = errors.New("redirect target not found")
+)
+
+// LoadError is returned by Loader.Load for an invalid CommandSpec.
+type LoadError struct {
+	Path []string // The path of the invalid node.
+	Err  error
+}
+
+// Unwrap implements errors.Unwrap.
+func (e *LoadError) Unwrap() error { return e.Err }
+func (e *LoadError) Error() string {
+	return fmt.Sprintf("invalid command spec %q: %v", strings.Join(e.Path, " "), e.Err)
+}
+
+// LoadJSON decodes a JSON array of CommandSpecs from r and loads them using Load.
+func (l *Loader) LoadJSON(d *Dispatcher, r io.Reader) error {
+	var specs []*CommandSpec
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if err := dec.Decode(&specs); err != nil {
+		return fmt.Errorf("error decoding command specs: %w", err)
+	}
+	return l.Load(d, specs)
+}
+
+// Load registers the commands declared by specs like Dispatcher.TryRegister.
+//
+// All specs are built and validated before any command is registered, so nothing
+// is registered if an error is returned, e.g. a *LoadError for the first invalid spec
+// or a *NameError or *CommandConflictError as returned by Dispatcher.TryRegister.
+// Redirects may target nodes declared by any of the specs or already registered
+// to the Dispatcher and are resolved before the Dispatcher.NamePolicy renames them.
+func (l *Loader) Load(d *Dispatcher, specs []*CommandSpec) error {
+	types := l.Types
+	if types == nil {
+		types = d.types()
+	}
+	var redirects []pendingRedirect
+	literals := make([]*LiteralCommandNode, 0, len(specs))
+	for _, spec := range specs {
+		b, err := l.builder(types, spec, nil, &redirects)
+		if err != nil {
+			return err
+		}
+		if b.l == nil {
+			return &LoadError{Path: []string{spec.Name}, Err: errors.New("commands must be literals")}
+		}
+		literals = append(literals, b.l.BuildLiteral())
+	}
+	for _, r := range redirects {
+		node := findLoaded(literals, r.path)
+		target := findLoaded(literals, r.target)
+		if target == nil {
+			target = d.findPolicyNode(r.target)
+		}
+		if target == nil {
+			return &LoadError{Path: r.path, Err: fmt.Errorf("%w: %q", ErrRedirectNotFound, strings.Join(r.target, " "))}
+		}
+		n := baseNode(node)
+		n.redirect = target
+		if n.command == nil {
+			n.command = target.Command()
+		}
+	}
+	if err := d.checkRegister(literals); err != nil {
+		return err
+	}
+	for _, literal := range literals {
+		d.addRoot(literal)
+	}
+	d.ClearParseCache()
+	d.ClearSuggestionCache()
+	return nil
+}
+
+// pendingRedirect is a redirect resolved after all specs are built.
+type pendingRedirect struct{ path, target []string }
+
+// findLoaded returns the node at the path in the loaded literals
+// or nil if none of them declares it. An empty path is not declared.
+func findLoaded(literals []*LiteralCommandNode, path []string) CommandNode {
+	if len(path) == 0 {
+		return nil
+	}
+	for _, literal := range literals {
+		if literal.Literal != path[0] {
+			continue
+		}
+		var node CommandNode = literal
+		for _, name := range path[1:] {
+			if node = node.Children()[name]; node == nil {
+				break
+			}
+		}
+		if node != nil {
+			return node
+		}
+	}
+	return nil
+}
+
+// findPolicyNode is like FindNode but also looks up
+// the path as renamed by the Dispatcher.NamePolicy.
+func (d *Dispatcher) findPolicyNode(path []string) CommandNode {
+	if node := d.FindNode(path...); node != nil || d.NamePolicy == nil {
+		return node
+	}
+	renamed := make([]string, len(path))
+	for i, name := range path {
+		var err error
+		if renamed[i], err = d.NamePolicy(name); err != nil {
+			return nil
+		}
+	}
+	return d.FindNode(renamed...)
+}
+
+// checkRegister applies the Dispatcher.NamePolicy to the literals and checks
+// that all of them can be registered, including conflicts among themselves.
+func (d *Dispatcher) checkRegister(literals []*LiteralCommandNode) error {
+	batch := map[string]*LiteralCommandNode{}
+	for _, literal := range literals {
+		if d.NamePolicy != nil {
+			if err := d.applyNamePolicy(literal); err != nil {
+				return err
+			}
+		}
+		if err := d.checkConflict(literal); err != nil {
+			return err
+		}
+		previous := batch[literal.Literal]
+		if previous == nil {
+			batch[literal.Literal] = literal
+			continue
+		}
+		switch d.ConflictPolicy {
+		case ConflictError:
+			return &CommandConflictError{Literal: literal.Literal, Paths: [][]string{{literal.Literal}}}
+		case ConflictMerge:
+			if err := mergeNode(previous, literal, d.MergeStrategy, []string{literal.Literal}, false); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (l *Loader) builder(types *TypeRegistry, spec *CommandSpec, parent []string,
+	redirects *[]pendingRedirect) (*nodeBuilder, error) {
+	path := append(append(make([]string, 0, len(parent)+1), parent...), spec.Name)
+	fail := func(err error) (*nodeBuilder, error) { return nil, &LoadError{Path: path, Err: err} }
+	if spec.Name == "" || strings.ContainsRune(spec.Name, ArgumentSeparator) {
+		return fail(fmt.Errorf("invalid name %q", spec.Name))
+	}
+	b := &nodeBuilder{}
+	if spec.Type == "" {
+		b.l = Literal(spec.Name)
+	} else {
+		t, err := types.New(spec.Type, spec.Properties)
+		if err != nil {
+			return fail(err)
+		}
+		b.a = Argument(spec.Name, t)
+	}
+	if spec.Executes != "" {
+		command, ok := l.Handlers[spec.Executes]
+		if !ok {
+			return fail(fmt.Errorf("%w %q", ErrUnknownHandler, spec.Executes))
+		}
+		b.Executes(command)
+	}
+	if spec.Permission != "" {
+		if l.Permission == nil {
+			return fail(fmt.Errorf("%w: %q", ErrNoPermissionCheck, spec.Permission))
+		}
+		permission, check := spec.Permission, l.Permission
+		b.Requires(func(ctx context.Context) bool { return check(ctx, permission) })
+	}
+	if spec.Usage != "" {
+		b.Usage(spec.Usage)
+	}
+	if len(spec.Tags) != 0 {
+		b.Tags(spec.Tags...)
+	}
+	if spec.Redirect != nil {
+		if len(spec.Children) != 0 {
+			return fail(ErrBuilderRedirectWithChildren)
+		}
+		*redirects = append(*redirects, pendingRedirect{path: path, target: spec.Redirect})
+	}
+	for _, child := range spec.Children {
+		c, err := l.builder(types, child, path, redirects)
+		if err != nil {
+			return nil, err
+		}
+		b.Then(c)
+	}
+	return b, nil
+}
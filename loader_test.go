@@ -0,0 +1,151 @@
+package brigodier
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+	"strings"
+	"testing"
+)
+
+type permissionsKey struct{}
+
+func TestLoader_LoadJSON(t *testing.T) {
+	var ran []string
+	l := &Loader{
+		Handlers: map[string]Command{
+			"spawn": CommandFunc(func(c *CommandContext) error {
+				ran = append(ran, "spawn")
+				return nil
+			}),
+			"heal": CommandFunc(func(c *CommandContext) error {
+				ran = append(ran, "heal "+c.Input[strings.LastIndex(c.Input, " ")+1:])
+				return nil
+			}),
+		},
+		Permission: func(ctx context.Context, permission string) bool {
+			perms, _ := ctx.Value(permissionsKey{}).([]string)
+			return containsString(perms, permission)
+		},
+	}
+	var d Dispatcher
+	require.NoError(t, l.LoadJSON(&d, strings.NewReader(`[
+  {"name": "s", "redirect": ["spawn"]},
+  {"name": "spawn", "executes": "spawn", "tags": ["teleport"]},
+  {"name": "heal", "permission": "server.heal", "children": [
+    {"name": "amount", "type": "brigadier:integer", "properties": {"min": 1, "max": 20}, "executes": "heal"}
+  ]}
+]`)))
+
+	ctx := context.WithValue(context.TODO(), permissionsKey{}, []string{"server.heal"})
+	require.NoError(t, d.Do(ctx, "s"))
+	require.NoError(t, d.Do(ctx, "heal 5"))
+	require.Error(t, d.Do(ctx, "heal 0"))
+	require.Error(t, d.Do(context.TODO(), "heal 5"))
+	require.Equal(t, []string{"spawn", "heal 5"}, ran)
+	require.Equal(t, []CommandNode{d.FindNode("spawn")}, d.NodesByTag("teleport"))
+	require.Equal(t, d.FindNode("spawn"), d.FindNode("s").Redirect())
+}
+
+func TestLoader_Load_Errors(t *testing.T) {
+	l := &Loader{Handlers: map[string]Command{"ok": CommandFunc(func(*CommandContext) error { return nil })}}
+	for _, tc := range []struct {
+		spec *CommandSpec
+		path []string
+		err  error
+	}{
+		{&CommandSpec{Name: "a", Executes: "missing"}, []string{"a"}, ErrUnknownHandler},
+		{&CommandSpec{Name: "a", Children: []*CommandSpec{{Name: "b", Type: "custom:type"}}}, []string{"a", "b"}, ErrUnknownArgumentType},
+		{&CommandSpec{Name: "a", Permission: "perm"}, []string{"a"}, ErrNoPermissionCheck},
+		{&CommandSpec{Name: "a", Redirect: []string{"b"}}, []string{"a"}, ErrRedirectNotFound},
+		{&CommandSpec{Name: "a", Redirect: []string{}, Children: []*CommandSpec{{Name: "b"}}}, []string{"a"}, ErrBuilderRedirectWithChildren},
+	} {
+		var d Dispatcher
+		err := l.Load(&d, []*CommandSpec{tc.spec})
+		require.ErrorIs(t, err, tc.err)
+		var loadErr *LoadError
+		require.ErrorAs(t, err, &loadErr)
+		require.Equal(t, tc.path, loadErr.Path)
+		require.Empty(t, d.Root.Children(), "nothing registered on error")
+	}
+
+	var d Dispatcher
+	require.Error(t, l.Load(&d, []*CommandSpec{{Name: "a", Type: "brigadier:bool"}}))
+
+	// redirect to the root and to an already registered command
+	d.Register(Literal("existing").Executes(l.Handlers["ok"]))
+	require.NoError(t, l.Load(&d, []*CommandSpec{
+		{Name: "run", Redirect: []string{}},
+		{Name: "alias", Redirect: []string{"existing"}},
+	}))
+	require.Equal(t, &d.Root, d.FindNode("run").Redirect())
+	require.NoError(t, d.Do(context.TODO(), "run alias"))
+}
+
+func TestLoader_Load_Atomic(t *testing.T) {
+	l := &Loader{}
+	d := &Dispatcher{ConflictPolicy: ConflictError}
+	d.Register(Literal("existing"))
+
+	// a later spec conflicts with a registered command
+	err := l.Load(d, []*CommandSpec{{Name: "first"}, {Name: "existing"}})
+	var conflict *CommandConflictError
+	require.ErrorAs(t, err, &conflict)
+	require.Nil(t, d.FindNode("first"), "nothing registered on error")
+
+	// specs conflicting among themselves
+	require.ErrorAs(t, l.Load(d, []*CommandSpec{{Name: "dup"}, {Name: "dup"}}), &conflict)
+	require.Nil(t, d.FindNode("dup"))
+
+	// a later spec is rejected by the NamePolicy
+	d.NamePolicy = RejectNameChars(":")
+	var nameErr *NameError
+	require.ErrorAs(t, l.Load(d, []*CommandSpec{{Name: "first"}, {Name: "a:b"}}), &nameErr)
+	require.Nil(t, d.FindNode("first"))
+}
+
+func TestLoader_Load_NamePolicyRedirect(t *testing.T) {
+	var ran int
+	l := &Loader{Handlers: map[string]Command{"spawn": CommandFunc(func(*CommandContext) error {
+		ran++
+		return nil
+	})}}
+	d := &Dispatcher{NamePolicy: LowercaseNames}
+	d.Register(Literal("Home").Executes(l.Handlers["spawn"]))
+
+	require.NoError(t, l.Load(d, []*CommandSpec{
+		{Name: "S", Redirect: []string{"Spawn"}},
+		{Name: "Spawn", Executes: "spawn"},
+		{Name: "H", Redirect: []string{"Home"}},
+	}))
+	require.Equal(t, d.FindNode("spawn"), d.FindNode("s").Redirect())
+	require.Equal(t, d.FindNode("home"), d.FindNode("h").Redirect())
+	require.NoError(t, d.Do(context.TODO(), "s"))
+	require.NoError(t, d.Do(context.TODO(), "h"))
+	require.Equal(t, 2, ran)
+}
+
+func TestLoader_Load_YAML(t *testing.T) {
+	var healed string
+	l := &Loader{Handlers: map[string]Command{"heal": CommandFunc(func(c *CommandContext) error {
+		healed = c.Input
+		return nil
+	})}}
+	var specs []*CommandSpec
+	require.NoError(t, yaml.Unmarshal([]byte(`
+- name: heal
+  children:
+    - name: amount
+      type: brigadier:integer
+      properties: {min: 1, max: 20}
+      executes: heal
+- name: h
+  redirect: [heal]
+`), &specs))
+
+	var d Dispatcher
+	require.NoError(t, l.Load(&d, specs))
+	require.NoError(t, d.Do(context.TODO(), "h 20"))
+	require.Equal(t, "h 20", healed)
+	require.Error(t, d.Do(context.TODO(), "heal 21"))
+}
@@ -0,0 +1,192 @@
+package brigodier
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MergeAction decides what happens to a property of a node when another node
+// with the same name is merged into it, e.g. by AddChild, and both define the property.
+// If only one of them defines the property, the merged node has it either way.
+type MergeAction uint8
+
+// MergeAction values.
+const (
+	// MergeDefault uses the default action of the property, see MergeStrategy.
+	MergeDefault MergeAction = iota
+	// MergeOverwrite replaces the property with the one of the added node.
+	MergeOverwrite
+	// MergeKeep keeps the property of the existing node.
+	MergeKeep
+	// MergeError rejects the merge with a *MergeConflictError.
+	MergeError
+)
+
+func (a MergeAction) String() string {
+	switch a {
+	case MergeDefault:
+		return "default"
+	case MergeOverwrite:
+		return "overwrite"
+	case MergeKeep:
+		return "keep"
+	case MergeError:
+		return "error"
+	}
+	return fmt.Sprintf("MergeAction(%d)", uint8(a))
+}
+
+// MergeStrategy decides how the properties of nodes with the same name are merged,
+// see MergeChild. The zero value is the strategy used by AddChild.
+type MergeStrategy struct {
+	// Command defaults to MergeOverwrite.
+	Command MergeAction
+	// Requirement defaults to MergeKeep.
+	Requirement MergeAction
+	// Redirect, including the RedirectModifier and fork flag, defaults to MergeKeep.
+	Redirect MergeAction
+	// Suggestions of arguments defaults to MergeKeep.
+	Suggestions MergeAction
+	// ArgumentType of arguments with unequal types defaults to MergeKeep.
+	// MergeError also rejects merging a literal and an argument with the same name.
+	ArgumentType MergeAction
+}
+
+// ErrMergeConflict is wrapped by MergeConflictError.
+var ErrMergeConflict = errors.New("merge conflict")
+
+// MergeConflictError is returned by MergeChild and Dispatcher.TryRegister
+// if the MergeStrategy rejected merging a property of a node.
+type MergeConflictError struct {
+	Path     []string // The path of the conflicting node starting with the added child.
+	Property string   // The conflicting property, e.g. "requirement".
+}
+
+// Unwrap implements errors.Unwrap.
+func (e *MergeConflictError) Unwrap() error { return ErrMergeConflict }
+func (e *MergeConflictError) Error() string {
+	return fmt.Sprintf("%v: %s of %q", ErrMergeConflict, e.Property, strings.Join(e.Path, " "))
+}
+
+// MergeChild adds the child to the parent like CommandNode.AddChild but merges it into
+// an existing child with the same name using the MergeStrategy. Nothing is modified
+// if a *MergeConflictError is returned.
+func MergeChild(parent, child CommandNode, s MergeStrategy) error {
+	if child == nil {
+		return nil
+	}
+	existing := parent.Children()[child.Name()]
+	if existing == nil {
+		parent.AddChild(child)
+		return nil
+	}
+	path := []string{child.Name()}
+	if err := mergeNode(existing, child, s, path, false); err != nil {
+		return err
+	}
+	return mergeNode(existing, child, s, path, true)
+}
+
+// mergeNode merges node into existing if apply or
+// otherwise only checks for conflicts.
+func mergeNode(existing, node CommandNode, s MergeStrategy, path []string, apply bool) error {
+	// resolve reports whether the property of existing is overwritten
+	// given whether node and existing have the property and their values conflict.
+	resolve := func(property string, action, def MergeAction, has, existingHas, conflict bool) (overwrite bool, err error) {
+		if !has {
+			return false, nil
+		}
+		if !existingHas {
+			return true, nil
+		}
+		if !conflict {
+			return false, nil
+		}
+		if action == MergeDefault {
+			action = def
+		}
+		switch action {
+		case MergeOverwrite:
+			return true, nil
+		case MergeError:
+			return false, &MergeConflictError{Path: path, Property: property}
+		}
+		return false, nil
+	}
+
+	ea, existingIsArg := existing.(*ArgumentCommandNode)
+	a, isArg := node.(*ArgumentCommandNode)
+	if existingIsArg != isArg && s.ArgumentType == MergeError {
+		return &MergeConflictError{Path: path, Property: "node kind"}
+	}
+
+	// Command
+	overwrite, err := resolve("command", s.Command, MergeOverwrite,
+		node.Command() != nil, existing.Command() != nil, true)
+	if err != nil {
+		return err
+	}
+	if overwrite && apply {
+		existing.setCommand(node.Command())
+	}
+
+	// Requirement
+	overwrite, err = resolve("requirement", s.Requirement, MergeKeep,
+		node.Requirement() != nil, existing.Requirement() != nil, true)
+	if err != nil {
+		return err
+	}
+	if overwrite && apply {
+		if n := baseNode(existing); n != nil {
+			n.requirement = node.Requirement()
+		}
+	}
+
+	// Redirect
+	overwrite, err = resolve("redirect", s.Redirect, MergeKeep,
+		node.Redirect() != nil, existing.Redirect() != nil, existing.Redirect() != node.Redirect())
+	if err != nil {
+		return err
+	}
+	if overwrite && apply {
+		if n := baseNode(existing); n != nil {
+			n.redirect = node.Redirect()
+			n.modifier = node.RedirectModifier()
+			n.forks = node.IsFork()
+		}
+	}
+
+	if existingIsArg && isArg {
+		// Suggestions
+		overwrite, err = resolve("suggestions", s.Suggestions, MergeKeep,
+			a.customSuggestions != nil, ea.customSuggestions != nil, true)
+		if err != nil {
+			return err
+		}
+		if overwrite && apply {
+			ea.customSuggestions = a.customSuggestions
+		}
+
+		// ArgumentType
+		overwrite, err = resolve("argument type", s.ArgumentType, MergeKeep,
+			a.argType != nil, ea.argType != nil, !reflect.DeepEqual(ea.argType, a.argType))
+		if err != nil {
+			return err
+		}
+		if overwrite && apply {
+			ea.argType = a.argType
+		}
+	}
+
+	node.ChildrenOrdered().Range(func(name string, child CommandNode) bool {
+		if other := existing.Children()[name]; other != nil {
+			err = mergeNode(other, child, s, append(path[:len(path):len(path)], name), apply)
+		} else if apply {
+			existing.AddChild(child)
+		}
+		return err == nil
+	})
+	return err
+}
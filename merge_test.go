@@ -0,0 +1,72 @@
+package brigodier
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestAddChild_MergesMissingProperties(t *testing.T) {
+	var d Dispatcher
+	op := func(ctx context.Context) bool { return false }
+	players := suggestionProviderFunc(func(_ *CommandContext, b *SuggestionsBuilder) *Suggestions { return b.Build() })
+	d.Register(Literal("kick").Then(Argument("player", StringWord)))
+	d.Register(Literal("kick").Requires(op).Then(Argument("player", StringWord).Suggests(players)))
+
+	require.NotNil(t, d.FindNode("kick").Requirement())
+	require.NotNil(t, d.FindNode("kick", "player").(*ArgumentCommandNode).CustomSuggestions())
+	require.ErrorIs(t, d.Do(context.TODO(), "kick x"), ErrDispatcherUnknownCommand)
+}
+
+func TestMergeChild(t *testing.T) {
+	cmd := CommandFunc(func(c *CommandContext) error { return nil })
+	allow := func(context.Context) bool { return true }
+	deny := func(context.Context) bool { return false }
+
+	root := &RootCommandNode{}
+	root.AddChild(Literal("a").Requires(allow).Then(Argument("n", Int).Executes(cmd)).Build())
+
+	// conflicts are rejected without modifying the tree
+	err := MergeChild(root, Literal("a").Requires(deny).Then(Argument("n", Int64).Then(Literal("b"))).Build(),
+		MergeStrategy{ArgumentType: MergeError})
+	require.ErrorIs(t, err, ErrMergeConflict)
+	var mergeErr *MergeConflictError
+	require.True(t, errors.As(err, &mergeErr))
+	require.Equal(t, &MergeConflictError{Path: []string{"a", "n"}, Property: "argument type"}, mergeErr)
+	require.Nil(t, root.Children()["a"].Children()["n"].Children()["b"])
+
+	err = MergeChild(root, Literal("a").Requires(deny).Build(), MergeStrategy{Requirement: MergeError})
+	require.Equal(t, &MergeConflictError{Path: []string{"a"}, Property: "requirement"}, err)
+
+	err = MergeChild(root, Literal("a").Then(Literal("n")).Build(), MergeStrategy{ArgumentType: MergeError})
+	require.Equal(t, &MergeConflictError{Path: []string{"a", "n"}, Property: "node kind"}, err)
+
+	// equal argument types do not conflict
+	require.NoError(t, MergeChild(root, Literal("a").Then(Argument("n", Int).Then(Literal("b"))).Build(),
+		MergeStrategy{ArgumentType: MergeError}))
+	require.NotNil(t, root.Children()["a"].Children()["n"].Children()["b"])
+
+	// overwrite
+	require.NoError(t, MergeChild(root, Literal("a").Requires(deny).Then(Argument("n", Int64)).Build(),
+		MergeStrategy{Requirement: MergeOverwrite, ArgumentType: MergeOverwrite}))
+	require.False(t, root.Children()["a"].CanUse(context.TODO()))
+	require.Equal(t, Int64, root.Children()["a"].Children()["n"].(*ArgumentCommandNode).Type())
+
+	// keep
+	require.NoError(t, MergeChild(root, Literal("a").Executes(cmd).Requires(allow).Build(),
+		MergeStrategy{Command: MergeKeep}))
+	require.False(t, root.Children()["a"].CanUse(context.TODO()))
+	require.NotNil(t, root.Children()["a"].Command())
+}
+
+func TestDispatcher_MergeStrategy(t *testing.T) {
+	d := NewDispatcher(WithMergeStrategy(MergeStrategy{Command: MergeError}))
+	cmd := CommandFunc(func(c *CommandContext) error { return nil })
+	d.Register(Literal("a").Executes(cmd))
+	_, err := d.TryRegister(Literal("a").Executes(cmd))
+	require.ErrorIs(t, err, ErrMergeConflict)
+	_, err = d.TryRegister(Literal("a").Then(Literal("b").Executes(cmd)))
+	require.NoError(t, err)
+	require.NotNil(t, d.FindNode("a", "b"))
+}
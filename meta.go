@@ -0,0 +1,65 @@
+package brigodier
+
+// Metadata stores arbitrary values of a CommandNode by key,
+// e.g. a category, a cooldown configuration or a client-side parser id.
+//
+// As with context.Context, keys should be of an unexported type
+// defined by the package using them to avoid collisions.
+// The zero value is an empty Metadata ready to use.
+// Metadata must not be modified concurrently with its use.
+type Metadata struct {
+	values map[interface{}]interface{}
+}
+
+// Set sets the value of a key.
+func (m *Metadata) Set(key, value interface{}) {
+	if m.values == nil {
+		m.values = map[interface{}]interface{}{}
+	}
+	m.values[key] = value
+}
+
+// Get returns the value of a key or nil if not set.
+func (m *Metadata) Get(key interface{}) interface{} {
+	return m.values[key]
+}
+
+// Lookup returns the value of a key and whether it is set.
+func (m *Metadata) Lookup(key interface{}) (value interface{}, ok bool) {
+	value, ok = m.values[key]
+	return
+}
+
+// Delete removes a key.
+func (m *Metadata) Delete(key interface{}) { delete(m.values, key) }
+
+// Len returns the number of keys set.
+func (m *Metadata) Len() int { return len(m.values) }
+
+// Range calls fn for each key and value until fn returns false.
+func (m *Metadata) Range(fn func(key, value interface{}) bool) {
+	for k, v := range m.values {
+		if !fn(k, v) {
+			return
+		}
+	}
+}
+
+// Copy returns a shallow copy of the Metadata.
+func (m *Metadata) Copy() Metadata {
+	if len(m.values) == 0 {
+		return Metadata{}
+	}
+	values := make(map[interface{}]interface{}, len(m.values))
+	for k, v := range m.values {
+		values[k] = v
+	}
+	return Metadata{values: values}
+}
+
+// MetaValue returns the metadata value of a key of the node as T
+// and whether it is set and of type T.
+func MetaValue[T any](node CommandNode, key interface{}) (T, bool) {
+	v, ok := node.Meta().Get(key).(T)
+	return v, ok
+}
@@ -0,0 +1,41 @@
+package brigodier
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type categoryKey struct{}
+
+func TestMetadata(t *testing.T) {
+	var d Dispatcher
+	d.Register(Literal("tp").
+		Meta(categoryKey{}, "teleport").
+		Then(Argument("target", StringWord).Meta("parser", 7)))
+
+	tp := d.Root.Children()["tp"]
+	category, ok := MetaValue[string](tp, categoryKey{})
+	require.True(t, ok)
+	require.Equal(t, "teleport", category)
+	_, ok = MetaValue[int](tp, categoryKey{})
+	require.False(t, ok)
+
+	target := tp.Children()["target"]
+	require.Equal(t, 7, target.Meta().Get("parser"))
+	require.Nil(t, target.Meta().Get(categoryKey{}))
+
+	// Nodes can be annotated after registration.
+	target.Meta().Set(categoryKey{}, "player")
+	require.Equal(t, 2, target.Meta().Len())
+	target.Meta().Delete("parser")
+	_, ok = target.Meta().Lookup("parser")
+	require.False(t, ok)
+
+	// Copied to and from builders.
+	clone := tp.CreateBuilder().Meta("x", 1).Build()
+	require.Equal(t, "teleport", clone.Meta().Get(categoryKey{}))
+	require.Equal(t, 1, clone.Meta().Get("x"))
+	require.Nil(t, tp.Meta().Get("x"))
+
+	require.Equal(t, 0, d.Root.Meta().Len())
+}
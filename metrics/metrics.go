@@ -0,0 +1,181 @@
+// Package metrics exports counters and histograms of a brigodier Dispatcher,
+// e.g. to operate servers handling many commands.
+//
+// Measurements are recorded to a MetricsSink. PrometheusSink is a
+// MetricsSink serving the recorded metrics in the Prometheus text format
+// without depending on the Prometheus client library:
+//
+//	sink := metrics.NewPrometheusSink()
+//	d := brigodier.NewDispatcher(
+//		brigodier.WithInstrumentation(metrics.New(sink)),
+//		brigodier.WithParseCache(1024),
+//	)
+//	http.Handle("/metrics", sink)
+package metrics
+
+import (
+	"context"
+	"errors"
+	"go.minekube.com/brigodier"
+	"time"
+)
+
+// Metric names recorded by Instrumentation.
+const (
+	// MetricParses counts the parsed inputs by label LabelResult.
+	// Inputs that could not be parsed completely are counted as "error".
+	MetricParses = "brigodier_parses_total"
+	// MetricExecutions counts the executed inputs by label LabelCommand and LabelResult.
+	MetricExecutions = "brigodier_executions_total"
+	// MetricErrors counts the failed executions by label LabelCode.
+	MetricErrors = "brigodier_errors_total"
+	// MetricExecuteDuration records the latency of executions in seconds by label LabelCommand.
+	MetricExecuteDuration = "brigodier_execute_duration_seconds"
+	// MetricSuggestDuration records the latency of suggestions in seconds.
+	MetricSuggestDuration = "brigodier_suggest_duration_seconds"
+	// MetricCacheLookups counts the cache lookups by label LabelCache and LabelResult.
+	MetricCacheLookups = "brigodier_cache_lookups_total"
+)
+
+// Label names recorded by Instrumentation.
+const (
+	LabelResult  = "result"  // "ok" or "error" and "hit" or "miss" for MetricCacheLookups.
+	LabelCommand = "command" // The root literal of the command or empty if unknown.
+	LabelCode    = "code"    // The code of the error, see ErrorCode.
+	LabelCache   = "cache"   // brigodier.CacheParse or brigodier.CacheSuggestion.
+)
+
+// Descriptions are the help texts of the metrics recorded by Instrumentation.
+var Descriptions = map[string]string{
+	MetricParses:          "Number of parsed command inputs.",
+	MetricExecutions:      "Number of executed command inputs by root literal.",
+	MetricErrors:          "Number of failed command executions by error code.",
+	MetricExecuteDuration: "Latency of command executions in seconds.",
+	MetricSuggestDuration: "Latency of command suggestions in seconds.",
+	MetricCacheLookups:    "Number of parse and suggestion cache lookups.",
+}
+
+// Label is a name-value pair identifying a series of a metric.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// MetricsSink records measurements.
+// Implementations must be safe for concurrent use.
+type MetricsSink interface {
+	// Add adds value to the counter name.
+	Add(name string, value float64, labels ...Label)
+	// Observe records value in the histogram name.
+	Observe(name string, value float64, labels ...Label)
+}
+
+// Instrumentation is a brigodier.Instrumentation recording the
+// metrics of a Dispatcher to a MetricsSink.
+type Instrumentation struct {
+	Sink MetricsSink
+	// ErrorCode returns the LabelCode of an execution error.
+	// If nil, ErrorCode is used.
+	ErrorCode func(err error) string
+}
+
+var (
+	_ brigodier.Instrumentation      = (*Instrumentation)(nil)
+	_ brigodier.CacheInstrumentation = (*Instrumentation)(nil)
+)
+
+// New returns a new Instrumentation recording to the sink.
+// Pass it to the Dispatcher using brigodier.WithInstrumentation.
+func New(sink MetricsSink) *Instrumentation {
+	return &Instrumentation{Sink: sink}
+}
+
+// StartParse implements brigodier.Instrumentation.
+func (i *Instrumentation) StartParse(_ context.Context, _ string) func(*brigodier.ParseResults) {
+	return func(parse *brigodier.ParseResults) {
+		result := "ok"
+		if parse.Reader.CanRead() {
+			result = "error" // the input could not be parsed completely
+		}
+		i.Sink.Add(MetricParses, 1, Label{Name: LabelResult, Value: result})
+	}
+}
+
+// StartExecute implements brigodier.Instrumentation.
+func (i *Instrumentation) StartExecute(parse *brigodier.ParseResults) func(error) {
+	start := time.Now()
+	command := rootLiteral(parse)
+	return func(err error) {
+		cmd := Label{Name: LabelCommand, Value: command}
+		i.Sink.Observe(MetricExecuteDuration, time.Since(start).Seconds(), cmd)
+		if err == nil {
+			i.Sink.Add(MetricExecutions, 1, cmd, Label{Name: LabelResult, Value: "ok"})
+			return
+		}
+		i.Sink.Add(MetricExecutions, 1, cmd, Label{Name: LabelResult, Value: "error"})
+		code := i.ErrorCode
+		if code == nil {
+			code = ErrorCode
+		}
+		i.Sink.Add(MetricErrors, 1, Label{Name: LabelCode, Value: code(err)})
+	}
+}
+
+// StartCommand implements brigodier.Instrumentation.
+// Commands are measured by StartExecute.
+func (i *Instrumentation) StartCommand(*brigodier.CommandContext) func(error) {
+	return func(error) {}
+}
+
+// StartSuggest implements brigodier.Instrumentation.
+func (i *Instrumentation) StartSuggest(*brigodier.ParseResults, int) func(*brigodier.Suggestions, error) {
+	start := time.Now()
+	return func(*brigodier.Suggestions, error) {
+		i.Sink.Observe(MetricSuggestDuration, time.Since(start).Seconds())
+	}
+}
+
+// CacheLookup implements brigodier.CacheInstrumentation.
+func (i *Instrumentation) CacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	i.Sink.Add(MetricCacheLookups, 1,
+		Label{Name: LabelCache, Value: cache},
+		Label{Name: LabelResult, Value: result})
+}
+
+// ErrorCode returns a short code classifying an error returned by
+// brigodier.Dispatcher.Execute, e.g. "unknown_command" or "timeout".
+// Errors of commands are classified as "command".
+func ErrorCode(err error) string {
+	var syntaxErr *brigodier.CommandSyntaxError
+	switch {
+	case errors.Is(err, brigodier.ErrDispatcherUnknownCommand):
+		return "unknown_command"
+	case errors.Is(err, brigodier.ErrDispatcherUnknownArgument):
+		return "unknown_argument"
+	case errors.Is(err, brigodier.ErrCommandTimeout):
+		return "timeout"
+	case errors.Is(err, brigodier.ErrRateLimited):
+		return "rate_limited"
+	case errors.Is(err, brigodier.ErrCooldown):
+		return "cooldown"
+	case errors.Is(err, brigodier.ErrDispatcherMaxDepthExceeded):
+		return "max_depth"
+	case errors.Is(err, brigodier.ErrTooManyRedirects):
+		return "too_many_redirects"
+	case errors.As(err, &syntaxErr):
+		return "syntax"
+	}
+	return "command"
+}
+
+// rootLiteral returns the name of the first parsed node or an empty string.
+func rootLiteral(parse *brigodier.ParseResults) string {
+	if parse.Context == nil || len(parse.Context.Nodes) == 0 {
+		return ""
+	}
+	return parse.Context.Nodes[0].Node.Name()
+}
@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"go.minekube.com/brigodier"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestInstrumentation(t *testing.T) {
+	sink := NewPrometheusSink()
+	d := brigodier.NewDispatcher(
+		brigodier.WithInstrumentation(New(sink)),
+		brigodier.WithParseCache(8),
+	)
+	d.Register(brigodier.Literal("ok").Executes(brigodier.CommandFunc(func(*brigodier.CommandContext) error { return nil })))
+	d.Register(brigodier.Literal("fail").Executes(brigodier.CommandFunc(func(*brigodier.CommandContext) error {
+		return errors.New("failed")
+	})))
+
+	ctx := context.TODO()
+	require.NoError(t, d.Do(ctx, "ok"))
+	require.NoError(t, d.Do(ctx, "ok"))
+	require.Error(t, d.Do(ctx, "fail"))
+	require.Error(t, d.Do(ctx, "unknown"))
+	_, err := d.CompletionSuggestions(d.Parse(ctx, "o"))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, sink.Write(&buf))
+	out := buf.String()
+	for _, line := range []string{
+		"# HELP brigodier_parses_total Number of parsed command inputs.",
+		"# TYPE brigodier_parses_total counter",
+		`brigodier_parses_total{result="ok"} 3`,
+		`brigodier_parses_total{result="error"} 2`,
+		`brigodier_executions_total{command="ok",result="ok"} 2`,
+		`brigodier_executions_total{command="fail",result="error"} 1`,
+		`brigodier_executions_total{command="",result="error"} 1`,
+		`brigodier_errors_total{code="command"} 1`,
+		`brigodier_errors_total{code="unknown_command"} 1`,
+		`brigodier_cache_lookups_total{cache="parse",result="hit"} 1`,
+		`brigodier_cache_lookups_total{cache="parse",result="miss"} 4`,
+		"# TYPE brigodier_suggest_duration_seconds histogram",
+		`brigodier_suggest_duration_seconds_bucket{le="+Inf"} 1`,
+		"brigodier_suggest_duration_seconds_count 1",
+		`brigodier_execute_duration_seconds_count{command="ok"} 2`,
+	} {
+		require.Contains(t, out, line+"\n")
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	require.Equal(t, "unknown_command", ErrorCode(&brigodier.CommandSyntaxError{Err: brigodier.ErrDispatcherUnknownCommand}))
+	require.Equal(t, "syntax", ErrorCode(&brigodier.CommandSyntaxError{Err: brigodier.ErrReaderExpectedInt}))
+	require.Equal(t, "timeout", ErrorCode(fmt.Errorf("%w: 1s", brigodier.ErrCommandTimeout)))
+	require.Equal(t, "command", ErrorCode(errors.New("failed")))
+
+	sink := NewPrometheusSink()
+	i := New(sink)
+	i.ErrorCode = func(error) string { return "custom" }
+	i.StartExecute(&brigodier.ParseResults{})(errors.New("failed"))
+	var buf bytes.Buffer
+	require.NoError(t, sink.Write(&buf))
+	require.Contains(t, buf.String(), `brigodier_errors_total{code="custom"} 1`)
+}
+
+func TestPrometheusSink(t *testing.T) {
+	sink := &PrometheusSink{Buckets: []float64{1, 2}}
+	sink.Observe("latency", 0.5, Label{Name: "b", Value: "x"}, Label{Name: "a", Value: "q\"\n"})
+	sink.Observe("latency", 2, Label{Name: "a", Value: "q\"\n"}, Label{Name: "b", Value: "x"})
+	sink.Observe("latency", 3, Label{Name: "a", Value: "q\"\n"}, Label{Name: "b", Value: "x"})
+	sink.Add("total", 2)
+	sink.Add("total", 0.5)
+
+	rec := httptest.NewRecorder()
+	sink.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	require.Equal(t, strings.Join([]string{
+		"# TYPE latency histogram",
+		`latency_bucket{a="q\"\n",b="x",le="1"} 1`,
+		`latency_bucket{a="q\"\n",b="x",le="2"} 2`,
+		`latency_bucket{a="q\"\n",b="x",le="+Inf"} 3`,
+		`latency_sum{a="q\"\n",b="x"} 5.5`,
+		`latency_count{a="q\"\n",b="x"} 3`,
+		"# TYPE total counter",
+		"total 2.5",
+		"",
+	}, "\n"), rec.Body.String())
+}
@@ -0,0 +1,193 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the default upper bounds of PrometheusSink histograms in seconds.
+var DefaultBuckets = []float64{.0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// PrometheusSink is a MetricsSink keeping the recorded metrics in memory
+// and serving them in the Prometheus text exposition format.
+type PrometheusSink struct {
+	// Buckets are the upper bounds of the histograms.
+	// They must be sorted and not be modified after the first Observe.
+	Buckets []float64
+	// Help are the help texts written for metrics, see Descriptions.
+	Help map[string]string
+
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+var (
+	_ MetricsSink  = (*PrometheusSink)(nil)
+	_ http.Handler = (*PrometheusSink)(nil)
+)
+
+// NewPrometheusSink returns a new PrometheusSink using
+// DefaultBuckets and the Descriptions of this package.
+func NewPrometheusSink() *PrometheusSink {
+	return &PrometheusSink{Buckets: DefaultBuckets, Help: Descriptions}
+}
+
+type family struct {
+	histogram bool
+	series    map[string]*series // by formatted labels
+}
+
+type series struct {
+	labels string   // The formatted labels without braces.
+	value  float64  // The counter value or the sum of observations.
+	count  uint64   // The number of observations.
+	counts []uint64 // The observations by bucket, not cumulative.
+}
+
+// Add implements MetricsSink.
+func (s *PrometheusSink) Add(name string, value float64, labels ...Label) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lookup(name, false, labels).value += value
+}
+
+// Observe implements MetricsSink.
+func (s *PrometheusSink) Observe(name string, value float64, labels ...Label) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m := s.lookup(name, true, labels)
+	if m.counts == nil {
+		m.counts = make([]uint64, len(s.Buckets))
+	}
+	m.value += value
+	m.count++
+	if i := sort.SearchFloat64s(s.Buckets, value); i < len(m.counts) {
+		m.counts[i]++
+	}
+}
+
+// lookup returns the series of the metric name with the labels.
+// A metric recorded as both counter and histogram keeps its first kind.
+func (s *PrometheusSink) lookup(name string, histogram bool, labels []Label) *series {
+	if s.families == nil {
+		s.families = map[string]*family{}
+	}
+	f, ok := s.families[name]
+	if !ok {
+		f = &family{histogram: histogram, series: map[string]*series{}}
+		s.families[name] = f
+	}
+	key := formatLabels(labels)
+	m, ok := f.series[key]
+	if !ok {
+		m = &series{labels: key}
+		f.series[key] = m
+	}
+	return m
+}
+
+// ServeHTTP implements http.Handler serving the metrics.
+func (s *PrometheusSink) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = s.Write(w)
+}
+
+// Write writes the metrics in the Prometheus text exposition format to w,
+// sorted by metric name and labels.
+func (s *PrometheusSink) Write(w io.Writer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	bw := bufio.NewWriter(w)
+	names := make([]string, 0, len(s.families))
+	for name := range s.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f := s.families[name]
+		if help, ok := s.Help[name]; ok {
+			bw.WriteString("# HELP " + name + " " + helpEscaper.Replace(help) + "\n")
+		}
+		typ := "counter"
+		if f.histogram {
+			typ = "histogram"
+		}
+		bw.WriteString("# TYPE " + name + " " + typ + "\n")
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			m := f.series[key]
+			if !f.histogram {
+				writeSample(bw, name, m.labels, "", m.value)
+				continue
+			}
+			var cumulative uint64
+			for i, upper := range s.Buckets {
+				if i < len(m.counts) {
+					cumulative += m.counts[i]
+				}
+				writeSample(bw, name+"_bucket", m.labels, formatFloat(upper), float64(cumulative))
+			}
+			writeSample(bw, name+"_bucket", m.labels, "+Inf", float64(m.count))
+			writeSample(bw, name+"_sum", m.labels, "", m.value)
+			writeSample(bw, name+"_count", m.labels, "", float64(m.count))
+		}
+	}
+	return bw.Flush()
+}
+
+// writeSample writes a sample line with the formatted labels and an optional le label.
+func writeSample(w *bufio.Writer, name, labels, le string, value float64) {
+	w.WriteString(name)
+	if le != "" {
+		if labels != "" {
+			labels += ","
+		}
+		labels += `le="` + le + `"`
+	}
+	if labels != "" {
+		w.WriteString("{" + labels + "}")
+	}
+	w.WriteString(" " + formatFloat(value) + "\n")
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+// formatLabels formats the labels sorted by name, e.g. `cache="parse",result="hit"`.
+func formatLabels(labels []Label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	sorted := append([]Label(nil), labels...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	var b strings.Builder
+	for i, l := range sorted {
+		if i != 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(l.Name + `="` + labelEscaper.Replace(l.Value) + `"`)
+	}
+	return b.String()
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,124 @@
+package brigodier
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// NamePolicy normalizes the name of a literal registered using
+// Dispatcher.Register or rejects it by returning an error,
+// e.g. to keep the commands of different plugins consistent.
+type NamePolicy func(name string) (string, error)
+
+// ErrInvalidName is returned by the NamePolicy created by RejectNameChars.
+var ErrInvalidName = errors.New("invalid name")
+
+// NameError is returned by Dispatcher.TryRegister
+// if the Dispatcher.NamePolicy rejected a literal.
+type NameError struct {
+	Path []string // The path to the rejected literal starting at the registered command.
+	Name string   // The rejected literal.
+	Err  error    // The error returned by the NamePolicy.
+}
+
+// Unwrap implements errors.Unwrap.
+func (e *NameError) Unwrap() error { return e.Err }
+func (e *NameError) Error() string {
+	return fmt.Sprintf("literal %q of %q rejected: %v", e.Name, strings.Join(e.Path, " "), e.Err)
+}
+
+// LowercaseNames is a NamePolicy that lower-cases and trims spaces of names.
+func LowercaseNames(name string) (string, error) {
+	return strings.ToLower(strings.TrimSpace(name)), nil
+}
+
+// RejectNameChars returns a NamePolicy that rejects names
+// that are empty or contain any of the chars with ErrInvalidName.
+func RejectNameChars(chars string) NamePolicy {
+	return func(name string) (string, error) {
+		if name == "" {
+			return "", fmt.Errorf("%w: empty", ErrInvalidName)
+		}
+		if i := strings.IndexAny(name, chars); i != -1 {
+			return "", fmt.Errorf("%w: contains %q", ErrInvalidName, name[i])
+		}
+		return name, nil
+	}
+}
+
+// NamePolicies returns a NamePolicy that applies the policies in order.
+func NamePolicies(policies ...NamePolicy) NamePolicy {
+	return func(name string) (_ string, err error) {
+		for _, p := range policies {
+			if name, err = p(name); err != nil {
+				return "", err
+			}
+		}
+		return name, nil
+	}
+}
+
+// applyNamePolicy applies the Dispatcher.NamePolicy to the literal
+// and all literals of its subtree. Renamed literals are merged
+// into siblings of the same name as if added with AddChild.
+func (d *Dispatcher) applyNamePolicy(literal *LiteralCommandNode) error {
+	name, err := d.NamePolicy(literal.Literal)
+	if err != nil {
+		return &NameError{Path: []string{literal.Literal}, Name: literal.Literal, Err: err}
+	}
+	if err = d.applyChildNamePolicy(literal, []string{name}); err != nil {
+		return err
+	}
+	literal.rename(name)
+	return nil
+}
+
+func (d *Dispatcher) applyChildNamePolicy(node CommandNode, path []string) error {
+	var (
+		children []CommandNode
+		names    []string
+		renamed  []string
+	)
+	node.ChildrenOrdered().Range(func(name string, child CommandNode) bool {
+		children = append(children, child)
+		names = append(names, name)
+		return true
+	})
+	for i, child := range children {
+		childPath := append(append(make([]string, 0, len(path)+1), path...), names[i])
+		newName := names[i]
+		if lit, ok := child.(*LiteralCommandNode); ok {
+			var err error
+			if newName, err = d.NamePolicy(lit.Literal); err != nil {
+				return &NameError{Path: childPath, Name: lit.Literal, Err: err}
+			}
+			childPath[len(childPath)-1] = newName
+		}
+		if err := d.applyChildNamePolicy(child, childPath); err != nil {
+			return err
+		}
+		renamed = append(renamed, newName)
+	}
+	for i, name := range names {
+		if name == renamed[i] {
+			continue
+		}
+		// Re-add all children to keep their order.
+		node.RemoveChild(names...)
+		for j, child := range children {
+			if lit, ok := child.(*LiteralCommandNode); ok {
+				lit.rename(renamed[j])
+			}
+		}
+		node.AddChild(children...)
+		break
+	}
+	return nil
+}
+
+// rename sets the Literal and resets its cached lowercase form.
+func (n *LiteralCommandNode) rename(name string) {
+	n.Literal = name
+	n.cachedLiteralLowerCase = ""
+}
@@ -0,0 +1,68 @@
+package brigodier
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDispatcher_NamePolicy(t *testing.T) {
+	var ran []string
+	cmd := func(name string) Command {
+		return CommandFunc(func(c *CommandContext) error {
+			ran = append(ran, name)
+			return nil
+		})
+	}
+	d := NewDispatcher(WithNamePolicy(NamePolicies(LowercaseNames, RejectNameChars(" :"))))
+	d.Register(Literal(" Foo").Executes(cmd("Foo")).Then(
+		Literal("B").Executes(cmd("B")),
+		Literal("Bar").Executes(cmd("Bar")),
+		Argument("n", Int).Then(Literal("QUX").Executes(cmd("QUX"))),
+	))
+	d.Register(Literal("foo").Then(Literal("BAZ").Executes(cmd("BAZ"))))
+
+	require.Len(t, d.Root.Children(), 1)
+	require.NoError(t, d.Do(context.TODO(), "foo"))
+	require.NoError(t, d.Do(context.TODO(), "foo bar"))
+	require.NoError(t, d.Do(context.TODO(), "foo baz"))
+	require.NoError(t, d.Do(context.TODO(), "foo 1 qux"))
+	require.Error(t, d.Do(context.TODO(), "foo Bar"))
+	require.Equal(t, []string{"Foo", "Bar", "BAZ", "QUX"}, ran)
+	require.Equal(t, []string{"b", "bar", "n", "baz"}, d.Root.Children()["foo"].ChildrenOrdered().Keys())
+
+	_, err := d.TryRegister(Literal("plugin").Then(Literal("plugin:cmd")))
+	require.ErrorIs(t, err, ErrInvalidName)
+	var nameErr *NameError
+	require.ErrorAs(t, err, &nameErr)
+	require.Equal(t, []string{"plugin", "plugin:cmd"}, nameErr.Path)
+	require.Equal(t, "plugin:cmd", nameErr.Name)
+	require.Nil(t, d.FindNode("plugin"))
+
+	require.Panics(t, func() { d.Register(Literal("")) })
+}
+
+func TestDispatcher_NamePolicy_Suggestions(t *testing.T) {
+	d := NewDispatcher(WithNamePolicy(func(name string) (string, error) {
+		if name == "oldsub" {
+			return "newsub", nil
+		}
+		return name, nil
+	}))
+	d.Register(Literal("cmd").Then(Literal("oldsub").Executes(CommandFunc(func(*CommandContext) error { return nil }))))
+
+	cmd := d.Root.Children()["cmd"]
+	require.Len(t, cmd.LiteralsWithPrefix("n"), 1)
+	require.Empty(t, cmd.LiteralsWithPrefix("o"))
+	suggest := func(input string) []string {
+		s, err := d.CompletionSuggestions(d.Parse(context.TODO(), input))
+		require.NoError(t, err)
+		var texts []string
+		for _, suggestion := range s.Suggestions {
+			texts = append(texts, suggestion.Text)
+		}
+		return texts
+	}
+	require.Equal(t, []string{"newsub"}, suggest("cmd n"))
+	require.Empty(t, suggest("cmd o"))
+}
@@ -0,0 +1,76 @@
+package brigodier
+
+// NamespaceSeparator separates the namespace from the literal
+// of commands registered using Dispatcher.RegisterNamespaced.
+const NamespaceSeparator = ":"
+
+// RegisterNamespaced registers the command under both its literal "cmd"
+// and its namespaced literal "namespace:cmd", e.g. to register the commands
+// of a plugin while keeping them reachable if another plugin uses the same literal.
+//
+// If "cmd" is already registered without or with another namespace,
+// only the namespaced literal is registered, so the first registration keeps
+// the plain literal and the namespaced literal always resolves to this command.
+// Registering the same namespace and literal again is subject to
+// the Dispatcher.ConflictPolicy like Register.
+//
+// It returns the namespaced LiteralCommandNode and panics if the command is
+// rejected by the Dispatcher.NamePolicy or the Dispatcher.ConflictPolicy,
+// use TryRegisterNamespaced to handle the error instead.
+func (d *Dispatcher) RegisterNamespaced(namespace string, command LiteralNodeBuilder) *LiteralCommandNode {
+	b, err := d.TryRegisterNamespaced(namespace, command)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// TryRegisterNamespaced is like RegisterNamespaced but returns a *NameError if
+// the Dispatcher.NamePolicy rejected the namespace or a literal of the command
+// or a *CommandConflictError if the Dispatcher.ConflictPolicy is ConflictError.
+// Nothing is registered if an error is returned.
+func (d *Dispatcher) TryRegisterNamespaced(namespace string, command LiteralNodeBuilder) (*LiteralCommandNode, error) {
+	b := command.BuildLiteral()
+	if d.NamePolicy != nil {
+		ns, err := d.NamePolicy(namespace)
+		if err != nil {
+			return nil, &NameError{Path: []string{namespace}, Name: namespace, Err: err}
+		}
+		namespace = ns
+		if err = d.applyNamePolicy(b); err != nil {
+			return nil, err
+		}
+	}
+
+	namespaced := b.CreateLiteralBuilder().BuildLiteral()
+	namespaced.Literal = namespace + NamespaceSeparator + b.Literal
+	b.ChildrenOrdered().Range(func(_ string, child CommandNode) bool {
+		namespaced.AddChild(child)
+		return true
+	})
+
+	if err := d.checkConflict(namespaced); err != nil {
+		return nil, err
+	}
+	if owner, ok := d.namespaces[b.Literal]; ok && owner == namespace || d.Root.Children()[b.Literal] == nil {
+		if err := d.checkConflict(b); err != nil {
+			return nil, err
+		}
+		if d.namespaces == nil {
+			d.namespaces = map[string]string{}
+		}
+		d.namespaces[b.Literal] = namespace
+		d.addRoot(b)
+	}
+	namespaced = d.addRoot(namespaced)
+	d.ClearParseCache()
+	d.ClearSuggestionCache()
+	return namespaced, nil
+}
+
+// Namespace returns the namespace of the command registered using
+// RegisterNamespaced that owns the plain literal and whether there is one.
+func (d *Dispatcher) Namespace(literal string) (string, bool) {
+	namespace, ok := d.namespaces[literal]
+	return namespace, ok
+}
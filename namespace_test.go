@@ -0,0 +1,39 @@
+package brigodier
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDispatcher_RegisterNamespaced(t *testing.T) {
+	var ran []string
+	cmd := func(name string) Command {
+		return CommandFunc(func(c *CommandContext) error {
+			ran = append(ran, name)
+			return nil
+		})
+	}
+	var d Dispatcher
+	d.Register(Literal("list").Executes(cmd("core")))
+	d.RegisterNamespaced("a", Literal("list").Executes(cmd("a")))
+	d.RegisterNamespaced("a", Literal("home").Executes(cmd("a")).Then(Argument("name", String).Executes(cmd("a name"))))
+	b := d.RegisterNamespaced("b", Literal("home").Executes(cmd("b")))
+	require.Equal(t, "b:home", b.Literal)
+
+	for _, input := range []string{"list", "a:list", "home", "home x", "a:home", "a:home x", "b:home"} {
+		require.NoError(t, d.Do(context.TODO(), input))
+	}
+	require.Equal(t, []string{"core", "a", "a", "a name", "a", "a name", "b"}, ran)
+
+	ns, ok := d.Namespace("home")
+	require.True(t, ok)
+	require.Equal(t, "a", ns)
+	_, ok = d.Namespace("list")
+	require.False(t, ok)
+
+	d.NamePolicy = RejectNameChars(" :")
+	_, err := d.TryRegisterNamespaced("c:d", Literal("tp"))
+	require.ErrorIs(t, err, ErrInvalidName)
+	require.Nil(t, d.FindNode("tp"))
+}
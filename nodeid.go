@@ -0,0 +1,38 @@
+package brigodier
+
+import "sync/atomic"
+
+// lastNodeID is the last ID assigned to a Node.
+var lastNodeID uint64
+
+// ID implements CommandNode.ID.
+// The ID is assigned when it is first requested.
+func (n *Node) ID() uint64 {
+	if id := atomic.LoadUint64(&n.id); id != 0 {
+		return id
+	}
+	atomic.CompareAndSwapUint64(&n.id, 0, atomic.AddUint64(&lastNodeID, 1))
+	return atomic.LoadUint64(&n.id)
+}
+
+// NodeByID returns the node of the command tree with the ID or nil if
+// there is none, including the Dispatcher.Root. See CommandNode.ID.
+//
+// The tree is walked to find the node, serializers looking up many
+// nodes should index the IDs of the nodes using Walk instead.
+func (d *Dispatcher) NodeByID(id uint64) CommandNode {
+	if id == 0 {
+		return nil
+	}
+	if d.Root.ID() == id {
+		return &d.Root
+	}
+	var found CommandNode
+	d.Walk(func(_ []CommandNode, node CommandNode) bool {
+		if node.ID() == id {
+			found = node
+		}
+		return found == nil
+	})
+	return found
+}
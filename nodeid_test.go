@@ -0,0 +1,51 @@
+package brigodier
+
+import (
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+)
+
+func TestDispatcher_NodeByID(t *testing.T) {
+	var d Dispatcher
+	tp := d.Register(Literal("tp").Then(Argument("target", StringWord)))
+	target := d.FindNode("tp", "target")
+
+	require.NotZero(t, tp.ID())
+	require.Equal(t, tp.ID(), tp.ID())
+	require.NotEqual(t, tp.ID(), target.ID())
+	require.Equal(t, CommandNode(tp), d.NodeByID(tp.ID()))
+	require.Equal(t, target, d.NodeByID(target.ID()))
+	require.Equal(t, CommandNode(&d.Root), d.NodeByID(d.Root.ID()))
+	require.Nil(t, d.NodeByID(0))
+
+	// the ID is stable when the tree changes
+	id := target.ID()
+	d.Register(Literal("tp").Then(Argument("x", Int)))
+	d.Register(Literal("a"))
+	require.Equal(t, id, d.FindNode("tp", "target").ID())
+
+	clone := d.Clone()
+	require.NotEqual(t, id, clone.FindNode("tp", "target").ID())
+	require.Nil(t, clone.NodeByID(id))
+
+	d.Root.RemoveChild("tp")
+	require.Nil(t, d.NodeByID(id))
+}
+
+func TestNode_ID_Concurrent(t *testing.T) {
+	n := Literal("a").Build()
+	ids := make([]uint64, 8)
+	var wg sync.WaitGroup
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ids[i] = n.ID()
+		}(i)
+	}
+	wg.Wait()
+	for _, id := range ids {
+		require.Equal(t, ids[0], id)
+	}
+}
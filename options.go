@@ -0,0 +1,139 @@
+package brigodier
+
+import (
+	"context"
+	"time"
+)
+
+// Option configures a Dispatcher created by NewDispatcher.
+type Option func(d *Dispatcher)
+
+// NewDispatcher returns a new Dispatcher configured by the given options.
+//
+// A Dispatcher should be fully configured before it is used, so prefer passing
+// options over setting fields on a Dispatcher that may already be in use.
+// The zero-value Dispatcher remains valid and uses the same defaults.
+func NewDispatcher(opts ...Option) *Dispatcher {
+	d := &Dispatcher{
+		MaxDispatchDepth: DefaultMaxDispatchDepth,
+		MaxRedirects:     DefaultMaxRedirects,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// WithMaxDispatchDepth sets Dispatcher.MaxDispatchDepth.
+// A negative depth disables the limit.
+func WithMaxDispatchDepth(depth int) Option {
+	return func(d *Dispatcher) { d.MaxDispatchDepth = depth }
+}
+
+// WithMaxRedirects sets Dispatcher.MaxRedirects.
+// A negative limit disables it.
+func WithMaxRedirects(max int) Option {
+	return func(d *Dispatcher) { d.MaxRedirects = max }
+}
+
+// WithExecuteTimeout sets Dispatcher.ExecuteTimeout.
+func WithExecuteTimeout(timeout time.Duration) Option {
+	return func(d *Dispatcher) { d.ExecuteTimeout = timeout }
+}
+
+// WithBeforeExecute registers hooks using Dispatcher.BeforeExecute.
+func WithBeforeExecute(fns ...BeforeExecuteFn) Option {
+	return func(d *Dispatcher) { d.BeforeExecute(fns...) }
+}
+
+// WithAfterExecute registers hooks using Dispatcher.AfterExecute.
+func WithAfterExecute(fns ...AfterExecuteFn) Option {
+	return func(d *Dispatcher) { d.AfterExecute(fns...) }
+}
+
+// WithOnDeprecated registers hooks using Dispatcher.OnDeprecated.
+func WithOnDeprecated(fns ...DeprecatedFn) Option {
+	return func(d *Dispatcher) { d.OnDeprecated(fns...) }
+}
+
+// WithTypes sets Dispatcher.Types.
+func WithTypes(r *TypeRegistry) Option {
+	return func(d *Dispatcher) { d.Types = r }
+}
+
+// WithCooldowns sets Dispatcher.Cooldowns.
+func WithCooldowns(c *Cooldowns) Option {
+	return func(d *Dispatcher) { d.Cooldowns = c }
+}
+
+// WithInstrumentation sets Dispatcher.Instrumentation.
+func WithInstrumentation(i Instrumentation) Option {
+	return func(d *Dispatcher) { d.Instrumentation = i }
+}
+
+// WithAuditSink sets Dispatcher.AuditSink and the optional Dispatcher.AuditPrincipal.
+func WithAuditSink(sink AuditSink, principal func(ctx context.Context) string) Option {
+	return func(d *Dispatcher) {
+		d.AuditSink = sink
+		d.AuditPrincipal = principal
+	}
+}
+
+// WithCaseInsensitiveLiterals sets Dispatcher.CaseInsensitiveLiterals.
+func WithCaseInsensitiveLiterals() Option {
+	return func(d *Dispatcher) { d.CaseInsensitiveLiterals = true }
+}
+
+// WithNamePolicy sets Dispatcher.NamePolicy.
+func WithNamePolicy(p NamePolicy) Option {
+	return func(d *Dispatcher) { d.NamePolicy = p }
+}
+
+// WithConflictPolicy sets Dispatcher.ConflictPolicy.
+func WithConflictPolicy(p ConflictPolicy) Option {
+	return func(d *Dispatcher) { d.ConflictPolicy = p }
+}
+
+// WithMergeStrategy sets Dispatcher.MergeStrategy.
+func WithMergeStrategy(s MergeStrategy) Option {
+	return func(d *Dispatcher) { d.MergeStrategy = s }
+}
+
+// WithArgumentSiblingsOnLiteral sets Dispatcher.ArgumentSiblingsOnLiteral.
+func WithArgumentSiblingsOnLiteral() Option {
+	return func(d *Dispatcher) { d.ArgumentSiblingsOnLiteral = true }
+}
+
+// WithSnapSuggestionsToToken sets Dispatcher.SnapSuggestionsToToken.
+func WithSnapSuggestionsToToken() Option {
+	return func(d *Dispatcher) { d.SnapSuggestionsToToken = true }
+}
+
+// WithErrorProvider sets Dispatcher.ErrorProvider.
+func WithErrorProvider(p ErrorProvider) Option {
+	return func(d *Dispatcher) { d.ErrorProvider = p }
+}
+
+// WithParseCache caches the ParseResults of up to size recently parsed inputs,
+// see Dispatcher.ClearParseCache. A size <= 0 disables the cache.
+func WithParseCache(size int) Option {
+	return func(d *Dispatcher) {
+		d.parseCache = nil
+		if size > 0 {
+			d.parseCache = newParseCache(size)
+		}
+	}
+}
+
+// WithSuggestionCache caches the suggestions of argument nodes for up to size
+// recently completed remaining inputs, see Dispatcher.ClearSuggestionCache.
+// Use Dynamic to opt out suggestion providers that depend on the
+// CommandContext or change over time. A size <= 0 disables the cache.
+func WithSuggestionCache(size int) Option {
+	return func(d *Dispatcher) {
+		d.suggestionCache = nil
+		if size > 0 {
+			d.suggestionCache = newSuggestionCache(size)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package brigodier
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestNewDispatcher(t *testing.T) {
+	d := NewDispatcher()
+	require.Equal(t, DefaultMaxDispatchDepth, d.MaxDispatchDepth)
+
+	d = NewDispatcher(WithMaxDispatchDepth(1))
+	var depth int
+	d.Register(Literal("loop").Executes(CommandFunc(func(c *CommandContext) error {
+		depth++
+		return c.Dispatcher().Do(c, "loop")
+	})))
+	require.ErrorIs(t, d.Do(context.TODO(), "loop"), ErrDispatcherMaxDepthExceeded)
+	require.Equal(t, 2, depth)
+}
+
+func TestWithCaseInsensitiveLiterals(t *testing.T) {
+	var ran []string
+	cmd := func(name string) Command {
+		return CommandFunc(func(c *CommandContext) error {
+			ran = append(ran, name)
+			return nil
+		})
+	}
+	d := NewDispatcher(WithCaseInsensitiveLiterals())
+	d.Register(Literal("Foo").Executes(cmd("Foo")).Then(Literal("bar").Executes(cmd("bar"))))
+	d.Register(Literal("foo").Executes(cmd("foo")))
+
+	require.NoError(t, d.Do(context.TODO(), "FOO"))
+	require.NoError(t, d.Do(context.TODO(), "foo"))
+	require.NoError(t, d.Do(context.TODO(), "Foo"))
+	require.NoError(t, d.Do(context.TODO(), "fOO BAR"))
+	require.Equal(t, []string{"Foo", "foo", "Foo", "bar"}, ran)
+
+	var zero Dispatcher
+	zero.Register(Literal("foo").Executes(cmd("foo")))
+	require.ErrorIs(t, zero.Do(context.TODO(), "FOO"), ErrDispatcherUnknownCommand)
+}
+
+func TestWithErrorProvider(t *testing.T) {
+	errUnknown := errors.New("unbekannter Befehl")
+	d := NewDispatcher(WithErrorProvider(func(err *CommandSyntaxError) error {
+		if errors.Is(err, ErrDispatcherUnknownCommand) {
+			return errUnknown
+		}
+		return err
+	}))
+	errCmd := errors.New("command error")
+	d.Register(Literal("foo").Executes(CommandFunc(func(c *CommandContext) error { return errCmd })))
+
+	require.ErrorIs(t, d.Do(context.TODO(), "bar"), errUnknown)
+	require.ErrorIs(t, d.Validate(d.Parse(context.TODO(), "bar")), errUnknown)
+	require.ErrorIs(t, d.Do(context.TODO(), "foo"), errCmd)
+	require.ErrorIs(t, d.Do(context.TODO(), "foo bar"), ErrDispatcherUnknownArgument)
+}
+
+func TestWithParseCache(t *testing.T) {
+	type key struct{}
+	var got []interface{}
+	d := NewDispatcher(WithParseCache(2))
+	d.Register(Literal("foo").Executes(CommandFunc(func(c *CommandContext) error {
+		got = append(got, c.Value(key{}))
+		return nil
+	})))
+	d.Register(Literal("admin").
+		Requires(func(ctx context.Context) bool { return ctx.Value(key{}) == "admin" }).
+		Executes(CommandFunc(func(c *CommandContext) error { return nil })))
+
+	parse := d.Parse(context.TODO(), "foo")
+	require.Same(t, parse.Context, d.Parse(context.TODO(), "foo").Context)
+	require.NoError(t, d.Do(context.WithValue(context.TODO(), key{}, "a"), "foo"))
+	require.NoError(t, d.Do(context.WithValue(context.TODO(), key{}, "b"), "foo"))
+	require.Equal(t, []interface{}{"a", "b"}, got)
+
+	// Results depending on requirements are not cached.
+	require.ErrorIs(t, d.Do(context.TODO(), "admin"), ErrDispatcherUnknownCommand)
+	require.NoError(t, d.Do(context.WithValue(context.TODO(), key{}, "admin"), "admin"))
+
+	// Evicted and cleared.
+	d.Parse(context.TODO(), "x")
+	d.Parse(context.TODO(), "y")
+	require.NotSame(t, parse.Context, d.Parse(context.TODO(), "foo").Context)
+	parse = d.Parse(context.TODO(), "foo")
+	d.Register(Literal("bar"))
+	require.NotSame(t, parse.Context, d.Parse(context.TODO(), "foo").Context)
+}
+
+func TestWithArgumentSiblingsOnLiteral(t *testing.T) {
+	var ran []string
+	cmd := func(name string) Command {
+		return CommandFunc(func(c *CommandContext) error {
+			ran = append(ran, name)
+			return nil
+		})
+	}
+	register := func(d *Dispatcher) {
+		d.Register(Literal("foo").Then(
+			Literal("bar").Then(Argument("n", Int).Executes(cmd("bar n"))),
+			Argument("word", StringWord).Then(Literal("baz").Executes(cmd("word baz"))),
+		))
+	}
+
+	// Literal wins by default.
+	d := NewDispatcher()
+	register(d)
+	require.Error(t, d.Do(context.TODO(), "foo bar baz"))
+	require.NoError(t, d.Do(context.TODO(), "foo bar 1"))
+	require.NoError(t, d.Do(context.TODO(), "foo qux baz"))
+	require.Equal(t, []string{"bar n", "word baz"}, ran)
+
+	ran = nil
+	d = NewDispatcher(WithArgumentSiblingsOnLiteral())
+	register(d)
+	require.NoError(t, d.Do(context.TODO(), "foo bar baz"))
+	require.NoError(t, d.Do(context.TODO(), "foo bar 1"))
+	require.Equal(t, []string{"word baz", "bar n"}, ran)
+}
@@ -0,0 +1,592 @@
+package brigodier
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"unicode/utf8"
+)
+
+// PacketCodec encodes and decodes command trees in the binary format of the
+// Minecraft Declare Commands packet, e.g. for a proxy to splice its own
+// commands into the tree sent by the server:
+//
+//	root, err := codec.Decode(packet)
+//	root.AddChild(proxyCommands...)
+//	err = codec.Encode(ctx, w, root)
+//
+// The format is a VarInt-prefixed array of nodes referencing their children
+// and redirect target by index followed by the VarInt index of the root node.
+// The parsers of arguments are derived using TypeRegistry.ClientParser and unknown
+// parsers are decoded as *PlaceholderType, so they are encoded again unchanged.
+type PacketCodec struct {
+	// Types optionally replaces DefaultTypes to identify argument types.
+	Types *TypeRegistry
+	// ParserIDs are the numeric protocol IDs of the parsers by index
+	// as used since Minecraft 1.19. If nil, parsers are written
+	// as identifier strings like in earlier versions.
+	ParserIDs []string
+	// Properties are the codecs of the properties of parsers by identifier
+	// in addition to the builtin ones for the "brigadier:" parsers.
+	// Parsers without properties must be registered with NoProperties,
+	// since the length of unknown properties can not be determined.
+	Properties map[string]PropertiesCodec
+}
+
+// PropertiesCodec encodes and decodes the TypeProperties of a parser.
+// Minecraft VarInts are encoded like binary.PutUvarint and
+// strings are prefixed by their VarInt length.
+type PropertiesCodec struct {
+	Encode func(w io.Writer, props TypeProperties) error
+	Decode func(r io.ByteReader) (TypeProperties, error)
+}
+
+// NoProperties is the PropertiesCodec of parsers without properties.
+var NoProperties = PropertiesCodec{
+	Encode: func(io.Writer, TypeProperties) error { return nil },
+	Decode: func(io.ByteReader) (TypeProperties, error) { return nil, nil },
+}
+
+// SuggestionsAskServer is the suggestions type of argument nodes with custom
+// suggestions, which clients request from the server.
+const SuggestionsAskServer = "minecraft:ask_server"
+
+// ErrInvalidPacket occurs when decoding an invalid Declare Commands packet.
+var ErrInvalidPacket = errors.New("invalid declare commands packet")
+
+// Node flags of the Declare Commands packet.
+const (
+	packetNodeRoot        = 0x00
+	packetNodeLiteral     = 0x01
+	packetNodeArgument    = 0x02
+	packetNodeTypeMask    = 0x03
+	packetNodeExecutable  = 0x04
+	packetNodeRedirect    = 0x08
+	packetNodeSuggestions = 0x10
+
+	maxPacketNodes     = 1 << 20
+	maxPacketStringLen = 32767
+)
+
+// suggestionsTypeKey is the Metadata key of the suggestions type of a decoded argument node.
+type suggestionsTypeKey struct{}
+
+// Encode writes the tree below root restricted to the nodes
+// the given context.Context can use to w.
+func (c *PacketCodec) Encode(ctx context.Context, w io.Writer, root CommandNode) error {
+	// collect nodes breadth-first like Brigadier so the root has index 0
+	ids := map[CommandNode]int32{root: 0}
+	nodes := []CommandNode{root}
+	children := map[CommandNode][]CommandNode{}
+	for i := 0; i < len(nodes); i++ {
+		node := nodes[i]
+		add := func(n CommandNode) {
+			if _, ok := ids[n]; !ok {
+				ids[n] = int32(len(nodes))
+				nodes = append(nodes, n)
+			}
+		}
+		node.ChildrenOrdered().Range(func(_ string, child CommandNode) bool {
+			if child.CanUse(ctx) {
+				children[node] = append(children[node], child)
+				add(child)
+			}
+			return true
+		})
+		if node.Redirect() != nil {
+			add(node.Redirect())
+		}
+	}
+
+	pw := &packetWriter{w: w}
+	pw.varInt(int32(len(nodes)))
+	for _, node := range nodes {
+		var flags byte
+		var argument *ArgumentCommandNode
+		switch n := node.(type) {
+		case *RootCommandNode:
+			flags = packetNodeRoot
+		case *LiteralCommandNode:
+			flags = packetNodeLiteral
+		case *ArgumentCommandNode:
+			flags = packetNodeArgument
+			argument = n
+		}
+		if node.Command() != nil {
+			flags |= packetNodeExecutable
+		}
+		if node.Redirect() != nil {
+			flags |= packetNodeRedirect
+		}
+		suggestions := c.suggestionsType(argument)
+		if suggestions != "" {
+			flags |= packetNodeSuggestions
+		}
+		pw.byte(flags)
+		pw.varInt(int32(len(children[node])))
+		for _, child := range children[node] {
+			pw.varInt(ids[child])
+		}
+		if node.Redirect() != nil {
+			pw.varInt(ids[node.Redirect()])
+		}
+		if flags&packetNodeTypeMask != packetNodeRoot {
+			pw.string(node.Name())
+		}
+		if argument != nil {
+			if err := c.encodeParser(pw, argument); err != nil {
+				return err
+			}
+		}
+		if suggestions != "" {
+			pw.string(suggestions)
+		}
+		if pw.err != nil {
+			return pw.err
+		}
+	}
+	pw.varInt(ids[root])
+	return pw.err
+}
+
+func (c *PacketCodec) suggestionsType(a *ArgumentCommandNode) string {
+	if a == nil {
+		return ""
+	}
+	if s, ok := a.meta.Get(suggestionsTypeKey{}).(string); ok {
+		return s
+	}
+	if a.customSuggestions != nil {
+		return SuggestionsAskServer
+	}
+	return ""
+}
+
+func (c *PacketCodec) encodeParser(pw *packetWriter, a *ArgumentCommandNode) error {
+	id, props := c.types().ClientParser(a)
+	if c.ParserIDs == nil {
+		pw.string(id)
+	} else {
+		index := -1
+		for i, parser := range c.ParserIDs {
+			if parser == id {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return fmt.Errorf("error encoding argument %q: no protocol id for parser %q", a.Name(), id)
+		}
+		pw.varInt(int32(index))
+	}
+	codec, err := c.properties(id)
+	if err == nil && pw.err == nil {
+		err = codec.Encode(pw, props)
+	}
+	if err != nil {
+		return fmt.Errorf("error encoding argument %q: %w", a.Name(), err)
+	}
+	return nil
+}
+
+// Decode reads a tree written by Encode from r and returns its root.
+// Executable nodes are decoded with a Command returning ErrImportedCommand.
+func (c *PacketCodec) Decode(r io.Reader) (*RootCommandNode, error) {
+	pr := &packetReader{}
+	if br, ok := r.(io.ByteReader); ok {
+		pr.r = br
+	} else {
+		pr.r = &byteReader{r: r}
+	}
+	count := pr.varInt()
+	if pr.err == nil && (count < 0 || count > maxPacketNodes) {
+		return nil, fmt.Errorf("%w: node count %d", ErrInvalidPacket, count)
+	}
+	type packetNode struct {
+		node     CommandNode
+		children []int32
+		redirect int32
+	}
+	nodes := make([]packetNode, 0, min(int(count), 1024))
+	for i := int32(0); i < count && pr.err == nil; i++ {
+		flags := pr.byte()
+		n := packetNode{redirect: -1}
+		childCount := pr.varInt()
+		if childCount < 0 || childCount > count {
+			return nil, fmt.Errorf("%w: node %d has %d children", ErrInvalidPacket, i, childCount)
+		}
+		for j := int32(0); j < childCount && pr.err == nil; j++ {
+			n.children = append(n.children, pr.varInt())
+		}
+		if flags&packetNodeRedirect != 0 {
+			n.redirect = pr.varInt()
+		}
+		switch flags & packetNodeTypeMask {
+		case packetNodeRoot:
+			n.node = &RootCommandNode{}
+		case packetNodeLiteral:
+			n.node = &LiteralCommandNode{Literal: pr.string()}
+		case packetNodeArgument:
+			name := pr.string()
+			t, err := c.decodeParser(pr)
+			if err != nil {
+				return nil, fmt.Errorf("error decoding argument %q: %w", name, err)
+			}
+			n.node = &ArgumentCommandNode{name: name, argType: t}
+		default:
+			return nil, fmt.Errorf("%w: node %d has flags %#x", ErrInvalidPacket, i, flags)
+		}
+		if flags&packetNodeExecutable != 0 {
+			baseNode(n.node).command = CommandFunc(func(*CommandContext) error { return ErrImportedCommand })
+		}
+		if flags&packetNodeSuggestions != 0 {
+			suggestions := pr.string()
+			if a, ok := n.node.(*ArgumentCommandNode); ok {
+				a.meta.Set(suggestionsTypeKey{}, suggestions)
+			}
+		}
+		nodes = append(nodes, n)
+	}
+	rootIndex := pr.varInt()
+	if pr.err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPacket, pr.err)
+	}
+	valid := func(i int32) bool { return i >= 0 && int(i) < len(nodes) }
+	if !valid(rootIndex) {
+		return nil, fmt.Errorf("%w: root index %d", ErrInvalidPacket, rootIndex)
+	}
+	root, ok := nodes[rootIndex].node.(*RootCommandNode)
+	if !ok {
+		return nil, fmt.Errorf("%w: node %d is no root node", ErrInvalidPacket, rootIndex)
+	}
+	for i, n := range nodes {
+		node := baseNode(n.node)
+		for _, child := range n.children {
+			if !valid(child) {
+				return nil, fmt.Errorf("%w: node %d has child %d", ErrInvalidPacket, i, child)
+			}
+			name := nodes[child].node.Name()
+			if _, ok := nodes[child].node.(*RootCommandNode); ok || node.Children()[name] != nil {
+				return nil, fmt.Errorf("%w: node %d has invalid child %d", ErrInvalidPacket, i, child)
+			}
+			node.AddChild(nodes[child].node)
+		}
+		if n.redirect >= 0 {
+			if !valid(n.redirect) {
+				return nil, fmt.Errorf("%w: node %d redirects to %d", ErrInvalidPacket, i, n.redirect)
+			}
+			node.redirect = nodes[n.redirect].node
+		}
+	}
+	if hasChildCycle(root) {
+		return nil, fmt.Errorf("%w: cyclic children", ErrInvalidPacket)
+	}
+	return root, nil
+}
+
+func (c *PacketCodec) decodeParser(pr *packetReader) (ArgumentType, error) {
+	var id string
+	if c.ParserIDs == nil {
+		id = pr.string()
+	} else {
+		index := pr.varInt()
+		if pr.err == nil && (index < 0 || int(index) >= len(c.ParserIDs)) {
+			return nil, fmt.Errorf("%w: unknown parser id %d", ErrInvalidPacket, index)
+		}
+		if pr.err == nil {
+			id = c.ParserIDs[index]
+		}
+	}
+	if pr.err != nil {
+		return nil, pr.err
+	}
+	codec, err := c.properties(id)
+	if err != nil {
+		return nil, err
+	}
+	props, err := codec.Decode(pr)
+	if err != nil {
+		return nil, err
+	}
+	return c.types().NewOrPlaceholder(id, props)
+}
+
+func (c *PacketCodec) types() *TypeRegistry {
+	if c.Types != nil {
+		return c.Types
+	}
+	return DefaultTypes
+}
+
+func (c *PacketCodec) properties(id string) (PropertiesCodec, error) {
+	if codec, ok := c.Properties[id]; ok {
+		return codec, nil
+	}
+	if codec, ok := builtinProperties[id]; ok {
+		return codec, nil
+	}
+	return PropertiesCodec{}, fmt.Errorf("no properties codec for parser %q", id)
+}
+
+// hasChildCycle reports whether a node is its own descendant.
+func hasChildCycle(root CommandNode) bool {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := map[CommandNode]int{}
+	var visit func(node CommandNode) bool
+	visit = func(node CommandNode) bool {
+		switch state[node] {
+		case visiting:
+			return true
+		case done:
+			return false
+		}
+		state[node] = visiting
+		cycle := false
+		node.ChildrenOrdered().Range(func(_ string, child CommandNode) bool {
+			cycle = visit(child)
+			return !cycle
+		})
+		state[node] = done
+		return cycle
+	}
+	return visit(root)
+}
+
+// builtinProperties are the properties codecs of the brigadier parsers.
+var builtinProperties = map[string]PropertiesCodec{
+	"brigadier:bool":    NoProperties,
+	"brigadier:integer": boundsProperties(4, false),
+	"brigadier:long":    boundsProperties(8, false),
+	"brigadier:float":   boundsProperties(4, true),
+	"brigadier:double":  boundsProperties(8, true),
+	"brigadier:string": {
+		Encode: func(w io.Writer, props TypeProperties) error {
+			var mode byte
+			switch s, _ := props["type"].(string); s {
+			case "word":
+				mode = 0
+			case "phrase", "":
+				mode = 1
+			case "greedy":
+				mode = 2
+			default:
+				return fmt.Errorf("%w type %q", ErrInvalidTypeProperty, s)
+			}
+			_, err := w.Write([]byte{mode})
+			return err
+		},
+		Decode: func(r io.ByteReader) (TypeProperties, error) {
+			mode, err := readVarInt(r)
+			if err != nil {
+				return nil, err
+			}
+			switch mode {
+			case 0:
+				return TypeProperties{"type": "word"}, nil
+			case 1:
+				return TypeProperties{"type": "phrase"}, nil
+			case 2:
+				return TypeProperties{"type": "greedy"}, nil
+			}
+			return nil, fmt.Errorf("%w: string type %d", ErrInvalidPacket, mode)
+		},
+	},
+}
+
+// boundsProperties returns the codec of the "min" and "max" properties of the numeric brigadier
+// parsers: a flags byte (0x01 min, 0x02 max) followed by the present bounds of the given size.
+func boundsProperties(size int, float bool) PropertiesCodec {
+	return PropertiesCodec{
+		Encode: func(w io.Writer, props TypeProperties) error {
+			buf := []byte{0}
+			for i, key := range []string{"min", "max"} {
+				v, ok := props[key]
+				if !ok {
+					continue
+				}
+				n, err := number(v)
+				if err == nil {
+					buf, err = appendBound(buf, n, size, float)
+				}
+				if err != nil {
+					return fmt.Errorf("%w %s %v: %v", ErrInvalidTypeProperty, key, v, err)
+				}
+				buf[0] |= 1 << i
+			}
+			_, err := w.Write(buf)
+			return err
+		},
+		Decode: func(r io.ByteReader) (TypeProperties, error) {
+			flags, err := r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			var props TypeProperties
+			for i, key := range []string{"min", "max"} {
+				if flags&(1<<i) == 0 {
+					continue
+				}
+				var bits uint64
+				for j := 0; j < size; j++ {
+					b, err := r.ReadByte()
+					if err != nil {
+						return nil, err
+					}
+					bits = bits<<8 | uint64(b)
+				}
+				if props == nil {
+					props = TypeProperties{}
+				}
+				switch {
+				case size == 4 && float:
+					props[key] = math.Float32frombits(uint32(bits))
+				case size == 4:
+					props[key] = int32(bits)
+				case float:
+					props[key] = math.Float64frombits(bits)
+				default:
+					props[key] = int64(bits)
+				}
+			}
+			return props, nil
+		},
+	}
+}
+
+// appendBound appends n as big-endian number of the given size.
+func appendBound(buf []byte, n json.Number, size int, float bool) ([]byte, error) {
+	var bits uint64
+	if float {
+		f, err := strconv.ParseFloat(n.String(), size*8)
+		if err != nil {
+			return nil, err
+		}
+		if size == 4 {
+			bits = uint64(math.Float32bits(float32(f)))
+		} else {
+			bits = math.Float64bits(f)
+		}
+	} else {
+		i, err := strconv.ParseInt(n.String(), 10, size*8)
+		if err != nil {
+			return nil, err
+		}
+		bits = uint64(i)
+	}
+	for j := size - 1; j >= 0; j-- {
+		buf = append(buf, byte(bits>>(8*j)))
+	}
+	return buf, nil
+}
+
+// packetWriter writes the primitives of the Minecraft protocol and keeps the first error.
+type packetWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (w *packetWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	var n int
+	n, w.err = w.w.Write(p)
+	return n, w.err
+}
+
+func (w *packetWriter) byte(b byte) { _, _ = w.Write([]byte{b}) }
+
+func (w *packetWriter) varInt(v int32) {
+	var buf [binary.MaxVarintLen32]byte
+	_, _ = w.Write(buf[:binary.PutUvarint(buf[:], uint64(uint32(v)))])
+}
+
+func (w *packetWriter) string(s string) {
+	w.varInt(int32(len(s)))
+	_, _ = w.Write([]byte(s))
+}
+
+// packetReader reads the primitives of the Minecraft protocol and keeps the first error.
+type packetReader struct {
+	r   io.ByteReader
+	err error
+}
+
+func (r *packetReader) ReadByte() (byte, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	var b byte
+	b, r.err = r.r.ReadByte()
+	return b, r.err
+}
+
+func (r *packetReader) byte() byte {
+	b, _ := r.ReadByte()
+	return b
+}
+
+func (r *packetReader) varInt() int32 {
+	if r.err != nil {
+		return 0
+	}
+	var v int32
+	v, r.err = readVarInt(r)
+	return v
+}
+
+func (r *packetReader) string() string {
+	n := r.varInt()
+	if r.err != nil {
+		return ""
+	}
+	if n < 0 || n > maxPacketStringLen*utf8.UTFMax {
+		r.err = fmt.Errorf("string length %d", n)
+		return ""
+	}
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = r.byte()
+	}
+	if r.err == nil && !utf8.Valid(b) {
+		r.err = errors.New("invalid utf-8 string")
+	}
+	return string(b)
+}
+
+// readVarInt reads a Minecraft VarInt of at most 5 bytes.
+func readVarInt(r io.ByteReader) (int32, error) {
+	var v uint32
+	for i := 0; i < binary.MaxVarintLen32; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		v |= uint32(b&0x7F) << (7 * i)
+		if b&0x80 == 0 {
+			return int32(v), nil
+		}
+	}
+	return 0, errors.New("varint too long")
+}
+
+// byteReader reads single bytes without reading ahead.
+type byteReader struct {
+	r   io.Reader
+	buf [1]byte
+}
+
+func (r *byteReader) ReadByte() (byte, error) {
+	if _, err := io.ReadFull(r.r, r.buf[:]); err != nil {
+		return 0, err
+	}
+	return r.buf[0], nil
+}
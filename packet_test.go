@@ -0,0 +1,100 @@
+package brigodier
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/require"
+	"io"
+	"testing"
+)
+
+func TestPacketCodec_RoundTrip(t *testing.T) {
+	entity := PropertiesCodec{
+		Encode: func(w io.Writer, props TypeProperties) error {
+			_, err := w.Write([]byte{props["flags"].(byte)})
+			return err
+		},
+		Decode: func(r io.ByteReader) (TypeProperties, error) {
+			b, err := r.ReadByte()
+			return TypeProperties{"flags": b}, err
+		},
+	}
+	for _, codec := range []*PacketCodec{
+		{Properties: map[string]PropertiesCodec{"minecraft:entity": entity, "minecraft:message": NoProperties}},
+		{
+			Properties: map[string]PropertiesCodec{"minecraft:entity": entity, "minecraft:message": NoProperties},
+			ParserIDs: []string{"brigadier:bool", "brigadier:float", "brigadier:double", "brigadier:integer",
+				"brigadier:long", "brigadier:string", "minecraft:entity", "minecraft:message"},
+		},
+	} {
+		cmd := CommandFunc(func(c *CommandContext) error { return nil })
+		players := suggestionProviderFunc(func(_ *CommandContext, b *SuggestionsBuilder) *Suggestions { return b.Build() })
+		d := &Dispatcher{}
+		msg := d.Register(Literal("msg").Then(
+			Argument("targets", &PlaceholderType{ID: "minecraft:entity", Properties: TypeProperties{"flags": byte(2)}}).Suggests(players).Then(
+				Argument("message", &PlaceholderType{ID: "minecraft:message"}).Executes(cmd))))
+		d.Register(Literal("tell").Redirect(msg))
+		d.Register(Literal("execute").Then(Literal("run").Redirect(&d.Root)))
+		d.Register(Literal("nums").Then(
+			Argument("i", &Int32ArgumentType{Min: -5, Max: 5}).Executes(cmd),
+			Argument("l", &Int64ArgumentType{Min: MinInt64, Max: 1 << 40}).Executes(cmd),
+			Argument("f", &Float32ArgumentType{Min: 0.5, Max: MaxFloat32}).Executes(cmd),
+			Argument("d", Float64).Executes(cmd),
+			Argument("b", Bool).Executes(cmd),
+			Argument("w", StringWord).Executes(cmd),
+			Argument("g", StringPhrase).Executes(cmd),
+		))
+		d.Register(Literal("hidden").Requires(func(context.Context) bool { return false }).Executes(cmd))
+
+		var packet bytes.Buffer
+		require.NoError(t, codec.Encode(context.TODO(), &packet, &d.Root))
+		encoded := append([]byte(nil), packet.Bytes()...)
+
+		root, err := codec.Decode(&packet)
+		require.NoError(t, err)
+		require.Zero(t, packet.Len())
+
+		decoded := &Dispatcher{Root: *root}
+		require.Nil(t, decoded.FindNode("hidden"))
+		require.Equal(t, decoded.FindNode("msg"), decoded.FindNode("tell").Redirect())
+		require.Equal(t, root, decoded.FindNode("execute", "run").Redirect())
+		require.Equal(t, &Int32ArgumentType{Min: -5, Max: 5}, decoded.FindNode("nums", "i").(*ArgumentCommandNode).Type())
+		require.Equal(t, &Float32ArgumentType{Min: 0.5, Max: MaxFloat32}, decoded.FindNode("nums", "f").(*ArgumentCommandNode).Type())
+		require.Equal(t, StringPhrase, decoded.FindNode("nums", "g").(*ArgumentCommandNode).Type())
+		require.ErrorIs(t, decoded.Do(context.TODO(), "nums 3"), ErrImportedCommand)
+
+		// encoding the decoded tree again yields the same packet
+		packet.Reset()
+		require.NoError(t, codec.Encode(context.TODO(), &packet, root))
+		require.Equal(t, encoded, packet.Bytes())
+	}
+}
+
+func TestPacketCodec_Encode_UnknownParser(t *testing.T) {
+	d := &Dispatcher{}
+	d.Register(Literal("a").Then(Argument("b", &PlaceholderType{ID: "minecraft:vec3"})))
+	err := new(PacketCodec).Encode(context.TODO(), io.Discard, &d.Root)
+	require.Error(t, err)
+
+	d = &Dispatcher{}
+	d.Register(Literal("a").Then(Argument("b", Int)))
+	err = (&PacketCodec{ParserIDs: []string{"brigadier:bool"}}).Encode(context.TODO(), io.Discard, &d.Root)
+	require.Error(t, err)
+}
+
+func TestPacketCodec_Decode_Invalid(t *testing.T) {
+	for _, packet := range [][]byte{
+		{},
+		{0xFF, 0xFF, 0xFF, 0xFF, 0x0F},         // negative node count
+		{1, 0x00, 0, 1},                        // root index out of range
+		{1, 0x01, 0, 1, 'a', 0},                // root is a literal
+		{1, 0x00, 1, 0, 0},                     // root is its own child
+		{2, 0x00, 1, 1, 0x01, 1, 1, 1, 'a', 0}, // child cycle
+		{1, 0x03, 0, 0},                        // invalid node type
+		{1, 0x08, 0, 5, 0},                     // redirect out of range
+		{2, 0x00, 1, 1, 0x02, 0, 1, 'a', 4, 'x', ':', 'y', 'z', 0}, // parser without properties codec
+	} {
+		_, err := new(PacketCodec).Decode(bytes.NewReader(packet))
+		require.Error(t, err, "%v", packet)
+	}
+}
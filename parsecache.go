@@ -0,0 +1,85 @@
+package brigodier
+
+import (
+	"container/list"
+	"sync"
+)
+
+// ClearParseCache clears the parse cache enabled by WithParseCache.
+//
+// Register clears the cache automatically, but it must be called
+// after otherwise modifying the command tree, e.g. using CommandNode.AddChild.
+func (d *Dispatcher) ClearParseCache() {
+	if d.parseCache != nil {
+		d.parseCache.clear()
+	}
+}
+
+// parseCache is a least recently used cache of ParseResults.
+//
+// Only results that do not depend on the context.Context passed to
+// Dispatcher.Parse are cached, i.e. no node requirement was checked.
+type parseCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // of *parseCacheEntry, most recently used first
+	entries map[parseCacheKey]*list.Element
+}
+
+type parseCacheKey struct {
+	input  string
+	cursor int
+}
+
+type parseCacheEntry struct {
+	key   parseCacheKey
+	parse *ParseResults
+}
+
+func newParseCache(size int) *parseCache {
+	return &parseCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[parseCacheKey]*list.Element, size),
+	}
+}
+
+// get returns a copy of the cached ParseResults of the reader or nil.
+func (c *parseCache) get(rd *StringReader) *ParseResults {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[parseCacheKey{input: rd.String, cursor: rd.Cursor}]
+	if !ok {
+		return nil
+	}
+	c.order.MoveToFront(e)
+	parse := e.Value.(*parseCacheEntry).parse
+	reader := *parse.Reader
+	return &ParseResults{Context: parse.Context, Reader: &reader, Errs: parse.Errs}
+}
+
+func (c *parseCache) put(rd *StringReader, parse *ParseResults) {
+	key := parseCacheKey{input: rd.String, cursor: rd.Cursor}
+	reader := *parse.Reader
+	entry := &parseCacheEntry{key: key, parse: &ParseResults{Context: parse.Context, Reader: &reader, Errs: parse.Errs}}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		e.Value = entry
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*parseCacheEntry).key)
+	}
+}
+
+func (c *parseCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	c.entries = make(map[parseCacheKey]*list.Element, c.size)
+}
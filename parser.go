@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strings"
+	"sync"
 )
 
 // Parse parses a given command.
@@ -32,13 +34,53 @@ func (d *Dispatcher) Parse(ctx context.Context, command string) *ParseResults {
 // ParseReader parses a given command within a reader and optional StringReader.Cursor offset.
 //
 // See Parse for more details.
-func (d *Dispatcher) ParseReader(ctx context.Context, command *StringReader) *ParseResults {
-	return d.parseNodes(command, &d.Root, &CommandContext{
+func (d *Dispatcher) ParseReader(ctx context.Context, command *StringReader) (parse *ParseResults) {
+	if d.Instrumentation != nil {
+		end := d.Instrumentation.StartParse(ctx, command.String)
+		defer func() { end(parse) }()
+	}
+	if d.parseCache != nil {
+		parse := d.parseCache.get(command)
+		d.observeCache(CacheParse, parse != nil)
+		if parse != nil {
+			parse.Context = parse.Context.CopyFor(ctx)
+			parse.dispatcher = d
+			return parse
+		}
+	}
+	state := &parseState{}
+	parse = d.parse(ctx, command, state)
+	if d.parseCache != nil && !state.restricted {
+		d.parseCache.put(command, parse)
+	}
+	return parse
+}
+
+func (d *Dispatcher) parse(ctx context.Context, command *StringReader, state *parseState) *ParseResults {
+	parse := d.parseNodes(command, &d.Root, &CommandContext{
 		Context:  ctx,
 		RootNode: &d.Root,
 		Range:    StringRange{Start: command.Cursor, End: command.Cursor},
 		cursor:   command.Cursor,
-	})
+	}, state)
+	parse.dispatcher = d
+	return parse
+}
+
+// parseState is the state of a single Dispatcher.ParseReader call.
+type parseState struct {
+	restricted bool // Whether a node requirement was checked.
+
+	explain *Explanation // Optional trace of Dispatcher.Explain
+	depth   int          // The current depth of visited nodes
+}
+
+// visit records a visited node if explaining.
+func (s *parseState) visit(step ExplainStep) {
+	if s.explain != nil {
+		step.Depth = s.depth
+		s.explain.Steps = append(s.explain.Steps, step)
+	}
 }
 
 // ParseResults stores the parse results returned by Dispatcher.Parse.
@@ -46,6 +88,8 @@ type ParseResults struct {
 	Context *CommandContext
 	Reader  *StringReader
 	Errs    map[CommandNode]error
+
+	dispatcher *Dispatcher // The Dispatcher that parsed the results, see Extend.
 }
 
 // CommandContext is the context for executing a command.
@@ -61,9 +105,44 @@ type CommandContext struct {
 	Forks     bool
 	Input     string
 
-	cursor int
+	cursor     int
+	dispatcher *Dispatcher
+	depth      int
+
+	// Copy-on-write state of contexts created by parseCopy:
+	// Arguments and Nodes may be shared with the parent context
+	// and are copied into the spare buffers before they are modified.
+	argsShared  bool
+	nodesShared bool
+	spareArgs   map[string]*ParsedArgument
+	spareNodes  []*ParsedCommandNode
+}
+
+// commandContextKey is the context.Context value key to the nearest CommandContext.
+type commandContextKey struct{}
+
+// Value implements context.Context.
+// The CommandContext itself can be looked up from a derived context.Context,
+// which allows Dispatcher.Execute to track re-entrant dispatches.
+func (c *CommandContext) Value(key interface{}) interface{} {
+	if key == (commandContextKey{}) {
+		return c
+	}
+	if c.Context == nil {
+		return nil
+	}
+	return c.Context.Value(key)
 }
 
+// Dispatcher returns the Dispatcher that is executing the command.
+// Commands may use it to programmatically dispatch other commands, e.g.
+//
+//	c.Dispatcher().Do(c, "say hello")
+//
+// Re-entrant dispatches are limited by Dispatcher.MaxDispatchDepth.
+// It returns nil if the CommandContext was not created by Dispatcher.Execute.
+func (c *CommandContext) Dispatcher() *Dispatcher { return c.dispatcher }
+
 func (c *CommandContext) build(input string) *CommandContext {
 	var child *CommandContext
 	if c.Child != nil {
@@ -106,7 +185,47 @@ func (c *CommandContext) Copy() *CommandContext {
 		Forks:    c.Forks,
 		Input:    c.Input,
 		cursor:   c.cursor,
+
+		dispatcher: c.dispatcher,
+		depth:      c.depth,
+	}
+}
+
+// contextPool pools the CommandContexts of failed parse branches.
+var contextPool = sync.Pool{New: func() interface{} { return new(CommandContext) }}
+
+// parseCopy is like Copy but takes the CommandContext from contextPool
+// and shares Arguments and Nodes with c until they are modified,
+// so that failed parse branches don't pay the cost of copying them.
+// The parent context c must not be modified afterwards.
+func (c *CommandContext) parseCopy() *CommandContext {
+	clone := contextPool.Get().(*CommandContext)
+	spareArgs, spareNodes := clone.spareArgs, clone.spareNodes
+	*clone = *c
+	clone.argsShared = c.Arguments != nil
+	clone.nodesShared = c.Nodes != nil
+	clone.spareArgs, clone.spareNodes = spareArgs, spareNodes
+	return clone
+}
+
+// release puts a CommandContext created by parseCopy back into contextPool.
+// The CommandContext must not be used afterwards.
+func (c *CommandContext) release() {
+	spareArgs, spareNodes := c.spareArgs, c.spareNodes
+	if !c.argsShared && c.Arguments != nil {
+		for k := range c.Arguments {
+			delete(c.Arguments, k)
+		}
+		spareArgs = c.Arguments
+	}
+	if !c.nodesShared && c.Nodes != nil {
+		for i := range c.Nodes {
+			c.Nodes[i] = nil
+		}
+		spareNodes = c.Nodes[:0]
 	}
+	*c = CommandContext{spareArgs: spareArgs, spareNodes: spareNodes}
+	contextPool.Put(c)
 }
 
 // CopyFor copies the CommandContext if ctx is not equal to
@@ -141,77 +260,159 @@ func (e *CommandSyntaxError) Error() string {
 	return e.Err.Error()
 }
 
-func (d *Dispatcher) parseNodes(originalReader *StringReader, node CommandNode, ctxSoFar *CommandContext) *ParseResults {
-	errs := map[CommandNode]error{}
-	var potentials []*ParseResults
-	cursor := originalReader.Cursor
+// parseFrame is the state of parsing the children of a node.
+type parseFrame struct {
+	reader     *StringReader   // The reader positioned at the children.
+	ctx        *CommandContext // The context so far.
+	children   []CommandNode   // The relevant children left to parse.
+	cursor     int
+	redirects  int // The number of redirects followed to reach this frame.
+	errs       map[CommandNode]error
+	potentials []*ParseResults
+
+	redirected *CommandContext // The context whose redirect is parsed by the next frame.
+	result     *ParseResults   // The result once the frame is done.
+}
 
+func (d *Dispatcher) newParseFrame(rd *StringReader, node CommandNode, ctx *CommandContext, redirects int) *parseFrame {
+	return &parseFrame{
+		reader:    rd,
+		ctx:       ctx,
+		children:  d.relevantNodes(node, rd),
+		cursor:    rd.Cursor,
+		redirects: redirects,
+	}
+}
+
+// parseNodes parses the input from the children of node.
+// Instead of recursing per parsed node, it keeps an explicit stack of
+// parseFrames so that long inputs and redirect chains can't overflow the stack.
+func (d *Dispatcher) parseNodes(originalReader *StringReader, node CommandNode, ctxSoFar *CommandContext, state *parseState) *ParseResults {
+	stack := []*parseFrame{d.newParseFrame(originalReader, node, ctxSoFar, 0)}
+	for {
+		f := stack[len(stack)-1]
+		if next := d.parseFrameStep(f, state); next != nil {
+			stack = append(stack, next)
+			state.depth++
+			continue
+		}
+		stack = stack[:len(stack)-1]
+		if len(stack) == 0 {
+			return f.result
+		}
+		state.depth--
+		stack[len(stack)-1].receive(f.result)
+	}
+}
+
+// receive receives the result of the frame pushed by parseFrameStep.
+func (f *parseFrame) receive(parse *ParseResults) {
+	if f.redirected == nil {
+		f.potentials = append(f.potentials, parse)
+		return
+	}
+	f.redirected.Child = parse.Context
+	f.result = &ParseResults{
+		Context: f.redirected,
+		Reader:  parse.Reader,
+		Errs:    parse.Errs,
+	}
+}
+
+// parseFrameStep parses the remaining children of f until a matched child
+// has to be parsed further, in which case the frame to push is returned.
+// Otherwise f.result is set and nil is returned.
+func (d *Dispatcher) parseFrameStep(f *parseFrame, state *parseState) *parseFrame {
+	if f.result != nil {
+		return nil
+	}
 	var (
 		err error
 		ctx *CommandContext
 		rd  *StringReader
 	)
-	for _, child := range node.RelevantNodes(originalReader) {
-		if !child.CanUse(ctxSoFar) {
+	for len(f.children) != 0 {
+		child := f.children[0]
+		f.children = f.children[1:]
+		if child.Requirement() != nil {
+			state.restricted = true
+		}
+		if !child.CanUse(f.ctx) {
+			state.visit(ExplainStep{Node: child, Outcome: ExplainSkipped, Cursor: f.cursor})
 			continue
 		}
-		ctx = ctxSoFar.Copy()
+		ctx = f.ctx.parseCopy()
 		rd = &StringReader{
-			Cursor: originalReader.Cursor,
-			String: originalReader.String,
+			Cursor: f.reader.Cursor,
+			String: f.reader.String,
 		}
 
-		err = child.Parse(ctx, rd)
+		if lit, ok := child.(*LiteralCommandNode); ok && d.CaseInsensitiveLiterals {
+			err = lit.parseLiteral(ctx, rd, true)
+		} else {
+			err = child.Parse(ctx, rd)
+		}
 		if err == nil && rd.CanRead() && rd.Peek() != ArgumentSeparator {
 			err = &CommandSyntaxError{Err: &ReaderError{
 				Err:    ErrDispatcherExpectedArgumentSeparator,
 				Reader: rd,
 			}}
 		}
-		if err != nil {
-			errs[child] = err
-			rd.Cursor = cursor
-			continue
-		}
-
-		ctx.Command = child.Command()
 		redirect := child.Redirect()
 		canRead := 1
 		if redirect == nil {
 			canRead = 2
 		}
-		if rd.CanReadLen(canRead) {
-			rd.Skip()
-			if redirect != nil {
-				childCtx := &CommandContext{
-					Context:  ctx,
-					RootNode: redirect,
-					cursor:   rd.Cursor,
-					Range: StringRange{
-						Start: rd.Cursor,
-						End:   rd.Cursor,
-					},
-				}
-				parse := d.parseNodes(rd, redirect, childCtx)
-				ctx.Child = parse.Context
-				return &ParseResults{
-					Context: ctx,
-					Reader:  parse.Reader,
-					Errs:    parse.Errs,
-				}
+		if err == nil && redirect != nil && rd.CanReadLen(canRead) {
+			if max := d.maxRedirects(); max >= 0 && f.redirects >= max {
+				err = &CommandSyntaxError{Err: &ReaderError{
+					Err:    ErrTooManyRedirects,
+					Reader: rd,
+				}}
 			}
-			potentials = append(potentials, d.parseNodes(rd, child, ctx))
-		} else {
-			potentials = append(potentials, &ParseResults{
+		}
+		if err != nil {
+			if f.errs == nil {
+				f.errs = map[CommandNode]error{}
+			}
+			f.errs[child] = err
+			state.visit(ExplainStep{Node: child, Outcome: ExplainFailed, Cursor: f.cursor, Err: err})
+			rd.Cursor = f.cursor
+			ctx.release()
+			continue
+		}
+
+		state.visit(ExplainStep{Node: child, Outcome: ExplainMatched, Cursor: f.cursor,
+			Range: StringRange{Start: f.cursor, End: rd.Cursor}})
+		ctx.Command = child.Command()
+		if !rd.CanReadLen(canRead) {
+			f.potentials = append(f.potentials, &ParseResults{
 				Context: ctx,
 				Reader:  rd,
 			})
+			continue
+		}
+		rd.Skip()
+		if redirect != nil {
+			childCtx := &CommandContext{
+				Context:  ctx,
+				RootNode: redirect,
+				cursor:   rd.Cursor,
+				Range: StringRange{
+					Start: rd.Cursor,
+					End:   rd.Cursor,
+				},
+			}
+			f.redirected = ctx
+			return d.newParseFrame(rd, redirect, childCtx, f.redirects+1)
 		}
+		return d.newParseFrame(rd, child, ctx, f.redirects)
 	}
 
-	if len(potentials) != 0 {
+	if len(f.potentials) != 0 {
+		potentials := f.potentials
 		if len(potentials) > 1 {
-			sort.Slice(potentials, func(i, j int) bool {
+			sort.SliceStable(potentials, func(i, j int) bool {
 				a := potentials[i]
 				b := potentials[j]
 				if !a.Reader.CanRead() && b.Reader.CanRead() {
@@ -229,14 +430,16 @@ func (d *Dispatcher) parseNodes(originalReader *StringReader, node CommandNode,
 				return false
 			})
 		}
-		return potentials[0]
+		f.result = potentials[0]
+		return nil
 	}
 
-	return &ParseResults{
-		Context: ctxSoFar,
-		Reader:  originalReader,
-		Errs:    errs,
+	f.result = &ParseResults{
+		Context: f.ctx,
+		Reader:  f.reader,
+		Errs:    f.errs,
 	}
+	return nil
 }
 
 func (r *ParseResults) firstErr() error {
@@ -259,13 +462,53 @@ func (n *Node) RelevantNodes(input *StringReader) []CommandNode {
 			return []CommandNode{literal}
 		}
 	}
-	nodes := make([]CommandNode, 0, len(n.arguments))
-	for _, a := range n.arguments {
+	nodes := make([]CommandNode, 0, len(n.argumentOrder))
+	for _, a := range n.argumentOrder {
 		nodes = append(nodes, a)
 	}
 	return nodes
 }
 
+// relevantNodes returns the relevant nodes of node for the input respecting
+// Dispatcher.CaseInsensitiveLiterals and Dispatcher.ArgumentSiblingsOnLiteral.
+func (d *Dispatcher) relevantNodes(node CommandNode, input *StringReader) []CommandNode {
+	nodes := d.relevantLiteralOrArguments(node, input)
+	if !d.ArgumentSiblingsOnLiteral || len(nodes) != 1 {
+		return nodes
+	}
+	if _, ok := nodes[0].(*LiteralCommandNode); !ok {
+		return nodes
+	}
+	for _, a := range node.ArgumentsOrdered() {
+		nodes = append(nodes, a)
+	}
+	return nodes
+}
+
+func (d *Dispatcher) relevantLiteralOrArguments(node CommandNode, input *StringReader) []CommandNode {
+	nodes := node.RelevantNodes(input)
+	if !d.CaseInsensitiveLiterals || len(node.Literals()) == 0 {
+		return nodes
+	}
+	if len(nodes) == 1 {
+		if _, ok := nodes[0].(*LiteralCommandNode); ok {
+			return nodes // exact match
+		}
+	}
+	cursor := input.Cursor
+	for input.CanRead() && input.Peek() != ArgumentSeparator {
+		input.Skip()
+	}
+	text := input.String[cursor:input.Cursor]
+	input.Cursor = cursor
+	for _, literal := range node.LiteralsWithPrefix(text) {
+		if len(literal.Literal) == len(text) && strings.EqualFold(literal.Literal, text) {
+			return []CommandNode{literal}
+		}
+	}
+	return nodes
+}
+
 // IncorrectLiteralError is used to indicate an incorrect literal parse error.
 type IncorrectLiteralError struct {
 	Literal string // The incorrect literal value.
@@ -275,23 +518,32 @@ func (e *IncorrectLiteralError) Error() string { return fmt.Sprintf("incorrect l
 
 // Parse parses the literal from an input reader.
 func (n *LiteralCommandNode) Parse(ctx *CommandContext, rd *StringReader) error {
+	return n.parseLiteral(ctx, rd, false)
+}
+
+// parseLiteral parses the literal optionally ignoring case.
+func (n *LiteralCommandNode) parseLiteral(ctx *CommandContext, rd *StringReader, fold bool) error {
 	start := rd.Cursor
-	end := n.parse(rd)
+	end := n.parse(rd, fold)
 	if end <= -1 {
 		return &CommandSyntaxError{Err: &ReaderError{
 			Err:    &IncorrectLiteralError{Literal: n.Literal},
 			Reader: rd,
 		}}
 	}
-	ctx.withNode(n, &StringRange{Start: start, End: end})
+	r := &StringRange{Start: start, End: end}
+	ctx.withNode(n, r)
+	if n.mappedArgument != "" {
+		ctx.withArgument(n.mappedArgument, &ParsedArgument{Range: r, Result: n.mappedValue})
+	}
 	return nil
 }
 
-func (n *LiteralCommandNode) parse(rd *StringReader) int {
+func (n *LiteralCommandNode) parse(rd *StringReader, fold bool) int {
 	start := rd.Cursor
 	if rd.CanReadLen(len(n.Literal)) {
 		end := start + len(n.Literal)
-		if rd.String[start:end] == n.Literal {
+		if text := rd.String[start:end]; text == n.Literal || fold && strings.EqualFold(text, n.Literal) {
 			rd.Cursor = end
 			if !rd.CanRead() || rd.Peek() == ArgumentSeparator {
 				return end
@@ -308,13 +560,50 @@ type ParsedArgument struct {
 	Result interface{}  // The parsed result value.
 }
 
-// Parse parses the argument from an input reader.
+// Raw returns the original text of the argument in the command input.
+func (a *ParsedArgument) Raw(input string) string { return a.Range.Get(input) }
+
+// As returns the parsed result of the argument as T
+// and whether the argument is not nil and of type T.
+func As[T any](a *ParsedArgument) (T, bool) {
+	if a == nil {
+		var zero T
+		return zero, false
+	}
+	v, ok := a.Result.(T)
+	return v, ok
+}
+
+// Parse parses the argument from an input reader, runs the optional
+// validations and transformations and applies the optional CanonicalizeFn to the result.
 func (a *ArgumentCommandNode) Parse(ctx *CommandContext, rd *StringReader) error {
 	start := rd.Cursor
 	result, err := a.argType.Parse(rd)
 	if err != nil {
 		return fmt.Errorf("error parsing argument: %w", err)
 	}
+	if argumentTokens(a.argType) != 1 {
+		for rd.Cursor > start && rune(rd.String[rd.Cursor-1]) == ArgumentSeparator {
+			rd.Cursor--
+		}
+	}
+	for _, transform := range a.transforms {
+		if result, err = transform(result); err != nil {
+			value := rd.String[start:rd.Cursor]
+			rd.Cursor = start
+			return fmt.Errorf("error parsing argument: %w", &CommandSyntaxError{Err: &ReaderError{
+				Err: &ReaderInvalidValueError{
+					Type:  a.argType,
+					Value: value,
+					Err:   err,
+				},
+				Reader: rd,
+			}})
+		}
+	}
+	if a.canonicalize != nil {
+		result = a.canonicalize(result)
+	}
 	parsed := &ParsedArgument{
 		Range:  &StringRange{Start: start, End: rd.Cursor},
 		Result: result,
@@ -325,6 +614,15 @@ func (a *ArgumentCommandNode) Parse(ctx *CommandContext, rd *StringReader) error
 }
 
 func (c *CommandContext) withNode(node CommandNode, r *StringRange) {
+	if c.nodesShared {
+		nodes := c.spareNodes
+		if cap(nodes) <= len(c.Nodes) {
+			// Reserve space for the node parsed next.
+			nodes = make([]*ParsedCommandNode, 0, len(c.Nodes)+1)
+		}
+		c.Nodes = append(nodes, c.Nodes...)
+		c.nodesShared, c.spareNodes = false, nil
+	}
 	c.Nodes = append(c.Nodes, &ParsedCommandNode{
 		Node:  node,
 		Range: r,
@@ -335,8 +633,16 @@ func (c *CommandContext) withNode(node CommandNode, r *StringRange) {
 }
 
 func (c *CommandContext) withArgument(name string, parsed *ParsedArgument) {
-	if c.Arguments == nil {
-		c.Arguments = map[string]*ParsedArgument{}
+	if c.argsShared || c.Arguments == nil {
+		args := c.spareArgs
+		if args == nil {
+			args = make(map[string]*ParsedArgument, len(c.Arguments)+1)
+		}
+		for k, v := range c.Arguments {
+			args[k] = v
+		}
+		c.Arguments = args
+		c.argsShared, c.spareArgs = false, nil
 	}
 	c.Arguments[name] = parsed
 }
@@ -0,0 +1,210 @@
+package brigodier
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	// ErrExpectedPath occurs when a path was expected but none was found.
+	ErrExpectedPath = errors.New("expected path")
+	// ErrPathOutsideBase occurs when a path is absolute or leaves the base directory.
+	ErrPathOutsideBase = errors.New("path outside of base directory")
+	// ErrPathNotFound occurs when a path of a PathArgumentType with MustExist does not exist.
+	ErrPathNotFound = errors.New("path not found")
+	// ErrPathExtension occurs when a path does not have one of the extensions of a PathArgumentType.
+	ErrPathExtension = errors.New("unsupported path extension")
+)
+
+// PathArgumentType parses a slash-separated path relative to the Base directory,
+// e.g. for console commands like "/schematic load <file>", and suggests the
+// matching files and directories.
+//
+// The result is the path joined with Base as string, see CommandContext.String.
+// Paths containing spaces must be quoted.
+//
+// Absolute paths, paths leaving Base with ".." and paths resolving to
+// a location outside of Base through symbolic links are rejected with
+// ErrPathOutsideBase. Symbolic links within Base are allowed.
+type PathArgumentType struct {
+	// Base is the directory the paths are relative to,
+	// the working directory if empty.
+	Base string
+	// Extensions optionally restricts the paths to those with one of the
+	// extensions, e.g. ".schem". Directories are still suggested to
+	// complete the files inside them.
+	Extensions []string
+	// MustExist is whether the path must exist when parsed.
+	MustExist bool
+}
+
+func (t *PathArgumentType) String() string { return "path" }
+func (t *PathArgumentType) Parse(rd *StringReader) (interface{}, error) {
+	start := rd.Cursor
+	var (
+		p   string
+		err error
+	)
+	if rd.CanRead() && IsQuotedStringStart(rd.Peek()) {
+		if p, err = rd.ReadQuotedString(); err != nil {
+			return nil, err
+		}
+	} else {
+		p = rd.ReadWhile(func(c rune) bool { return c != ' ' })
+	}
+	if p == "" {
+		rd.Cursor = start
+		return nil, &CommandSyntaxError{Err: &ReaderError{
+			Err:    ErrExpectedPath,
+			Reader: rd,
+		}}
+	}
+	fail := func(err error) (interface{}, error) {
+		rd.Cursor = start
+		return nil, &CommandSyntaxError{Err: &ReaderError{
+			Err:    fmt.Errorf("%w: %q", err, p),
+			Reader: rd,
+		}}
+	}
+	rel, ok := localPath(p)
+	if !ok {
+		return fail(ErrPathOutsideBase)
+	}
+	full := filepath.Join(t.Base, rel)
+	if !t.resolvesWithinBase(full) {
+		return fail(ErrPathOutsideBase)
+	}
+	if t.MustExist {
+		if _, err := os.Stat(full); err != nil {
+			return fail(ErrPathNotFound)
+		}
+	}
+	if !t.hasExtension(rel) {
+		return fail(ErrPathExtension)
+	}
+	return full, nil
+}
+
+// localPath returns the slash-separated path p as cleaned
+// relative path or false if it is absolute or leaves its base.
+func localPath(p string) (string, bool) {
+	if strings.ContainsRune(p, '\\') || strings.ContainsRune(p, 0) {
+		return "", false
+	}
+	clean := path.Clean(p)
+	if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", false
+	}
+	rel := filepath.FromSlash(clean)
+	if filepath.IsAbs(rel) || filepath.VolumeName(rel) != "" {
+		return "", false
+	}
+	return rel, true
+}
+
+// resolvesWithinBase indicates whether the longest existing prefix
+// of full is within Base after resolving symbolic links, so files
+// created at full can not end up outside of Base either.
+func (t *PathArgumentType) resolvesWithinBase(full string) bool {
+	base, err := filepath.Abs(t.Base)
+	if err == nil {
+		full, err = filepath.Abs(full)
+	}
+	if err != nil {
+		return false
+	}
+	if base, err = filepath.EvalSymlinks(base); err != nil {
+		return true // nothing exists below a missing Base
+	}
+	for p := full; ; p = filepath.Dir(p) {
+		resolved, err := filepath.EvalSymlinks(p)
+		if err == nil {
+			rel, err := filepath.Rel(base, resolved)
+			return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+		}
+		if filepath.Dir(p) == p {
+			return true
+		}
+	}
+}
+
+// hasExtension indicates whether the file name has one of the Extensions
+// or Extensions is empty.
+func (t *PathArgumentType) hasExtension(name string) bool {
+	if len(t.Extensions) == 0 {
+		return true
+	}
+	for _, ext := range t.Extensions {
+		if strings.HasSuffix(strings.ToLower(name), strings.ToLower(ext)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Suggestions implements SuggestionProvider and suggests the files and directories
+// in the directory of the input matching the name typed so far.
+// Directories are suggested with a trailing slash to continue completing inside them.
+// Hidden entries starting with a dot are only suggested if the name starts with a dot.
+func (t *PathArgumentType) Suggestions(_ *CommandContext, builder *SuggestionsBuilder) *Suggestions {
+	typed := builder.Remaining
+	if typed != "" && IsQuotedStringStart(rune(typed[0])) {
+		typed = typed[1:]
+	}
+	dir, name := "", typed
+	if i := strings.LastIndexByte(typed, '/'); i != -1 {
+		dir, name = typed[:i+1], typed[i+1:]
+	}
+	rel, ok := localPath(dir + ".")
+	if !ok {
+		return emptySuggestions
+	}
+	full := filepath.Join(t.Base, rel)
+	if !t.resolvesWithinBase(full) {
+		return emptySuggestions
+	}
+	entries, err := os.ReadDir(full)
+	if err != nil {
+		return emptySuggestions
+	}
+	lowerName := strings.ToLower(name)
+	for _, e := range entries {
+		n := e.Name()
+		if strings.HasPrefix(n, ".") && !strings.HasPrefix(name, ".") ||
+			!strings.HasPrefix(strings.ToLower(n), lowerName) {
+			continue
+		}
+		if e.Type()&os.ModeSymlink != 0 {
+			if !t.resolvesWithinBase(filepath.Join(full, n)) {
+				continue
+			}
+			if info, err := os.Stat(filepath.Join(full, n)); err == nil && info.IsDir() {
+				builder.Suggest(dir + n + "/")
+				continue
+			}
+		}
+		if e.IsDir() {
+			builder.Suggest(dir + n + "/")
+		} else if t.hasExtension(n) {
+			builder.Suggest(dir + n)
+		}
+	}
+	return builder.Build()
+}
+
+// Dynamic implements DynamicSuggestionProvider since the
+// suggestions list the files currently in the directory.
+func (t *PathArgumentType) Dynamic() bool { return true }
+
+// QuoteSuggestion implements SuggestionQuoter and quotes
+// suggested paths that would otherwise not parse as one path.
+func (t *PathArgumentType) QuoteSuggestion(text string) string {
+	if text != "" && !IsQuotedStringStart(rune(text[0])) && !strings.ContainsRune(text, ' ') {
+		return text
+	}
+	return QuoteString(text)
+}
@@ -0,0 +1,98 @@
+package brigodier
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPathArgumentType(t *testing.T) {
+	base := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(base, "castles", "old"), 0o755))
+	for _, name := range []string{"house.schem", "House 2.schem", "notes.txt", ".hidden.schem", "castles/keep.schem"} {
+		require.NoError(t, os.WriteFile(filepath.Join(base, name), nil, 0o644))
+	}
+	at := &PathArgumentType{Base: base, Extensions: []string{".schem"}, MustExist: true}
+
+	r := &StringReader{String: "castles/keep.schem 1"}
+	p, err := at.Parse(r)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(base, "castles", "keep.schem"), p)
+	require.Equal(t, " 1", r.Remaining())
+
+	p, err = at.Parse(&StringReader{String: `"House 2.schem"`})
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(base, "House 2.schem"), p)
+
+	for _, input := range []string{"../house.schem", "castles/../../x.schem", "/etc/passwd", `..\x.schem`} {
+		r = &StringReader{String: "load " + input}
+		r.Cursor = 5
+		_, err = at.Parse(r)
+		require.ErrorIs(t, err, ErrPathOutsideBase, input)
+		require.Equal(t, 5, r.Cursor)
+	}
+	_, err = at.Parse(&StringReader{String: "missing.schem"})
+	require.ErrorIs(t, err, ErrPathNotFound)
+	_, err = at.Parse(&StringReader{String: "notes.txt"})
+	require.ErrorIs(t, err, ErrPathExtension)
+	_, err = at.Parse(&StringReader{String: ""})
+	require.ErrorIs(t, err, ErrExpectedPath)
+
+	d := NewDispatcher()
+	d.Register(Literal("schematic").Then(Literal("load").Then(Argument("file", at))))
+	suggest := func(input string) []string {
+		s, err := d.CompletionSuggestions(d.Parse(context.TODO(), input))
+		require.NoError(t, err)
+		var texts []string
+		for _, suggestion := range s.Suggestions {
+			texts = append(texts, suggestion.Text)
+		}
+		return texts
+	}
+	require.Equal(t, []string{`"House 2.schem"`, "castles/", "house.schem"}, suggest("schematic load "))
+	require.Equal(t, []string{`"House 2.schem"`, "house.schem"}, suggest("schematic load h"))
+	require.Equal(t, []string{"castles/keep.schem", "castles/old/"}, suggest("schematic load castles/"))
+	require.Equal(t, []string{".hidden.schem"}, suggest("schematic load ."))
+	require.Empty(t, suggest("schematic load ../"))
+}
+
+func TestPathArgumentType_Symlinks(t *testing.T) {
+	base, outside := t.TempDir(), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "secret.schem"), nil, 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(base, "real"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(base, "real", "house.schem"), nil, 0o644))
+	if err := os.Symlink(outside, filepath.Join(base, "escape")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	require.NoError(t, os.Symlink(filepath.Join(base, "real"), filepath.Join(base, "inside")))
+	at := &PathArgumentType{Base: base}
+
+	for _, input := range []string{"escape/secret.schem", "escape/new.schem", "escape"} {
+		_, err := at.Parse(&StringReader{String: input})
+		require.ErrorIs(t, err, ErrPathOutsideBase, input)
+	}
+	p, err := at.Parse(&StringReader{String: "inside/house.schem"})
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(base, "inside", "house.schem"), p)
+
+	d := NewDispatcher(WithSuggestionCache(8))
+	d.Register(Literal("load").Then(Argument("file", at)))
+	suggest := func(input string) []string {
+		s, err := d.CompletionSuggestions(d.Parse(context.TODO(), input))
+		require.NoError(t, err)
+		var texts []string
+		for _, suggestion := range s.Suggestions {
+			texts = append(texts, suggestion.Text)
+		}
+		return texts
+	}
+	require.Empty(t, suggest("load escape/"))
+	require.Equal(t, []string{"inside/", "real/"}, suggest("load "))
+	require.Equal(t, []string{"inside/house.schem"}, suggest("load inside/"))
+
+	// suggestions are not cached
+	require.NoError(t, os.WriteFile(filepath.Join(base, "real", "tower.schem"), nil, 0o644))
+	require.Equal(t, []string{"inside/house.schem", "inside/tower.schem"}, suggest("load inside/"))
+}
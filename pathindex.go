@@ -0,0 +1,68 @@
+package brigodier
+
+import "sync"
+
+// pathIndex is the lazily built index of the parents of the nodes
+// of a command tree used by Dispatcher.Path.
+//
+// Since the tree may be modified without notice, e.g. using CommandNode.AddChild,
+// a path found in the index is verified to still lead to the node and
+// the index is rebuilt if it does not or the node is not indexed.
+type pathIndex struct {
+	mu      sync.Mutex
+	parents map[CommandNode]CommandNode // The parent of the first instance of a node in the tree.
+}
+
+func (d *Dispatcher) pathIndex() *pathIndex {
+	if idx, ok := d.paths.Load().(*pathIndex); ok {
+		return idx
+	}
+	d.paths.CompareAndSwap(nil, &pathIndex{})
+	return d.paths.Load().(*pathIndex)
+}
+
+func (idx *pathIndex) pathNodes(root, target CommandNode) []CommandNode {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if nodes, ok := idx.path(root, target); ok {
+		return nodes
+	}
+	idx.rebuild(root)
+	nodes, _ := idx.path(root, target)
+	return nodes
+}
+
+// path returns the path to target following the parents
+// and whether it is indexed and still valid.
+func (idx *pathIndex) path(root, target CommandNode) ([]CommandNode, bool) {
+	var reversed []CommandNode
+	for node := target; node != root; {
+		parent, ok := idx.parents[node]
+		if !ok || parent.Children()[node.Name()] != node || len(reversed) > len(idx.parents) {
+			return nil, false
+		}
+		reversed = append(reversed, node)
+		node = parent
+	}
+	nodes := make([]CommandNode, len(reversed))
+	for i, node := range reversed {
+		nodes[len(nodes)-1-i] = node
+	}
+	return nodes, true
+}
+
+// rebuild indexes the parents of the first instances of the nodes
+// in the order they are visited by Dispatcher.Walk.
+func (idx *pathIndex) rebuild(root CommandNode) {
+	idx.parents = map[CommandNode]CommandNode{}
+	WalkNode(root, func(path []CommandNode, node CommandNode) bool {
+		if _, ok := idx.parents[node]; !ok {
+			parent := root
+			if len(path) != 0 {
+				parent = path[len(path)-1]
+			}
+			idx.parents[node] = parent
+		}
+		return true
+	})
+}
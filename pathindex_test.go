@@ -0,0 +1,52 @@
+package brigodier
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDispatcher_Path_Index(t *testing.T) {
+	var d Dispatcher
+	d.Register(Literal("a").Then(Literal("b").Then(Literal("c"))))
+	c := d.FindNode("a", "b", "c")
+	require.Equal(t, []string{"a", "b", "c"}, d.Path(c))
+	require.Nil(t, d.Path(&d.Root))
+	require.Nil(t, d.Path(Literal("x").Build()))
+
+	// modifying the tree invalidates indexed paths
+	b := d.FindNode("a", "b")
+	d.Root.RemoveChild("a")
+	require.Nil(t, d.Path(c))
+	d.Register(Literal("x").Then(Literal("y")))
+	d.FindNode("x", "y").AddChild(b)
+	require.Equal(t, []string{"x", "y", "b", "c"}, d.Path(c))
+
+	// a shared node keeps its first path
+	d.FindNode("x").AddChild(b)
+	require.Equal(t, []string{"x", "y", "b"}, d.Path(b))
+	d.FindNode("x", "y").RemoveChild("b")
+	require.Equal(t, []string{"x", "b"}, d.Path(b))
+
+	clone := d.Clone()
+	require.Nil(t, clone.Path(c))
+	require.Equal(t, []string{"x", "b", "c"}, clone.Path(clone.FindNode("x", "b", "c")))
+}
+
+func BenchmarkDispatcher_Path(b *testing.B) {
+	var d Dispatcher
+	for i := 0; i < 100; i++ {
+		cmd := Literal(fmt.Sprint("cmd", i))
+		for j := 0; j < 100; j++ {
+			cmd.Then(Literal(fmt.Sprint("sub", j)).Then(Argument("arg", Int)))
+		}
+		d.Register(cmd)
+	}
+	target := d.FindNode("cmd99", "sub99", "arg")
+	require.Equal(b, []string{"cmd99", "sub99", "arg"}, d.Path(target))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d.Path(target)
+	}
+}
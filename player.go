@@ -0,0 +1,173 @@
+package brigodier
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PlayerArgumentType reads a player name or UUID into a PlayerRef.
+//
+// The PlayerRef is not resolved while parsing, since resolving may be slow
+// (e.g. querying a profile API) and parsing happens for every suggestion request.
+// Instead, the command resolves it at execute time using CommandContext.Player,
+// which calls the PlayerResolver with the command's context.Context.
+type PlayerArgumentType struct {
+	// Resolver resolves the PlayerRef at execute time.
+	Resolver PlayerResolver
+	// Online optionally returns the names of the online players used for suggestions.
+	Online func(ctx context.Context) []string
+}
+
+// PlayerResolver resolves a PlayerRef to a player.
+type PlayerResolver interface {
+	ResolvePlayer(ctx context.Context, ref PlayerRef) (interface{}, error)
+}
+
+// PlayerResolverFunc is a convenient function type implementing the PlayerResolver interface.
+type PlayerResolverFunc func(ctx context.Context, ref PlayerRef) (interface{}, error)
+
+// ResolvePlayer implements PlayerResolver.
+func (f PlayerResolverFunc) ResolvePlayer(ctx context.Context, ref PlayerRef) (interface{}, error) {
+	return f(ctx, ref)
+}
+
+// PlayerRef is an unresolved reference to a player by name or UUID
+// as parsed by PlayerArgumentType.
+type PlayerRef struct {
+	Name string // The player name, empty if referenced by UUID.
+	UUID UUID   // The player UUID, zero if referenced by name.
+
+	resolver PlayerResolver
+}
+
+// IsUUID indicates whether the player is referenced by UUID.
+func (r PlayerRef) IsUUID() bool { return r.Name == "" }
+
+func (r PlayerRef) String() string {
+	if r.IsUUID() {
+		return r.UUID.String()
+	}
+	return r.Name
+}
+
+var (
+	// ErrArgumentInvalidPlayer occurs when the read value is neither a valid player name nor UUID.
+	ErrArgumentInvalidPlayer = errors.New("invalid player name or uuid")
+	// ErrNoPlayerResolver occurs when resolving a PlayerRef of a
+	// PlayerArgumentType without PlayerArgumentType.Resolver.
+	ErrNoPlayerResolver = errors.New("no player resolver")
+)
+
+// Resolve resolves the player using the PlayerArgumentType.Resolver.
+func (r PlayerRef) Resolve(ctx context.Context) (interface{}, error) {
+	if r.resolver == nil {
+		return nil, fmt.Errorf("%w to resolve %s", ErrNoPlayerResolver, r)
+	}
+	return r.resolver.ResolvePlayer(ctx, r)
+}
+
+// Maximum and minimum length of a player name.
+const (
+	MinPlayerNameLength = 3
+	MaxPlayerNameLength = 16
+)
+
+// IsValidPlayerName indicated whether name is a valid player name.
+func IsValidPlayerName(name string) bool {
+	if len(name) < MinPlayerNameLength || len(name) > MaxPlayerNameLength {
+		return false
+	}
+	for _, c := range name {
+		if !(c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *PlayerArgumentType) String() string { return "player" }
+func (t *PlayerArgumentType) Parse(rd *StringReader) (interface{}, error) {
+	start := rd.Cursor
+	value := rd.ReadUnquotedString()
+	if id, err := ParseUUID(value); err == nil {
+		return PlayerRef{UUID: id, resolver: t.Resolver}, nil
+	}
+	if IsValidPlayerName(value) {
+		return PlayerRef{Name: value, resolver: t.Resolver}, nil
+	}
+	rd.Cursor = start
+	return nil, &CommandSyntaxError{Err: &ReaderError{
+		Err: &ReaderInvalidValueError{
+			Type:  t,
+			Value: value,
+			Err:   fmt.Errorf("%w %q", ErrArgumentInvalidPlayer, value),
+		},
+		Reader: rd,
+	}}
+}
+
+// Suggestions implements SuggestionProvider and suggests the names of online players.
+func (t *PlayerArgumentType) Suggestions(ctx *CommandContext, builder *SuggestionsBuilder) *Suggestions {
+	if t.Online == nil {
+		return emptySuggestions
+	}
+	for _, name := range t.Online(ctx) {
+		if strings.HasPrefix(strings.ToLower(name), builder.RemainingLowerCase) {
+			builder.Suggest(name)
+		}
+	}
+	return builder.Build()
+}
+
+// PlayerRef returns the parsed PlayerRef argument from the command context.
+// It returns the zero-value if not found.
+func (c *CommandContext) PlayerRef(argumentName string) PlayerRef {
+	if c.Arguments == nil {
+		return PlayerRef{}
+	}
+	r, ok := c.Arguments[argumentName]
+	if !ok {
+		return PlayerRef{}
+	}
+	v, _ := r.Result.(PlayerRef)
+	return v
+}
+
+// Player resolves the parsed PlayerRef argument from the command context
+// using the PlayerArgumentType.Resolver and the command's context.
+func (c *CommandContext) Player(argumentName string) (interface{}, error) {
+	return c.PlayerRef(argumentName).Resolve(c)
+}
+
+// UUID is a universally unique identifier, e.g. of a player.
+type UUID [16]byte
+
+// ErrInvalidUUID occurs when parsing an invalid UUID.
+var ErrInvalidUUID = errors.New("invalid uuid")
+
+// ParseUUID parses a UUID in the canonical 8-4-4-4-12 form or as 32 hexadecimal digits.
+func ParseUUID(s string) (UUID, error) {
+	var id UUID
+	if len(s) == 36 {
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return id, fmt.Errorf("%w %q", ErrInvalidUUID, s)
+		}
+		s = s[:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	}
+	if len(s) != 32 {
+		return id, fmt.Errorf("%w %q", ErrInvalidUUID, s)
+	}
+	if _, err := hex.Decode(id[:], []byte(s)); err != nil {
+		return id, fmt.Errorf("%w %q: %v", ErrInvalidUUID, s, err)
+	}
+	return id, nil
+}
+
+// String returns the UUID in canonical 8-4-4-4-12 form.
+func (u UUID) String() string {
+	s := hex.EncodeToString(u[:])
+	return s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
@@ -0,0 +1,69 @@
+package brigodier
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestPlayerType_Parse(t *testing.T) {
+	r := &StringReader{String: "Notch rest"}
+	ref, err := (&PlayerArgumentType{}).Parse(r)
+	require.NoError(t, err)
+	require.Equal(t, "Notch", ref.(PlayerRef).Name)
+	require.False(t, ref.(PlayerRef).IsUUID())
+	require.Equal(t, " rest", r.Remaining())
+
+	const id = "069a79f4-44e9-4726-a5be-fca90e38aaf5"
+	ref, err = (&PlayerArgumentType{}).Parse(&StringReader{String: id})
+	require.NoError(t, err)
+	require.True(t, ref.(PlayerRef).IsUUID())
+	require.Equal(t, id, ref.(PlayerRef).String())
+
+	ref, err = (&PlayerArgumentType{}).Parse(&StringReader{String: "069a79f444e94726a5befca90e38aaf5"})
+	require.NoError(t, err)
+	require.Equal(t, id, ref.(PlayerRef).UUID.String())
+}
+
+func TestPlayerType_Parse_Invalid(t *testing.T) {
+	for _, input := range []string{"ab", "this_name_is_too_long", "a.b"} {
+		_, err := (&PlayerArgumentType{}).Parse(&StringReader{String: input})
+		require.ErrorIs(t, err, ErrArgumentInvalidPlayer, input)
+		var rErr *ReaderError
+		require.True(t, errors.As(err, &rErr))
+		require.Equal(t, 0, rErr.Reader.Cursor)
+	}
+}
+
+func TestCommandContext_Player(t *testing.T) {
+	type player struct{ name string }
+	var resolveCtx context.Context
+	arg := &PlayerArgumentType{
+		Resolver: PlayerResolverFunc(func(ctx context.Context, ref PlayerRef) (interface{}, error) {
+			resolveCtx = ctx
+			return &player{name: ref.Name}, nil
+		}),
+		Online: func(context.Context) []string { return []string{"Alice", "Bob", "alex"} },
+	}
+
+	var d Dispatcher
+	var resolved interface{}
+	d.Register(Literal("kick").Then(Argument("player", arg).
+		Executes(CommandFunc(func(c *CommandContext) error {
+			var err error
+			resolved, err = c.Player("player")
+			return err
+		}))))
+
+	require.NoError(t, d.Do(context.TODO(), "kick Alice"))
+	require.Equal(t, &player{name: "Alice"}, resolved)
+	require.NotNil(t, resolveCtx)
+
+	testSuggestions(t, &d, "kick al", 7, StringRange{Start: 5, End: 7}, "Alice", "alex")
+}
+
+func TestPlayerRef_Resolve_NoResolver(t *testing.T) {
+	_, err := PlayerRef{Name: "Notch"}.Resolve(context.TODO())
+	require.ErrorIs(t, err, ErrNoPlayerResolver)
+}
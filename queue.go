@@ -0,0 +1,135 @@
+package brigodier
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrQueueFull is returned by CommandQueue.Submit if a backpressure limit is reached.
+var ErrQueueFull = errors.New("command queue full")
+
+// CommandQueue executes parsed commands in the background, one at a time per source key
+// in the order they were submitted, while commands of different sources run in parallel.
+// It is for servers whose command handlers must not interleave for the same player.
+//
+// The zero value is not usable, use NewCommandQueue.
+type CommandQueue struct {
+	Dispatcher *Dispatcher
+	// Key optionally extracts the source key from the context, e.g. the
+	// ID of the executing player. If nil, all commands share one key and
+	// are executed sequentially. Keys must be comparable.
+	Key func(ctx context.Context) interface{}
+	// MaxPendingPerKey limits the number of commands waiting for or in
+	// execution per key. Zero or negative means no limit.
+	MaxPendingPerKey int
+	// MaxPending limits the number of commands waiting for or in
+	// execution for all keys. Zero or negative means no limit.
+	MaxPending int
+
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	sources map[interface{}]*sourceQueue
+	stats   QueueStats
+}
+
+// QueueStats are metrics of a CommandQueue returned by CommandQueue.Stats.
+type QueueStats struct {
+	Pending  int    // The number of submitted commands not yet executing.
+	Running  int    // The number of commands executing.
+	Sources  int    // The number of keys with pending or running commands.
+	Executed uint64 // The total number of executed commands.
+	Failed   uint64 // The total number of executed commands that returned an error.
+	Rejected uint64 // The total number of commands rejected with ErrQueueFull.
+}
+
+type sourceQueue struct {
+	jobs []queuedCommand
+}
+
+type queuedCommand struct {
+	parse  *ParseResults
+	result chan error
+}
+
+// NewCommandQueue returns a new CommandQueue executing commands with the Dispatcher.
+func NewCommandQueue(d *Dispatcher, key func(ctx context.Context) interface{}) *CommandQueue {
+	return &CommandQueue{Dispatcher: d, Key: key}
+}
+
+// Submit queues the pre-parsed command for execution after all commands
+// previously submitted for the same key and returns a channel receiving
+// the error returned by Dispatcher.Execute. The channel is buffered,
+// so it need not be received from.
+//
+// ErrQueueFull is returned without queueing the command if
+// MaxPendingPerKey or MaxPending would be exceeded.
+func (q *CommandQueue) Submit(parse *ParseResults) (<-chan error, error) {
+	var key interface{}
+	if q.Key != nil {
+		key = q.Key(parse.Context)
+	}
+	job := queuedCommand{parse: parse, result: make(chan error, 1)}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.sources == nil {
+		q.sources = map[interface{}]*sourceQueue{}
+	}
+	s, ok := q.sources[key]
+	if (q.MaxPending > 0 && q.stats.Pending+q.stats.Running >= q.MaxPending) ||
+		(ok && q.MaxPendingPerKey > 0 && len(s.jobs) >= q.MaxPendingPerKey) {
+		q.stats.Rejected++
+		return nil, ErrQueueFull
+	}
+	q.stats.Pending++
+	if ok {
+		s.jobs = append(s.jobs, job)
+		return job.result, nil
+	}
+	s = &sourceQueue{jobs: []queuedCommand{job}}
+	q.sources[key] = s
+	q.stats.Sources++
+	q.wg.Add(1)
+	go q.drain(key, s)
+	return job.result, nil
+}
+
+// drain executes the commands of the key until its queue is empty.
+// The running command stays at the head of the queue.
+func (q *CommandQueue) drain(key interface{}, s *sourceQueue) {
+	defer q.wg.Done()
+	q.mu.Lock()
+	for len(s.jobs) != 0 {
+		job := s.jobs[0]
+		q.stats.Pending--
+		q.stats.Running++
+		q.mu.Unlock()
+
+		err := q.Dispatcher.Execute(job.parse)
+
+		q.mu.Lock()
+		q.stats.Running--
+		q.stats.Executed++
+		if err != nil {
+			q.stats.Failed++
+		}
+		s.jobs[0] = queuedCommand{}
+		s.jobs = s.jobs[1:]
+		// the result is buffered, so sending it holding the lock does not block
+		job.result <- err
+	}
+	delete(q.sources, key)
+	q.stats.Sources--
+	q.mu.Unlock()
+}
+
+// Wait blocks until all submitted commands have been executed.
+func (q *CommandQueue) Wait() { q.wg.Wait() }
+
+// Stats returns the current metrics of the queue.
+func (q *CommandQueue) Stats() QueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.stats
+}
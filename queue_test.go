@@ -0,0 +1,73 @@
+package brigodier
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+)
+
+func TestCommandQueue(t *testing.T) {
+	type playerKey struct{}
+	var (
+		d       Dispatcher
+		mu      sync.Mutex
+		order   []string
+		release = make(chan struct{})
+		started = make(chan struct{})
+	)
+	d.Register(Literal("say").Then(Argument("msg", String).Executes(CommandFunc(func(c *CommandContext) error {
+		msg := c.String("msg")
+		if msg == "block" {
+			close(started)
+			<-release
+		}
+		mu.Lock()
+		order = append(order, c.Value(playerKey{}).(string)+":"+msg)
+		mu.Unlock()
+		return nil
+	}))))
+	q := NewCommandQueue(&d, func(ctx context.Context) interface{} { return ctx.Value(playerKey{}) })
+	q.MaxPendingPerKey = 2
+
+	alice := context.WithValue(context.TODO(), playerKey{}, "alice")
+	bob := context.WithValue(context.TODO(), playerKey{}, "bob")
+
+	_, err := q.Submit(d.Parse(alice, "say block"))
+	require.NoError(t, err)
+	<-started
+	second, err := q.Submit(d.Parse(alice, "say second"))
+	require.NoError(t, err)
+	_, err = q.Submit(d.Parse(alice, "say third"))
+	require.ErrorIs(t, err, ErrQueueFull)
+
+	// other sources are not blocked
+	require.NoError(t, <-mustSubmit(t, q, d.Parse(bob, "say hi")))
+	stats := q.Stats()
+	require.Equal(t, QueueStats{Pending: 1, Running: 1, Sources: 1, Executed: 1, Rejected: 1}, stats)
+
+	close(release)
+	require.NoError(t, <-second)
+	require.Error(t, <-mustSubmit(t, q, d.Parse(bob, "unknown")))
+	q.Wait()
+	require.Equal(t, []string{"bob:hi", "alice:block", "alice:second"}, order)
+	require.Equal(t, QueueStats{Executed: 4, Failed: 1, Rejected: 1}, q.Stats())
+}
+
+func TestCommandQueue_MaxPending(t *testing.T) {
+	var d Dispatcher
+	release := make(chan struct{})
+	d.Register(Literal("wait").Executes(CommandFunc(func(c *CommandContext) error { <-release; return nil })))
+	q := &CommandQueue{Dispatcher: &d, MaxPending: 1}
+	mustSubmit(t, q, d.Parse(context.TODO(), "wait"))
+	_, err := q.Submit(d.Parse(context.TODO(), "wait"))
+	require.ErrorIs(t, err, ErrQueueFull)
+	close(release)
+	q.Wait()
+}
+
+func mustSubmit(t *testing.T, q *CommandQueue, parse *ParseResults) <-chan error {
+	result, err := q.Submit(parse)
+	require.NoError(t, err)
+	return result
+}
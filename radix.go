@@ -0,0 +1,150 @@
+package brigodier
+
+import (
+	"sort"
+	"strings"
+)
+
+// literalIndex is a radix tree of literal nodes keyed by their lower-cased literal.
+//
+// It lets suggestion lookups for partial input find all matching literals
+// without scanning every literal child of a node.
+type literalIndex struct {
+	root radixNode
+	seq  int // registration counter
+}
+
+type indexedLiteral struct {
+	*LiteralCommandNode
+	seq int
+}
+
+type radixNode struct {
+	prefix   string
+	children []*radixNode // sorted by the first byte of prefix
+	literals []indexedLiteral
+}
+
+func literalKey(n *LiteralCommandNode) string {
+	if n.cachedLiteralLowerCase == "" {
+		n.cachedLiteralLowerCase = strings.ToLower(n.Literal)
+	}
+	return n.cachedLiteralLowerCase
+}
+
+// insert adds a literal node to the index.
+func (x *literalIndex) insert(lit *LiteralCommandNode) {
+	key := literalKey(lit)
+	x.seq++
+	entry := indexedLiteral{LiteralCommandNode: lit, seq: x.seq}
+	n := &x.root
+	for {
+		if key == "" {
+			n.literals = append(n.literals, entry)
+			return
+		}
+		i, child := n.child(key[0])
+		if child == nil {
+			n.children = append(n.children, nil)
+			copy(n.children[i+1:], n.children[i:])
+			n.children[i] = &radixNode{prefix: key, literals: []indexedLiteral{entry}}
+			return
+		}
+		common := commonPrefixLen(key, child.prefix)
+		if common < len(child.prefix) {
+			// Split the child at the common prefix.
+			split := &radixNode{prefix: child.prefix[:common], children: []*radixNode{child}}
+			child.prefix = child.prefix[common:]
+			n.children[i] = split
+			child = split
+		}
+		key = key[common:]
+		n = child
+	}
+}
+
+// remove removes a literal node from the index.
+func (x *literalIndex) remove(lit *LiteralCommandNode) {
+	x.root.remove(literalKey(lit), lit)
+}
+
+func (n *radixNode) remove(key string, lit *LiteralCommandNode) {
+	if key == "" {
+		for i, l := range n.literals {
+			if l.LiteralCommandNode == lit {
+				n.literals = append(n.literals[:i], n.literals[i+1:]...)
+				break
+			}
+		}
+		return
+	}
+	i, child := n.child(key[0])
+	if child == nil || !strings.HasPrefix(key, child.prefix) {
+		return
+	}
+	child.remove(key[len(child.prefix):], lit)
+	switch {
+	case len(child.literals) == 0 && len(child.children) == 0:
+		n.children = append(n.children[:i], n.children[i+1:]...)
+	case len(child.literals) == 0 && len(child.children) == 1:
+		// Merge the child with its only child.
+		grandchild := child.children[0]
+		grandchild.prefix = child.prefix + grandchild.prefix
+		n.children[i] = grandchild
+	}
+}
+
+// withPrefix returns all literal nodes whose lower-cased literal
+// starts with the lower-cased prefix in registration order.
+func (x *literalIndex) withPrefix(prefix string) []*LiteralCommandNode {
+	n := &x.root
+	for prefix != "" {
+		_, child := n.child(prefix[0])
+		if child == nil {
+			return nil
+		}
+		common := commonPrefixLen(prefix, child.prefix)
+		if common == len(prefix) {
+			n = child
+			break
+		}
+		if common < len(child.prefix) {
+			return nil
+		}
+		prefix = prefix[common:]
+		n = child
+	}
+	entries := n.collect(nil)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].seq < entries[j].seq })
+	literals := make([]*LiteralCommandNode, len(entries))
+	for i, e := range entries {
+		literals[i] = e.LiteralCommandNode
+	}
+	return literals
+}
+
+func (n *radixNode) collect(entries []indexedLiteral) []indexedLiteral {
+	entries = append(entries, n.literals...)
+	for _, child := range n.children {
+		entries = child.collect(entries)
+	}
+	return entries
+}
+
+// child returns the child starting with byte b or
+// the index the child would be inserted at.
+func (n *radixNode) child(b byte) (int, *radixNode) {
+	i := sort.Search(len(n.children), func(i int) bool { return n.children[i].prefix[0] >= b })
+	if i < len(n.children) && n.children[i].prefix[0] == b {
+		return i, n.children[i]
+	}
+	return i, nil
+}
+
+func commonPrefixLen(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
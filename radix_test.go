@@ -0,0 +1,48 @@
+package brigodier
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func literalNames(literals []*LiteralCommandNode) []string {
+	names := make([]string, 0, len(literals))
+	for _, l := range literals {
+		names = append(names, l.Literal)
+	}
+	return names
+}
+
+func TestNode_LiteralsWithPrefix(t *testing.T) {
+	var d Dispatcher
+	for _, name := range []string{"team", "tell", "Teleport", "te", "time", "kill", "t"} {
+		d.Register(Literal(name))
+	}
+	d.Root.AddChild(Argument("arg", Int).Build())
+
+	require.Equal(t, []string{"team", "tell", "Teleport", "te"}, literalNames(d.Root.LiteralsWithPrefix("te")))
+	require.Equal(t, []string{"tell", "Teleport"}, literalNames(d.Root.LiteralsWithPrefix("TEL")))
+	require.Equal(t, []string{"time"}, literalNames(d.Root.LiteralsWithPrefix("time")))
+	require.Empty(t, d.Root.LiteralsWithPrefix("timer"))
+	require.Empty(t, d.Root.LiteralsWithPrefix("x"))
+	require.Len(t, d.Root.LiteralsWithPrefix(""), 7)
+
+	d.Root.RemoveChild("tell", "te", "t")
+	require.Equal(t, []string{"team", "Teleport", "time"}, literalNames(d.Root.LiteralsWithPrefix("t")))
+	require.Equal(t, []string{"Teleport"}, literalNames(d.Root.LiteralsWithPrefix("tel")))
+}
+
+func BenchmarkDispatcher_CompletionSuggestions_WideRoot(b *testing.B) {
+	var d Dispatcher
+	for i := 0; i < 10000; i++ {
+		d.Register(Literal(fmt.Sprintf("command%d", i)))
+	}
+	parse := d.Parse(context.TODO(), "command123")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = d.CompletionSuggestions(parse)
+	}
+}
@@ -0,0 +1,130 @@
+package brigodier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter limits how often commands can be executed.
+// It is set on a node using the RateLimit builder method
+// and is consulted by Dispatcher.Execute before running a command.
+type RateLimiter interface {
+	// Allow reports whether a command may be executed now with the given context
+	// and if not, how long to wait before it may be executed again.
+	Allow(ctx context.Context) (ok bool, retryAfter time.Duration)
+}
+
+// ErrRateLimited indicates that the execution of a command was denied by a RateLimiter.
+// The returned error is a *RateLimitError wrapping ErrRateLimited.
+var ErrRateLimited = errors.New("rate limited")
+
+// RateLimitError is returned by Dispatcher.Execute if a RateLimiter denied executing a command.
+type RateLimitError struct {
+	Node       CommandNode   // The node whose RateLimiter denied the execution.
+	RetryAfter time.Duration // The duration to wait before retrying.
+}
+
+// Unwrap implements errors.Unwrap and returns ErrRateLimited.
+func (e *RateLimitError) Unwrap() error { return ErrRateLimited }
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s: retry after %s", ErrRateLimited, e.RetryAfter)
+}
+
+// checkRateLimits consults the RateLimiter of all nodes
+// executed with the context, see chainNodes.
+func checkRateLimits(c *CommandContext, nodes []*ParsedCommandNode) error {
+	for _, parsed := range nodes {
+		limiter := parsed.Node.RateLimiter()
+		if limiter == nil {
+			continue
+		}
+		if ok, retryAfter := limiter.Allow(c); !ok {
+			return &RateLimitError{Node: parsed.Node, RetryAfter: retryAfter}
+		}
+	}
+	return nil
+}
+
+// TokenBucket is a RateLimiter that keeps a token bucket per key extracted from the context.
+//
+// Each bucket holds up to Burst tokens and is refilled by one token every Every duration.
+// Executing a command takes one token. Buckets that are refilled completely are
+// removed, so only the keys that executed a command recently take up memory.
+type TokenBucket struct {
+	Every time.Duration
+	Burst int
+	// Key optionally extracts the bucket key from the context, e.g. the
+	// ID of the executing player. If nil, all executions share one bucket.
+	// Keys must be comparable.
+	Key func(ctx context.Context) interface{}
+
+	now     func() time.Time
+	mu      sync.Mutex
+	buckets map[interface{}]*bucket
+	swept   time.Time // When full buckets were last removed.
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+var _ RateLimiter = (*TokenBucket)(nil)
+
+// NewTokenBucket returns a new TokenBucket.
+func NewTokenBucket(every time.Duration, burst int, key func(ctx context.Context) interface{}) *TokenBucket {
+	return &TokenBucket{Every: every, Burst: burst, Key: key}
+}
+
+// Allow implements RateLimiter.
+func (t *TokenBucket) Allow(ctx context.Context) (bool, time.Duration) {
+	var key interface{}
+	if t.Key != nil {
+		key = t.Key(ctx)
+	}
+	now := time.Now()
+	if t.now != nil {
+		now = t.now()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.buckets == nil {
+		t.buckets = map[interface{}]*bucket{}
+	}
+	t.sweep(now)
+	b, ok := t.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(t.Burst), last: now}
+		t.buckets[key] = b
+	}
+	if t.Every > 0 {
+		b.tokens = math.Min(float64(t.Burst), b.tokens+float64(now.Sub(b.last))/float64(t.Every))
+	}
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) * float64(t.Every))
+}
+
+// sweep removes the buckets that are refilled completely, since they equal
+// new buckets. It runs at most once per refill period to keep Allow cheap.
+// Buckets are never refilled and thus kept if Every is not positive.
+func (t *TokenBucket) sweep(now time.Time) {
+	refill := t.Every * time.Duration(t.Burst)
+	if t.Every <= 0 || now.Sub(t.swept) < refill {
+		return
+	}
+	t.swept = now
+	for key, b := range t.buckets {
+		if b.tokens+float64(now.Sub(b.last))/float64(t.Every) >= float64(t.Burst) {
+			delete(t.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package brigodier
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket_Allow(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := NewTokenBucket(time.Second, 2, nil)
+	b.now = func() time.Time { return now }
+
+	ok, _ := b.Allow(context.TODO())
+	require.True(t, ok)
+	ok, _ = b.Allow(context.TODO())
+	require.True(t, ok)
+	ok, retryAfter := b.Allow(context.TODO())
+	require.False(t, ok)
+	require.Equal(t, time.Second, retryAfter)
+
+	now = now.Add(500 * time.Millisecond)
+	ok, retryAfter = b.Allow(context.TODO())
+	require.False(t, ok)
+	require.Equal(t, 500*time.Millisecond, retryAfter)
+
+	now = now.Add(500 * time.Millisecond)
+	ok, _ = b.Allow(context.TODO())
+	require.True(t, ok)
+}
+
+func TestTokenBucket_Evict(t *testing.T) {
+	type playerKey struct{}
+	now := time.Unix(0, 0)
+	b := NewTokenBucket(time.Second, 2, func(ctx context.Context) interface{} { return ctx.Value(playerKey{}) })
+	b.now = func() time.Time { return now }
+	allow := func(key int) bool {
+		ok, _ := b.Allow(context.WithValue(context.TODO(), playerKey{}, key))
+		return ok
+	}
+
+	for i := 0; i < 100; i++ {
+		require.True(t, allow(i))
+	}
+	now = now.Add(time.Second)
+	require.True(t, allow(0))
+	require.True(t, allow(0))
+	require.False(t, allow(0))
+	require.Len(t, b.buckets, 100)
+
+	// all buckets but the one of key 0 are full again
+	now = now.Add(time.Second)
+	require.True(t, allow(1))
+	require.Len(t, b.buckets, 2)
+	require.True(t, allow(0))
+	require.False(t, allow(0))
+}
+
+func TestDispatcher_Execute_RateLimit(t *testing.T) {
+	type playerKey struct{}
+	var (
+		d     Dispatcher
+		times int
+	)
+	limiter := NewTokenBucket(time.Hour, 1, func(ctx context.Context) interface{} {
+		return ctx.Value(playerKey{})
+	})
+	cmd := CommandFunc(func(c *CommandContext) error { times++; return nil })
+	d.Register(Literal("heal").RateLimit(limiter).Then(Argument("amount", Int).Executes(cmd)))
+
+	alice := context.WithValue(context.TODO(), playerKey{}, "alice")
+	bob := context.WithValue(context.TODO(), playerKey{}, "bob")
+	require.NoError(t, d.Do(alice, "heal 1"))
+	require.NoError(t, d.Do(bob, "heal 1"))
+
+	err := d.Do(alice, "heal 2")
+	require.ErrorIs(t, err, ErrRateLimited)
+	var rlErr *RateLimitError
+	require.True(t, errors.As(err, &rlErr))
+	require.Equal(t, d.FindNode("heal"), rlErr.Node)
+	require.Greater(t, rlErr.RetryAfter, time.Duration(0))
+	require.Equal(t, 2, times)
+}
+
+func TestDispatcher_Execute_RateLimitRedirect(t *testing.T) {
+	var (
+		d     Dispatcher
+		times int
+	)
+	d.Register(Literal("say").Executes(CommandFunc(func(c *CommandContext) error { times++; return nil })))
+	execute := d.Register(Literal("execute").RateLimit(NewTokenBucket(time.Hour, 1, nil)))
+	execute.AddChild(Literal("run").Redirect(&d.Root).Build())
+
+	require.NoError(t, d.Do(context.TODO(), "execute run say"))
+	err := d.Do(context.TODO(), "execute run say")
+	require.ErrorIs(t, err, ErrRateLimited)
+	var rlErr *RateLimitError
+	require.True(t, errors.As(err, &rlErr))
+	require.Equal(t, execute, rlErr.Node)
+	require.Equal(t, 1, times)
+}
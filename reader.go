@@ -11,6 +11,9 @@ import (
 type StringReader struct {
 	Cursor int
 	String string
+
+	parent *StringReader // The reader forked from, see Fork.
+	start  int           // The Cursor of parent when forked.
 }
 
 // ReaderError indicates a StringReader error.
@@ -148,15 +151,93 @@ func (r *StringReader) ReadStringUntil(terminator rune) (string, error) {
 	}}
 }
 
-// ReadUnquotedString reads an unquoted string.
-func (r *StringReader) ReadUnquotedString() string {
+// ReadStringUntilAny reads a string until one of the delimiter runes or the end of input.
+// The delimiter itself is not consumed, so Peek returns the delimiter that was found, if any.
+// Delimiters and SyntaxEscape can be escaped by prefixing them with SyntaxEscape.
+func (r *StringReader) ReadStringUntilAny(delims ...rune) (string, error) {
+	var (
+		result  strings.Builder
+		escaped = false
+	)
+	for r.CanRead() {
+		c := r.Peek()
+		if escaped {
+			if c == SyntaxEscape || containsRune(delims, c) {
+				result.WriteRune(c)
+				escaped = false
+			} else {
+				return "", &CommandSyntaxError{Err: &ReaderError{
+					Err: &ReaderInvalidValueError{
+						Value: string(c),
+						Err:   ErrReaderInvalidEscape,
+					},
+					Reader: r,
+				}}
+			}
+		} else if c == SyntaxEscape {
+			escaped = true
+		} else if containsRune(delims, c) {
+			return result.String(), nil
+		} else {
+			result.WriteRune(c)
+		}
+		r.Skip()
+	}
+	if escaped {
+		return "", &CommandSyntaxError{Err: &ReaderError{
+			Err: &ReaderInvalidValueError{
+				Value: string(SyntaxEscape),
+				Err:   ErrReaderInvalidEscape,
+			},
+			Reader: r,
+		}}
+	}
+	return result.String(), nil
+}
+
+func containsRune(runes []rune, c rune) bool {
+	for _, r := range runes {
+		if r == c {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadWhile reads runes as long as pred returns true for the next rune.
+func (r *StringReader) ReadWhile(pred func(rune) bool) string {
 	start := r.Cursor
-	for r.CanRead() && IsAllowedInUnquotedString(r.Peek()) {
+	for r.CanRead() && pred(r.Peek()) {
 		r.Skip()
 	}
 	return r.String[start:r.Cursor]
 }
 
+// ReaderExpectedRuneError occurs when the reader expected a specific rune.
+type ReaderExpectedRuneError struct {
+	Rune rune // The expected rune.
+}
+
+func (e *ReaderExpectedRuneError) Error() string { return fmt.Sprintf("reader expected %q", e.Rune) }
+
+// Expect skips the next rune if it is c or returns
+// a ReaderExpectedRuneError without moving the Cursor.
+func (r *StringReader) Expect(c rune) error {
+	if !r.CanRead() || r.Peek() != c {
+		return &CommandSyntaxError{Err: &ReaderError{
+			Err:    &ReaderExpectedRuneError{Rune: c},
+			Reader: r,
+		}}
+	}
+	r.Skip()
+	return nil
+}
+
+// ReadUnquotedString reads an unquoted string.
+func (r *StringReader) ReadUnquotedString() string {
+	return r.ReadWhile(IsAllowedInUnquotedString)
+}
+
 // ReadQuotedString reads a quoted string.
 func (r *StringReader) ReadQuotedString() (string, error) {
 	if !r.CanRead() {
@@ -185,9 +266,14 @@ var (
 	ErrReaderInvalidInt = errors.New("read invalid int")
 	// ErrReaderInvalidFloat occurs when the reader read an invalid int float.
 	ErrReaderInvalidFloat = errors.New("read invalid float")
+	// ErrReaderNonFiniteFloat occurs when the reader read an infinite or NaN float
+	// where only finite floats are allowed.
+	ErrReaderNonFiniteFloat = errors.New("read non-finite float")
 )
 
 // ReadInt tries to read an int32.
+//
+// Integers may be written as decimal, 0x prefixed hexadecimal or 0b prefixed binary literals.
 func (r *StringReader) ReadInt() (int, error) {
 	i, err := r.ReadInt32()
 	return int(i), err
@@ -202,19 +288,109 @@ func (r *StringReader) ReadInt32() (int32, error) {
 // ReadInt64 tries to read an int64.
 func (r *StringReader) ReadInt64() (int64, error) { return r.readInt(64) }
 
-func (r *StringReader) readInt(bitSize int) (int64, error) {
+// ReadInt8 tries to read an int8.
+func (r *StringReader) ReadInt8() (int8, error) {
+	i, err := r.readInt(8)
+	return int8(i), err
+}
+
+// ReadUint is the same as ReadUint32.
+func (r *StringReader) ReadUint() (uint, error) {
+	i, err := r.ReadUint32()
+	return uint(i), err
+}
+
+// ReadUint32 tries to read an uint32.
+func (r *StringReader) ReadUint32() (uint32, error) {
+	i, err := r.readUint(32)
+	return uint32(i), err
+}
+
+// ReadUint64 tries to read an uint64.
+func (r *StringReader) ReadUint64() (uint64, error) { return r.readUint(64) }
+
+// readIntLiteral reads a decimal, 0x prefixed hexadecimal or 0b prefixed binary integer literal.
+func (r *StringReader) readIntLiteral() string {
 	start := r.Cursor
-	for r.CanRead() && IsAllowedNumber(r.Peek()) {
+	if r.CanRead() && r.Peek() == '-' {
 		r.Skip()
 	}
-	number := r.String[start:r.Cursor]
+	if r.CanReadLen(2) && r.Peek() == '0' {
+		var isDigit func(rune) bool
+		switch r.String[r.Cursor+1] {
+		case 'x', 'X':
+			isDigit = isHexDigit
+		case 'b', 'B':
+			isDigit = isBinaryDigit
+		}
+		if isDigit != nil {
+			r.Cursor += 2
+			r.ReadWhile(isDigit)
+			return r.String[start:r.Cursor]
+		}
+	}
+	r.Cursor = start
+	return r.ReadWhile(IsAllowedNumber)
+}
+
+func isHexDigit(c rune) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+func isBinaryDigit(c rune) bool { return c == '0' || c == '1' }
+
+// splitIntLiteral returns the digits of the integer literal read by readIntLiteral
+// with the 0x or 0b prefix removed and their base. Leading zeros are decimal.
+func splitIntLiteral(number string) (digits string, base int) {
+	sign, digits := "", number
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+	if len(digits) >= 2 && digits[0] == '0' {
+		switch digits[1] {
+		case 'x', 'X':
+			return sign + digits[2:], 16
+		case 'b', 'B':
+			return sign + digits[2:], 2
+		}
+	}
+	return number, 10
+}
+
+func (r *StringReader) readInt(bitSize int) (int64, error) {
+	start := r.Cursor
+	number := r.readIntLiteral()
+	if number == "" {
+		return 0, &CommandSyntaxError{Err: &ReaderError{
+			Err:    ErrReaderExpectedInt,
+			Reader: r,
+		}}
+	}
+	digits, base := splitIntLiteral(number)
+	i, err := strconv.ParseInt(digits, base, bitSize)
+	if err != nil {
+		r.Cursor = start
+		return 0, &CommandSyntaxError{Err: &ReaderError{
+			Err: &ReaderInvalidValueError{
+				Value: number,
+				Err:   fmt.Errorf("%w (%q): %v", ErrReaderInvalidInt, number, err),
+			},
+			Reader: r,
+		}}
+	}
+	return i, nil
+}
+
+func (r *StringReader) readUint(bitSize int) (uint64, error) {
+	start := r.Cursor
+	number := r.readIntLiteral()
 	if number == "" {
 		return 0, &CommandSyntaxError{Err: &ReaderError{
 			Err:    ErrReaderExpectedInt,
 			Reader: r,
 		}}
 	}
-	i, err := strconv.ParseInt(number, 0, bitSize)
+	digits, base := splitIntLiteral(number)
+	i, err := strconv.ParseUint(digits, base, bitSize)
 	if err != nil {
 		r.Cursor = start
 		return 0, &CommandSyntaxError{Err: &ReaderError{
@@ -229,29 +405,44 @@ func (r *StringReader) readInt(bitSize int) (int64, error) {
 }
 
 // ReadFloat32 tries to read a float32.
+//
+// Floats may use exponent notation like 1e6 or 2.5E-3.
+// Infinite and NaN values are rejected with ErrReaderNonFiniteFloat.
 func (r *StringReader) ReadFloat32() (float32, error) {
-	f, err := r.readFloat(32)
+	f, err := r.readFloat(32, false)
 	return float32(f), err
 }
 
 // ReadFloat64 tries to read a float64.
+// See ReadFloat32 for the accepted notation.
 func (r *StringReader) ReadFloat64() (float64, error) {
-	return r.readFloat(64)
+	return r.readFloat(64, false)
 }
 
-func (r *StringReader) readFloat(bitSize int) (float64, error) {
+func (r *StringReader) readFloat(bitSize int, allowNonFinite bool) (float64, error) {
 	start := r.Cursor
-	for r.CanRead() && IsAllowedNumber(r.Peek()) {
-		r.Skip()
-	}
-	number := r.String[start:r.Cursor]
+	number, nonFinite := r.readFloatLiteral()
 	if number == "" {
 		return 0, &CommandSyntaxError{Err: &ReaderError{
 			Err:    ErrReaderExpectedFloat,
 			Reader: r,
 		}}
 	}
-	f, err := strconv.ParseFloat(number, bitSize)
+	if nonFinite && !allowNonFinite {
+		r.Cursor = start
+		return 0, &CommandSyntaxError{Err: &ReaderError{
+			Err: &ReaderInvalidValueError{
+				Value: number,
+				Err:   fmt.Errorf("%w (%q)", ErrReaderNonFiniteFloat, number),
+			},
+			Reader: r,
+		}}
+	}
+	value := number
+	if nonFinite && strings.EqualFold(strings.TrimLeft(number, "+-"), "nan") {
+		value = "NaN" // NaN has no sign but strconv rejects a signed one
+	}
+	f, err := strconv.ParseFloat(value, bitSize)
 	if err != nil {
 		r.Cursor = start
 		return 0, &CommandSyntaxError{Err: &ReaderError{
@@ -265,6 +456,92 @@ func (r *StringReader) readFloat(bitSize int) (float64, error) {
 	return f, nil
 }
 
+// nonFiniteFloats are the case-insensitive literals of infinite and NaN floats.
+// Longer literals must come first.
+var nonFiniteFloats = []string{"infinity", "inf", "nan"}
+
+// readFloatLiteral reads a float literal with optional exponent
+// or one of nonFiniteFloats and reports whether the latter was read.
+func (r *StringReader) readFloatLiteral() (number string, nonFinite bool) {
+	start := r.Cursor
+	if r.CanRead() && (r.Peek() == '-' || r.Peek() == '+') {
+		r.Skip()
+	}
+	for _, literal := range nonFiniteFloats {
+		end := r.Cursor + len(literal)
+		if r.CanReadLen(len(literal)) && strings.EqualFold(r.String[r.Cursor:end], literal) &&
+			(end == len(r.String) || !IsAllowedInUnquotedString(rune(r.String[end]))) {
+			r.Cursor = end
+			return r.String[start:r.Cursor], true
+		}
+	}
+	mantissa := r.Cursor // after the sign, like for non-finite values
+	r.ReadWhile(IsAllowedNumber)
+	if r.Cursor != mantissa && r.CanRead() && (r.Peek() == 'e' || r.Peek() == 'E') {
+		mantissaEnd := r.Cursor
+		r.Skip()
+		if r.CanRead() && (r.Peek() == '-' || r.Peek() == '+') {
+			r.Skip()
+		}
+		if r.ReadWhile(isDigit) == "" {
+			r.Cursor = mantissaEnd // not an exponent
+		}
+	}
+	return r.String[start:r.Cursor], false
+}
+
+func isDigit(c rune) bool { return c >= '0' && c <= '9' }
+
+// SubReader returns a new StringReader bounded to the limit range of this reader's String.
+//
+// The returned reader starts at limit.Start and cannot read past limit.End,
+// so nested parsers, e.g. of list or compound argument types, cannot overrun into
+// the next argument. Cursor positions, and therefore error positions, of the
+// returned reader stay relative to the full input. Set Cursor to the Cursor of
+// the returned reader to continue after the nested parser's input.
+func (r *StringReader) SubReader(limit StringRange) *StringReader {
+	end := min(max(limit.End, 0), len(r.String))
+	return &StringReader{
+		String: r.String[:end],
+		Cursor: min(max(limit.Start, 0), end),
+	}
+}
+
+// Fork returns a new reader at the Cursor of r to try parsing an alternative
+// without moving r. Commit the fork to advance r to the Cursor of the fork
+// or Rollback to leave r at the position it was forked at:
+//
+//	fork := rd.Fork()
+//	if v, err := a.Parse(fork); err == nil {
+//		fork.Commit()
+//		return v, nil
+//	}
+//	fork.Rollback()
+//	return b.Parse(rd)
+//
+// Errors returned while parsing the fork keep referencing the fork,
+// so their cursors stay at the error position when r is parsed further.
+func (r *StringReader) Fork() *StringReader {
+	return &StringReader{String: r.String, Cursor: r.Cursor, parent: r, start: r.Cursor}
+}
+
+// Commit sets the Cursor of the reader r was forked from to the Cursor of r.
+// It does nothing if r is not a fork, see Fork.
+func (r *StringReader) Commit() {
+	if r.parent != nil {
+		r.parent.Cursor = r.Cursor
+	}
+}
+
+// Rollback resets the Cursor of the reader r was forked from to the
+// position r was forked at, undoing a previous Commit of r.
+// The Cursor of r is not changed. It does nothing if r is not a fork, see Fork.
+func (r *StringReader) Rollback() {
+	if r.parent != nil {
+		r.parent.Cursor = r.start
+	}
+}
+
 // Remaining returns the remaining string beginning at the current Cursor
 func (r *StringReader) Remaining() string { return r.String[r.Cursor:] }
 
@@ -297,6 +574,26 @@ func IsAllowedInUnquotedString(c rune) bool {
 		c == '.' || c == '+'
 }
 
+// QuoteString returns s as it must be written to be read by StringReader.ReadString,
+// i.e. s itself if it is a non-empty unquoted string or otherwise s double-quoted
+// with SyntaxDoubleQuote and SyntaxEscape escaped.
+func QuoteString(s string) string {
+	if s != "" && strings.IndexFunc(s, func(c rune) bool { return !IsAllowedInUnquotedString(c) }) == -1 {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+	b.WriteRune(SyntaxDoubleQuote)
+	for _, c := range s {
+		if c == SyntaxDoubleQuote || c == SyntaxEscape {
+			b.WriteRune(SyntaxEscape)
+		}
+		b.WriteRune(c)
+	}
+	b.WriteRune(SyntaxDoubleQuote)
+	return b.String()
+}
+
 // StringRange stores a range indicating the start and end of a string
 type StringRange struct{ Start, End int }
 
@@ -3,6 +3,7 @@ package brigodier
 import (
 	"errors"
 	"github.com/stretchr/testify/require"
+	"math"
 	"testing"
 )
 
@@ -258,3 +259,254 @@ func TestStringReader_ReadBool_None(t *testing.T) {
 	require.True(t, errors.As(err, &rErr))
 	require.Equal(t, 0, rErr.Reader.Cursor)
 }
+
+func TestStringReader_ReadStringUntilAny(t *testing.T) {
+	r := StringReader{String: "key=value,next"}
+	s, err := r.ReadStringUntilAny('=', ',')
+	require.NoError(t, err)
+	require.Equal(t, "key", s)
+	require.Equal(t, "=value,next", r.Remaining())
+}
+func TestStringReader_ReadStringUntilAny_Escaped(t *testing.T) {
+	r := StringReader{String: `a\=b\\=c`}
+	s, err := r.ReadStringUntilAny('=')
+	require.NoError(t, err)
+	require.Equal(t, `a=b\`, s)
+	require.Equal(t, "=c", r.Remaining())
+}
+func TestStringReader_ReadStringUntilAny_End(t *testing.T) {
+	r := StringReader{String: "hello world"}
+	s, err := r.ReadStringUntilAny(',')
+	require.NoError(t, err)
+	require.Equal(t, "hello world", s)
+	require.False(t, r.CanRead())
+}
+func TestStringReader_ReadStringUntilAny_InvalidEscape(t *testing.T) {
+	r := StringReader{String: `a\b`}
+	_, err := r.ReadStringUntilAny(',')
+	require.ErrorIs(t, err, ErrReaderInvalidEscape)
+	var rErr *ReaderError
+	require.True(t, errors.As(err, &rErr))
+	require.Equal(t, 2, rErr.Reader.Cursor)
+}
+
+func TestStringReader_ReadWhile(t *testing.T) {
+	r := StringReader{String: "aaab"}
+	require.Equal(t, "aaa", r.ReadWhile(func(c rune) bool { return c == 'a' }))
+	require.Equal(t, "b", r.Remaining())
+}
+
+func TestStringReader_Expect(t *testing.T) {
+	r := StringReader{String: "abc"}
+	require.NoError(t, r.Expect('a'))
+	require.Equal(t, 1, r.Cursor)
+}
+func TestStringReader_Expect_Incorrect(t *testing.T) {
+	r := StringReader{String: "bcd"}
+	err := r.Expect('a')
+	var (
+		rErr *ReaderError
+		eErr *ReaderExpectedRuneError
+	)
+	require.True(t, errors.As(err, &rErr))
+	require.True(t, errors.As(err, &eErr))
+	require.Equal(t, 'a', eErr.Rune)
+	require.Equal(t, 0, rErr.Reader.Cursor)
+}
+func TestStringReader_Expect_None(t *testing.T) {
+	r := StringReader{}
+	var eErr *ReaderExpectedRuneError
+	require.True(t, errors.As(r.Expect('a'), &eErr))
+}
+
+func TestStringReader_ReadInt_Hex(t *testing.T) {
+	r := StringReader{String: "0xFF00ff foo"}
+	i, err := r.ReadInt()
+	require.NoError(t, err)
+	require.Equal(t, 0xFF00FF, i)
+	require.Equal(t, " foo", r.Remaining())
+}
+func TestStringReader_ReadInt_NegativeBinary(t *testing.T) {
+	r := StringReader{String: "-0b101"}
+	i, err := r.ReadInt()
+	require.NoError(t, err)
+	require.Equal(t, -5, i)
+	require.Empty(t, r.Remaining())
+}
+func TestStringReader_ReadInt_InvalidHex(t *testing.T) {
+	r := StringReader{String: "0x"}
+	_, err := r.ReadInt()
+	require.ErrorIs(t, err, ErrReaderInvalidInt)
+	var rErr *ReaderError
+	require.True(t, errors.As(err, &rErr))
+	require.Equal(t, 0, rErr.Reader.Cursor)
+}
+func TestStringReader_ReadInt_LeadingZeros(t *testing.T) {
+	for input, want := range map[string]int{"010": 10, "-010": -10, "09": 9, "007": 7} {
+		r := StringReader{String: input}
+		i, err := r.ReadInt()
+		require.NoError(t, err, input)
+		require.Equal(t, want, i, input)
+	}
+	r := StringReader{String: "010"}
+	u, err := r.ReadUint64()
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), u)
+}
+func TestStringReader_ReadInt8_Overflow(t *testing.T) {
+	r := StringReader{String: "128"}
+	_, err := r.ReadInt8()
+	require.ErrorIs(t, err, ErrReaderInvalidInt)
+}
+
+func TestStringReader_ReadUint32(t *testing.T) {
+	r := StringReader{String: "4294967295"}
+	i, err := r.ReadUint32()
+	require.NoError(t, err)
+	require.Equal(t, uint32(4294967295), i)
+	require.Empty(t, r.Remaining())
+}
+func TestStringReader_ReadUint64_Negative(t *testing.T) {
+	r := StringReader{String: "-1"}
+	_, err := r.ReadUint64()
+	require.ErrorIs(t, err, ErrReaderInvalidInt)
+	var rErr *ReaderError
+	require.True(t, errors.As(err, &rErr))
+	require.Equal(t, 0, rErr.Reader.Cursor)
+}
+
+func TestStringReader_ReadFloat_Exponent(t *testing.T) {
+	r := StringReader{String: "1e6 2.5E-3"}
+	f, err := r.ReadFloat64()
+	require.NoError(t, err)
+	require.Equal(t, 1e6, f)
+	r.Skip()
+	f, err = r.ReadFloat64()
+	require.NoError(t, err)
+	require.Equal(t, 2.5e-3, f)
+	require.Empty(t, r.Remaining())
+}
+func TestStringReader_ReadFloat_IncompleteExponent(t *testing.T) {
+	r := StringReader{String: "12em"}
+	f, err := r.ReadFloat64()
+	require.NoError(t, err)
+	require.Equal(t, float64(12), f)
+	require.Equal(t, "em", r.Remaining())
+}
+func TestStringReader_ReadFloat_NonFinite(t *testing.T) {
+	for _, input := range []string{"inf", "-Infinity", "NaN"} {
+		r := StringReader{String: input}
+		_, err := r.ReadFloat64()
+		require.ErrorIs(t, err, ErrReaderNonFiniteFloat)
+		var rErr *ReaderError
+		require.True(t, errors.As(err, &rErr))
+		require.Equal(t, 0, rErr.Reader.Cursor)
+	}
+}
+func TestStringReader_ReadFloat_Sign(t *testing.T) {
+	for _, tc := range []struct {
+		input     string
+		want      float64
+		nonFinite bool
+	}{
+		{input: "+5", want: 5},
+		{input: "-5", want: -5},
+		{input: "+1.5e3", want: 1500},
+		{input: "-inf", want: math.Inf(-1), nonFinite: true},
+		{input: "+inf", want: math.Inf(1), nonFinite: true},
+		{input: "+nan", want: math.NaN(), nonFinite: true},
+	} {
+		r := StringReader{String: tc.input + " x"}
+		f, err := r.readFloat(64, tc.nonFinite)
+		require.NoError(t, err, tc.input)
+		if math.IsNaN(tc.want) {
+			require.True(t, math.IsNaN(f), tc.input)
+		} else {
+			require.Equal(t, tc.want, f, tc.input)
+		}
+		require.Equal(t, " x", r.Remaining(), tc.input)
+	}
+
+	r := StringReader{String: "+"}
+	_, err := r.ReadFloat64()
+	require.ErrorIs(t, err, ErrReaderInvalidFloat)
+	require.Equal(t, 0, r.Cursor)
+}
+func TestStringReader_ReadFloat_NotNonFinite(t *testing.T) {
+	r := StringReader{String: "information"}
+	_, err := r.ReadFloat64()
+	require.ErrorIs(t, err, ErrReaderExpectedFloat)
+}
+
+func TestStringReader_SubReader(t *testing.T) {
+	r := StringReader{String: "1,2 3", Cursor: 0}
+	sub := r.SubReader(StringRange{Start: 2, End: 3})
+	i, err := sub.ReadInt()
+	require.NoError(t, err)
+	require.Equal(t, 2, i)
+	require.False(t, sub.CanRead())
+	require.Equal(t, 3, sub.Cursor)
+
+	sub = r.SubReader(StringRange{Start: 3, End: 3})
+	_, err = sub.ReadInt()
+	var rErr *ReaderError
+	require.True(t, errors.As(err, &rErr))
+	require.Equal(t, 3, rErr.Reader.Cursor)
+
+	sub = r.SubReader(StringRange{Start: -1, End: 100})
+	require.Equal(t, r.String, sub.Remaining())
+}
+
+func TestStringReader_Fork(t *testing.T) {
+	r := &StringReader{String: "12 abc", Cursor: 0}
+	fork := r.Fork()
+	_, err := fork.ReadInt()
+	require.NoError(t, err)
+	require.Equal(t, 0, r.Cursor)
+	fork.Commit()
+	require.Equal(t, 2, r.Cursor)
+	fork.Rollback()
+	require.Equal(t, 0, r.Cursor)
+	require.Equal(t, 2, fork.Cursor)
+
+	// errors keep the cursor of the fork
+	r.Cursor = 3
+	fork = r.Fork()
+	fork.Skip()
+	_, err = fork.ReadInt()
+	var rErr *ReaderError
+	require.True(t, errors.As(err, &rErr))
+	r.Skip()
+	r.Skip()
+	require.Equal(t, 4, rErr.Reader.Cursor)
+
+	// nested forks commit to their parent only
+	r = &StringReader{String: "abc"}
+	fork = r.Fork()
+	nested := fork.Fork()
+	nested.Skip()
+	nested.Commit()
+	require.Equal(t, 1, fork.Cursor)
+	require.Equal(t, 0, r.Cursor)
+	fork.Commit()
+	require.Equal(t, 1, r.Cursor)
+
+	plain := &StringReader{String: "x"}
+	plain.Skip()
+	plain.Commit()
+	plain.Rollback()
+	require.Equal(t, 1, plain.Cursor)
+}
+
+func TestQuoteString(t *testing.T) {
+	for _, s := range []string{"", "word", "two words", `a "quote"`, `back\slash`, "minecraft:stone"} {
+		quoted := QuoteString(s)
+		r := &StringReader{String: quoted}
+		read, err := r.ReadString()
+		require.NoError(t, err)
+		require.Equal(t, s, read)
+		require.False(t, r.CanRead())
+	}
+	require.Equal(t, "word", QuoteString("word"))
+	require.Equal(t, `"two words"`, QuoteString("two words"))
+}
@@ -0,0 +1,119 @@
+package brigodier
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RedirectIssueKind is the kind of a RedirectIssue.
+type RedirectIssueKind uint8
+
+// RedirectIssueKind values.
+const (
+	// RedirectUnreachable indicates that the redirect target
+	// is not part of the Dispatcher's command tree.
+	RedirectUnreachable RedirectIssueKind = iota
+	// RedirectCycle indicates that the redirecting node can be reached again
+	// from its redirect target by only parsing literals, so that no
+	// argument is consumed in between, e.g. "loop again again again ...".
+	RedirectCycle
+)
+
+func (k RedirectIssueKind) String() string {
+	switch k {
+	case RedirectUnreachable:
+		return "unreachable"
+	case RedirectCycle:
+		return "cycle"
+	}
+	return fmt.Sprintf("RedirectIssueKind(%d)", uint8(k))
+}
+
+// RedirectIssue is a diagnostic returned by Dispatcher.CheckRedirects.
+type RedirectIssue struct {
+	Kind   RedirectIssueKind
+	Path   []string    // The path to Node as returned by Dispatcher.Path.
+	Node   CommandNode // The redirecting node.
+	Target CommandNode // The redirect target of Node.
+	// Cycle are the nodes parsed after Node until Node is parsed again
+	// if RedirectCycle, with Node being the last element.
+	Cycle []CommandNode
+}
+
+func (i *RedirectIssue) String() string {
+	s := fmt.Sprintf("redirect %s: %s", i.Kind, strings.Join(i.Path, " "))
+	if i.Kind == RedirectCycle {
+		names := make([]string, 0, len(i.Cycle))
+		for _, n := range i.Cycle {
+			names = append(names, n.Name())
+		}
+		s += " -> " + strings.Join(names, " ")
+	}
+	return s
+}
+
+// CheckRedirects checks the redirects of the command tree and returns
+// a RedirectIssue for every redirect target that is not part of the tree
+// and every node redirecting to one of its ancestors with only literals
+// in between, which can be repeated endlessly without consuming an argument.
+//
+// Redirect loops through arguments like "execute as <target> ..." and
+// redirects to the Dispatcher.Root like "execute run ..." are legal
+// and not reported. The issues are ordered depth-first in registration order.
+func (d *Dispatcher) CheckRedirects() []*RedirectIssue {
+	var (
+		issues    []*RedirectIssue
+		redirects []*RedirectIssue
+		inTree    = map[CommandNode]bool{&d.Root: true}
+		walk      func(node CommandNode, path []string, nodes []CommandNode)
+	)
+	walk = func(node CommandNode, path []string, nodes []CommandNode) {
+		node.ChildrenOrdered().Range(func(name string, child CommandNode) bool {
+			if inTree[child] {
+				return true
+			}
+			inTree[child] = true
+			childPath := append(append(make([]string, 0, len(path)+1), path...), name)
+			childNodes := append(append(make([]CommandNode, 0, len(nodes)+1), nodes...), child)
+			if target := child.Redirect(); target != nil {
+				redirects = append(redirects, &RedirectIssue{
+					Path:   childPath,
+					Node:   child,
+					Target: target,
+					Cycle:  literalCycle(childNodes, target),
+				})
+			}
+			walk(child, childPath, childNodes)
+			return true
+		})
+	}
+	walk(&d.Root, nil, nil)
+
+	for _, r := range redirects {
+		if !inTree[r.Target] {
+			r.Kind = RedirectUnreachable
+			issues = append(issues, r)
+		} else if r.Cycle != nil {
+			r.Kind = RedirectCycle
+			issues = append(issues, r)
+		}
+	}
+	return issues
+}
+
+// literalCycle returns the nodes after target in the path to a redirecting node
+// if target is in the path and all of these nodes are literals.
+func literalCycle(path []CommandNode, target CommandNode) []CommandNode {
+	for i := len(path) - 1; i >= 0; i-- {
+		if _, ok := path[i].(*LiteralCommandNode); !ok {
+			return nil
+		}
+		if path[i] == target {
+			if i == len(path)-1 {
+				return nil // The node's own children are parsed next.
+			}
+			return path[i+1:]
+		}
+	}
+	return nil
+}
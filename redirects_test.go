@@ -0,0 +1,38 @@
+package brigodier
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDispatcher_CheckRedirects(t *testing.T) {
+	var d Dispatcher
+	execute := d.Register(Literal("execute"))
+	execute.AddChild(
+		Literal("as").Then(Argument("target", String).Redirect(execute)).Build(),
+		Literal("run").Redirect(&d.Root).Build(),
+	)
+	require.Empty(t, d.CheckRedirects())
+
+	loop := d.Register(Literal("loop"))
+	again := Literal("again").Redirect(loop).Build()
+	loop.AddChild(again)
+	orphan := Literal("orphan").Build()
+	dangling := d.Register(Literal("dangling").Redirect(orphan))
+
+	issues := d.CheckRedirects()
+	require.Len(t, issues, 2)
+
+	require.Equal(t, RedirectCycle, issues[0].Kind)
+	require.Equal(t, []string{"loop", "again"}, issues[0].Path)
+	require.Equal(t, again, issues[0].Node)
+	require.Equal(t, loop, issues[0].Target)
+	require.Equal(t, []CommandNode{again}, issues[0].Cycle)
+	require.Equal(t, "redirect cycle: loop again -> again", issues[0].String())
+
+	require.Equal(t, RedirectUnreachable, issues[1].Kind)
+	require.Equal(t, []string{"dangling"}, issues[1].Path)
+	require.Equal(t, dangling, issues[1].Node)
+	require.Equal(t, orphan, issues[1].Target)
+	require.Nil(t, issues[1].Cycle)
+}
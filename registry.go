@@ -0,0 +1,533 @@
+package brigodier
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// TypeProperties are the properties of an ArgumentType registered in a TypeRegistry,
+// e.g. the bounds of a "brigadier:integer". Numbers may be of any Go integer or
+// float type or a json.Number, as decoded from JSON or a config file.
+type TypeProperties map[string]interface{}
+
+// TypeDef defines how an ArgumentType is constructed from
+// and described by its identifier and TypeProperties.
+type TypeDef struct {
+	// ID identifies the type, e.g. "brigadier:string".
+	ID string
+	// New returns the ArgumentType for the properties, which may be nil.
+	New func(props TypeProperties) (ArgumentType, error)
+	// Properties returns the properties of t and whether t is of this type.
+	Properties func(t ArgumentType) (TypeProperties, bool)
+}
+
+var (
+	// ErrUnknownArgumentType occurs when an identifier is not registered in a TypeRegistry.
+	ErrUnknownArgumentType = errors.New("unknown argument type")
+	// ErrArgumentTypeRegistered occurs when registering an identifier twice.
+	ErrArgumentTypeRegistered = errors.New("argument type already registered")
+	// ErrInvalidTypeProperty occurs when a TypeProperties value is invalid for the type.
+	ErrInvalidTypeProperty = errors.New("invalid argument type property")
+)
+
+// TypeRegistry maps identifiers like "brigadier:integer" to ArgumentType
+// constructors and ArgumentTypes back to identifiers and properties,
+// so serialized command trees can be round-tripped.
+//
+// A TypeRegistry is safe for concurrent use.
+type TypeRegistry struct {
+	mu   sync.RWMutex
+	defs []*TypeDef
+	byID map[string]*TypeDef
+}
+
+// DefaultTypes is the TypeRegistry used by a Dispatcher without Dispatcher.Types.
+// It contains the builtin ArgumentTypes, see NewTypeRegistry.
+var DefaultTypes = NewTypeRegistry()
+
+// NewTypeRegistry returns a new TypeRegistry containing the builtin ArgumentTypes:
+//
+//	brigadier:bool
+//	brigadier:integer       Int32ArgumentType with properties "min" and "max"
+//	brigadier:long          Int64ArgumentType with properties "min" and "max"
+//	brigadier:float         Float32ArgumentType with properties "min" and "max"
+//	brigadier:double        Float64ArgumentType with properties "min" and "max"
+//	brigadier:string        StringType with property "type" ("word", "phrase" or "greedy")
+//	brigodier:uint32        Uint32ArgumentType with properties "min" and "max"
+//	brigodier:uint64        Uint64ArgumentType with properties "min" and "max"
+//	brigodier:word          WordArgumentType with property "extra" of the extra runes
+//	brigodier:string_length StringLenArgumentType with properties "type", "min" and "max"
+//	brigodier:identifier    IdentifierArgumentType with property "extra" of the extra runes
+//	brigodier:bytes         BytesArgumentType with properties "encoding" ("base64" or "hex") and "max"
+//	brigodier:color         ColorArgumentType
+//
+// Properties equal to the defaults are omitted.
+func NewTypeRegistry() *TypeRegistry {
+	r := &TypeRegistry{}
+	for _, def := range builtinTypes() {
+		r.Register(def)
+	}
+	return r
+}
+
+// Register registers the TypeDef and panics if the TypeDef is invalid
+// or its identifier already registered. See TryRegister.
+func (r *TypeRegistry) Register(def TypeDef) {
+	if err := r.TryRegister(def); err != nil {
+		panic(err)
+	}
+}
+
+// TryRegister registers the TypeDef or returns an error
+// wrapping ErrArgumentTypeRegistered if the identifier is already registered.
+func (r *TypeRegistry) TryRegister(def TypeDef) error {
+	if def.ID == "" || def.New == nil || def.Properties == nil {
+		return fmt.Errorf("invalid argument type definition %q: id, New and Properties are required", def.ID)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.byID[def.ID]; ok {
+		return fmt.Errorf("%w: %q", ErrArgumentTypeRegistered, def.ID)
+	}
+	if r.byID == nil {
+		r.byID = map[string]*TypeDef{}
+	}
+	r.byID[def.ID] = &def
+	r.defs = append(r.defs, &def)
+	return nil
+}
+
+// New returns the ArgumentType registered as id with the properties or an
+// error wrapping ErrUnknownArgumentType if the identifier is not registered.
+func (r *TypeRegistry) New(id string, props TypeProperties) (ArgumentType, error) {
+	r.mu.RLock()
+	def, ok := r.byID[id]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownArgumentType, id)
+	}
+	t, err := def.New(props)
+	if err != nil {
+		return nil, fmt.Errorf("argument type %q: %w", id, err)
+	}
+	return t, nil
+}
+
+// NewOrPlaceholder returns the ArgumentType registered as id with the properties
+// or a *PlaceholderType if the identifier is not registered,
+// e.g. to import a tree using argument types not implemented by this package.
+func (r *TypeRegistry) NewOrPlaceholder(id string, props TypeProperties) (ArgumentType, error) {
+	t, err := r.New(id, props)
+	if errors.Is(err, ErrUnknownArgumentType) {
+		return &PlaceholderType{ID: id, Properties: props}, nil
+	}
+	return t, err
+}
+
+// Identify returns the identifier and properties of t and whether t is of a registered type.
+// TypeDefs are tried in registration order. A *PlaceholderType is identified by its ID.
+func (r *TypeRegistry) Identify(t ArgumentType) (id string, props TypeProperties, ok bool) {
+	if p, ok := t.(*PlaceholderType); ok {
+		return p.ID, p.Properties, true
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, def := range r.defs {
+		if props, ok = def.Properties(t); ok {
+			return def.ID, props, true
+		}
+	}
+	return "", nil, false
+}
+
+// IDs returns the registered identifiers in registration order.
+func (r *TypeRegistry) IDs() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ids := make([]string, len(r.defs))
+	for i, def := range r.defs {
+		ids[i] = def.ID
+	}
+	return ids
+}
+
+// PlaceholderType stands in for an ArgumentType that is not registered in a TypeRegistry,
+// keeping its identifier and properties so the tree can be serialized again unchanged.
+//
+// It parses any input up to the next ArgumentSeparator, so
+// arguments containing spaces are not parsed like the original type.
+type PlaceholderType struct {
+	ID         string
+	Properties TypeProperties
+}
+
+func (t *PlaceholderType) String() string { return t.ID }
+func (t *PlaceholderType) Parse(rd *StringReader) (interface{}, error) {
+	return rd.ReadWhile(func(c rune) bool { return c != ArgumentSeparator }), nil
+}
+
+// types returns the Dispatcher.Types or DefaultTypes.
+func (d *Dispatcher) types() *TypeRegistry {
+	if d.Types != nil {
+		return d.Types
+	}
+	return DefaultTypes
+}
+
+// DefaultClientParser is the parser sent to clients by TypeRegistry.ClientParser
+// for arguments of types clients do not know, a single-word "brigadier:string".
+const DefaultClientParser = "brigadier:string"
+
+// ClientParser returns the parser identifier and properties of the argument sent to clients:
+// the ArgumentCommandNode.ClientParser if set, otherwise the identifier and properties of the
+// argument type unless it is a "brigodier:" type unknown to Minecraft clients.
+// Other arguments fall back to a single-word DefaultClientParser.
+func (r *TypeRegistry) ClientParser(a *ArgumentCommandNode) (id string, props TypeProperties) {
+	if a.clientParserID != "" {
+		return a.clientParserID, a.clientParserProps
+	}
+	if id, props, ok := r.Identify(a.argType); ok && !strings.HasPrefix(id, "brigodier:") {
+		return id, props
+	}
+	return DefaultClientParser, TypeProperties{"type": "word"}
+}
+
+// parserOf returns the ArgumentCommandNode.ClientParser if set, otherwise the identifier
+// and properties of the argument type or its name if the type is not registered.
+func (r *TypeRegistry) parserOf(a *ArgumentCommandNode) (string, TypeProperties) {
+	if a.clientParserID != "" {
+		return a.clientParserID, a.clientParserProps
+	}
+	if id, props, ok := r.Identify(a.argType); ok {
+		return id, props
+	}
+	return a.argType.String(), nil
+}
+
+func builtinTypes() []TypeDef {
+	return []TypeDef{
+		{
+			ID: "brigadier:bool",
+			New: func(TypeProperties) (ArgumentType, error) {
+				return Bool, nil
+			},
+			Properties: func(t ArgumentType) (TypeProperties, bool) {
+				_, ok := t.(*BoolArgumentType)
+				return nil, ok
+			},
+		},
+		{
+			ID: "brigadier:integer",
+			New: func(props TypeProperties) (ArgumentType, error) {
+				t := &Int32ArgumentType{Min: MinInt32, Max: MaxInt32}
+				return t, props.bounds(
+					func(v json.Number) (err error) { t.Min, err = parseInt32(v); return },
+					func(v json.Number) (err error) { t.Max, err = parseInt32(v); return })
+			},
+			Properties: func(t ArgumentType) (TypeProperties, bool) {
+				a, ok := t.(*Int32ArgumentType)
+				if !ok {
+					return nil, false
+				}
+				return boundProps(a.Min != MinInt32, a.Min, a.Max != MaxInt32, a.Max), true
+			},
+		},
+		{
+			ID: "brigadier:long",
+			New: func(props TypeProperties) (ArgumentType, error) {
+				t := &Int64ArgumentType{Min: MinInt64, Max: MaxInt64}
+				return t, props.bounds(
+					func(v json.Number) (err error) { t.Min, err = v.Int64(); return },
+					func(v json.Number) (err error) { t.Max, err = v.Int64(); return })
+			},
+			Properties: func(t ArgumentType) (TypeProperties, bool) {
+				a, ok := t.(*Int64ArgumentType)
+				if !ok {
+					return nil, false
+				}
+				return boundProps(a.Min != MinInt64, a.Min, a.Max != MaxInt64, a.Max), true
+			},
+		},
+		{
+			ID: "brigadier:float",
+			New: func(props TypeProperties) (ArgumentType, error) {
+				t := &Float32ArgumentType{Min: MinFloat32, Max: MaxFloat32}
+				return t, props.bounds(
+					func(v json.Number) (err error) { t.Min, err = parseFloat32(v); return },
+					func(v json.Number) (err error) { t.Max, err = parseFloat32(v); return })
+			},
+			Properties: func(t ArgumentType) (TypeProperties, bool) {
+				a, ok := t.(*Float32ArgumentType)
+				if !ok {
+					return nil, false
+				}
+				return boundProps(a.Min != MinFloat32, a.Min, a.Max != MaxFloat32, a.Max), true
+			},
+		},
+		{
+			ID: "brigadier:double",
+			New: func(props TypeProperties) (ArgumentType, error) {
+				t := &Float64ArgumentType{Min: MinFloat64, Max: MaxFloat64}
+				return t, props.bounds(
+					func(v json.Number) (err error) { t.Min, err = v.Float64(); return },
+					func(v json.Number) (err error) { t.Max, err = v.Float64(); return })
+			},
+			Properties: func(t ArgumentType) (TypeProperties, bool) {
+				a, ok := t.(*Float64ArgumentType)
+				if !ok {
+					return nil, false
+				}
+				return boundProps(a.Min != MinFloat64, a.Min, a.Max != MaxFloat64, a.Max), true
+			},
+		},
+		{
+			ID: "brigadier:string",
+			New: func(props TypeProperties) (ArgumentType, error) {
+				return props.stringType()
+			},
+			Properties: func(t ArgumentType) (TypeProperties, bool) {
+				s, ok := t.(StringType)
+				if !ok {
+					return nil, false
+				}
+				return TypeProperties{"type": stringTypeName(s)}, true
+			},
+		},
+		{
+			ID: "brigodier:uint32",
+			New: func(props TypeProperties) (ArgumentType, error) {
+				t := &Uint32ArgumentType{Min: MinUint32, Max: MaxUint32}
+				return t, props.bounds(
+					func(v json.Number) (err error) { t.Min, err = parseUint32(v); return },
+					func(v json.Number) (err error) { t.Max, err = parseUint32(v); return })
+			},
+			Properties: func(t ArgumentType) (TypeProperties, bool) {
+				a, ok := t.(*Uint32ArgumentType)
+				if !ok {
+					return nil, false
+				}
+				return boundProps(a.Min != MinUint32, a.Min, a.Max != MaxUint32, a.Max), true
+			},
+		},
+		{
+			ID: "brigodier:uint64",
+			New: func(props TypeProperties) (ArgumentType, error) {
+				t := &Uint64ArgumentType{Min: MinUint64, Max: MaxUint64}
+				return t, props.bounds(
+					func(v json.Number) (err error) { t.Min, err = strconv.ParseUint(v.String(), 10, 64); return },
+					func(v json.Number) (err error) { t.Max, err = strconv.ParseUint(v.String(), 10, 64); return })
+			},
+			Properties: func(t ArgumentType) (TypeProperties, bool) {
+				a, ok := t.(*Uint64ArgumentType)
+				if !ok {
+					return nil, false
+				}
+				return boundProps(a.Min != MinUint64, a.Min, a.Max != MaxUint64, a.Max), true
+			},
+		},
+		{
+			ID: "brigodier:word",
+			New: func(props TypeProperties) (ArgumentType, error) {
+				extra, _ := props["extra"].(string)
+				return WordWith([]rune(extra)...), nil
+			},
+			Properties: func(t ArgumentType) (TypeProperties, bool) {
+				w, ok := t.(*WordArgumentType)
+				if !ok || w.Allowed != nil {
+					return nil, false
+				}
+				if len(w.Extra) == 0 {
+					return nil, true
+				}
+				return TypeProperties{"extra": string(w.Extra)}, true
+			},
+		},
+		{
+			ID: "brigodier:string_length",
+			New: func(props TypeProperties) (ArgumentType, error) {
+				st, err := props.stringType()
+				if err != nil {
+					return nil, err
+				}
+				t := &StringLenArgumentType{Type: st}
+				return t, props.bounds(
+					func(v json.Number) (err error) { t.Min, err = parseIntProp(v); return },
+					func(v json.Number) (err error) { t.Max, err = parseIntProp(v); return })
+			},
+			Properties: func(t ArgumentType) (TypeProperties, bool) {
+				a, ok := t.(*StringLenArgumentType)
+				if !ok {
+					return nil, false
+				}
+				return TypeProperties{"type": stringTypeName(a.Type), "min": a.Min, "max": a.Max}, true
+			},
+		},
+		{
+			ID: "brigodier:identifier",
+			New: func(props TypeProperties) (ArgumentType, error) {
+				t := &IdentifierArgumentType{}
+				if extra, _ := props["extra"].(string); extra != "" {
+					t.Extra = []rune(extra)
+				}
+				return t, nil
+			},
+			Properties: func(t ArgumentType) (TypeProperties, bool) {
+				i, ok := t.(*IdentifierArgumentType)
+				if !ok || i.First != nil {
+					return nil, false
+				}
+				if len(i.Extra) == 0 {
+					return nil, true
+				}
+				return TypeProperties{"extra": string(i.Extra)}, true
+			},
+		},
+		{
+			ID: "brigodier:bytes",
+			New: func(props TypeProperties) (ArgumentType, error) {
+				t := &BytesArgumentType{}
+				switch e, _ := props["encoding"].(string); e {
+				case "base64", "":
+				case "hex":
+					t.Encoding = BytesHex
+				default:
+					return nil, fmt.Errorf("%w encoding %q", ErrInvalidTypeProperty, e)
+				}
+				if v, ok := props["max"]; ok {
+					n, err := number(v)
+					if err == nil {
+						t.MaxSize, err = parseIntProp(n)
+					}
+					if err != nil {
+						return nil, fmt.Errorf("%w max %v: %v", ErrInvalidTypeProperty, v, err)
+					}
+				}
+				return t, nil
+			},
+			Properties: func(t ArgumentType) (TypeProperties, bool) {
+				b, ok := t.(*BytesArgumentType)
+				if !ok {
+					return nil, false
+				}
+				props := TypeProperties{"encoding": b.Encoding.String()}
+				if b.MaxSize > 0 {
+					props["max"] = b.MaxSize
+				}
+				return props, true
+			},
+		},
+		{
+			ID: "brigodier:color",
+			New: func(TypeProperties) (ArgumentType, error) {
+				return ColorArgument, nil
+			},
+			Properties: func(t ArgumentType) (TypeProperties, bool) {
+				_, ok := t.(*ColorArgumentType)
+				return nil, ok
+			},
+		},
+	}
+}
+
+// stringType returns the StringType of the "type" property, QuotablePhase by default.
+func (p TypeProperties) stringType() (StringType, error) {
+	s, _ := p["type"].(string)
+	switch s {
+	case "word":
+		return SingleWord, nil
+	case "phrase", "":
+		return QuotablePhase, nil
+	case "greedy":
+		return GreedyPhrase, nil
+	}
+	return 0, fmt.Errorf("%w type %q", ErrInvalidTypeProperty, s)
+}
+
+// stringTypeName returns the "type" property of the StringType.
+func stringTypeName(t StringType) string {
+	switch t {
+	case SingleWord:
+		return "word"
+	case GreedyPhrase:
+		return "greedy"
+	}
+	return "phrase"
+}
+
+// bounds calls min and max with the "min" and "max" properties if present.
+func (p TypeProperties) bounds(min, max func(v json.Number) error) error {
+	for key, set := range map[string]func(json.Number) error{"min": min, "max": max} {
+		v, ok := p[key]
+		if !ok {
+			continue
+		}
+		n, err := number(v)
+		if err == nil {
+			err = set(n)
+		}
+		if err != nil {
+			return fmt.Errorf("%w %s %v: %v", ErrInvalidTypeProperty, key, v, err)
+		}
+	}
+	return nil
+}
+
+// boundProps returns the "min" and "max" properties that are set.
+func boundProps(hasMin bool, min interface{}, hasMax bool, max interface{}) TypeProperties {
+	if !hasMin && !hasMax {
+		return nil
+	}
+	props := TypeProperties{}
+	if hasMin {
+		props["min"] = min
+	}
+	if hasMax {
+		props["max"] = max
+	}
+	return props
+}
+
+// number returns a numeric property value as json.Number.
+func number(v interface{}) (json.Number, error) {
+	switch n := v.(type) {
+	case json.Number:
+		return n, nil
+	case string:
+		return json.Number(n), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return json.Number(fmt.Sprint(n)), nil
+	case float32:
+		return json.Number(strconv.FormatFloat(float64(n), 'g', -1, 32)), nil
+	case float64:
+		if n == math.Trunc(n) && math.Abs(n) < 1<<53 {
+			return json.Number(strconv.FormatInt(int64(n), 10)), nil
+		}
+		return json.Number(strconv.FormatFloat(n, 'g', -1, 64)), nil
+	}
+	return "", fmt.Errorf("not a number: %T", v)
+}
+
+func parseInt32(v json.Number) (int32, error) {
+	i, err := strconv.ParseInt(v.String(), 10, 32)
+	return int32(i), err
+}
+
+func parseIntProp(v json.Number) (int, error) {
+	i, err := strconv.ParseInt(v.String(), 10, 0)
+	return int(i), err
+}
+
+func parseUint32(v json.Number) (uint32, error) {
+	i, err := strconv.ParseUint(v.String(), 10, 32)
+	return uint32(i), err
+}
+
+func parseFloat32(v json.Number) (float32, error) {
+	f, err := strconv.ParseFloat(v.String(), 32)
+	return float32(f), err
+}
@@ -0,0 +1,111 @@
+package brigodier
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestTypeRegistry_RoundTrip(t *testing.T) {
+	for _, at := range []ArgumentType{
+		Bool, Int32, Int64, Uint32, Uint64, Float32, Float64,
+		String, StringWord, StringPhrase, ColorArgument,
+		&Int32ArgumentType{Min: -5, Max: 5},
+		&Int64ArgumentType{Min: MinInt64, Max: 1 << 60},
+		&Uint64ArgumentType{Min: 1, Max: MaxUint64},
+		&Float32ArgumentType{Min: 0.5, Max: MaxFloat32},
+		&Float64ArgumentType{Min: -1.25, Max: 1e300},
+		WordWith(':', '#'),
+		StringWithLen(1, 16), WordWithLen(0, 3),
+		Identifier, &IdentifierArgumentType{Extra: []rune{'-', '.'}},
+		Base64Argument, HexArgument, &BytesArgumentType{Encoding: BytesHex, MaxSize: 32},
+	} {
+		id, props, ok := DefaultTypes.Identify(at)
+		require.True(t, ok, at)
+
+		// through JSON like a serialized tree
+		b, err := json.Marshal(props)
+		require.NoError(t, err)
+		var decoded TypeProperties
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		require.NoError(t, dec.Decode(&decoded))
+
+		got, err := DefaultTypes.New(id, decoded)
+		require.NoError(t, err, id)
+		require.Equal(t, at, got, id)
+	}
+}
+
+func TestTypeRegistry(t *testing.T) {
+	id, props, ok := DefaultTypes.Identify(&Int32ArgumentType{Min: 0, Max: MaxInt32})
+	require.True(t, ok)
+	require.Equal(t, "brigadier:integer", id)
+	require.Equal(t, TypeProperties{"min": int32(0)}, props)
+
+	at, err := DefaultTypes.New("brigadier:integer", TypeProperties{"min": 1.0, "max": 10})
+	require.NoError(t, err)
+	require.Equal(t, &Int32ArgumentType{Min: 1, Max: 10}, at)
+
+	_, err = DefaultTypes.New("brigadier:integer", TypeProperties{"max": 1.5})
+	require.ErrorIs(t, err, ErrInvalidTypeProperty)
+	_, err = DefaultTypes.New("brigadier:string", TypeProperties{"type": "sentence"})
+	require.ErrorIs(t, err, ErrInvalidTypeProperty)
+	_, err = DefaultTypes.New("minecraft:entity", nil)
+	require.ErrorIs(t, err, ErrUnknownArgumentType)
+
+	_, _, ok = DefaultTypes.Identify(&WordArgumentType{Allowed: isHexDigit})
+	require.False(t, ok)
+
+	r := NewTypeRegistry()
+	require.ErrorIs(t, r.TryRegister(TypeDef{
+		ID:         "brigadier:bool",
+		New:        func(TypeProperties) (ArgumentType, error) { return Bool, nil },
+		Properties: func(ArgumentType) (TypeProperties, bool) { return nil, false },
+	}), ErrArgumentTypeRegistered)
+	require.Error(t, r.TryRegister(TypeDef{ID: "custom:empty"}))
+
+	custom := &ArgumentTypeFuncs{Name: "entity"}
+	r.Register(TypeDef{
+		ID:  "minecraft:entity",
+		New: func(TypeProperties) (ArgumentType, error) { return custom, nil },
+		Properties: func(t ArgumentType) (TypeProperties, bool) {
+			return TypeProperties{"amount": "single"}, t == custom
+		},
+	})
+	require.Contains(t, r.IDs(), "minecraft:entity")
+	require.NotContains(t, DefaultTypes.IDs(), "minecraft:entity")
+	id, props, ok = r.Identify(custom)
+	require.True(t, ok)
+	require.Equal(t, "minecraft:entity", id)
+	require.Equal(t, TypeProperties{"amount": "single"}, props)
+}
+
+func TestTypeRegistry_ClientParser(t *testing.T) {
+	clientParser := func(b ArgumentNodeBuilder) (string, TypeProperties) {
+		return DefaultTypes.ClientParser(b.BuildArgument())
+	}
+	id, props := clientParser(Argument("n", &Int32ArgumentType{Min: 1, Max: MaxInt32}))
+	require.Equal(t, "brigadier:integer", id)
+	require.Equal(t, TypeProperties{"min": int32(1)}, props)
+
+	for _, at := range []ArgumentType{WordWith(':'), Uint32, ColorArgument, &PlayerArgumentType{}} {
+		id, props = clientParser(Argument("a", at))
+		require.Equal(t, DefaultClientParser, id)
+		require.Equal(t, TypeProperties{"type": "word"}, props)
+	}
+
+	entity := Argument("targets", &PlayerArgumentType{}).ClientParser("minecraft:entity", TypeProperties{"flags": byte(2)})
+	id, props = clientParser(entity)
+	require.Equal(t, "minecraft:entity", id)
+	require.Equal(t, TypeProperties{"flags": byte(2)}, props)
+
+	// kept by CreateBuilder and Clone
+	node := entity.BuildArgument()
+	id, props = node.CreateArgumentBuilder().BuildArgument().ClientParser()
+	require.Equal(t, "minecraft:entity", id)
+	require.Equal(t, TypeProperties{"flags": byte(2)}, props)
+	id, _ = node.Clone(false).(*ArgumentCommandNode).ClientParser()
+	require.Equal(t, "minecraft:entity", id)
+}
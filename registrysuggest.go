@@ -0,0 +1,108 @@
+package brigodier
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RegistrySuggestions is a SuggestionProvider suggesting the values of
+// a dynamic list, e.g. the names of online players or worlds, which are
+// fetched at most once per TTL and cached in between.
+//
+// While the expired list is refetched, concurrent requests are served
+// the previous list instead of waiting, so slow fetches do not stall
+// completion. Only the very first requests wait for the list.
+//
+// A RegistrySuggestions is safe for concurrent use.
+type RegistrySuggestions struct {
+	fetch func(ctx context.Context) []string
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu      sync.Mutex
+	values  []string
+	fetched bool
+	expires time.Time
+	pending chan struct{} // closed when the running fetch is done, nil if none
+}
+
+var _ DynamicSuggestionProvider = (*RegistrySuggestions)(nil)
+
+// NewRegistrySuggestions returns a new RegistrySuggestions fetching the list using
+// fetch with the context of the suggestion request and caching it for ttl.
+// If ttl is not positive, the list is fetched for every request.
+//
+//	worlds := brigodier.NewRegistrySuggestions(server.WorldNames, 5*time.Second)
+//	Argument("world", brigodier.SingleWord).Suggests(worlds)
+func NewRegistrySuggestions(fetch func(ctx context.Context) []string, ttl time.Duration) *RegistrySuggestions {
+	return &RegistrySuggestions{fetch: fetch, ttl: ttl}
+}
+
+// Suggestions implements SuggestionProvider and suggests the values
+// starting with the remaining input, ignoring case.
+func (r *RegistrySuggestions) Suggestions(ctx *CommandContext, builder *SuggestionsBuilder) *Suggestions {
+	for _, v := range r.Values(ctx) {
+		if strings.HasPrefix(strings.ToLower(v), builder.RemainingLowerCase) {
+			builder.Suggest(v)
+		}
+	}
+	return builder.Build()
+}
+
+// Dynamic implements DynamicSuggestionProvider since the values change over time
+// and are already cached by the RegistrySuggestions itself.
+func (r *RegistrySuggestions) Dynamic() bool { return true }
+
+// Values returns the cached list, fetching it first if it is expired.
+// The returned slice must not be modified.
+func (r *RegistrySuggestions) Values(ctx context.Context) []string {
+	r.mu.Lock()
+	if r.fetched && (r.pending != nil || r.time().Before(r.expires)) {
+		values := r.values
+		r.mu.Unlock()
+		return values
+	}
+	if pending := r.pending; pending != nil {
+		r.mu.Unlock()
+		select {
+		case <-pending:
+		case <-ctx.Done():
+			return nil
+		}
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.values
+	}
+	pending := make(chan struct{})
+	r.pending = pending
+	r.mu.Unlock()
+
+	var values []string
+	defer func() {
+		r.mu.Lock()
+		r.values, r.fetched = values, true
+		r.expires = r.time().Add(r.ttl)
+		r.pending = nil
+		r.mu.Unlock()
+		close(pending)
+	}()
+	values = r.fetch(ctx)
+	return values
+}
+
+// Invalidate expires the cached list so that it is
+// fetched again by the next suggestion request.
+func (r *RegistrySuggestions) Invalidate() {
+	r.mu.Lock()
+	r.expires = time.Time{}
+	r.mu.Unlock()
+}
+
+func (r *RegistrySuggestions) time() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
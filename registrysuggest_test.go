@@ -0,0 +1,79 @@
+package brigodier
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRegistrySuggestions(t *testing.T) {
+	fetches := 0
+	players := []string{"Alice", "albert", "Bob"}
+	r := NewRegistrySuggestions(func(context.Context) []string {
+		fetches++
+		return players
+	}, time.Minute)
+	now := time.Unix(0, 0)
+	r.now = func() time.Time { return now }
+
+	d := NewDispatcher()
+	d.Register(Literal("msg").Then(Argument("player", SingleWord).Suggests(r)))
+	suggest := func(input string) []string {
+		s, err := d.CompletionSuggestions(d.Parse(context.TODO(), input))
+		require.NoError(t, err)
+		var texts []string
+		for _, suggestion := range s.Suggestions {
+			texts = append(texts, suggestion.Text)
+		}
+		return texts
+	}
+
+	require.Equal(t, []string{"Alice", "albert"}, suggest("msg al"))
+	require.Equal(t, []string{"Bob"}, suggest("msg b"))
+	require.Equal(t, 1, fetches)
+
+	players = []string{"Carol"}
+	now = now.Add(30 * time.Second)
+	require.Equal(t, []string{"Alice", "albert", "Bob"}, suggest("msg "))
+	require.Equal(t, 1, fetches)
+
+	now = now.Add(time.Minute)
+	require.Equal(t, []string{"Carol"}, suggest("msg "))
+	require.Equal(t, 2, fetches)
+
+	players = []string{"Dave"}
+	r.Invalidate()
+	require.Equal(t, []string{"Dave"}, suggest("msg "))
+	require.Equal(t, 3, fetches)
+}
+
+func TestRegistrySuggestions_concurrentRefresh(t *testing.T) {
+	release := make(chan struct{})
+	var fetches int
+	var mu sync.Mutex
+	r := NewRegistrySuggestions(func(context.Context) []string {
+		mu.Lock()
+		fetches++
+		n := fetches
+		mu.Unlock()
+		if n > 1 {
+			<-release
+		}
+		return []string{"v" + string(rune('0'+n))}
+	}, 0)
+	require.Equal(t, []string{"v1"}, r.Values(context.TODO()))
+
+	done := make(chan []string)
+	go func() { done <- r.Values(context.TODO()) }()
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return fetches == 2
+	}, time.Second, time.Millisecond)
+	// the stale list is served while refreshing
+	require.Equal(t, []string{"v1"}, r.Values(context.TODO()))
+	close(release)
+	require.Equal(t, []string{"v2"}, <-done)
+}
@@ -0,0 +1,259 @@
+// Package remote exposes a brigodier Dispatcher to remote frontends,
+// e.g. a proxy serving suggestions and executing commands for thin clients.
+//
+// The messages and the Service mirror the CommandService defined in remote.proto.
+// This package has no dependency on a RPC framework: register a Server with
+// the generated gRPC stubs by copying fields between the generated messages
+// and the types of this package, or serve them as JSON.
+//
+// The caller's identity is passed in the context.Context of each call
+// (e.g. added by an interceptor), and is what node requirements are checked against.
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.minekube.com/brigodier"
+)
+
+// Service is the CommandService defined in remote.proto.
+type Service interface {
+	// GetTree returns the command tree usable by the caller.
+	GetTree(ctx context.Context, req *GetTreeRequest) (*Tree, error)
+	// Suggest returns completion suggestions for a partial input.
+	Suggest(ctx context.Context, req *SuggestRequest) (*SuggestResponse, error)
+	// Execute parses and executes a command.
+	Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error)
+}
+
+// NodeType is the type of a Node.
+type NodeType int32
+
+// Supported NodeType values.
+const (
+	NodeRoot NodeType = iota
+	NodeLiteral
+	NodeArgument
+)
+
+type (
+	// GetTreeRequest is the request of Service.GetTree.
+	GetTreeRequest struct{}
+	// Tree is a flattened command tree. Nodes reference each other by their
+	// index in Nodes, so redirects and cycles are representable.
+	Tree struct {
+		Nodes []*Node `json:"nodes"`
+		Root  int32   `json:"root"`
+	}
+	// Node is a node of a Tree.
+	Node struct {
+		Type   NodeType `json:"type"`
+		Name   string   `json:"name,omitempty"`
+		Parser string   `json:"parser,omitempty"` // The ArgumentType identifier of argument nodes.
+		// The ArgumentType properties of argument nodes, see brigodier.TypeRegistry.
+		Properties brigodier.TypeProperties `json:"properties,omitempty"`
+		Executable bool                     `json:"executable,omitempty"`
+		Fork       bool                     `json:"fork,omitempty"`
+		Children   []int32                  `json:"children,omitempty"`
+		Redirect   int32                    `json:"redirect"` // Index of the redirect target or -1.
+	}
+	// Range is a range of the input.
+	Range struct {
+		Start int32 `json:"start"`
+		End   int32 `json:"end"`
+	}
+	// SuggestRequest is the request of Service.Suggest.
+	SuggestRequest struct {
+		Input  string `json:"input"`
+		Cursor int32  `json:"cursor"` // The cursor to suggest at, or -1 for the end of input.
+	}
+	// Suggestion is a suggestion of a SuggestResponse.
+	Suggestion struct {
+		Range   Range  `json:"range"`
+		Text    string `json:"text"`
+		Tooltip string `json:"tooltip,omitempty"`
+	}
+	// SuggestResponse is the response of Service.Suggest.
+	SuggestResponse struct {
+		Range       Range         `json:"range"`
+		Suggestions []*Suggestion `json:"suggestions"`
+	}
+	// ExecuteRequest is the request of Service.Execute.
+	ExecuteRequest struct {
+		Input string `json:"input"`
+	}
+	// ExecuteResponse is the response of Service.Execute.
+	ExecuteResponse struct {
+		Error       string `json:"error,omitempty"`        // Empty if the command executed successfully.
+		SyntaxError bool   `json:"syntax_error,omitempty"` // Whether Error is a syntax error of the input.
+		Cursor      int32  `json:"cursor"`                 // The input cursor of a syntax error or -1.
+	}
+)
+
+// Server implements Service for a Dispatcher.
+type Server struct {
+	Dispatcher *brigodier.Dispatcher
+}
+
+var _ Service = (*Server)(nil)
+
+// NewServer returns a new Server for the Dispatcher.
+func NewServer(d *brigodier.Dispatcher) *Server { return &Server{Dispatcher: d} }
+
+// GetTree implements Service.
+func (s *Server) GetTree(ctx context.Context, _ *GetTreeRequest) (*Tree, error) {
+	types := s.Dispatcher.Types
+	if types == nil {
+		types = brigodier.DefaultTypes
+	}
+	return encodeTree(ctx, &s.Dispatcher.Root, types), nil
+}
+
+// Suggest implements Service.
+func (s *Server) Suggest(ctx context.Context, req *SuggestRequest) (*SuggestResponse, error) {
+	cursor := int(req.Cursor)
+	if cursor < 0 || cursor > len(req.Input) {
+		cursor = len(req.Input)
+	}
+	parse := s.Dispatcher.Parse(ctx, req.Input)
+	suggestions, err := s.Dispatcher.CompletionSuggestionsCursor(parse, cursor)
+	if err != nil {
+		return nil, err
+	}
+	res := &SuggestResponse{
+		Range:       encodeRange(suggestions.Range),
+		Suggestions: make([]*Suggestion, 0, len(suggestions.Suggestions)),
+	}
+	for _, suggestion := range suggestions.Suggestions {
+		s := &Suggestion{Range: encodeRange(suggestion.Range), Text: suggestion.Text}
+		if suggestion.Tooltip != nil {
+			s.Tooltip = suggestion.Tooltip.String()
+		}
+		res.Suggestions = append(res.Suggestions, s)
+	}
+	return res, nil
+}
+
+// Execute implements Service.
+//
+// Errors of the command are returned in the ExecuteResponse.
+func (s *Server) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	err := s.Dispatcher.Do(ctx, req.Input)
+	res := &ExecuteResponse{Cursor: -1}
+	if err == nil {
+		return res, nil
+	}
+	res.Error = err.Error()
+	var syntaxErr *brigodier.CommandSyntaxError
+	res.SyntaxError = errors.As(err, &syntaxErr)
+	var readerErr *brigodier.ReaderError
+	if errors.As(err, &readerErr) && readerErr.Reader != nil {
+		res.Cursor = int32(readerErr.Reader.Cursor)
+	}
+	return res, nil
+}
+
+// EncodeTree flattens the command tree below node restricted
+// to the nodes the given context.Context can use.
+// Argument parsers are derived using brigodier.DefaultTypes.ClientParser.
+func EncodeTree(ctx context.Context, node brigodier.CommandNode) *Tree {
+	return encodeTree(ctx, node, brigodier.DefaultTypes)
+}
+
+func encodeTree(ctx context.Context, node brigodier.CommandNode, types *brigodier.TypeRegistry) *Tree {
+	t := &Tree{}
+	ids := map[brigodier.CommandNode]int32{}
+	var encode func(n brigodier.CommandNode) int32
+	encode = func(n brigodier.CommandNode) int32 {
+		if id, ok := ids[n]; ok {
+			return id
+		}
+		id := int32(len(t.Nodes))
+		ids[n] = id
+		out := &Node{Name: n.Name(), Executable: n.Command() != nil, Fork: n.IsFork(), Redirect: -1}
+		t.Nodes = append(t.Nodes, out)
+		switch a := n.(type) {
+		case *brigodier.RootCommandNode:
+			out.Type = NodeRoot
+		case *brigodier.LiteralCommandNode:
+			out.Type = NodeLiteral
+		case *brigodier.ArgumentCommandNode:
+			out.Type = NodeArgument
+			out.Parser, out.Properties = types.ClientParser(a)
+		}
+		n.ChildrenOrdered().Range(func(_ string, child brigodier.CommandNode) bool {
+			if child.CanUse(ctx) {
+				out.Children = append(out.Children, encode(child))
+			}
+			return true
+		})
+		if n.Redirect() != nil {
+			out.Redirect = encode(n.Redirect())
+		}
+		return id
+	}
+	t.Root = encode(node)
+	return t
+}
+
+func encodeRange(r brigodier.StringRange) Range {
+	return Range{Start: int32(r.Start), End: int32(r.End)}
+}
+
+// Client is a convenience wrapper around a remote Service, e.g. an adapted gRPC client.
+type Client struct {
+	Service Service
+}
+
+// ExecuteError is returned by Client.Execute if the remote command failed.
+type ExecuteError struct {
+	Message     string
+	SyntaxError bool // Whether the error is a syntax error of the input.
+	Cursor      int  // The input cursor of a syntax error or -1.
+}
+
+func (e *ExecuteError) Error() string {
+	if e.Cursor >= 0 {
+		return fmt.Sprintf("%s (at %d)", e.Message, e.Cursor)
+	}
+	return e.Message
+}
+
+// Suggest returns the remote suggestions for the end of input.
+func (c *Client) Suggest(ctx context.Context, input string) (*brigodier.Suggestions, error) {
+	res, err := c.Service.Suggest(ctx, &SuggestRequest{Input: input, Cursor: -1})
+	if err != nil {
+		return nil, err
+	}
+	suggestions := &brigodier.Suggestions{Range: decodeRange(res.Range)}
+	for _, s := range res.Suggestions {
+		suggestion := &brigodier.Suggestion{Range: decodeRange(s.Range), Text: s.Text}
+		if s.Tooltip != "" {
+			suggestion.Tooltip = tooltip(s.Tooltip)
+		}
+		suggestions.Suggestions = append(suggestions.Suggestions, suggestion)
+	}
+	return suggestions, nil
+}
+
+// Execute executes the input remotely.
+// It returns an *ExecuteError if the remote command failed.
+func (c *Client) Execute(ctx context.Context, input string) error {
+	res, err := c.Service.Execute(ctx, &ExecuteRequest{Input: input})
+	if err != nil {
+		return err
+	}
+	if res.Error == "" {
+		return nil
+	}
+	return &ExecuteError{Message: res.Error, SyntaxError: res.SyntaxError, Cursor: int(res.Cursor)}
+}
+
+func decodeRange(r Range) brigodier.StringRange {
+	return brigodier.StringRange{Start: int(r.Start), End: int(r.End)}
+}
+
+type tooltip string
+
+func (t tooltip) String() string { return string(t) }
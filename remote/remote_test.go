@@ -0,0 +1,67 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"go.minekube.com/brigodier"
+	"testing"
+)
+
+type adminKey struct{}
+
+func testDispatcher(executed *[]string) *brigodier.Dispatcher {
+	d := new(brigodier.Dispatcher)
+	cmd := brigodier.CommandFunc(func(c *brigodier.CommandContext) error {
+		*executed = append(*executed, c.Input)
+		return nil
+	})
+	d.Register(brigodier.Literal("say").Then(brigodier.Argument("message", brigodier.StringPhrase).Executes(cmd)))
+	d.Register(brigodier.Literal("stop").Executes(cmd).Requires(func(ctx context.Context) bool {
+		return ctx.Value(adminKey{}) != nil
+	}))
+	d.Register(brigodier.Literal("alias").Redirect(&d.Root))
+	return d
+}
+
+func TestServer_GetTree(t *testing.T) {
+	var executed []string
+	s := NewServer(testDispatcher(&executed))
+
+	tree, err := s.GetTree(context.TODO(), &GetTreeRequest{})
+	require.NoError(t, err)
+	root := tree.Nodes[tree.Root]
+	require.Equal(t, NodeRoot, root.Type)
+	require.Len(t, root.Children, 2) // stop is hidden
+
+	say := tree.Nodes[root.Children[0]]
+	require.Equal(t, "say", say.Name)
+	message := tree.Nodes[say.Children[0]]
+	require.Equal(t, Node{Type: NodeArgument, Name: "message", Parser: "brigadier:string",
+		Properties: brigodier.TypeProperties{"type": "greedy"}, Executable: true, Redirect: -1}, *message)
+	require.Equal(t, tree.Root, tree.Nodes[root.Children[1]].Redirect)
+
+	admin := context.WithValue(context.TODO(), adminKey{}, true)
+	tree, err = s.GetTree(admin, &GetTreeRequest{})
+	require.NoError(t, err)
+	require.Len(t, tree.Nodes[tree.Root].Children, 3)
+}
+
+func TestClient(t *testing.T) {
+	var executed []string
+	c := &Client{Service: NewServer(testDispatcher(&executed))}
+
+	suggestions, err := c.Suggest(context.TODO(), "sa")
+	require.NoError(t, err)
+	require.Len(t, suggestions.Suggestions, 1)
+	require.Equal(t, "say", suggestions.Suggestions[0].Text)
+
+	require.NoError(t, c.Execute(context.TODO(), "alias say hello world"))
+	require.Equal(t, []string{"alias say hello world"}, executed)
+
+	err = c.Execute(context.TODO(), "stop")
+	var execErr *ExecuteError
+	require.True(t, errors.As(err, &execErr))
+	require.True(t, execErr.SyntaxError)
+	require.Equal(t, 0, execErr.Cursor)
+}
@@ -0,0 +1,45 @@
+package brigodier
+
+// CopyInto adds a deep copy of the node to the parent and returns the copy,
+// so the subtree can be used under multiple parents and modified independently,
+// unlike adding the same node to multiple parents using AddChild.
+//
+// Redirects to nodes of the subtree point to the copied nodes, see CommandNode.Clone,
+// while redirects from outside the subtree keep pointing to the original nodes.
+// If the parent has a child with the same name, the copy is merged into it like by AddChild
+// and the existing child is returned.
+func CopyInto(parent, node CommandNode) CommandNode {
+	if node == nil {
+		return nil
+	}
+	clone := node.Clone(true)
+	parent.AddChild(clone)
+	return parent.Children()[clone.Name()]
+}
+
+// SharedNodes returns the nodes below root that were added to more than one
+// parent, in the order they are visited by WalkNode, e.g. to audit a command
+// tree for unintended structural sharing. See CopyInto.
+func SharedNodes(root CommandNode) []CommandNode {
+	parents := map[CommandNode]map[CommandNode]bool{}
+	var order []CommandNode
+	WalkNode(root, func(path []CommandNode, node CommandNode) bool {
+		parent := root
+		if len(path) != 0 {
+			parent = path[len(path)-1]
+		}
+		if parents[node] == nil {
+			parents[node] = map[CommandNode]bool{}
+			order = append(order, node)
+		}
+		parents[node][parent] = true
+		return true
+	})
+	var shared []CommandNode
+	for _, node := range order {
+		if len(parents[node]) > 1 {
+			shared = append(shared, node)
+		}
+	}
+	return shared
+}
@@ -0,0 +1,48 @@
+package brigodier
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCopyInto(t *testing.T) {
+	var d Dispatcher
+	var got []string
+	cmd := CommandFunc(func(c *CommandContext) error {
+		got = append(got, c.String("message"))
+		return nil
+	})
+	target := Argument("target", StringWord).Then(Argument("message", StringPhrase).Executes(cmd)).Build()
+	msg := d.Register(Literal("msg").ThenNode(target))
+	d.Register(Literal("tell").Redirect(msg))
+
+	// sharing the same node
+	d.Register(Literal("whisper").ThenNode(target))
+	require.Equal(t, []CommandNode{target}, SharedNodes(&d.Root))
+
+	// an independent copy
+	reply := d.Register(Literal("reply"))
+	copied := CopyInto(reply, target)
+	require.NotSame(t, target, copied)
+	require.Same(t, copied, d.FindNode("reply", "target"))
+	require.Equal(t, []CommandNode{target}, SharedNodes(&d.Root))
+
+	copied.AddChild(Literal("urgent").Executes(cmd).Build())
+	require.Nil(t, d.FindNode("msg", "target", "urgent"))
+	target.AddChild(Literal("later").Executes(cmd).Build())
+	require.NotNil(t, d.FindNode("whisper", "target", "later"))
+	require.Nil(t, d.FindNode("reply", "target", "later"))
+
+	// redirects from outside the copied subtree keep their target
+	require.NoError(t, d.Do(context.TODO(), "tell alice hi"))
+	require.NoError(t, d.Do(context.TODO(), "reply bob hey"))
+	require.Equal(t, []string{"hi", "hey"}, got)
+
+	// redirects within the copied subtree point to the copies
+	loop := Literal("loop").Build()
+	loop.AddChild(Literal("again").Redirect(loop).Build())
+	loopCopy := CopyInto(&d.Root, loop)
+	require.Same(t, loopCopy, d.FindNode("loop", "again").Redirect())
+	require.Nil(t, CopyInto(&d.Root, nil))
+}
@@ -0,0 +1,170 @@
+package brigodier
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrSnapshotMismatch is wrapped by a SnapshotMismatchError.
+var ErrSnapshotMismatch = errors.New("snapshot mismatch")
+
+// SnapshotMismatchError is returned by Dispatcher.CompareSnapshot
+// if the command tree differs from the golden file.
+type SnapshotMismatchError struct {
+	Path string // The path of the golden file.
+	// Diff lists the differing lines of the golden file prefixed
+	// by "-" and of the current tree prefixed by "+".
+	Diff string
+}
+
+// Unwrap implements errors.Unwrap.
+func (e *SnapshotMismatchError) Unwrap() error { return ErrSnapshotMismatch }
+func (e *SnapshotMismatchError) Error() string {
+	return fmt.Sprintf("%v: command tree differs from %s:\n%s", ErrSnapshotMismatch, e.Path, e.Diff)
+}
+
+// Snapshot returns a canonical text representation of the command tree below node,
+// e.g. to detect accidental changes of the tree in CI, see WriteSnapshot and CompareSnapshot.
+//
+// Each node is written on its own line indented by its depth and children are
+// sorted by name, so the output does not depend on the registration order:
+//
+//	teleport -> tp
+//	tp
+//	  [target] brigadier:string{type=word} requires
+//	    [y] brigadier:integer executes
+//
+// A line lists the literal or [argument] name, the parser and TypeProperties of
+// arguments as sent to clients, whether the node requires, executes, forks, and
+// its redirect target as path, "(root)" for the root or "?" outside the tree.
+// Requirements are not checked.
+func (d *Dispatcher) Snapshot(node CommandNode) string {
+	var b strings.Builder
+	d.snapshot(&b, node, 0, map[CommandNode]bool{node: true})
+	return b.String()
+}
+
+// snapshot writes the children of node. Ancestors are tracked in path
+// to not descend into a node that is its own descendant.
+func (d *Dispatcher) snapshot(b *strings.Builder, node CommandNode, depth int, path map[CommandNode]bool) {
+	children := make([]CommandNode, 0, len(node.Children()))
+	for _, child := range node.Children() {
+		children = append(children, child)
+	}
+	sort.Slice(children, func(i, j int) bool { return children[i].Name() < children[j].Name() })
+	for _, child := range children {
+		b.WriteString(strings.Repeat("  ", depth))
+		b.WriteString(d.snapshotLine(child))
+		if path[child] {
+			b.WriteString(" (cycle)\n")
+			continue
+		}
+		b.WriteByte('\n')
+		path[child] = true
+		d.snapshot(b, child, depth+1, path)
+		delete(path, child)
+	}
+}
+
+func (d *Dispatcher) snapshotLine(node CommandNode) string {
+	parts := []string{node.UsageText()}
+	if a, ok := node.(*ArgumentCommandNode); ok {
+		id, props := d.types().parserOf(a)
+		parts = append(parts, id+formatProperties(props))
+	}
+	if node.Requirement() != nil {
+		parts = append(parts, "requires")
+	}
+	if node.Command() != nil {
+		parts = append(parts, "executes")
+	}
+	if node.IsFork() {
+		parts = append(parts, "fork")
+	}
+	if target := node.Redirect(); target != nil {
+		path := "?"
+		if target == CommandNode(&d.Root) {
+			path = "(root)"
+		} else if p := d.Path(target); len(p) != 0 {
+			path = strings.Join(p, " ")
+		}
+		parts = append(parts, "->", path)
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatProperties formats the properties sorted by key, e.g. "{max=10,min=0}".
+func formatProperties(props TypeProperties) string {
+	if len(props) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for i, k := range keys {
+		keys[i] = fmt.Sprintf("%s=%v", k, props[k])
+	}
+	return "{" + strings.Join(keys, ",") + "}"
+}
+
+// WriteSnapshot writes the Snapshot of the command tree below node to
+// the golden file at path, creating its directory if necessary.
+func (d *Dispatcher) WriteSnapshot(path string, node CommandNode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(d.Snapshot(node)), 0o644)
+}
+
+// CompareSnapshot compares the Snapshot of the command tree below node
+// to the golden file at path written by WriteSnapshot.
+// It returns a *SnapshotMismatchError if they differ.
+func (d *Dispatcher) CompareSnapshot(path string, node CommandNode) error {
+	want, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	got := d.Snapshot(node)
+	if string(want) == got {
+		return nil
+	}
+	return &SnapshotMismatchError{Path: path, Diff: diffLines(string(want), got)}
+}
+
+// maxDiffLines is the maximum number of lines of a SnapshotMismatchError.Diff per side.
+const maxDiffLines = 20
+
+// diffLines returns the lines between the common prefix and suffix of want and got.
+func diffLines(want, got string) string {
+	w := strings.Split(strings.TrimSuffix(want, "\n"), "\n")
+	g := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	start := 0
+	for start < len(w) && start < len(g) && w[start] == g[start] {
+		start++
+	}
+	endW, endG := len(w), len(g)
+	for endW > start && endG > start && w[endW-1] == g[endG-1] {
+		endW--
+		endG--
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "@@ line %d @@\n", start+1)
+	write := func(prefix string, lines []string) {
+		for i, line := range lines {
+			if i == maxDiffLines {
+				fmt.Fprintf(&b, "%s... %d more lines\n", prefix, len(lines)-i)
+				return
+			}
+			b.WriteString(prefix + line + "\n")
+		}
+	}
+	write("-", w[start:endW])
+	write("+", g[start:endG])
+	return b.String()
+}
@@ -0,0 +1,58 @@
+package brigodier
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+)
+
+func TestDispatcher_Snapshot(t *testing.T) {
+	d := NewDispatcher()
+	tp := d.Register(Literal("tp").Then(
+		Argument("target", StringWord).
+			Requires(func(context.Context) bool { return true }).
+			Then(Argument("y", &Int32ArgumentType{Min: 0, Max: 10}).Executes(CommandFunc(func(*CommandContext) error { return nil })))))
+	d.Register(Literal("teleport").Redirect(tp))
+	d.Register(Literal("execute").Then(Literal("as").Fork(&d.Root, nil)))
+
+	require.Equal(t, `execute
+  as fork -> (root)
+teleport -> tp
+tp
+  [target] brigadier:string{type=word} requires
+    [y] brigadier:integer{max=10,min=0} executes
+`, d.Snapshot(&d.Root))
+	require.Equal(t, "[target] brigadier:string{type=word} requires\n  [y] brigadier:integer{max=10,min=0} executes\n", d.Snapshot(tp))
+
+	// registration order does not matter
+	other := NewDispatcher()
+	other.Register(Literal("b"))
+	other.Register(Literal("a"))
+	require.Equal(t, "a\nb\n", other.Snapshot(&other.Root))
+
+	// cycles are not followed
+	loop := Literal("loop").Build()
+	loop.AddChild(loop)
+	require.Equal(t, "loop (cycle)\n", other.Snapshot(loop))
+}
+
+func TestDispatcher_CompareSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "commands.golden")
+	d := NewDispatcher()
+	d.Register(Literal("a"))
+	d.Register(Literal("b").Then(Literal("c")))
+
+	require.Error(t, d.CompareSnapshot(path, &d.Root))
+	require.NoError(t, d.WriteSnapshot(path, &d.Root))
+	require.NoError(t, d.CompareSnapshot(path, &d.Root))
+
+	d.Register(Literal("b").Then(Literal("d").Executes(CommandFunc(func(*CommandContext) error { return nil }))))
+	err := d.CompareSnapshot(path, &d.Root)
+	require.True(t, errors.Is(err, ErrSnapshotMismatch))
+	var mismatch *SnapshotMismatchError
+	require.True(t, errors.As(err, &mismatch))
+	require.Equal(t, path, mismatch.Path)
+	require.Equal(t, "@@ line 4 @@\n+  d executes\n", mismatch.Diff)
+}
@@ -0,0 +1,63 @@
+package brigodier
+
+import "context"
+
+// Source is who executes a command, e.g. a player, the console or a remote client.
+//
+// The Source is carried in the context.Context passed to Dispatcher.Parse
+// using WithSource and swapped for redirects using SourceModifier,
+// similar to the source type parameter of Mojang's Brigadier.
+type Source interface {
+	// Name returns the display name of the source, e.g. used by audit logs.
+	Name() string
+}
+
+type sourceKey struct{}
+
+// WithSource returns a copy of ctx carrying the Source.
+func WithSource(ctx context.Context, src Source) context.Context {
+	return context.WithValue(ctx, sourceKey{}, src)
+}
+
+// SourceFrom returns the Source carried by ctx or nil.
+func SourceFrom(ctx context.Context) Source {
+	src, _ := ctx.Value(sourceKey{}).(Source)
+	return src
+}
+
+// SourceAs returns the Source carried by ctx as T
+// and whether there is a Source of type T.
+func SourceAs[T Source](ctx context.Context) (T, bool) {
+	src, ok := SourceFrom(ctx).(T)
+	return src, ok
+}
+
+// Source returns the Source executing the command or nil.
+func (c *CommandContext) Source() Source { return SourceFrom(c) }
+
+// SourceModifier returns a RedirectModifier swapping the Source
+// for the commands following the redirect, e.g.
+//
+//	d.Register(Literal("execute").Then(Literal("as").Then(
+//		Argument("player", StringWord).Fork(&d.Root, SourceModifier(
+//			func(c *CommandContext) (Source, error) { return findPlayer(c.String("player")) },
+//		)),
+//	)))
+func SourceModifier(fn func(c *CommandContext) (Source, error)) RedirectModifier {
+	return ModifierFunc(func(c *CommandContext) (context.Context, error) {
+		src, err := fn(c)
+		if err != nil {
+			return nil, err
+		}
+		return WithSource(c, src), nil
+	})
+}
+
+// RequireSource returns a RequireFn testing the Source of the context.Context.
+// The requirement is not met if there is no Source.
+func RequireSource(fn func(src Source) bool) RequireFn {
+	return func(ctx context.Context) bool {
+		src := SourceFrom(ctx)
+		return src != nil && fn(src)
+	}
+}
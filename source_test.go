@@ -0,0 +1,53 @@
+package brigodier
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type testSource struct {
+	name string
+	op   bool
+}
+
+func (s *testSource) Name() string { return s.name }
+
+func TestSource(t *testing.T) {
+	players := map[string]*testSource{"Alex": {name: "Alex"}}
+	errNotFound := errors.New("player not found")
+	var ran []string
+	var audited []string
+	d := NewDispatcher(WithAuditSink(AuditSinkFunc(func(e *AuditEntry) {
+		audited = append(audited, e.Principal)
+	}), nil))
+	d.Register(Literal("whoami").Executes(CommandFunc(func(c *CommandContext) error {
+		src, ok := SourceAs[*testSource](c)
+		require.True(t, ok)
+		ran = append(ran, src.Name())
+		return nil
+	})))
+	d.Register(Literal("op").
+		Requires(RequireSource(func(src Source) bool { return src.(*testSource).op })).
+		Then(Literal("as").Then(Argument("player", StringWord).
+			RedirectWithModifier(&d.Root, SourceModifier(func(c *CommandContext) (Source, error) {
+				p, ok := players[c.String("player")]
+				if !ok {
+					return nil, errNotFound
+				}
+				return p, nil
+			})))))
+
+	steve := WithSource(context.TODO(), &testSource{name: "Steve", op: true})
+	require.NoError(t, d.Do(steve, "whoami"))
+	require.NoError(t, d.Do(steve, "op as Alex whoami"))
+	require.ErrorIs(t, d.Do(steve, "op as Bob whoami"), errNotFound)
+	require.Equal(t, []string{"Steve", "Alex"}, ran)
+	require.Equal(t, []string{"Steve", "Steve", "Steve"}, audited)
+
+	alex := WithSource(context.TODO(), players["Alex"])
+	require.ErrorIs(t, d.Do(alex, "op as Alex whoami"), ErrDispatcherUnknownCommand)
+	require.False(t, d.Root.Children()["op"].CanUse(context.TODO()))
+	require.Nil(t, SourceFrom(context.TODO()))
+}
@@ -0,0 +1,101 @@
+package brigodier
+
+// TreeStats are statistics about a command tree returned by Dispatcher.Stats.
+//
+// Redirects are not followed and nodes reachable through
+// multiple parents are counted once.
+type TreeStats struct {
+	Nodes       int // The number of nodes without the root.
+	Literals    int // The number of LiteralCommandNode.
+	Arguments   int // The number of ArgumentCommandNode.
+	Redirects   int // The number of nodes with a redirect.
+	Executables int // The number of nodes with a Command.
+	MaxDepth    int // The number of nodes on the longest path below the root.
+	// Subtrees are the sizes of the registered commands
+	// by literal as returned by CommandNode.Count.
+	Subtrees map[string]int
+}
+
+// Stats returns statistics about the command tree, e.g. to monitor
+// the tree size or to pre-allocate buffers for serializing it.
+func (d *Dispatcher) Stats() TreeStats {
+	s := TreeStats{Subtrees: make(map[string]int, len(d.Root.Children()))}
+	seen := map[CommandNode]bool{}
+	var walk func(node CommandNode, depth int)
+	walk = func(node CommandNode, depth int) {
+		if depth > s.MaxDepth {
+			s.MaxDepth = depth
+		}
+		node.ChildrenOrdered().Range(func(_ string, child CommandNode) bool {
+			if seen[child] {
+				return true
+			}
+			seen[child] = true
+			s.Nodes++
+			switch child.(type) {
+			case *LiteralCommandNode:
+				s.Literals++
+			case *ArgumentCommandNode:
+				s.Arguments++
+			}
+			if child.Redirect() != nil {
+				s.Redirects++
+			}
+			if child.Command() != nil {
+				s.Executables++
+			}
+			walk(child, depth+1)
+			return true
+		})
+	}
+	walk(&d.Root, 0)
+	d.Root.ChildrenOrdered().Range(func(name string, child CommandNode) bool {
+		s.Subtrees[name] = child.Count()
+		return true
+	})
+	return s
+}
+
+// Count returns the number of nodes in the subtree of the node including itself.
+// Redirects are not followed and nodes reachable through multiple parents are counted once.
+func (n *Node) Count() int {
+	seen := map[CommandNode]bool{}
+	var count func(node childrenOrdered) int
+	count = func(node childrenOrdered) int {
+		c := 0
+		node.ChildrenOrdered().Range(func(_ string, child CommandNode) bool {
+			if !seen[child] {
+				seen[child] = true
+				c += 1 + count(child)
+			}
+			return true
+		})
+		return c
+	}
+	return 1 + count(n)
+}
+
+// Depth returns the number of nodes on the longest path below the node,
+// i.e. zero if the node has no children. Redirects are not followed.
+func (n *Node) Depth() int {
+	depths := map[CommandNode]int{}
+	var depth func(node childrenOrdered) int
+	depth = func(node childrenOrdered) int {
+		max := 0
+		node.ChildrenOrdered().Range(func(_ string, child CommandNode) bool {
+			d, ok := depths[child]
+			if !ok {
+				d = 1 + depth(child)
+				depths[child] = d
+			}
+			if d > max {
+				max = d
+			}
+			return true
+		})
+		return max
+	}
+	return depth(n)
+}
+
+type childrenOrdered interface{ ChildrenOrdered() StringCommandNodeMap }
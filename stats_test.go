@@ -0,0 +1,34 @@
+package brigodier
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDispatcher_Stats(t *testing.T) {
+	var d Dispatcher
+	cmd := CommandFunc(func(c *CommandContext) error { return nil })
+	shared := Argument("n", Int).Executes(cmd).BuildArgument()
+	foo := d.Register(Literal("foo").Executes(cmd).Then(
+		Literal("bar").Then(shared, Argument("s", String).Then(Literal("x").Executes(cmd))),
+		Literal("baz").Then(shared),
+	))
+	d.Register(Literal("alias").Redirect(foo))
+
+	s := d.Stats()
+	require.Equal(t, TreeStats{
+		Nodes:       7,
+		Literals:    5,
+		Arguments:   2,
+		Redirects:   1,
+		Executables: 3,
+		MaxDepth:    4,
+		Subtrees:    map[string]int{"foo": 6, "alias": 1},
+	}, s)
+
+	require.Equal(t, 6, foo.Count())
+	require.Equal(t, 3, foo.Depth())
+	require.Equal(t, 0, d.FindNode("alias").Depth())
+	require.Equal(t, 8, d.Root.Count())
+	require.Equal(t, 4, d.Root.Depth())
+}
@@ -0,0 +1,149 @@
+package brigodier
+
+import (
+	"container/list"
+	"sync"
+)
+
+// DynamicSuggestionProvider is a SuggestionProvider whose suggestions are not
+// cached by the suggestion cache enabled by WithSuggestionCache if Dynamic
+// returns true, e.g. because they depend on the CommandContext or change over time.
+type DynamicSuggestionProvider interface {
+	SuggestionProvider
+	Dynamic() bool
+}
+
+// Dynamic returns the provider as DynamicSuggestionProvider
+// to opt out of the suggestion cache, e.g.
+//
+//	Argument("player", String).Suggests(Dynamic(onlinePlayers))
+func Dynamic(provider SuggestionProvider) DynamicSuggestionProvider {
+	return &dynamicSuggestionProvider{provider}
+}
+
+type dynamicSuggestionProvider struct{ SuggestionProvider }
+
+func (*dynamicSuggestionProvider) Dynamic() bool { return true }
+
+// ClearSuggestionCache clears the suggestion cache enabled by WithSuggestionCache.
+//
+// Register clears the cache automatically, but it must be called
+// after otherwise modifying the command tree, e.g. using CommandNode.AddChild.
+func (d *Dispatcher) ClearSuggestionCache() {
+	if d.suggestionCache != nil {
+		d.suggestionCache.clear()
+	}
+}
+
+// cacheableSuggestions indicates whether the suggestions of the node may be cached.
+// Only arguments are cached since literal suggestions are cheap.
+func cacheableSuggestions(node CommandNode) bool {
+	a, ok := node.(*ArgumentCommandNode)
+	if !ok {
+		return false
+	}
+	var provider interface{} = a.customSuggestions
+	if a.customSuggestions == nil {
+		provider = a.argType
+	}
+	if p, ok := provider.(DynamicSuggestionProvider); ok && p.Dynamic() {
+		return false
+	}
+	return true
+}
+
+// suggestionCache is a least recently used cache of the Suggestions
+// provided by a node for the remaining input of a SuggestionsBuilder.
+// The suggestion ranges are stored relative to SuggestionsBuilder.Start.
+type suggestionCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // of *suggestionCacheEntry, most recently used first
+	entries map[suggestionCacheKey]*list.Element
+}
+
+type suggestionCacheKey struct {
+	node      CommandNode
+	remaining string
+}
+
+type suggestionCacheEntry struct {
+	key         suggestionCacheKey
+	suggestions *Suggestions
+}
+
+func newSuggestionCache(size int) *suggestionCache {
+	return &suggestionCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[suggestionCacheKey]*list.Element, size),
+	}
+}
+
+// provide returns the cached suggestions of node for the builder
+// or caches the suggestions returned by the provide function.
+// It reports whether the suggestions were cached.
+func (c *suggestionCache) provide(node CommandNode, builder *SuggestionsBuilder, provide func() *Suggestions) (*Suggestions, bool) {
+	key := suggestionCacheKey{node: node, remaining: builder.Remaining}
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if ok {
+		c.order.MoveToFront(e)
+		s := e.Value.(*suggestionCacheEntry).suggestions
+		c.mu.Unlock()
+		return shiftSuggestions(s, builder.Start), true
+	}
+	c.mu.Unlock()
+
+	s := provide()
+	// Only suggestions for the remaining input are independent of the preceding input.
+	for _, suggestion := range s.Suggestions {
+		if suggestion.Range.Start < builder.Start {
+			return s, false
+		}
+	}
+	entry := &suggestionCacheEntry{key: key, suggestions: shiftSuggestions(s, -builder.Start)}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		e.Value = entry
+		c.order.MoveToFront(e)
+		return s, false
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*suggestionCacheEntry).key)
+	}
+	return s, false
+}
+
+func (c *suggestionCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	c.entries = make(map[suggestionCacheKey]*list.Element, c.size)
+}
+
+// shiftSuggestions returns a copy of the suggestions with their ranges shifted by offset.
+func shiftSuggestions(s *Suggestions, offset int) *Suggestions {
+	if len(s.Suggestions) == 0 {
+		return emptySuggestions
+	}
+	shift := func(r StringRange) StringRange {
+		return StringRange{Start: r.Start + offset, End: r.End + offset}
+	}
+	shifted := &Suggestions{
+		Range:       shift(s.Range),
+		Suggestions: make([]*Suggestion, len(s.Suggestions)),
+	}
+	for i, suggestion := range s.Suggestions {
+		shifted.Suggestions[i] = &Suggestion{
+			Range:   shift(suggestion.Range),
+			Text:    suggestion.Text,
+			Tooltip: suggestion.Tooltip,
+		}
+	}
+	return shifted
+}
@@ -0,0 +1,89 @@
+package brigodier
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type suggestionProviderFunc func(*CommandContext, *SuggestionsBuilder) *Suggestions
+
+func (f suggestionProviderFunc) Suggestions(c *CommandContext, b *SuggestionsBuilder) *Suggestions {
+	return f(c, b)
+}
+
+func TestWithSuggestionCache(t *testing.T) {
+	var calls, dynamicCalls int
+	players := suggestionProviderFunc(func(_ *CommandContext, b *SuggestionsBuilder) *Suggestions {
+		calls++
+		return b.Suggest("alice").Suggest("bob").Build()
+	})
+	online := suggestionProviderFunc(func(_ *CommandContext, b *SuggestionsBuilder) *Suggestions {
+		dynamicCalls++
+		return b.Suggest("carol").Build()
+	})
+	d := NewDispatcher(WithSuggestionCache(8))
+	d.Register(Literal("tp").Then(Argument("player", StringWord).Suggests(players)))
+	d.Register(Literal("execute").Then(Literal("tp").Then(Argument("player", StringWord).Suggests(players))))
+	d.Register(Literal("msg").Then(Argument("player", StringWord).Suggests(Dynamic(online))))
+
+	suggest := func(input string) *Suggestions {
+		s, err := d.CompletionSuggestions(d.Parse(context.TODO(), input))
+		require.NoError(t, err)
+		return s
+	}
+
+	for i := 0; i < 3; i++ {
+		s := suggest("tp ")
+		require.Equal(t, StringRange{Start: 3, End: 3}, s.Range)
+		require.Len(t, s.Suggestions, 2)
+		require.Equal(t, "alice", s.Suggestions[0].Text)
+		require.Equal(t, StringRange{Start: 3, End: 3}, s.Suggestions[0].Range)
+	}
+	require.Equal(t, 1, calls)
+
+	s := suggest("execute tp ")
+	require.Equal(t, StringRange{Start: 11, End: 11}, s.Suggestions[1].Range)
+	require.Equal(t, 2, calls) // other node
+
+	for i := 0; i < 3; i++ {
+		require.Len(t, suggest("msg ").Suggestions, 1)
+	}
+	require.Equal(t, 3, dynamicCalls)
+
+	d.Register(Literal("foo"))
+	suggest("tp ")
+	require.Equal(t, 3, calls)
+}
+
+type cacheRecorder struct {
+	lookups []string
+}
+
+func (r *cacheRecorder) StartParse(context.Context, string) func(*ParseResults) {
+	return func(*ParseResults) {}
+}
+func (r *cacheRecorder) StartExecute(*ParseResults) func(error)   { return func(error) {} }
+func (r *cacheRecorder) StartCommand(*CommandContext) func(error) { return func(error) {} }
+func (r *cacheRecorder) StartSuggest(*ParseResults, int) func(*Suggestions, error) {
+	return func(*Suggestions, error) {}
+}
+func (r *cacheRecorder) CacheLookup(cache string, hit bool) {
+	r.lookups = append(r.lookups, fmt.Sprint(cache, " ", hit))
+}
+
+func TestCacheInstrumentation(t *testing.T) {
+	r := &cacheRecorder{}
+	d := NewDispatcher(WithSuggestionCache(8), WithParseCache(8), WithInstrumentation(r))
+	d.Register(Literal("tp").Then(Argument("player", StringWord).Suggests(suggestionProviderFunc(
+		func(_ *CommandContext, b *SuggestionsBuilder) *Suggestions { return b.Suggest("alice").Build() }))))
+	for i := 0; i < 2; i++ {
+		_, err := d.CompletionSuggestions(d.Parse(context.TODO(), "tp "))
+		require.NoError(t, err)
+	}
+	require.Equal(t, []string{
+		"parse false", "suggestion false",
+		"parse true", "suggestion true",
+	}, r.lookups)
+}
@@ -60,11 +60,24 @@ type (
 		Remaining          string
 		RemainingLowerCase string
 		Result             []*Suggestion
+		// Quote optionally escapes or quotes suggested texts, e.g. to
+		// suggest values containing spaces for a QuotablePhase argument.
+		// It is set for arguments whose type implements SuggestionQuoter.
+		Quote func(text string) string
 	}
 )
 
+// SuggestionQuoter can optionally be implemented by an ArgumentType
+// to quote suggested texts so that they parse as a single argument.
+type SuggestionQuoter interface {
+	QuoteSuggestion(text string) string
+}
+
 // Suggest adds a suggestion to the builder.
 func (b *SuggestionsBuilder) Suggest(text string) *SuggestionsBuilder {
+	if b.Quote != nil {
+		text = b.Quote(text)
+	}
 	if text != b.Remaining {
 		b.Result = append(b.Result, &Suggestion{
 			Range: StringRange{Start: b.Start, End: len(b.Input)},
@@ -74,6 +87,19 @@ func (b *SuggestionsBuilder) Suggest(text string) *SuggestionsBuilder {
 	return b
 }
 
+// CreateOffset returns a new SuggestionsBuilder for the same input beginning at start.
+// It is useful for argument types suggesting only for a part of their input.
+func (b *SuggestionsBuilder) CreateOffset(start int) *SuggestionsBuilder {
+	return &SuggestionsBuilder{
+		Input:              b.Input,
+		InputLowerCase:     b.InputLowerCase,
+		Start:              start,
+		Remaining:          b.Input[start:],
+		RemainingLowerCase: b.InputLowerCase[start:],
+		Quote:              b.Quote,
+	}
+}
+
 // Build returns a Suggestions build from the builder.
 func (b *SuggestionsBuilder) Build() *Suggestions { return CreateSuggestion(b.Input, b.Result) }
 
@@ -92,7 +118,11 @@ func (d *Dispatcher) CompletionSuggestions(parse *ParseResults) (*Suggestions, e
 // CompletionSuggestionsCursor gets suggestions for a parsed input
 // string on what comes next with a cursor to begin suggesting at.
 // See CompletionSuggestions for details.
-func (d *Dispatcher) CompletionSuggestionsCursor(parse *ParseResults, cursor int) (*Suggestions, error) {
+func (d *Dispatcher) CompletionSuggestionsCursor(parse *ParseResults, cursor int) (result *Suggestions, err error) {
+	if d.Instrumentation != nil {
+		end := d.Instrumentation.StartSuggest(parse, cursor)
+		defer func() { end(result, err) }()
+	}
 	ctx := parse.Context
 
 	nodeBeforeCursor, err := ctx.FindSuggestionContext(cursor)
@@ -105,22 +135,73 @@ func (d *Dispatcher) CompletionSuggestionsCursor(parse *ParseResults, cursor int
 	fullInput := parse.Reader.String
 	truncatedInput := fullInput[:cursor]
 	truncatedInputLowerCase := strings.ToLower(truncatedInput)
-	suggestions := make([]*Suggestions, 0, len(parent.Children()))
-	parent.ChildrenOrdered().Range(func(_ string, node CommandNode) bool {
-		if !CanProvideSuggestions(node) {
-			return true
-		}
-		suggestions = append(suggestions, ProvideSuggestions(node, ctx.build(truncatedInput), &SuggestionsBuilder{
+	var suggestions []*Suggestions
+	provide := func(node CommandNode) {
+		builder := &SuggestionsBuilder{
 			Input:              truncatedInput,
 			InputLowerCase:     truncatedInputLowerCase,
 			Start:              start,
 			Remaining:          truncatedInput[start:],
 			RemainingLowerCase: truncatedInputLowerCase[start:],
-		}))
-		return true
-	})
+		}
+		if a, ok := node.(*ArgumentCommandNode); ok {
+			if q, ok := a.argType.(SuggestionQuoter); ok {
+				builder.Quote = q.QuoteSuggestion
+			}
+		}
+		if d.suggestionCache != nil && cacheableSuggestions(node) {
+			s, hit := d.suggestionCache.provide(node, builder, func() *Suggestions {
+				return ProvideSuggestions(node, ctx.build(truncatedInput), builder)
+			})
+			d.observeCache(CacheSuggestion, hit)
+			suggestions = append(suggestions, s)
+			return
+		}
+		suggestions = append(suggestions, ProvideSuggestions(node, ctx.build(truncatedInput), builder))
+	}
+	for _, argument := range parent.ArgumentsOrdered() {
+		provide(argument)
+	}
+	// Only look up the literals matching the remaining input.
+	for _, literal := range parent.LiteralsWithPrefix(truncatedInputLowerCase[start:]) {
+		provide(literal)
+	}
+
+	result = MergeSuggestions(fullInput, suggestions)
+	if d.SnapSuggestionsToToken {
+		result = snapSuggestions(result, fullInput, cursor)
+	}
+	return result, nil
+}
 
-	return MergeSuggestions(fullInput, suggestions), nil
+// snapSuggestions returns a copy of the suggestions with the ranges ending
+// at cursor extended to the end of the token under the cursor.
+func snapSuggestions(s *Suggestions, input string, cursor int) *Suggestions {
+	end := cursor
+	for end < len(input) && rune(input[end]) != ArgumentSeparator {
+		end++
+	}
+	if end == cursor || len(s.Suggestions) == 0 {
+		return s
+	}
+	snap := func(r StringRange) StringRange {
+		if r.End == cursor {
+			r.End = end
+		}
+		return r
+	}
+	snapped := &Suggestions{
+		Range:       snap(s.Range),
+		Suggestions: make([]*Suggestion, len(s.Suggestions)),
+	}
+	for i, suggestion := range s.Suggestions {
+		snapped.Suggestions[i] = &Suggestion{
+			Range:   snap(suggestion.Range),
+			Text:    suggestion.Text,
+			Tooltip: suggestion.Tooltip,
+		}
+	}
+	return snapped
 }
 
 // MergeSuggestions merges multiple Suggestions into one.
@@ -173,6 +254,28 @@ func (s *Suggestion) compareToIgnoreCase(other *Suggestion) bool {
 	return strings.EqualFold(s.Text, other.Text)
 }
 
+// Apply returns the input with the range of the suggestion replaced by its text.
+func (s *Suggestion) Apply(input string) string {
+	return applySuggestion(input, s.Range, s.Text)
+}
+
+// Apply returns the original input with the range of the Suggestions replaced
+// by the text of the chosen suggestion, which is one of the Suggestions.
+//
+// Unlike Suggestion.Apply it uses the range of the Suggestions that
+// the texts of all merged suggestions were expanded to.
+func (s *Suggestions) Apply(original string, chosen *Suggestion) string {
+	return applySuggestion(original, s.Range, chosen.Text)
+}
+
+func applySuggestion(input string, r StringRange, text string) string {
+	start, end := min(r.Start, len(input)), min(r.End, len(input))
+	if start == 0 && end == len(input) {
+		return text
+	}
+	return input[:start] + text + input[end:]
+}
+
 // Expand expands a command suggestion if appropriate.
 func (s *Suggestion) Expand(command string, strRange *StringRange) *Suggestion {
 	if *strRange == s.Range {
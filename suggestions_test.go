@@ -147,6 +147,25 @@ func TestDispatcher_CompletionSuggestions_MovingCursor_SubCommands(t *testing.T)
 	testSuggestions(t, d, "parent_one faz ", 15, StringRange{})
 }
 
+func TestDispatcher_CompletionSuggestions_SnapSuggestionsToToken(t *testing.T) {
+	d := NewDispatcher(WithSnapSuggestionsToToken())
+	d.Register(Literal("parent_one").Then(
+		Literal("faz"),
+		Literal("fbz"),
+		Literal("gaz"),
+	))
+	d.Register(Literal("parent_two"))
+
+	testSuggestions(t, d, "parent_one faz ", 1, StringRange{0, 10}, "parent_one", "parent_two")
+	testSuggestions(t, d, "parent_one faz ", 12, StringRange{11, 14}, "faz", "fbz")
+	testSuggestions(t, d, "parent_one faz ", 13, StringRange{11, 14}, "faz")
+	testSuggestions(t, d, "parent_one faz ", 15, StringRange{})
+
+	result, err := d.CompletionSuggestionsCursor(d.Parse(context.TODO(), "parent_one fbz"), 12)
+	require.NoError(t, err)
+	require.Equal(t, StringRange{11, 14}, result.Suggestions[0].Range)
+}
+
 func TestDispatcher_CompletionSuggestions_SubCommands_Partial(t *testing.T) {
 	var d Dispatcher
 	parent := Literal("parent")
@@ -323,3 +342,50 @@ func TestDispatcher_CompletionSuggestions_Execute_Simulation_Partial(t *testing.
 		require.Equal(t, expectedRange, result.Suggestions[i].Range)
 	}
 }
+
+func TestSuggestions_Apply(t *testing.T) {
+	s := &Suggestion{Range: StringRange{Start: 3, End: 5}, Text: "alice"}
+	require.Equal(t, "tp alice", s.Apply("tp al"))
+	require.Equal(t, "tp alice bob", s.Apply("tp al bob"))
+	require.Equal(t, "alice", (&Suggestion{Range: StringRange{End: 2}, Text: "alice"}).Apply("al"))
+	require.Equal(t, "tp alice", (&Suggestion{Range: StringRange{Start: 3, End: 3}, Text: "alice"}).Apply("tp "))
+
+	d := new(Dispatcher)
+	d.Register(Literal("parent_one").Then(Literal("faz"), Literal("fbz")))
+	input := "parent_one f"
+	result, err := d.CompletionSuggestions(d.Parse(context.TODO(), input))
+	require.NoError(t, err)
+	require.Len(t, result.Suggestions, 2)
+	for _, suggestion := range result.Suggestions {
+		require.Equal(t, "parent_one "+suggestion.Text, result.Apply(input, suggestion))
+	}
+}
+
+func TestDispatcher_CompletionSuggestions_Quoted(t *testing.T) {
+	names := suggestionProviderFunc(func(_ *CommandContext, b *SuggestionsBuilder) *Suggestions {
+		return b.Suggest("Steve").Suggest("New Player").Suggest(`Say "hi"`).Build()
+	})
+	var got string
+	d := &Dispatcher{}
+	d.Register(Literal("msg").Then(Argument("name", String).Suggests(names).Executes(CommandFunc(func(c *CommandContext) error {
+		got = c.String("name")
+		return nil
+	}))))
+	d.Register(Literal("word").Then(Argument("name", StringWord).Suggests(names)))
+
+	s, err := d.CompletionSuggestions(d.Parse(context.TODO(), "msg "))
+	require.NoError(t, err)
+	var texts []string
+	for _, suggestion := range s.Suggestions {
+		texts = append(texts, suggestion.Text)
+		require.NoError(t, d.Do(context.TODO(), suggestion.Apply("msg ")))
+		require.Equal(t, map[string]string{
+			"Steve": "Steve", `"New Player"`: "New Player", `"Say \"hi\""`: `Say "hi"`,
+		}[suggestion.Text], got)
+	}
+	require.ElementsMatch(t, []string{"Steve", `"New Player"`, `"Say \"hi\""`}, texts)
+
+	s, err = d.CompletionSuggestions(d.Parse(context.TODO(), "word "))
+	require.NoError(t, err)
+	require.Contains(t, []string{s.Suggestions[0].Text, s.Suggestions[1].Text, s.Suggestions[2].Text}, "New Player")
+}
@@ -0,0 +1,30 @@
+package brigodier
+
+// NodesByTag returns all nodes of the command tree having the tag,
+// e.g. to group related commands in a help system or permission manager.
+//
+// The tree is traversed depth-first in registration order without following redirects
+// and each node is returned at most once.
+func (d *Dispatcher) NodesByTag(tag string) []CommandNode {
+	var nodes []CommandNode
+	seen := map[CommandNode]bool{}
+	d.Walk(func(_ []CommandNode, node CommandNode) bool {
+		if !seen[node] {
+			seen[node] = true
+			if node.HasTag(tag) {
+				nodes = append(nodes, node)
+			}
+		}
+		return true
+	})
+	return nodes
+}
+
+func containsString(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
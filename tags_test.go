@@ -0,0 +1,31 @@
+package brigodier
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDispatcher_NodesByTag(t *testing.T) {
+	var d Dispatcher
+	tp := d.Register(Literal("tp").Tags("admin", "teleport", "admin").
+		Then(Argument("target", StringWord).Tags("teleport")))
+	d.Register(Literal("home").Tags("teleport"))
+	d.Register(Literal("ban").Tags("admin"))
+	d.Register(Literal("back").Redirect(tp))
+
+	require.Equal(t, []string{"admin", "teleport"}, tp.Tags())
+	require.True(t, tp.HasTag("admin"))
+	require.False(t, tp.HasTag("moderation"))
+
+	names := func(nodes []CommandNode) (s []string) {
+		for _, n := range nodes {
+			s = append(s, n.Name())
+		}
+		return s
+	}
+	require.Equal(t, []string{"tp", "target", "home"}, names(d.NodesByTag("teleport")))
+	require.Equal(t, []string{"tp", "ban"}, names(d.NodesByTag("admin")))
+	require.Empty(t, d.NodesByTag("moderation"))
+
+	require.Equal(t, tp.Tags(), tp.CreateBuilder().Build().Tags())
+}
@@ -0,0 +1,30 @@
+package brigodier
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrCommandTimeout indicates that a command ran longer than its execution timeout.
+// The returned error is a *CommandTimeoutError matching ErrCommandTimeout.
+var ErrCommandTimeout = errors.New("command timed out")
+
+// CommandTimeoutError is returned by Dispatcher.Execute if a command
+// ran longer than the Dispatcher.ExecuteTimeout or node timeout.
+type CommandTimeoutError struct {
+	Timeout time.Duration // The exceeded timeout.
+	Err     error         // The optional error returned by the command.
+}
+
+// Is implements errors.Is and matches ErrCommandTimeout.
+func (e *CommandTimeoutError) Is(target error) bool { return target == ErrCommandTimeout }
+
+// Unwrap implements errors.Unwrap and returns the error returned by the command.
+func (e *CommandTimeoutError) Unwrap() error { return e.Err }
+func (e *CommandTimeoutError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s after %s: %v", ErrCommandTimeout, e.Timeout, e.Err)
+	}
+	return fmt.Sprintf("%s after %s", ErrCommandTimeout, e.Timeout)
+}
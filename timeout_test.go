@@ -0,0 +1,77 @@
+package brigodier
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestDispatcher_Execute_Timeout(t *testing.T) {
+	d := NewDispatcher(WithExecuteTimeout(10 * time.Millisecond))
+	errAborted := errors.New("aborted")
+	d.Register(Literal("slow").Executes(CommandFunc(func(c *CommandContext) error {
+		<-c.Done()
+		return errAborted
+	})))
+	d.Register(Literal("fast").Executes(CommandFunc(func(c *CommandContext) error { return nil })))
+
+	err := d.Do(context.TODO(), "slow")
+	require.ErrorIs(t, err, ErrCommandTimeout)
+	require.ErrorIs(t, err, errAborted)
+	var tErr *CommandTimeoutError
+	require.True(t, errors.As(err, &tErr))
+	require.Equal(t, 10*time.Millisecond, tErr.Timeout)
+
+	require.NoError(t, d.Do(context.TODO(), "fast"))
+}
+
+func TestDispatcher_Execute_NodeTimeout(t *testing.T) {
+	var d Dispatcher
+	var deadline bool
+	d.Register(Literal("cmd").Timeout(time.Hour).Then(
+		Literal("sub").Executes(CommandFunc(func(c *CommandContext) error {
+			_, deadline = c.Deadline()
+			return nil
+		})),
+	))
+
+	require.NoError(t, d.Do(context.TODO(), "cmd sub"))
+	require.True(t, deadline)
+}
+
+func TestDispatcher_Execute_NodeTimeoutRedirect(t *testing.T) {
+	d := NewDispatcher(WithExecuteTimeout(time.Hour))
+	var timeout time.Duration
+	cmd := CommandFunc(func(c *CommandContext) error {
+		deadline, _ := c.Deadline()
+		timeout = time.Until(deadline)
+		return nil
+	})
+	d.Register(Literal("say").Executes(cmd))
+	d.Register(Literal("quick").Timeout(time.Second).Executes(cmd))
+	execute := d.Register(Literal("execute").Timeout(time.Minute))
+	execute.AddChild(Literal("run").Redirect(&d.Root).Build())
+
+	require.NoError(t, d.Do(context.TODO(), "execute run say"))
+	require.LessOrEqual(t, timeout, time.Minute)
+	require.Greater(t, timeout, time.Second)
+
+	// the nearest timeout wins
+	require.NoError(t, d.Do(context.TODO(), "execute run quick"))
+	require.LessOrEqual(t, timeout, time.Second)
+}
+
+func TestDispatcher_Execute_ParentCanceled(t *testing.T) {
+	var d Dispatcher
+	d.Register(Literal("cmd").Timeout(time.Hour).Executes(CommandFunc(func(c *CommandContext) error {
+		return c.Err()
+	})))
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	err := d.Do(ctx, "cmd")
+	require.ErrorIs(t, err, context.Canceled)
+	require.NotErrorIs(t, err, ErrCommandTimeout)
+}
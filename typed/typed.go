@@ -0,0 +1,126 @@
+// Package typed provides a generic API on top of brigodier where the type S
+// of the command source is checked at compile time rather than asserted
+// from the brigodier.Source carried by the context.Context.
+//
+// The source is carried using brigodier.WithSource, so it is also seen by
+// untyped code like brigodier.CommandContext.Source, RequireSource,
+// SourceModifier and audit logs.
+//
+// Commands, requirements and redirect modifiers are adapted to the
+// brigodier API, so typed and untyped nodes can be mixed in one tree:
+//
+//	d := typed.New[*Player](brigodier.NewDispatcher())
+//	d.Register(brigodier.Literal("heal").
+//		Requires(typed.Requires(func(p *Player) bool { return p.Op })).
+//		Executes(typed.Executes(func(ctx context.Context, p *Player, c *brigodier.CommandContext) error {
+//			p.Health = 20
+//			return nil
+//		})))
+//	err := d.Do(ctx, player, "heal")
+package typed
+
+import (
+	"context"
+	"errors"
+	"go.minekube.com/brigodier"
+)
+
+// Dispatcher is a brigodier.Dispatcher executing commands for sources of type S.
+type Dispatcher[S brigodier.Source] struct {
+	*brigodier.Dispatcher
+}
+
+// New returns a Dispatcher for sources of type S wrapping d.
+func New[S brigodier.Source](d *brigodier.Dispatcher) *Dispatcher[S] {
+	return &Dispatcher[S]{Dispatcher: d}
+}
+
+// Parse parses the input executed by src, see brigodier.Dispatcher.Parse.
+func (d *Dispatcher[S]) Parse(ctx context.Context, src S, input string) *brigodier.ParseResults {
+	return d.Dispatcher.Parse(WithSource(ctx, src), input)
+}
+
+// Do parses and executes the input for src, see brigodier.Dispatcher.Do.
+func (d *Dispatcher[S]) Do(ctx context.Context, src S, input string) error {
+	return d.Dispatcher.Do(WithSource(ctx, src), input)
+}
+
+// CompletionSuggestions returns the suggestions of the input for src,
+// see brigodier.Dispatcher.CompletionSuggestions.
+func (d *Dispatcher[S]) CompletionSuggestions(ctx context.Context, src S, input string) (*brigodier.Suggestions, error) {
+	return d.Dispatcher.CompletionSuggestions(d.Parse(ctx, src, input))
+}
+
+// Command is a command executed for a source of type S.
+type Command[S brigodier.Source] interface {
+	Run(ctx context.Context, src S, c *brigodier.CommandContext) error
+}
+
+// CommandFunc is a convenient function type implementing the Command interface.
+type CommandFunc[S brigodier.Source] func(ctx context.Context, src S, c *brigodier.CommandContext) error
+
+// Run implements Command.
+func (f CommandFunc[S]) Run(ctx context.Context, src S, c *brigodier.CommandContext) error {
+	return f(ctx, src, c)
+}
+
+// RequireFn tests whether a source of type S can use a node.
+type RequireFn[S brigodier.Source] func(src S) bool
+
+// ErrNoSource is returned by adapted commands and modifiers
+// executed without a source of type S.
+var ErrNoSource = errors.New("typed: no source of the expected type")
+
+// WithSource returns a copy of ctx carrying the source of type S,
+// see brigodier.WithSource.
+func WithSource[S brigodier.Source](ctx context.Context, src S) context.Context {
+	return brigodier.WithSource(ctx, src)
+}
+
+// SourceFrom returns the source carried by ctx as type S
+// and whether there is a source of type S, see brigodier.SourceAs.
+func SourceFrom[S brigodier.Source](ctx context.Context) (S, bool) {
+	return brigodier.SourceAs[S](ctx)
+}
+
+// Executes adapts a function to a brigodier.Command, see Adapt.
+func Executes[S brigodier.Source](fn func(ctx context.Context, src S, c *brigodier.CommandContext) error) brigodier.Command {
+	return Adapt[S](CommandFunc[S](fn))
+}
+
+// Adapt adapts a Command to a brigodier.Command.
+// The command returns ErrNoSource if executed without a source of type S.
+func Adapt[S brigodier.Source](cmd Command[S]) brigodier.Command {
+	return brigodier.CommandFunc(func(c *brigodier.CommandContext) error {
+		src, ok := SourceFrom[S](c)
+		if !ok {
+			return ErrNoSource
+		}
+		return cmd.Run(c, src, c)
+	})
+}
+
+// Requires adapts a RequireFn to a brigodier.RequireFn.
+// The requirement is not met without a source of type S.
+func Requires[S brigodier.Source](fn RequireFn[S]) brigodier.RequireFn {
+	return func(ctx context.Context) bool {
+		src, ok := SourceFrom[S](ctx)
+		return ok && fn(src)
+	}
+}
+
+// Modifier returns a brigodier.RedirectModifier swapping
+// the source of type S for the commands following the redirect.
+func Modifier[S brigodier.Source](fn func(c *brigodier.CommandContext, src S) (S, error)) brigodier.RedirectModifier {
+	return brigodier.ModifierFunc(func(c *brigodier.CommandContext) (context.Context, error) {
+		src, ok := SourceFrom[S](c)
+		if !ok {
+			return nil, ErrNoSource
+		}
+		next, err := fn(c, src)
+		if err != nil {
+			return nil, err
+		}
+		return WithSource(c, next), nil
+	})
+}
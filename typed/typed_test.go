@@ -0,0 +1,72 @@
+package typed
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"go.minekube.com/brigodier"
+	"testing"
+)
+
+type player struct {
+	name   string
+	op     bool
+	health int
+}
+
+func (p *player) Name() string { return p.name }
+
+func TestDispatcher(t *testing.T) {
+	players := map[string]*player{"Alex": {name: "Alex", health: 5}}
+	d := New[*player](brigodier.NewDispatcher())
+	d.Register(brigodier.Literal("heal").
+		Requires(Requires(func(p *player) bool { return p.op })).
+		Executes(Executes(func(ctx context.Context, p *player, c *brigodier.CommandContext) error {
+			p.health = 20
+			return nil
+		})))
+	d.Register(brigodier.Literal("heal").Then(brigodier.Literal("as").Then(
+		brigodier.Argument("player", brigodier.StringWord).
+			RedirectWithModifier(&d.Root, Modifier(func(c *brigodier.CommandContext, _ *player) (*player, error) {
+				return players[c.String("player")], nil
+			})))))
+
+	steve := &player{name: "Steve", op: true, health: 1}
+	require.NoError(t, d.Do(context.TODO(), steve, "heal"))
+	require.Equal(t, 20, steve.health)
+
+	require.NoError(t, d.Do(context.TODO(), steve, "heal as Alex heal"))
+	require.Equal(t, 20, players["Alex"].health)
+
+	require.ErrorIs(t, d.Do(context.TODO(), players["Alex"], "heal"), brigodier.ErrDispatcherUnknownCommand)
+	require.ErrorIs(t, d.Dispatcher.Do(context.TODO(), "heal"), brigodier.ErrDispatcherUnknownCommand)
+
+	suggestions, err := d.CompletionSuggestions(context.TODO(), steve, "heal ")
+	require.NoError(t, err)
+	require.Len(t, suggestions.Suggestions, 1)
+
+	d.Register(brigodier.Literal("ping").Executes(Executes(func(context.Context, *player, *brigodier.CommandContext) error {
+		return nil
+	})))
+	require.ErrorIs(t, d.Dispatcher.Do(context.TODO(), "ping"), ErrNoSource)
+}
+
+func TestSource_Untyped(t *testing.T) {
+	d := New[*player](brigodier.NewDispatcher())
+	var got brigodier.Source
+	d.Register(brigodier.Literal("whoami").Executes(brigodier.CommandFunc(func(c *brigodier.CommandContext) error {
+		got = c.Source()
+		return nil
+	})))
+	d.Register(brigodier.Literal("heal").Executes(Executes(func(_ context.Context, p *player, _ *brigodier.CommandContext) error {
+		got = p
+		return nil
+	})))
+
+	steve := &player{name: "Steve"}
+	require.NoError(t, d.Do(context.TODO(), steve, "whoami"))
+	require.Same(t, steve, got)
+
+	alex := &player{name: "Alex"}
+	require.NoError(t, d.Dispatcher.Do(brigodier.WithSource(context.TODO(), alex), "heal"))
+	require.Same(t, alex, got)
+}
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // Builtin argument types.
@@ -31,6 +33,19 @@ var (
 	// Int is an alias of Int32.
 	Int = Int32
 
+	// Uint32 argument type.
+	Uint32 ArgumentType = &Uint32ArgumentType{
+		Min: MinUint32,
+		Max: MaxUint32,
+	}
+	// Uint64 argument type.
+	Uint64 ArgumentType = &Uint64ArgumentType{
+		Min: MinUint64,
+		Max: MaxUint64,
+	}
+	// Uint is an alias of Uint32.
+	Uint = Uint32
+
 	// Float32 argument type.
 	Float32 ArgumentType = &Float32ArgumentType{
 		Min: MinFloat32,
@@ -49,6 +64,10 @@ const (
 	MaxInt32   = math.MaxInt32
 	MinInt64   = math.MinInt32
 	MaxInt64   = math.MaxInt64
+	MinUint32  = 0
+	MaxUint32  = math.MaxUint32
+	MinUint64  = 0
+	MaxUint64  = math.MaxUint64
 	MinFloat32 = -math.MaxFloat32
 	MaxFloat32 = math.MaxFloat32
 	MinFloat64 = -math.MaxFloat64
@@ -62,6 +81,28 @@ type ArgumentType interface {
 	String() string // String returns the name of the type.
 }
 
+// MultiTokenArgumentType is implemented by an ArgumentType that consumes
+// the ArgumentSeparators between its tokens, e.g. a Vec3 parsing "1 2 3".
+//
+// Like any argument, it must end at an ArgumentSeparator or the end of the
+// input, but a trailing ArgumentSeparator consumed by Parse is given back
+// to separate it from the next argument.
+type MultiTokenArgumentType interface {
+	ArgumentType
+	// Tokens returns the number of tokens the type consumes,
+	// e.g. 3 for a Vec3, or 0 if it varies with the input.
+	Tokens() int
+}
+
+// argumentTokens returns the MultiTokenArgumentType.Tokens
+// of t or 1 if t is not a MultiTokenArgumentType.
+func argumentTokens(t ArgumentType) int {
+	if m, ok := t.(MultiTokenArgumentType); ok {
+		return m.Tokens()
+	}
+	return 1
+}
+
 // ArgumentTypeFuncs is a convenient struct implementing ArgumentType.
 type ArgumentTypeFuncs struct {
 	Name    string                                      // The name of the argument type returned by ArgumentType.String.
@@ -73,6 +114,33 @@ type ArgumentTypeFuncs struct {
 func (t *ArgumentTypeFuncs) Parse(rd *StringReader) (interface{}, error) { return t.ParseFn(rd) }
 func (t *ArgumentTypeFuncs) String() string                              { return t.Name }
 
+// Get returns the parsed result of an argument from the command context
+// and whether the argument was present in the input or injected, see InjectFn.
+func (c *CommandContext) Get(argumentName string) (interface{}, bool) {
+	r, ok := c.Arguments[argumentName]
+	if !ok {
+		return nil, false
+	}
+	return r.Result, true
+}
+
+// Has indicates whether an argument was present in the input,
+// to distinguish an absent argument from a zero-value.
+func (c *CommandContext) Has(argumentName string) bool {
+	_, ok := c.Arguments[argumentName]
+	return ok
+}
+
+// ParsedRange returns the range of an argument in the command input
+// and whether the argument was present in the input.
+func (c *CommandContext) ParsedRange(argumentName string) (StringRange, bool) {
+	r, ok := c.Arguments[argumentName]
+	if !ok || r.Range == nil {
+		return StringRange{}, false
+	}
+	return *r.Range, true
+}
+
 // Int is the same as CommandContext.Int32.
 func (c *CommandContext) Int(argumentName string) int {
 	return int(c.Int32(argumentName))
@@ -106,6 +174,39 @@ func (c *CommandContext) Int64(argumentName string) int64 {
 	return v
 }
 
+// Uint is the same as CommandContext.Uint32.
+func (c *CommandContext) Uint(argumentName string) uint {
+	return uint(c.Uint32(argumentName))
+}
+
+// Uint32 returns the parsed uint32 argument from the command context.
+// It returns the zero-value if not found.
+func (c *CommandContext) Uint32(argumentName string) uint32 {
+	if c.Arguments == nil {
+		return 0
+	}
+	r, ok := c.Arguments[argumentName]
+	if !ok {
+		return 0
+	}
+	v, _ := r.Result.(uint32)
+	return v
+}
+
+// Uint64 returns the parsed uint64 argument from the command context.
+// It returns the zero-value if not found.
+func (c *CommandContext) Uint64(argumentName string) uint64 {
+	if c.Arguments == nil {
+		return 0
+	}
+	r, ok := c.Arguments[argumentName]
+	if !ok {
+		return 0
+	}
+	v, _ := r.Result.(uint64)
+	return v
+}
+
 // Bool returns the parsed bool argument from the command context.
 // It returns the zero-value if not found.
 func (c *CommandContext) Bool(argumentName string) bool {
@@ -186,11 +287,198 @@ func (t StringType) Parse(rd *StringReader) (interface{}, error) {
 	}
 }
 
+// QuoteSuggestion implements SuggestionQuoter and quotes
+// suggested texts of QuotablePhase arguments using QuoteString.
+func (t StringType) QuoteSuggestion(text string) string {
+	if t == QuotablePhase {
+		return QuoteString(text)
+	}
+	return text
+}
+
+// WordArgumentType is a single-word string ArgumentType like SingleWord
+// with a configurable set of allowed runes, e.g. to parse item IDs
+// like "minecraft:stone" or hex colors like "#FF0000" without quoting.
+type WordArgumentType struct {
+	// Extra are the runes allowed in addition to IsAllowedInUnquotedString.
+	Extra []rune
+	// Allowed optionally replaces IsAllowedInUnquotedString
+	// as the set of allowed runes. Extra runes are still allowed.
+	Allowed func(c rune) bool
+}
+
+// WordWith returns a single-word string ArgumentType allowing
+// the extraRunes in addition to IsAllowedInUnquotedString.
+func WordWith(extraRunes ...rune) *WordArgumentType {
+	return &WordArgumentType{Extra: extraRunes}
+}
+
+func (t *WordArgumentType) String() string { return "string" }
+func (t *WordArgumentType) Parse(rd *StringReader) (interface{}, error) {
+	return rd.ReadWhile(t.IsAllowed), nil
+}
+
+// IsAllowed indicates whether c is an allowed rune of the word.
+func (t *WordArgumentType) IsAllowed(c rune) bool {
+	for _, e := range t.Extra {
+		if c == e {
+			return true
+		}
+	}
+	if t.Allowed != nil {
+		return t.Allowed(c)
+	}
+	return IsAllowedInUnquotedString(c)
+}
+
+// StringLenArgumentType is a string ArgumentType restricted to
+// a number of runes, e.g. to limit the length of names.
+//
+// Clients are sent the single word parser of unknown types,
+// see TypeRegistry.ClientParser.
+type StringLenArgumentType struct {
+	Type     StringType // The string type to parse.
+	Min, Max int        // The inclusive minimum and maximum number of runes.
+}
+
+// StringWithLen returns a QuotablePhase string ArgumentType
+// accepting strings of min to max runes.
+func StringWithLen(min, max int) *StringLenArgumentType {
+	return &StringLenArgumentType{Type: QuotablePhase, Min: min, Max: max}
+}
+
+// WordWithLen returns a SingleWord string ArgumentType
+// accepting words of min to max runes.
+func WordWithLen(min, max int) *StringLenArgumentType {
+	return &StringLenArgumentType{Type: SingleWord, Min: min, Max: max}
+}
+
+func (t *StringLenArgumentType) String() string { return "string" }
+func (t *StringLenArgumentType) Parse(rd *StringReader) (interface{}, error) {
+	start := rd.Cursor
+	v, err := t.Type.Parse(rd)
+	if err != nil {
+		return nil, err
+	}
+	s := v.(string)
+	n := utf8.RuneCountInString(s)
+	lengthErr := &StringLengthError{Length: n, Min: t.Min, Max: t.Max}
+	switch {
+	case n < t.Min:
+		lengthErr.Err = ErrStringTooShort
+	case n > t.Max:
+		lengthErr.Err = ErrStringTooLong
+	default:
+		return s, nil
+	}
+	rd.Cursor = start
+	return nil, &CommandSyntaxError{Err: &ReaderError{Err: lengthErr, Reader: rd}}
+}
+
+// QuoteSuggestion implements SuggestionQuoter, see StringType.QuoteSuggestion.
+func (t *StringLenArgumentType) QuoteSuggestion(text string) string {
+	return t.Type.QuoteSuggestion(text)
+}
+
+// StringLengthError is returned by StringLenArgumentType
+// for strings of a length out of its bounds.
+type StringLengthError struct {
+	Err      error // ErrStringTooShort or ErrStringTooLong.
+	Length   int   // The number of runes of the string.
+	Min, Max int   // The bounds of the StringLenArgumentType.
+}
+
+// Unwrap implements errors.Unwrap.
+func (e *StringLengthError) Unwrap() error { return e.Err }
+func (e *StringLengthError) Error() string {
+	if errors.Is(e.Err, ErrStringTooShort) {
+		return fmt.Sprintf("%v (%d < %d)", e.Err, e.Length, e.Min)
+	}
+	return fmt.Sprintf("%v (%d > %d)", e.Err, e.Length, e.Max)
+}
+
 type BoolArgumentType struct{}
-type Int32ArgumentType struct{ Min, Max int32 }
-type Int64ArgumentType struct{ Min, Max int64 }
-type Float32ArgumentType struct{ Min, Max float32 }
-type Float64ArgumentType struct{ Min, Max float64 }
+type Int32ArgumentType struct {
+	Min, Max int32
+	Suggest  NumberSuggestions // The bounds to suggest, none by default.
+	Samples  []int32           // Additional values to suggest if within Min and Max.
+}
+type Int64ArgumentType struct {
+	Min, Max int64
+	Suggest  NumberSuggestions // The bounds to suggest, none by default.
+	Samples  []int64           // Additional values to suggest if within Min and Max.
+}
+type Uint32ArgumentType struct {
+	Min, Max uint32
+	Suggest  NumberSuggestions // The bounds to suggest, none by default.
+	Samples  []uint32          // Additional values to suggest if within Min and Max.
+}
+type Uint64ArgumentType struct {
+	Min, Max uint64
+	Suggest  NumberSuggestions // The bounds to suggest, none by default.
+	Samples  []uint64          // Additional values to suggest if within Min and Max.
+}
+type Float32ArgumentType struct {
+	Min, Max float32
+	// AllowNonFinite opts in to accept infinite and NaN values.
+	// Infinite values must still be within Min and Max, so
+	// set them to math.Inf to accept infinities. NaN is only
+	// accepted if Min and Max do not restrict the finite values.
+	AllowNonFinite bool
+	Suggest        NumberSuggestions // The bounds to suggest, none by default.
+	Samples        []float32         // Additional values to suggest if within Min and Max.
+}
+type Float64ArgumentType struct {
+	Min, Max float64
+	// AllowNonFinite opts in to accept infinite and NaN values.
+	// Infinite values must still be within Min and Max, so
+	// set them to math.Inf to accept infinities. NaN is only
+	// accepted if Min and Max do not restrict the finite values.
+	AllowNonFinite bool
+	Suggest        NumberSuggestions // The bounds to suggest, none by default.
+	Samples        []float64         // Additional values to suggest if within Min and Max.
+}
+
+// NumberSuggestions selects the values suggested by the numeric argument types,
+// e.g. to give clients a hint about the accepted range.
+type NumberSuggestions uint8
+
+// NumberSuggestions flags. Values are suggested in the order Min, zero, Max
+// and the Samples of the type, if they start with the input.
+const (
+	SuggestMin  NumberSuggestions = 1 << iota // Suggest the minimum.
+	SuggestMax                                // Suggest the maximum.
+	SuggestZero                               // Suggest 0 if within the minimum and maximum.
+
+	// SuggestBounds suggests the minimum, 0 and the maximum.
+	SuggestBounds = SuggestMin | SuggestMax | SuggestZero
+)
+
+// suggestNumbers suggests the formatted minimum, zero and maximum as selected
+// by flags followed by the samples that start with the remaining input.
+func suggestNumbers(builder *SuggestionsBuilder, flags NumberSuggestions, min, max string, zero bool, samples []string) *Suggestions {
+	values := make([]string, 0, 3+len(samples))
+	if flags&SuggestMin != 0 {
+		values = append(values, min)
+	}
+	if flags&SuggestZero != 0 && zero {
+		values = append(values, "0")
+	}
+	if flags&SuggestMax != 0 {
+		values = append(values, max)
+	}
+	values = append(values, samples...)
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		if !seen[v] && strings.HasPrefix(v, builder.Remaining) {
+			seen[v] = true
+			builder.Suggest(v)
+		}
+	}
+	return builder.Build()
+}
+
+func formatFloat(f float64, bitSize int) string { return strconv.FormatFloat(f, 'f', -1, bitSize) }
 
 var (
 	// ErrArgumentIntegerTooHigh occurs when the found integer is higher than the specified maximum.
@@ -202,6 +490,11 @@ var (
 	ErrArgumentFloatTooHigh = errors.New("float too high")
 	// ErrArgumentFloatTooLow occurs when the found float is lower than the specified minimum.
 	ErrArgumentFloatTooLow = errors.New("float too low")
+
+	// ErrStringTooShort occurs when a string has fewer runes than the specified minimum.
+	ErrStringTooShort = errors.New("string too short")
+	// ErrStringTooLong occurs when a string has more runes than the specified maximum.
+	ErrStringTooLong = errors.New("string too long")
 )
 
 func (t *BoolArgumentType) String() string                              { return "bool" }
@@ -219,10 +512,30 @@ func (t *Int32ArgumentType) Parse(rd *StringReader) (interface{}, error) {
 	i, err := parseInt(rd, 32, int64(t.Min), int64(t.Max))
 	return int32(i), err
 }
+func (t *Int32ArgumentType) Suggestions(_ *CommandContext, builder *SuggestionsBuilder) *Suggestions {
+	samples := make([]string, 0, len(t.Samples))
+	for _, v := range t.Samples {
+		if v >= t.Min && v <= t.Max {
+			samples = append(samples, strconv.FormatInt(int64(v), 10))
+		}
+	}
+	return suggestNumbers(builder, t.Suggest, strconv.FormatInt(int64(t.Min), 10),
+		strconv.FormatInt(int64(t.Max), 10), t.Min <= 0 && t.Max >= 0, samples)
+}
 func (t *Int64ArgumentType) String() string { return "int64" }
 func (t *Int64ArgumentType) Parse(rd *StringReader) (interface{}, error) {
 	return parseInt(rd, 64, t.Min, t.Max)
 }
+func (t *Int64ArgumentType) Suggestions(_ *CommandContext, builder *SuggestionsBuilder) *Suggestions {
+	samples := make([]string, 0, len(t.Samples))
+	for _, v := range t.Samples {
+		if v >= t.Min && v <= t.Max {
+			samples = append(samples, strconv.FormatInt(v, 10))
+		}
+	}
+	return suggestNumbers(builder, t.Suggest, strconv.FormatInt(t.Min, 10),
+		strconv.FormatInt(t.Max, 10), t.Min <= 0 && t.Max >= 0, samples)
+}
 func parseInt(rd *StringReader, bitSize int, min, max int64) (int64, error) {
 	start := rd.Cursor
 	result, err := rd.readInt(bitSize)
@@ -242,21 +555,101 @@ func parseInt(rd *StringReader, bitSize int, min, max int64) (int64, error) {
 	return result, nil
 }
 
+func (t *Uint32ArgumentType) String() string { return "uint32" }
+func (t *Uint32ArgumentType) Parse(rd *StringReader) (interface{}, error) {
+	i, err := parseUint(rd, 32, uint64(t.Min), uint64(t.Max))
+	return uint32(i), err
+}
+func (t *Uint32ArgumentType) Suggestions(_ *CommandContext, builder *SuggestionsBuilder) *Suggestions {
+	samples := make([]string, 0, len(t.Samples))
+	for _, v := range t.Samples {
+		if v >= t.Min && v <= t.Max {
+			samples = append(samples, strconv.FormatUint(uint64(v), 10))
+		}
+	}
+	return suggestNumbers(builder, t.Suggest, strconv.FormatUint(uint64(t.Min), 10),
+		strconv.FormatUint(uint64(t.Max), 10), t.Min == 0, samples)
+}
+func (t *Uint64ArgumentType) String() string { return "uint64" }
+func (t *Uint64ArgumentType) Parse(rd *StringReader) (interface{}, error) {
+	return parseUint(rd, 64, t.Min, t.Max)
+}
+func (t *Uint64ArgumentType) Suggestions(_ *CommandContext, builder *SuggestionsBuilder) *Suggestions {
+	samples := make([]string, 0, len(t.Samples))
+	for _, v := range t.Samples {
+		if v >= t.Min && v <= t.Max {
+			samples = append(samples, strconv.FormatUint(v, 10))
+		}
+	}
+	return suggestNumbers(builder, t.Suggest, strconv.FormatUint(t.Min, 10),
+		strconv.FormatUint(t.Max, 10), t.Min == 0, samples)
+}
+func parseUint(rd *StringReader, bitSize int, min, max uint64) (uint64, error) {
+	start := rd.Cursor
+	result, err := rd.readUint(bitSize)
+	if err != nil {
+		return 0, err
+	}
+	if result < min {
+		rd.Cursor = start
+		return 0, &CommandSyntaxError{Err: fmt.Errorf("%w (%d < %d)",
+			ErrArgumentIntegerTooLow, result, min)}
+	}
+	if result > max {
+		rd.Cursor = start
+		return 0, &CommandSyntaxError{Err: fmt.Errorf("%w (%d > %d)",
+			ErrArgumentIntegerTooHigh, result, max)}
+	}
+	return result, nil
+}
+
 func (t *Float32ArgumentType) String() string { return "float32" }
 func (t *Float32ArgumentType) Parse(rd *StringReader) (interface{}, error) {
-	f, err := parseFloat(rd, 32, float64(t.Min), float64(t.Max))
+	f, err := parseFloat(rd, 32, float64(t.Min), float64(t.Max), t.AllowNonFinite)
 	return float32(f), err
 }
+func (t *Float32ArgumentType) Suggestions(_ *CommandContext, builder *SuggestionsBuilder) *Suggestions {
+	samples := make([]string, 0, len(t.Samples))
+	for _, v := range t.Samples {
+		if v >= t.Min && v <= t.Max {
+			samples = append(samples, formatFloat(float64(v), 32))
+		}
+	}
+	return suggestNumbers(builder, t.Suggest, formatFloat(float64(t.Min), 32),
+		formatFloat(float64(t.Max), 32), t.Min <= 0 && t.Max >= 0, samples)
+}
 func (t *Float64ArgumentType) String() string { return "float64" }
 func (t *Float64ArgumentType) Parse(rd *StringReader) (interface{}, error) {
-	return parseFloat(rd, 64, t.Min, t.Max)
+	return parseFloat(rd, 64, t.Min, t.Max, t.AllowNonFinite)
+}
+func (t *Float64ArgumentType) Suggestions(_ *CommandContext, builder *SuggestionsBuilder) *Suggestions {
+	samples := make([]string, 0, len(t.Samples))
+	for _, v := range t.Samples {
+		if v >= t.Min && v <= t.Max {
+			samples = append(samples, formatFloat(v, 64))
+		}
+	}
+	return suggestNumbers(builder, t.Suggest, formatFloat(t.Min, 64),
+		formatFloat(t.Max, 64), t.Min <= 0 && t.Max >= 0, samples)
 }
-func parseFloat(rd *StringReader, bitSize int, min, max float64) (float64, error) {
+func parseFloat(rd *StringReader, bitSize int, min, max float64, allowNonFinite bool) (float64, error) {
 	start := rd.Cursor
-	result, err := rd.readFloat(bitSize)
+	result, err := rd.readFloat(bitSize, allowNonFinite)
 	if err != nil {
 		return 0, err
 	}
+	if math.IsNaN(result) {
+		// NaN compares false to any bound.
+		limit := math.MaxFloat64
+		if bitSize == 32 {
+			limit = math.MaxFloat32
+		}
+		if min > -limit || max < limit {
+			rd.Cursor = start
+			return 0, &CommandSyntaxError{Err: fmt.Errorf("%w (NaN not within %f and %f)",
+				ErrReaderNonFiniteFloat, min, max)}
+		}
+	}
 	if result < min {
 		rd.Cursor = start
 		return 0, &CommandSyntaxError{Err: fmt.Errorf("%w (%f < %f)",
@@ -1,7 +1,9 @@
 package brigodier
 
 import (
+	"context"
 	"github.com/stretchr/testify/require"
+	"math"
 	"testing"
 )
 
@@ -25,6 +27,62 @@ func TestStringType_Parse_Word(t *testing.T) {
 	require.Equal(t, "hello", s)
 	require.Equal(t, " world", r.Remaining())
 }
+func TestWordWith(t *testing.T) {
+	r := &StringReader{String: "minecraft:stone 64"}
+	s, err := WordWith(':').Parse(r)
+	require.NoError(t, err)
+	require.Equal(t, "minecraft:stone", s)
+	require.Equal(t, " 64", r.Remaining())
+
+	r = &StringReader{String: "minecraft:stone"}
+	s, err = StringWord.Parse(r)
+	require.NoError(t, err)
+	require.Equal(t, "minecraft", s)
+
+	hex := &WordArgumentType{Extra: []rune{'#'}, Allowed: func(c rune) bool {
+		return c >= '0' && c <= '9' || c >= 'A' && c <= 'F' || c >= 'a' && c <= 'f'
+	}}
+	r = &StringReader{String: "#FF00aa_"}
+	s, err = hex.Parse(r)
+	require.NoError(t, err)
+	require.Equal(t, "#FF00aa", s)
+	require.Equal(t, "_", r.Remaining())
+
+	d := &Dispatcher{}
+	var got string
+	d.Register(Literal("give").Then(Argument("item", WordWith(':')).Executes(CommandFunc(func(c *CommandContext) error {
+		got = c.String("item")
+		return nil
+	}))))
+	require.NoError(t, d.Do(context.TODO(), "give minecraft:stone"))
+	require.Equal(t, "minecraft:stone", got)
+}
+func TestStringWithLen(t *testing.T) {
+	r := &StringReader{String: `"hello world" x`}
+	s, err := StringWithLen(1, 11).Parse(r)
+	require.NoError(t, err)
+	require.Equal(t, "hello world", s)
+	require.Equal(t, " x", r.Remaining())
+
+	r = &StringReader{String: "x abcd"}
+	r.Cursor = 2
+	_, err = WordWithLen(1, 3).Parse(r)
+	require.ErrorIs(t, err, ErrStringTooLong)
+	require.Equal(t, "string too long (4 > 3)", err.Error())
+	var lengthErr *StringLengthError
+	require.ErrorAs(t, err, &lengthErr)
+	require.Equal(t, StringLengthError{Err: ErrStringTooLong, Length: 4, Min: 1, Max: 3}, *lengthErr)
+	var syntaxErr *CommandSyntaxError
+	require.ErrorAs(t, err, &syntaxErr)
+	require.Equal(t, 2, r.Cursor)
+
+	_, err = StringWithLen(2, 3).Parse(&StringReader{String: `""`})
+	require.ErrorIs(t, err, ErrStringTooShort)
+	require.Equal(t, "string too short (0 < 2)", err.Error())
+
+	require.Equal(t, `"a b"`, StringWithLen(0, 5).QuoteSuggestion("a b"))
+	require.Equal(t, "a b", WordWithLen(0, 5).QuoteSuggestion("a b"))
+}
 func TestStringType_Parse_Phrase(t *testing.T) {
 	r := &StringReader{String: "Hello world! This is a test."}
 	s, err := StringPhrase.Parse(r)
@@ -41,3 +99,141 @@ func TestBoolType_Parse(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, false, parse)
 }
+
+func TestUintType_Parse(t *testing.T) {
+	parse, err := Uint32.Parse(&StringReader{String: "0xFFFF"})
+	require.NoError(t, err)
+	require.Equal(t, uint32(0xFFFF), parse)
+
+	_, err = (&Uint64ArgumentType{Max: 10}).Parse(&StringReader{String: "11"})
+	require.ErrorIs(t, err, ErrArgumentIntegerTooHigh)
+}
+
+func TestFloatType_Parse_NonFinite(t *testing.T) {
+	_, err := Float64.Parse(&StringReader{String: "inf"})
+	require.ErrorIs(t, err, ErrReaderNonFiniteFloat)
+
+	parse, err := (&Float64ArgumentType{Min: MinFloat64, Max: MaxFloat64, AllowNonFinite: true}).
+		Parse(&StringReader{String: "nan"})
+	require.NoError(t, err)
+	require.True(t, math.IsNaN(parse.(float64)))
+
+	_, err = (&Float64ArgumentType{Min: MinFloat64, Max: MaxFloat64, AllowNonFinite: true}).
+		Parse(&StringReader{String: "inf"})
+	require.ErrorIs(t, err, ErrArgumentFloatTooHigh)
+
+	parse, err = (&Float32ArgumentType{Min: float32(math.Inf(-1)), Max: float32(math.Inf(1)), AllowNonFinite: true}).
+		Parse(&StringReader{String: "-inf"})
+	require.NoError(t, err)
+	require.True(t, math.IsInf(float64(parse.(float32)), -1))
+
+	parse, err = (&Float32ArgumentType{Min: MinFloat32, Max: MaxFloat32, AllowNonFinite: true}).
+		Parse(&StringReader{String: "NaN"})
+	require.NoError(t, err)
+	require.True(t, math.IsNaN(float64(parse.(float32))))
+
+	// NaN must not bypass the bounds
+	for _, typ := range []ArgumentType{
+		&Float64ArgumentType{Min: 0, Max: MaxFloat64, AllowNonFinite: true},
+		&Float64ArgumentType{Min: MinFloat64, Max: 1, AllowNonFinite: true},
+		&Float32ArgumentType{Min: 0, Max: 1, AllowNonFinite: true},
+	} {
+		rd := &StringReader{String: "nan"}
+		_, err = typ.Parse(rd)
+		require.ErrorIs(t, err, ErrReaderNonFiniteFloat, typ)
+		require.Equal(t, 0, rd.Cursor)
+	}
+}
+
+func TestCommandContext_Get(t *testing.T) {
+	var d Dispatcher
+	d.Register(Literal("foo").Then(Argument("bar", Int)))
+
+	const input = "foo 0"
+	c := d.Parse(context.TODO(), input).Context
+	v, ok := c.Get("bar")
+	require.True(t, ok)
+	require.Equal(t, int32(0), v)
+	require.True(t, c.Has("bar"))
+	r, ok := c.ParsedRange("bar")
+	require.True(t, ok)
+	require.Equal(t, StringRange{Start: 4, End: 5}, r)
+
+	_, ok = c.Get("baz")
+	require.False(t, ok)
+	require.False(t, c.Has("baz"))
+	_, ok = c.ParsedRange("baz")
+	require.False(t, ok)
+	require.False(t, new(CommandContext).Has("bar"))
+}
+
+func TestNumberType_Suggestions(t *testing.T) {
+	suggest := func(at ArgumentType, remaining string) []string {
+		input := "x " + remaining
+		s := ProvideSuggestions(at, nil, &SuggestionsBuilder{
+			Input: input, InputLowerCase: input, Start: 2,
+			Remaining: remaining, RemainingLowerCase: remaining,
+		})
+		texts := []string{}
+		for _, suggestion := range s.Suggestions {
+			texts = append(texts, suggestion.Text)
+		}
+		return texts
+	}
+	require.Empty(t, suggest(Int32, ""))
+
+	bounded := &Int32ArgumentType{Min: -5, Max: 10, Suggest: SuggestBounds, Samples: []int32{1, 5, 20, 0}}
+	require.Equal(t, []string{"-5", "0", "10", "1", "5"}, suggest(bounded, ""))
+	require.Equal(t, []string{"10"}, suggest(bounded, "1")) // the input itself is not suggested
+	require.Equal(t, []string{"-5"}, suggest(bounded, "-"))
+
+	require.Equal(t, []string{"5"}, suggest(&Int64ArgumentType{Min: 5, Max: 9, Suggest: SuggestMin | SuggestZero}, ""))
+	require.Equal(t, []string{"0", "7"}, suggest(&Uint32ArgumentType{Max: 7, Suggest: SuggestZero | SuggestMax}, ""))
+	require.Equal(t, []string{"3"}, suggest(&Uint64ArgumentType{Min: 1, Max: 9, Samples: []uint64{3}}, ""))
+	require.Equal(t, []string{"0", "0.5"}, suggest(&Float32ArgumentType{Min: 0, Max: 1, Suggest: SuggestMin, Samples: []float32{0.5}}, ""))
+	require.Equal(t, []string{"-1.5", "0", "2.25"}, suggest(&Float64ArgumentType{Min: -1.5, Max: 2.25, Suggest: SuggestBounds}, ""))
+}
+
+// vec3ArgumentType is a MultiTokenArgumentType parsing "x y z"
+// that also consumes the separator following each coordinate.
+type vec3ArgumentType struct{}
+
+func (vec3ArgumentType) String() string { return "vec3" }
+func (vec3ArgumentType) Tokens() int    { return 3 }
+func (vec3ArgumentType) Parse(rd *StringReader) (interface{}, error) {
+	var v [3]float64
+	for i := range v {
+		f, err := rd.ReadFloat64()
+		if err != nil {
+			return nil, err
+		}
+		v[i] = f
+		if rd.CanRead() && rd.Peek() == ArgumentSeparator {
+			rd.Skip()
+		}
+	}
+	return v, nil
+}
+
+func TestMultiTokenArgumentType(t *testing.T) {
+	d := NewDispatcher()
+	var (
+		pos [3]float64
+		yaw int32
+	)
+	d.Register(Literal("tp").Then(Argument("pos", vec3ArgumentType{}).
+		Then(Argument("yaw", Int).Executes(CommandFunc(func(c *CommandContext) error {
+			pos = c.Arguments["pos"].Result.([3]float64)
+			yaw = c.Int32("yaw")
+			return nil
+		})))))
+	parse := d.Parse(context.TODO(), "tp 1 2.5 3 90")
+	require.NoError(t, d.Execute(parse))
+	require.Equal(t, [3]float64{1, 2.5, 3}, pos)
+	require.Equal(t, int32(90), yaw)
+	// the consumed trailing separator is given back
+	require.Equal(t, &StringRange{Start: 3, End: 10}, parse.Context.Arguments["pos"].Range)
+
+	require.ErrorIs(t, d.Do(context.TODO(), "tp 1 2"), ErrReaderExpectedFloat)
+	require.ErrorIs(t, d.Do(context.TODO(), "tp 1 2 3x 90"), ErrDispatcherExpectedArgumentSeparator)
+}
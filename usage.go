@@ -3,6 +3,8 @@ package brigodier
 import (
 	"bytes"
 	"context"
+	"strings"
+	"unicode/utf8"
 )
 
 // AllUsage gets all possible executable commands following the given node.
@@ -17,15 +19,70 @@ import (
 //
 // The path to the specified node will NOT be prepended to the output, as there can theoretically be many
 // ways to reach a given node. It will only give you paths relative to the specified node, not absolute from root.
+//
+// Usages passing a deprecated node are followed by UsageDeprecated.
 func (d *Dispatcher) AllUsage(ctx context.Context, node CommandNode, restricted bool) []string {
-	return d.allUsage(ctx, node, nil, "", restricted)
+	var result []string
+	d.AllUsageFunc(ctx, node, restricted, func(usage string) bool {
+		result = append(result, usage)
+		return true
+	})
+	return result
+}
+
+// AllUsageOptions are the options used by Dispatcher.AllUsageWith.
+type AllUsageOptions struct {
+	// Restricted restricts the usage to the commands the context.Context can use.
+	Restricted bool
+	// Prefix is optionally prepended to each usage, e.g. "/".
+	Prefix string
+	// Path prepends the path from the root to the given node to each usage.
+	Path bool
+}
+
+// AllUsageWith is like AllUsage but formats each usage by the given options.
+func (d *Dispatcher) AllUsageWith(ctx context.Context, node CommandNode, opts AllUsageOptions) []string {
+	format := d.usageFormatter(node, opts.Prefix, opts.Path)
+	var result []string
+	d.AllUsageFunc(ctx, node, opts.Restricted, func(usage string) bool {
+		result = append(result, format(usage))
+		return true
+	})
+	return result
 }
-func (d *Dispatcher) allUsage(ctx context.Context, node CommandNode, result []string, prefix string, restricted bool) []string {
+
+// usageFormatter returns a function prepending the prefix and,
+// if path is true, the usage text of the path to node to a usage.
+func (d *Dispatcher) usageFormatter(node CommandNode, prefix string, path bool) func(usage string) string {
+	var p []string
+	if path {
+		for _, n := range d.pathNodes(node) {
+			p = append(p, n.UsageText())
+		}
+	}
+	return func(usage string) string {
+		if usage == "" {
+			return prefix + strings.Join(p, string(ArgumentSeparator))
+		}
+		return prefix + strings.Join(append(p[:len(p):len(p)], usage), string(ArgumentSeparator))
+	}
+}
+
+// AllUsageFunc is like AllUsage but calls yield for each usage as it is found
+// instead of collecting them, so callers can stream or paginate the usage of large trees.
+// The walk stops as soon as yield returns false.
+func (d *Dispatcher) AllUsageFunc(ctx context.Context, node CommandNode, restricted bool, yield func(usage string) bool) {
+	d.allUsage(ctx, node, yield, "", restricted, false)
+}
+
+// allUsage walks the usage of node and returns false if yield stopped the walk.
+func (d *Dispatcher) allUsage(ctx context.Context, node CommandNode, yield func(string) bool, prefix string, restricted, deprecated bool) bool {
 	if restricted && !node.CanUse(ctx) {
-		return result
+		return true
 	}
-	if node.Command() != nil {
-		result = append(result, prefix)
+	deprecated = deprecated || node.Deprecated() != ""
+	if node.Command() != nil && !yield(markDeprecated(prefix, deprecated)) {
+		return false
 	}
 	b := new(bytes.Buffer)
 	if node.Redirect() != nil {
@@ -41,20 +98,20 @@ func (d *Dispatcher) allUsage(ctx context.Context, node CommandNode, result []st
 			b.WriteString("-> ")
 			b.WriteString(node.Redirect().UsageText())
 		}
-		result = append(result, b.String())
-	} else { // if len(node.Children()) != 0
-		node.ChildrenOrdered().Range(func(_ string, child CommandNode) bool {
-			b.Reset()
-			if prefix != "" {
-				b.WriteString(prefix)
-				b.WriteRune(ArgumentSeparator)
-			}
-			b.WriteString(child.UsageText())
-			result = d.allUsage(ctx, child, result, b.String(), restricted)
-			return true
-		})
+		return yield(markDeprecated(b.String(), deprecated))
 	}
-	return result
+	ok := true
+	node.ChildrenOrdered().Range(func(_ string, child CommandNode) bool {
+		b.Reset()
+		if prefix != "" {
+			b.WriteString(prefix)
+			b.WriteRune(ArgumentSeparator)
+		}
+		b.WriteString(child.UsageText())
+		ok = d.allUsage(ctx, child, yield, b.String(), restricted, deprecated)
+		return ok
+	})
+	return ok
 }
 
 const (
@@ -70,6 +127,18 @@ const (
 	UsageOr rune = '|'
 )
 
+// UsageDeprecated is appended to the usages of deprecated
+// nodes returned by SmartUsage and AllUsage.
+const UsageDeprecated = "(deprecated)"
+
+// markDeprecated appends UsageDeprecated to a non-empty usage if deprecated is true.
+func markDeprecated(usage string, deprecated bool) string {
+	if !deprecated || usage == "" {
+		return usage
+	}
+	return usage + string(ArgumentSeparator) + UsageDeprecated
+}
+
 // SmartUsage gets the possible executable commands from a specified node.
 //
 // You may use Dispatcher.Root as a target to get usage data for the entire command tree.
@@ -83,23 +152,100 @@ const (
 // ways to reach a given node. It will only give you paths relative to the specified node, not absolute from root.
 //
 // The returned usage will be restricted to only commands that the provided context.Context can use.
+// Usages passing a deprecated node are followed by UsageDeprecated,
+// deprecated alternatives of an (either|or) group are not marked.
 func (d *Dispatcher) SmartUsage(ctx context.Context, node CommandNode) CommandNodeStringMap {
+	return d.SmartUsageWith(ctx, node, SmartUsageOptions{})
+}
+
+// SmartUsageOptions are the options used by Dispatcher.SmartUsageWith
+// to bound the size of the usage output, e.g. for "/help" on huge command trees.
+// The zero value imposes no limits.
+type SmartUsageOptions struct {
+	// MaxDepth optionally limits how many nodes follow the first node of a usage.
+	// Deeper nodes are elided with "...".
+	MaxDepth int
+	// MaxChildren optionally limits the alternatives listed in a (either|or) group.
+	// Further alternatives are elided with "...".
+	MaxChildren int
+	// ExecutableOnly omits nodes not leading to an executable command.
+	ExecutableOnly bool
+	// Prefix is optionally prepended to each usage, e.g. "/".
+	Prefix string
+	// Path prepends the path from the root to the given node to each usage.
+	Path bool
+}
+
+// SmartUsageWith is like SmartUsage but bounds the output by the given options.
+func (d *Dispatcher) SmartUsageWith(ctx context.Context, node CommandNode, opts SmartUsageOptions) CommandNodeStringMap {
+	w := &usageWalker{d: d, ctx: ctx, opts: opts}
+	format := func(usage string) string { return usage }
+	if opts.Prefix != "" || opts.Path {
+		format = d.usageFormatter(node, opts.Prefix, opts.Path)
+	}
 	result := NewCommandNodeStringMap()
 	optional := node.Command() != nil
 	node.ChildrenOrdered().Range(func(_ string, child CommandNode) bool {
-		usage := d.smartUsage(ctx, child, optional, false)
+		if !w.include(child) {
+			return true
+		}
+		w.deprecated = false
+		usage := w.usage(child, optional, false, 0)
 		if usage != "" {
-			result.Put(child, usage)
+			result.Put(child, markDeprecated(format(usage), w.deprecated))
 		}
 		return true
 	})
 	return result
 }
+
 func (d *Dispatcher) smartUsage(ctx context.Context, node CommandNode, optional bool, deep bool) string {
-	if !node.CanUse(ctx) {
+	return (&usageWalker{d: d, ctx: ctx}).usage(node, optional, deep, 0)
+}
+
+// usageWalker builds the smart usage of nodes.
+type usageWalker struct {
+	d          *Dispatcher
+	ctx        context.Context
+	opts       SmartUsageOptions
+	executable map[CommandNode]bool // memoizes canExecute
+	deprecated bool                 // whether the usage contains a deprecated node
+}
+
+// include reports whether node is included in the usage.
+func (w *usageWalker) include(node CommandNode) bool {
+	return node.CanUse(w.ctx) && (!w.opts.ExecutableOnly || w.canExecute(node))
+}
+
+// canExecute reports whether node leads to an executable command.
+func (w *usageWalker) canExecute(node CommandNode) bool {
+	if ok, seen := w.executable[node]; seen {
+		return ok
+	}
+	if w.executable == nil {
+		w.executable = map[CommandNode]bool{}
+	}
+	w.executable[node] = false // break cycles
+	ok := node.Command() != nil || node.Redirect() != nil
+	if !ok {
+		node.ChildrenOrdered().Range(func(_ string, child CommandNode) bool {
+			ok = w.canExecute(child)
+			return !ok
+		})
+	}
+	w.executable[node] = ok
+	return ok
+}
+
+// usage returns the usage of the node at the given depth below the first node of the usage.
+func (w *usageWalker) usage(node CommandNode, optional bool, deep bool, depth int) string {
+	if !node.CanUse(w.ctx) {
 		return ""
 	}
 
+	if node.Deprecated() != "" {
+		w.deprecated = true
+	}
 	b := new(bytes.Buffer) // self
 	if optional {
 		b.WriteRune(UsageOptionalOpen)
@@ -124,7 +270,7 @@ func (d *Dispatcher) smartUsage(ctx context.Context, node CommandNode, optional
 
 	if node.Redirect() != nil {
 		b.WriteRune(ArgumentSeparator)
-		if node.Redirect() == &d.Root {
+		if node.Redirect() == &w.d.Root {
 			b.WriteString("...")
 		} else {
 			b.WriteString("-> ")
@@ -135,13 +281,18 @@ func (d *Dispatcher) smartUsage(ctx context.Context, node CommandNode, optional
 
 	var children []CommandNode
 	node.ChildrenOrdered().Range(func(_ string, child CommandNode) bool {
-		if child.CanUse(ctx) {
+		if w.include(child) {
 			children = append(children, child)
 		}
 		return true
 	})
+	if len(children) != 0 && w.opts.MaxDepth > 0 && depth >= w.opts.MaxDepth {
+		b.WriteRune(ArgumentSeparator)
+		b.WriteString(usageElided)
+		return b.String()
+	}
 	if len(children) == 1 {
-		usage := d.smartUsage(ctx, children[0], childOptional, childOptional)
+		usage := w.usage(children[0], childOptional, childOptional, depth+1)
 		if usage != "" {
 			b.WriteRune(ArgumentSeparator)
 			b.WriteString(usage)
@@ -151,9 +302,10 @@ func (d *Dispatcher) smartUsage(ctx context.Context, node CommandNode, optional
 		var (
 			childUsage  []string
 			deduplicate = map[string]struct{}{}
+			deprecated  = w.deprecated // only some of the alternatives may be deprecated
 		)
 		for _, child := range children {
-			usage := d.smartUsage(ctx, child, optional, true)
+			usage := w.usage(child, optional, true, depth+1)
 			if usage != "" {
 				if _, ok := deduplicate[usage]; !ok {
 					childUsage = append(childUsage, usage)
@@ -161,6 +313,7 @@ func (d *Dispatcher) smartUsage(ctx context.Context, node CommandNode, optional
 				}
 			}
 		}
+		w.deprecated = deprecated
 		if len(childUsage) == 1 {
 			b.WriteRune(ArgumentSeparator)
 			if childOptional {
@@ -172,6 +325,10 @@ func (d *Dispatcher) smartUsage(ctx context.Context, node CommandNode, optional
 			}
 			return b.String()
 		} else if len(children) > 1 {
+			elided := false
+			if max := w.opts.MaxChildren; max > 0 && len(children) > max {
+				children, elided = children[:max], true
+			}
 			for i, child := range children {
 				if i == 0 {
 					b.WriteRune(ArgumentSeparator)
@@ -181,6 +338,10 @@ func (d *Dispatcher) smartUsage(ctx context.Context, node CommandNode, optional
 				}
 				b.WriteString(child.UsageText())
 				if i == len(children)-1 {
+					if elided {
+						b.WriteRune(UsageOr)
+						b.WriteString(usageElided)
+					}
 					b.WriteRune(closeChar)
 				}
 			}
@@ -189,3 +350,84 @@ func (d *Dispatcher) smartUsage(ctx context.Context, node CommandNode, optional
 
 	return b.String()
 }
+
+// usageElided replaces nodes elided by SmartUsageOptions.
+const usageElided = "..."
+
+// WrapUsage wraps a usage line as returned by Dispatcher.AllUsage or Dispatcher.SmartUsage
+// into lines that are at most width runes long, which is useful for fixed-width console output.
+//
+// Lines are preferably broken at argument separators outside of brackets so that optional
+// and required groups stay together. A group that is still too long is broken after
+// a UsageOr rune. Continuation lines are prefixed with indent.
+// A single argument that does not fit into width is never broken.
+func WrapUsage(usage string, width int, indent string) []string {
+	if width <= 0 || utf8.RuneCountInString(usage) <= width {
+		return []string{usage}
+	}
+	var (
+		lines []string
+		line  strings.Builder
+	)
+	lineLen := 0
+	write := func(sep, token string) {
+		tokenLen := utf8.RuneCountInString(token)
+		if lineLen != 0 && lineLen+utf8.RuneCountInString(sep)+tokenLen > width {
+			lines = append(lines, line.String())
+			line.Reset()
+			line.WriteString(indent)
+			lineLen = utf8.RuneCountInString(indent)
+			sep = ""
+		}
+		line.WriteString(sep)
+		line.WriteString(token)
+		lineLen += utf8.RuneCountInString(sep) + tokenLen
+	}
+	for i, token := range splitUsage(usage, ArgumentSeparator, 0) {
+		sep := string(ArgumentSeparator)
+		if i == 0 {
+			sep = ""
+		}
+		if utf8.RuneCountInString(indent+token) <= width {
+			write(sep, token)
+			continue
+		}
+		for j, part := range splitUsage(token, UsageOr, 1) {
+			if j == 0 {
+				write(sep, part)
+			} else {
+				write("", part)
+			}
+		}
+	}
+	return append(lines, line.String())
+}
+
+// splitUsage splits usage after each separator rune at the bracket depth.
+// Argument separators are dropped from the result while other separators are kept.
+func splitUsage(usage string, separator rune, depth int) []string {
+	var (
+		parts []string
+		level int
+		start int
+	)
+	for i, c := range usage {
+		switch c {
+		case UsageOptionalOpen, UsageRequiredOpen:
+			level++
+		case UsageOptionalClose, UsageRequiredClose:
+			level--
+		case separator:
+			if level != depth {
+				continue
+			}
+			if separator == ArgumentSeparator {
+				parts = append(parts, usage[start:i])
+			} else {
+				parts = append(parts, usage[start:i+1])
+			}
+			start = i + 1
+		}
+	}
+	return append(parts, usage[start:])
+}
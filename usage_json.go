@@ -0,0 +1,121 @@
+package brigodier
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// MarshalJSON implements json.Marshaler, e.g. for web dashboards consuming completions:
+//
+//	{"range":{"start":3,"end":5},"suggestions":[{"range":{"start":3,"end":5},"text":"alice","tooltip":"Alice"}]}
+func (s *Suggestions) MarshalJSON() ([]byte, error) {
+	suggestions := s.Suggestions
+	if suggestions == nil {
+		suggestions = []*Suggestion{}
+	}
+	return json.Marshal(struct {
+		Range       rangeJSON     `json:"range"`
+		Suggestions []*Suggestion `json:"suggestions"`
+	}{
+		Range:       rangeJSON{Start: s.Range.Start, End: s.Range.End},
+		Suggestions: suggestions,
+	})
+}
+
+// MarshalJSON implements json.Marshaler.
+// The tooltip is omitted if there is none.
+func (s *Suggestion) MarshalJSON() ([]byte, error) {
+	j := struct {
+		Range   rangeJSON `json:"range"`
+		Text    string    `json:"text"`
+		Tooltip string    `json:"tooltip,omitempty"`
+	}{
+		Range: rangeJSON{Start: s.Range.Start, End: s.Range.End},
+		Text:  s.Text,
+	}
+	if s.Tooltip != nil {
+		j.Tooltip = s.Tooltip.String()
+	}
+	return json.Marshal(j)
+}
+
+// UsageEntry is the structured usage of a node returned by Dispatcher.SmartUsageStructured.
+type UsageEntry struct {
+	Node CommandNode `json:"-"`
+	Name string      `json:"name"`
+	// Kind is "literal" or "argument".
+	Kind string `json:"kind"`
+	// Type is the ArgumentType.String of an argument.
+	Type string `json:"type,omitempty"`
+	// Path is the path from the root to the node, see Dispatcher.Path.
+	Path []string `json:"path"`
+	// Usage is the smart usage starting at the node, see Dispatcher.SmartUsage.
+	Usage string `json:"usage"`
+	// Executable indicates whether the node has a Command.
+	Executable bool `json:"executable"`
+	// Redirect is the path to the redirect target of the node or nil if there is none.
+	// It is empty but not nil for redirects to the root.
+	Redirect []string `json:"redirect"`
+	// Children are the usages of the children of the node.
+	Children []*UsageEntry `json:"children,omitempty"`
+}
+
+// SmartUsageStructured is like SmartUsageWith but returns the usage of each child of
+// the node as a UsageEntry with the usages of its children, so that tools can consume
+// the usage without parsing the usage strings.
+//
+// The options bound the entries like the usage strings: children deeper than MaxDepth
+// below the first node are omitted. Prefix and Path apply to the usage strings of the
+// returned entries, the usages of their children are relative to them.
+func (d *Dispatcher) SmartUsageStructured(ctx context.Context, node CommandNode, opts SmartUsageOptions) []*UsageEntry {
+	w := &usageWalker{d: d, ctx: ctx, opts: opts}
+	format := func(usage string) string { return usage }
+	if opts.Prefix != "" || opts.Path {
+		format = d.usageFormatter(node, opts.Prefix, opts.Path)
+	}
+	entries := w.entries(node, d.Path(node), 0, map[CommandNode]bool{})
+	for _, e := range entries {
+		e.Usage = format(e.Usage)
+	}
+	return entries
+}
+
+// entries returns the usage entries of the children of the node at the given depth.
+func (w *usageWalker) entries(node CommandNode, path []string, depth int, visiting map[CommandNode]bool) []*UsageEntry {
+	if visiting[node] {
+		return nil
+	}
+	visiting[node] = true
+	defer delete(visiting, node)
+
+	var entries []*UsageEntry
+	optional := node.Command() != nil
+	node.ChildrenOrdered().Range(func(name string, child CommandNode) bool {
+		if !w.include(child) {
+			return true
+		}
+		e := &UsageEntry{
+			Node:       child,
+			Name:       child.Name(),
+			Kind:       "literal",
+			Path:       append(append(make([]string, 0, len(path)+1), path...), name),
+			Usage:      w.usage(child, optional, false, 0),
+			Executable: child.Command() != nil,
+		}
+		if a, ok := child.(*ArgumentCommandNode); ok {
+			e.Kind = "argument"
+			e.Type = a.Type().String()
+		}
+		if target := child.Redirect(); target != nil {
+			e.Redirect = w.d.Path(target)
+			if e.Redirect == nil {
+				e.Redirect = []string{}
+			}
+		} else if w.opts.MaxDepth <= 0 || depth < w.opts.MaxDepth {
+			e.Children = w.entries(child, e.Path, depth+1, visiting)
+		}
+		entries = append(entries, e)
+		return true
+	})
+	return entries
+}
@@ -0,0 +1,64 @@
+package brigodier
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type stringTooltip string
+
+func (s stringTooltip) String() string { return string(s) }
+
+func TestSuggestions_MarshalJSON(t *testing.T) {
+	s := &Suggestions{
+		Range: StringRange{Start: 3, End: 5},
+		Suggestions: []*Suggestion{
+			{Range: StringRange{Start: 3, End: 5}, Text: "alice", Tooltip: stringTooltip("Alice")},
+			{Range: StringRange{Start: 3, End: 5}, Text: "bob"},
+		},
+	}
+	b, err := json.Marshal(s)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"range":{"start":3,"end":5},"suggestions":[
+		{"range":{"start":3,"end":5},"text":"alice","tooltip":"Alice"},
+		{"range":{"start":3,"end":5},"text":"bob"}
+	]}`, string(b))
+
+	b, err = json.Marshal(&Suggestions{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"range":{"start":0,"end":0},"suggestions":[]}`, string(b))
+}
+
+func TestDispatcher_SmartUsageStructured(t *testing.T) {
+	var d Dispatcher
+	cmd := CommandFunc(func(c *CommandContext) error { return nil })
+	tp := d.Register(Literal("tp").Then(
+		Argument("target", StringWord).Executes(cmd).Then(Argument("y", Int).Executes(cmd)),
+	))
+	d.Register(Literal("teleport").Redirect(tp))
+	d.Register(Literal("execute").Then(Literal("run").Redirect(&d.Root)))
+
+	entries := d.SmartUsageStructured(context.TODO(), &d.Root, SmartUsageOptions{Prefix: "/"})
+	b, err := json.Marshal(entries)
+	require.NoError(t, err)
+	require.JSONEq(t, `[
+		{"name":"tp","kind":"literal","path":["tp"],"usage":"/tp [target] [[y]]","executable":false,"redirect":null,"children":[
+			{"name":"target","kind":"argument","type":"string","path":["tp","target"],"usage":"[target] [[y]]","executable":true,"redirect":null,"children":[
+				{"name":"y","kind":"argument","type":"int32","path":["tp","target","y"],"usage":"[[y]]","executable":true,"redirect":null}
+			]}
+		]},
+		{"name":"teleport","kind":"literal","path":["teleport"],"usage":"/teleport -> tp","executable":false,"redirect":["tp"]},
+		{"name":"execute","kind":"literal","path":["execute"],"usage":"/execute run ...","executable":false,"redirect":null,"children":[
+			{"name":"run","kind":"literal","path":["execute","run"],"usage":"run ...","executable":false,"redirect":[]}
+		]}
+	]`, string(b))
+	require.Same(t, tp, entries[0].Node)
+
+	entries = d.SmartUsageStructured(context.TODO(), tp, SmartUsageOptions{MaxDepth: 1})
+	require.Len(t, entries, 1)
+	require.Equal(t, []string{"tp", "target"}, entries[0].Path)
+	require.Len(t, entries[0].Children, 1)
+	require.Empty(t, entries[0].Children[0].Children)
+}
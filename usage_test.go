@@ -175,3 +175,138 @@ func TestDispatcher_SmartUsage_OffsetH(t *testing.T) {
 		{get(d, "h 3"), "[3]"},
 	}...)
 }
+
+func TestWrapUsage(t *testing.T) {
+	require.Equal(t, []string{"a (1|2)"}, WrapUsage("a (1|2)", 20, "  "))
+	require.Equal(t, []string{
+		"teleport [target]",
+		"  [destination]",
+		"  [x] [y] [z]",
+	}, WrapUsage("teleport [target] [destination] [x] [y] [z]", 17, "  "))
+}
+
+func TestWrapUsage_Group(t *testing.T) {
+	require.Equal(t, []string{
+		"gamemode (survival|",
+		"  creative|",
+		"  adventure)",
+	}, WrapUsage("gamemode (survival|creative|adventure)", 20, "  "))
+}
+
+type hintedType struct{ ArgumentType }
+
+func (t hintedType) UsageHint(name string) string { return TypedUsageHint(name, t.ArgumentType) }
+
+func TestDispatcher_UsageHints(t *testing.T) {
+	var d Dispatcher
+	cmd := CommandFunc(func(c *CommandContext) error { return nil })
+	d.Register(Literal("give").Then(
+		Argument("item", hintedType{StringWord}).Then(
+			Argument("count", hintedType{&Int32ArgumentType{Min: 1, Max: 64}}).Executes(cmd),
+		),
+	))
+	d.Register(Literal("tp").Usage("teleport").Then(
+		Argument("target", StringWord).Usage("<player>").Executes(cmd),
+	))
+
+	require.Equal(t, []string{
+		"give <item:string> <count:int32[1..64]>",
+		"teleport <player>",
+	}, d.AllUsage(context.TODO(), &d.Root, false))
+	usage, _ := d.SmartUsage(context.TODO(), &d.Root).Get(d.Root.Children()["tp"])
+	require.Equal(t, "teleport <player>", usage)
+
+	require.Equal(t, "<x:float64>", TypedUsageHint("x", Float64))
+
+	all := d.AllUsage(context.TODO(), &d.Root, false)
+	require.Equal(t, []string{"give <item:string>", "  <count:int32[1..64]>"}, WrapUsage(all[0], 20, "  "))
+	require.Equal(t, "<player>", d.Root.Children()["tp"].Children()["target"].CreateBuilder().Build().UsageText())
+}
+
+func TestDispatcher_SmartUsageWith(t *testing.T) {
+	var d Dispatcher
+	cmd := CommandFunc(func(c *CommandContext) error { return nil })
+	d.Register(Literal("deep").Then(Literal("a").Then(Literal("b").Then(Literal("c").Executes(cmd)))))
+	d.Register(Literal("wide").Then(
+		Literal("x").Executes(cmd),
+		Literal("y").Executes(cmd),
+		Literal("z").Executes(cmd),
+		Literal("stub"),
+	))
+	d.Register(Literal("stub").Then(Literal("nothing")))
+
+	usage := func(opts SmartUsageOptions) (s []string) {
+		d.SmartUsageWith(context.TODO(), &d.Root, opts).Range(func(_ CommandNode, usage string) bool {
+			s = append(s, usage)
+			return true
+		})
+		return s
+	}
+	require.Equal(t, []string{"deep a b c", "wide (x|y|z|stub)", "stub nothing"}, usage(SmartUsageOptions{}))
+	require.Equal(t, []string{"deep a ...", "wide (x|y|...)", "stub nothing"},
+		usage(SmartUsageOptions{MaxDepth: 1, MaxChildren: 2}))
+	require.Equal(t, []string{"deep a b c", "wide (x|y|z)"}, usage(SmartUsageOptions{ExecutableOnly: true}))
+}
+
+func TestDispatcher_AllUsageFunc(t *testing.T) {
+	var d Dispatcher
+	setupUsage(&d)
+	all := d.AllUsage(context.TODO(), &d.Root, false)
+	require.Greater(t, len(all), 3)
+
+	var page []string
+	d.AllUsageFunc(context.TODO(), &d.Root, false, func(usage string) bool {
+		page = append(page, usage)
+		return len(page) < 3
+	})
+	require.Equal(t, all[:3], page)
+}
+
+func TestDispatcher_UsagePrefix(t *testing.T) {
+	var d Dispatcher
+	cmd := CommandFunc(func(c *CommandContext) error { return nil })
+	d.Register(Literal("tp").Then(Argument("target", StringWord).Executes(cmd).
+		Then(Literal("facing").Executes(cmd))))
+	target := d.Root.Children()["tp"].Children()["target"]
+
+	require.Equal(t, []string{"/tp [target]", "/tp [target] facing"},
+		d.AllUsageWith(context.TODO(), &d.Root, AllUsageOptions{Prefix: "/", Path: true}))
+	require.Equal(t, []string{"/tp [target]", "/tp [target] facing"},
+		d.AllUsageWith(context.TODO(), target, AllUsageOptions{Prefix: "/", Path: true}))
+	require.Equal(t, []string{"/", "/facing"},
+		d.AllUsageWith(context.TODO(), target, AllUsageOptions{Prefix: "/"}))
+
+	usage := d.SmartUsageWith(context.TODO(), target, SmartUsageOptions{Prefix: "/", Path: true})
+	facing, _ := usage.Get(target.Children()["facing"])
+	require.Equal(t, "/tp [target] [facing]", facing)
+}
+
+func TestDispatcher_Usage_Deprecated(t *testing.T) {
+	var d Dispatcher
+	cmd := CommandFunc(func(c *CommandContext) error { return nil })
+	d.Register(Literal("tp").Then(Argument("target", StringWord).Executes(cmd)))
+	d.Register(Literal("teleport").Deprecated("use /tp instead").
+		Then(Argument("target", StringWord).Executes(cmd)))
+	d.Register(Literal("kick").Then(
+		Literal("all").Executes(cmd),
+		Literal("everyone").Deprecated("use /kick all instead").Executes(cmd),
+	))
+
+	require.Equal(t, []string{
+		"tp [target]",
+		"teleport [target] (deprecated)",
+		"kick all",
+		"kick everyone (deprecated)",
+	}, d.AllUsage(context.TODO(), &d.Root, false))
+
+	var smart []string
+	d.SmartUsage(context.TODO(), &d.Root).Range(func(_ CommandNode, usage string) bool {
+		smart = append(smart, usage)
+		return true
+	})
+	require.Equal(t, []string{
+		"tp [target]",
+		"teleport [target] (deprecated)",
+		"kick (all|everyone)",
+	}, smart)
+}
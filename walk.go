@@ -0,0 +1,46 @@
+package brigodier
+
+// WalkFn is called by Dispatcher.Walk for each visited node with the path
+// of nodes leading to it, starting at a child of the root and excluding node.
+// The path must not be retained after the call since it is reused.
+// Returning false stops the walk.
+type WalkFn func(path []CommandNode, node CommandNode) bool
+
+// Walk walks the command tree below the Dispatcher.Root depth-first in
+// registration order and calls fn for each node, see WalkNode.
+// It returns false if fn stopped the walk.
+func (d *Dispatcher) Walk(fn WalkFn) bool {
+	return WalkNode(&d.Root, fn)
+}
+
+// WalkNode walks the subtree below node depth-first in registration order
+// and calls fn for each node, excluding node itself.
+// It returns false if fn stopped the walk.
+//
+// Redirects are not followed, use CommandNode.Redirect to inspect them.
+// A node reachable through multiple parents is visited once per path,
+// but a node already in its own path or node itself is skipped to not loop endlessly.
+func WalkNode(node CommandNode, fn WalkFn) bool {
+	return walk(node, node, fn, nil)
+}
+
+func walk(start, node CommandNode, fn WalkFn, path []CommandNode) bool {
+	ok := true
+	node.ChildrenOrdered().Range(func(_ string, child CommandNode) bool {
+		if child == start {
+			return true // cycle
+		}
+		for _, p := range path {
+			if p == child {
+				return true // cycle
+			}
+		}
+		if !fn(path, child) {
+			ok = false
+			return false
+		}
+		ok = walk(start, child, fn, append(path, child))
+		return ok
+	})
+	return ok
+}
@@ -0,0 +1,42 @@
+package brigodier
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestDispatcher_Walk(t *testing.T) {
+	var d Dispatcher
+	foo := d.Register(Literal("foo").Then(
+		Literal("bar").Then(Argument("n", Int)),
+		Literal("baz"),
+	))
+	d.Register(Literal("alias").Redirect(foo))
+	foo.AddChild(foo) // cycle
+
+	var visited []string
+	require.True(t, d.Walk(func(path []CommandNode, node CommandNode) bool {
+		names := []string{}
+		for _, p := range path {
+			names = append(names, p.Name())
+		}
+		visited = append(visited, strings.Join(append(names, node.Name()), " "))
+		return true
+	}))
+	require.Equal(t, []string{"foo", "foo bar", "foo bar n", "foo baz", "alias"}, visited)
+
+	visited = nil
+	require.False(t, d.Walk(func(path []CommandNode, node CommandNode) bool {
+		visited = append(visited, node.Name())
+		return node.Name() != "n"
+	}))
+	require.Equal(t, []string{"foo", "bar", "n"}, visited)
+
+	visited = nil
+	require.True(t, WalkNode(foo, func(path []CommandNode, node CommandNode) bool {
+		visited = append(visited, node.Name())
+		return true
+	}))
+	require.Equal(t, []string{"bar", "n", "baz"}, visited)
+}
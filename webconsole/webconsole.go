@@ -0,0 +1,222 @@
+// Package webconsole exposes a brigodier Dispatcher over HTTP and WebSocket,
+// e.g. to drive the commands of a server from a web admin console.
+//
+// The Handler serves the remote.Service of the Dispatcher as JSON:
+//
+//	POST /execute                 {"input":"say hi"} -> remote.ExecuteResponse
+//	GET  /suggest?input=&cursor=  -> remote.SuggestResponse
+//	GET  /tree                    -> remote.Tree
+//	GET  /ws                      WebSocket, see Message
+//
+// Each request is authenticated by the Auth function of the Handler, which maps
+// it to the context.Context the commands are parsed with, e.g. using brigodier.WithSource.
+// Requests from browsers are only served for the origin of the Handler by default,
+// so other websites can not drive the console with the credentials of a user.
+package webconsole
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.minekube.com/brigodier"
+	"go.minekube.com/brigodier/remote"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxMessageSize is the default of Handler.MaxMessageSize.
+const DefaultMaxMessageSize = 64 << 10
+
+// AuthFunc authenticates a request and returns the context.Context
+// the commands of the request are parsed and executed with.
+// The request is rejected with 401 Unauthorized if it returns an error.
+type AuthFunc func(r *http.Request) (context.Context, error)
+
+// ErrUnauthorized can be returned by an AuthFunc to reject a request.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// Handler is an http.Handler serving a remote.Service.
+type Handler struct {
+	Service remote.Service
+	// Auth authenticates requests. If nil, requests are
+	// rejected unless InsecureSkipAuth is set.
+	Auth AuthFunc
+	// InsecureSkipAuth serves all requests with the context.Context of the
+	// request if Auth is nil. It should only be used for testing or if access
+	// to the Handler is restricted otherwise.
+	InsecureSkipAuth bool
+	// CheckOrigin optionally reports whether to serve a request with an Origin
+	// header, which browsers set for cross-origin and WebSocket requests.
+	// If nil, the host of the Origin must match the Host of the request.
+	// Requests are rejected with 403 Forbidden if it returns false.
+	CheckOrigin func(r *http.Request) bool
+	// MaxMessageSize limits the size of request bodies and WebSocket messages.
+	// Zero uses DefaultMaxMessageSize.
+	MaxMessageSize int64
+
+	mux *http.ServeMux
+}
+
+var _ http.Handler = (*Handler)(nil)
+
+// NewHandler returns a new Handler serving the Dispatcher.
+// Requests are rejected if auth is nil, see Handler.InsecureSkipAuth.
+func NewHandler(d *brigodier.Dispatcher, auth AuthFunc) *Handler {
+	return &Handler{Service: remote.NewServer(d), Auth: auth}
+}
+
+// ErrNoAuth is returned with 500 Internal Server Error for all
+// requests to a Handler without Auth and InsecureSkipAuth.
+var ErrNoAuth = errors.New("webconsole: no Auth configured")
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !h.checkOrigin(r) {
+		http.Error(w, "cross-origin request rejected", http.StatusForbidden)
+		return
+	}
+	switch {
+	case h.Auth != nil:
+		var err error
+		if ctx, err = h.Auth(r); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	case !h.InsecureSkipAuth:
+		http.Error(w, ErrNoAuth.Error(), http.StatusInternalServerError)
+		return
+	}
+	switch r.URL.Path {
+	case "/execute":
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		// Reject bodies browsers can send cross-origin without preflight, e.g. text/plain forms.
+		if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+			http.Error(w, "content type must be application/json", http.StatusUnsupportedMediaType)
+			return
+		}
+		var req remote.ExecuteRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.maxMessageSize())).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("error decoding request: %v", err), http.StatusBadRequest)
+			return
+		}
+		h.respond(w, func() (interface{}, error) { return h.Service.Execute(ctx, &req) })
+	case "/suggest":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		req := remote.SuggestRequest{Input: r.URL.Query().Get("input"), Cursor: -1}
+		if c := r.URL.Query().Get("cursor"); c != "" {
+			cursor, err := strconv.ParseInt(c, 10, 32)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid cursor: %v", err), http.StatusBadRequest)
+				return
+			}
+			req.Cursor = int32(cursor)
+		}
+		h.respond(w, func() (interface{}, error) { return h.Service.Suggest(ctx, &req) })
+	case "/tree":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		h.respond(w, func() (interface{}, error) { return h.Service.GetTree(ctx, &remote.GetTreeRequest{}) })
+	case "/ws":
+		h.serveWebSocket(ctx, w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// checkOrigin reports whether the request is served according to CheckOrigin.
+func (h *Handler) checkOrigin(r *http.Request) bool {
+	if r.Header.Get("Origin") == "" {
+		return true // not sent by a browser
+	}
+	if h.CheckOrigin != nil {
+		return h.CheckOrigin(r)
+	}
+	u, err := url.Parse(r.Header.Get("Origin"))
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+func (h *Handler) maxMessageSize() int64 {
+	if h.MaxMessageSize == 0 {
+		return DefaultMaxMessageSize
+	}
+	return h.MaxMessageSize
+}
+
+func (h *Handler) respond(w http.ResponseWriter, call func() (interface{}, error)) {
+	res, err := call()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func methodNotAllowed(w http.ResponseWriter, allow string) {
+	w.Header().Set("Allow", allow)
+	http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+}
+
+// Message is a request or response of the WebSocket endpoint.
+// Each text message of the client is a request answered by a response with the same ID:
+//
+//	{"id":1,"method":"execute","input":"say hi"}  -> {"id":1,"result":<remote.ExecuteResponse>}
+//	{"id":2,"method":"suggest","input":"sa","cursor":-1} -> {"id":2,"result":<remote.SuggestResponse>}
+//	{"id":3,"method":"tree"}                      -> {"id":3,"result":<remote.Tree>}
+//
+// Failed requests are answered with an error instead of a result.
+type Message struct {
+	ID     int64           `json:"id"`
+	Method string          `json:"method,omitempty"` // Request only.
+	Input  string          `json:"input,omitempty"`  // Request only.
+	Cursor *int32          `json:"cursor,omitempty"` // Request only, the end of input if nil.
+	Result json.RawMessage `json:"result,omitempty"` // Response only.
+	Error  string          `json:"error,omitempty"`  // Response only.
+}
+
+// handleMessage answers a request of the WebSocket endpoint.
+func (h *Handler) handleMessage(ctx context.Context, data []byte) *Message {
+	var req Message
+	if err := json.Unmarshal(data, &req); err != nil {
+		return &Message{Error: fmt.Sprintf("error decoding request: %v", err)}
+	}
+	var (
+		res interface{}
+		err error
+	)
+	switch req.Method {
+	case "execute":
+		res, err = h.Service.Execute(ctx, &remote.ExecuteRequest{Input: req.Input})
+	case "suggest":
+		cursor := int32(-1)
+		if req.Cursor != nil {
+			cursor = *req.Cursor
+		}
+		res, err = h.Service.Suggest(ctx, &remote.SuggestRequest{Input: req.Input, Cursor: cursor})
+	case "tree":
+		res, err = h.Service.GetTree(ctx, &remote.GetTreeRequest{})
+	default:
+		err = fmt.Errorf("unknown method %q", req.Method)
+	}
+	out := &Message{ID: req.ID}
+	if err == nil {
+		out.Result, err = json.Marshal(res)
+	}
+	if err != nil {
+		out.Error = err.Error()
+	}
+	return out
+}
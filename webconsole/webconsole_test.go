@@ -0,0 +1,235 @@
+package webconsole
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"go.minekube.com/brigodier"
+	"go.minekube.com/brigodier/remote"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type adminKey struct{}
+
+func testServer(t *testing.T, executed *[]string) *httptest.Server {
+	d := new(brigodier.Dispatcher)
+	cmd := brigodier.CommandFunc(func(c *brigodier.CommandContext) error {
+		*executed = append(*executed, c.Input)
+		return nil
+	})
+	d.Register(brigodier.Literal("say").Then(brigodier.Argument("message", brigodier.StringPhrase).Executes(cmd)))
+	d.Register(brigodier.Literal("stop").Executes(cmd).Requires(func(ctx context.Context) bool {
+		return ctx.Value(adminKey{}) != nil
+	}))
+	h := NewHandler(d, func(r *http.Request) (context.Context, error) {
+		switch r.Header.Get("Authorization") {
+		case "admin":
+			return context.WithValue(r.Context(), adminKey{}, true), nil
+		case "user":
+			return r.Context(), nil
+		}
+		return nil, ErrUnauthorized
+	})
+	s := httptest.NewServer(h)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func do(t *testing.T, method, url, auth, body string, v interface{}) int {
+	return doWith(t, method, url, body, v, http.Header{"Authorization": {auth}, "Content-Type": {"application/json"}})
+}
+
+func doWith(t *testing.T, method, url, body string, v interface{}, header http.Header) int {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header = header
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusOK && v != nil {
+		require.NoError(t, json.NewDecoder(res.Body).Decode(v))
+	}
+	return res.StatusCode
+}
+
+func TestHandler(t *testing.T) {
+	var executed []string
+	s := testServer(t, &executed)
+
+	var exec remote.ExecuteResponse
+	require.Equal(t, http.StatusOK, do(t, "POST", s.URL+"/execute", "user", `{"input":"say hi"}`, &exec))
+	require.Equal(t, remote.ExecuteResponse{Cursor: -1}, exec)
+	require.Equal(t, []string{"say hi"}, executed)
+
+	require.Equal(t, http.StatusOK, do(t, "POST", s.URL+"/execute", "user", `{"input":"stop"}`, &exec))
+	require.True(t, exec.SyntaxError)
+	exec = remote.ExecuteResponse{}
+	require.Equal(t, http.StatusOK, do(t, "POST", s.URL+"/execute", "admin", `{"input":"stop"}`, &exec))
+	require.Empty(t, exec.Error)
+
+	var suggest remote.SuggestResponse
+	require.Equal(t, http.StatusOK, do(t, "GET", s.URL+"/suggest?input=s&cursor=1", "admin", "", &suggest))
+	require.Len(t, suggest.Suggestions, 2)
+	require.Equal(t, http.StatusOK, do(t, "GET", s.URL+"/suggest?input=say+h&cursor=2", "user", "", &suggest))
+	require.Len(t, suggest.Suggestions, 1)
+	require.Equal(t, "say", suggest.Suggestions[0].Text)
+
+	var tree remote.Tree
+	require.Equal(t, http.StatusOK, do(t, "GET", s.URL+"/tree", "user", "", &tree))
+	require.Len(t, tree.Nodes[tree.Root].Children, 1)
+
+	require.Equal(t, http.StatusUnauthorized, do(t, "GET", s.URL+"/tree", "", "", nil))
+	require.Equal(t, http.StatusMethodNotAllowed, do(t, "GET", s.URL+"/execute", "user", "", nil))
+	require.Equal(t, http.StatusBadRequest, do(t, "POST", s.URL+"/execute", "user", `{`, nil))
+	require.Equal(t, http.StatusBadRequest, do(t, "GET", s.URL+"/suggest?cursor=x", "user", "", nil))
+	require.Equal(t, http.StatusNotFound, do(t, "GET", s.URL+"/missing", "user", "", nil))
+	require.Equal(t, http.StatusUpgradeRequired, do(t, "GET", s.URL+"/ws", "user", "", nil))
+}
+
+func TestHandler_CrossOrigin(t *testing.T) {
+	var executed []string
+	s := testServer(t, &executed)
+	header := func(origin, contentType string) http.Header {
+		return http.Header{"Authorization": {"admin"}, "Origin": {origin}, "Content-Type": {contentType}}
+	}
+
+	same := s.URL
+	require.Equal(t, http.StatusOK, doWith(t, "POST", s.URL+"/execute", `{"input":"say a"}`, nil, header(same, "application/json; charset=utf-8")))
+	require.Equal(t, http.StatusForbidden, doWith(t, "POST", s.URL+"/execute", `{"input":"say b"}`, nil, header("https://evil.example", "application/json")))
+	require.Equal(t, http.StatusForbidden, doWith(t, "GET", s.URL+"/ws", "", nil, header("null", "")))
+	require.Equal(t, http.StatusUnsupportedMediaType, doWith(t, "POST", s.URL+"/execute", `{"input":"say c"}`, nil, header(same, "text/plain")))
+	require.Equal(t, http.StatusUnsupportedMediaType, doWith(t, "POST", s.URL+"/execute", `{"input":"say d"}`, nil, http.Header{"Authorization": {"admin"}}))
+	require.Equal(t, []string{"say a"}, executed)
+
+	h := s.Config.Handler.(*Handler)
+	h.CheckOrigin = func(r *http.Request) bool { return r.Header.Get("Origin") == "https://admin.example" }
+	require.Equal(t, http.StatusOK, doWith(t, "POST", s.URL+"/execute", `{"input":"say e"}`, nil, header("https://admin.example", "application/json")))
+	require.Equal(t, http.StatusForbidden, doWith(t, "POST", s.URL+"/execute", `{"input":"say f"}`, nil, header(same, "application/json")))
+	require.Equal(t, []string{"say a", "say e"}, executed)
+}
+
+func TestHandler_NoAuth(t *testing.T) {
+	d := new(brigodier.Dispatcher)
+	d.Register(brigodier.Literal("ping").Executes(brigodier.CommandFunc(func(c *brigodier.CommandContext) error { return nil })))
+	h := NewHandler(d, nil)
+	s := httptest.NewServer(h)
+	t.Cleanup(s.Close)
+
+	require.Equal(t, http.StatusInternalServerError, do(t, "POST", s.URL+"/execute", "", `{"input":"ping"}`, nil))
+	require.Equal(t, http.StatusInternalServerError, do(t, "GET", s.URL+"/tree", "", "", nil))
+	h.InsecureSkipAuth = true
+	var exec remote.ExecuteResponse
+	require.Equal(t, http.StatusOK, do(t, "POST", s.URL+"/execute", "", `{"input":"ping"}`, &exec))
+	require.Empty(t, exec.Error)
+}
+
+func TestHandler_WebSocket(t *testing.T) {
+	var executed []string
+	s := testServer(t, &executed)
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(s.URL, "http://"))
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.SetDeadline(time.Now().Add(10*time.Second)))
+	_, err = conn.Write([]byte("GET /ws HTTP/1.1\r\nHost: test\r\nAuthorization: admin\r\n" +
+		"Upgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n"))
+	require.NoError(t, err)
+	r := bufio.NewReader(conn)
+	res, err := http.ReadResponse(r, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusSwitchingProtocols, res.StatusCode)
+	require.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", res.Header.Get("Sec-WebSocket-Accept"))
+
+	call := func(req string) Message {
+		// a masked text frame split into two fragments
+		writeMasked(t, conn, 0x01, []byte(req[:3]))
+		writeMasked(t, conn, 0x80, []byte(req[3:]))
+		fin, op, payload := readServerFrame(t, r)
+		require.True(t, fin)
+		require.Equal(t, byte(opText), op)
+		var m Message
+		require.NoError(t, json.Unmarshal(payload, &m))
+		return m
+	}
+
+	m := call(`{"id":1,"method":"execute","input":"stop"}`)
+	require.Equal(t, int64(1), m.ID)
+	require.Empty(t, m.Error)
+	require.Equal(t, []string{"stop"}, executed)
+
+	m = call(`{"id":2,"method":"suggest","input":"sa"}`)
+	var suggest remote.SuggestResponse
+	require.NoError(t, json.Unmarshal(m.Result, &suggest))
+	require.Equal(t, "say", suggest.Suggestions[0].Text)
+
+	m = call(`{"id":3,"method":"tree"}`)
+	var tree remote.Tree
+	require.NoError(t, json.Unmarshal(m.Result, &tree))
+	require.Len(t, tree.Nodes[tree.Root].Children, 2)
+
+	m = call(`{"id":4,"method":"nope"}`)
+	require.Equal(t, Message{ID: 4, Error: `unknown method "nope"`}, m)
+
+	// a ping between the fragments of a message
+	writeMasked(t, conn, opText, []byte(`{"id":5,`))
+	writeMasked(t, conn, 0x80|opPing, []byte("mid"))
+	writeMasked(t, conn, 0x80|opContinuation, []byte(`"method":"execute","input":"say hi"}`))
+	_, op, payload := readServerFrame(t, r)
+	require.Equal(t, byte(opPong), op)
+	require.Equal(t, "mid", string(payload))
+	_, op, payload = readServerFrame(t, r)
+	require.Equal(t, byte(opText), op)
+	var m5 Message
+	require.NoError(t, json.Unmarshal(payload, &m5))
+	require.Equal(t, int64(5), m5.ID)
+	require.Empty(t, m5.Error)
+	require.Equal(t, []string{"stop", "say hi"}, executed)
+
+	writeMasked(t, conn, 0x80|opPing, []byte("hi"))
+	_, op, payload = readServerFrame(t, r)
+	require.Equal(t, byte(opPong), op)
+	require.Equal(t, "hi", string(payload))
+
+	writeMasked(t, conn, 0x80|opClose, nil)
+	_, op, _ = readServerFrame(t, r)
+	require.Equal(t, byte(opClose), op)
+}
+
+func writeMasked(t *testing.T, conn net.Conn, head byte, payload []byte) {
+	mask := [4]byte{1, 2, 3, 4}
+	frame := []byte{head, 0x80 | byte(len(payload))}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	_, err := conn.Write(frame)
+	require.NoError(t, err)
+}
+
+func readServerFrame(t *testing.T, r *bufio.Reader) (fin bool, op byte, payload []byte) {
+	head := make([]byte, 2)
+	_, err := r.Read(head[:1])
+	require.NoError(t, err)
+	_, err = r.Read(head[1:])
+	require.NoError(t, err)
+	size := int(head[1] & 0x7F)
+	if size == 126 {
+		b1, _ := r.ReadByte()
+		b2, _ := r.ReadByte()
+		size = int(b1)<<8 | int(b2)
+	}
+	payload = make([]byte, size)
+	for n := 0; n < size; {
+		m, err := r.Read(payload[n:])
+		require.NoError(t, err)
+		n += m
+	}
+	return head[0]&0x80 != 0, head[0] & 0x0F, payload
+}
@@ -0,0 +1,202 @@
+package webconsole
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// A minimal server side implementation of the WebSocket protocol (RFC 6455)
+// sufficient for exchanging JSON text messages with browsers.
+
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket opcodes.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+var (
+	errMessageTooLarge = errors.New("websocket: message too large")
+	errClosed          = errors.New("websocket: closed by client")
+)
+
+func (h *Handler) serveWebSocket(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet ||
+		!headerContains(r.Header, "Connection", "upgrade") ||
+		!headerContains(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "websocket upgrade required", http.StatusUpgradeRequired)
+		return
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" || r.Header.Get("Sec-WebSocket-Version") != "13" {
+		http.Error(w, "unsupported websocket version", http.StatusBadRequest)
+		return
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket not supported", http.StatusInternalServerError)
+		return
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer conn.Close()
+
+	accept := sha1.Sum([]byte(key + websocketGUID))
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\n"+
+		"Upgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n",
+		base64.StdEncoding.EncodeToString(accept[:]))
+	if err = rw.Flush(); err != nil {
+		return
+	}
+
+	for {
+		op, data, err := readMessage(rw, h.maxMessageSize())
+		if err != nil {
+			if errors.Is(err, errMessageTooLarge) {
+				_ = writeFrame(rw.Writer, opClose, []byte{0x03, 0xF1}) // 1009 message too big
+				_ = rw.Flush()
+			}
+			return
+		}
+		if op != opText && op != opBinary {
+			continue // continuation without a started message
+		}
+		var res []byte
+		if res, err = json.Marshal(h.handleMessage(ctx, data)); err == nil {
+			err = writeFrame(rw.Writer, opText, res)
+		}
+		if err == nil {
+			err = rw.Flush()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// headerContains reports whether the comma separated
+// header values contain the token ignoring case.
+func headerContains(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readMessage reads the frames of the next data message. Control frames,
+// which may be interleaved with the fragments of a message, are handled
+// in between: pings are answered, pongs are ignored and a close frame is
+// answered and returns errClosed.
+func readMessage(rw *bufio.ReadWriter, max int64) (op byte, data []byte, err error) {
+	for {
+		fin, frameOp, payload, err := readFrame(rw.Reader, max-int64(len(data)))
+		if err != nil {
+			return 0, nil, err
+		}
+		switch frameOp {
+		case opPing:
+			if err = writeFrame(rw.Writer, opPong, payload); err == nil {
+				err = rw.Flush()
+			}
+			if err != nil {
+				return 0, nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			_ = writeFrame(rw.Writer, opClose, nil)
+			_ = rw.Flush()
+			return 0, nil, errClosed
+		}
+		if frameOp != opContinuation {
+			op = frameOp
+		}
+		data = append(data, payload...)
+		if fin {
+			return op, data, nil
+		}
+	}
+}
+
+func readFrame(r *bufio.Reader, max int64) (fin bool, op byte, payload []byte, err error) {
+	var head [2]byte
+	if _, err = io.ReadFull(r, head[:]); err != nil {
+		return
+	}
+	fin, op = head[0]&0x80 != 0, head[0]&0x0F
+	masked := head[1]&0x80 != 0
+	size := int64(head[1] & 0x7F)
+	switch size {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(r, ext[:]); err != nil {
+			return
+		}
+		size = int64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(r, ext[:]); err != nil {
+			return
+		}
+		size = int64(binary.BigEndian.Uint64(ext[:]))
+	}
+	if size < 0 || size > max {
+		return false, 0, nil, errMessageTooLarge
+	}
+	if !masked {
+		return false, 0, nil, errors.New("websocket: unmasked client frame")
+	}
+	var mask [4]byte
+	if _, err = io.ReadFull(r, mask[:]); err != nil {
+		return
+	}
+	payload = make([]byte, size)
+	if _, err = io.ReadFull(r, payload); err != nil {
+		return
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, op, payload, nil
+}
+
+func writeFrame(w io.Writer, op byte, payload []byte) error {
+	head := []byte{0x80 | op}
+	switch n := len(payload); {
+	case n < 126:
+		head = append(head, byte(n))
+	case n <= 0xFFFF:
+		head = append(head, 126, byte(n>>8), byte(n))
+	default:
+		var ext [8]byte
+		binary.BigEndian.PutUint64(ext[:], uint64(n))
+		head = append(append(head, 127), ext[:]...)
+	}
+	if _, err := w.Write(head); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+	return err
+}